@@ -3,7 +3,11 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/kiracore/kanban/internal/config"
 	"github.com/kiracore/kanban/internal/github"
@@ -16,15 +20,38 @@ var auditCmd = &cobra.Command{
 	Short: "Check label consistency across repositories",
 	Long: `Audit repositories for label consistency.
 
-Reports missing, extra, and different labels compared to the config.`,
+Reports missing, extra, and different labels compared to the config.
+With --format json, missing and modified labels also include the full
+expected (and actual) label.
+
+With --fix, missing labels are created and modified ones updated to match
+the config. --delete-extra also deletes extra labels, which are only
+reported when settings.preserve_unknown is false; it lists them and asks
+first (--yes skips the question). --dry-run shows what would change
+without touching any repository.
+
+Examples:
+  kanban audit --all
+  kanban audit --all --fix --dry-run
+  kanban audit --repo myrepo --fix --delete-extra`,
 	RunE: runAudit,
 }
 
+var (
+	auditFix         bool
+	auditDeleteExtra bool
+)
+
 func init() {
 	rootCmd.AddCommand(auditCmd)
 	auditCmd.Flags().StringVarP(&repo, "repo", "r", "", "specific repository")
+	auditCmd.Flags().StringVar(&repoList, "repos", "", "comma-separated repositories (overrides --repo and the config list)")
 	auditCmd.Flags().BoolVar(&allRepos, "all", false, "audit all repositories")
 	auditCmd.Flags().StringVarP(&format, "format", "f", "table", "output format (table|json)")
+	auditCmd.Flags().StringVar(&outputFile, "output", "", "write the report to this file instead of stdout")
+	auditCmd.Flags().BoolVar(&auditFix, "fix", false, "create missing and update modified labels")
+	auditCmd.Flags().BoolVar(&auditDeleteExtra, "delete-extra", false, "with --fix, also delete extra labels")
+	auditCmd.Flags().IntVar(&concurrencyOverride, "concurrency", 0, "repos audited in parallel (overrides settings.concurrency)")
 }
 
 type AuditResult struct {
@@ -32,9 +59,36 @@ type AuditResult struct {
 	Missing  []string `json:"missing"`
 	Extra    []string `json:"extra"`
 	Modified []string `json:"modified"`
+
+	// Full labels for remediation tooling (--format json)
+	MissingLabels  []config.Label  `json:"missing_labels"`
+	ModifiedLabels []ModifiedLabel `json:"modified_labels"`
+
+	// Set when --fix was given
+	Fix *AuditFix `json:"fix,omitempty"`
+}
+
+// AuditFix records the label changes --fix applied to a repository
+// (or would apply, under --dry-run)
+type AuditFix struct {
+	DryRun  bool     `json:"dry_run"`
+	Created []string `json:"created"`
+	Updated []string `json:"updated"`
+	Deleted []string `json:"deleted"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+// ModifiedLabel is a label whose color or description differs from the config
+type ModifiedLabel struct {
+	Name     string       `json:"name"`
+	Expected config.Label `json:"expected"`
+	Actual   config.Label `json:"actual"`
 }
 
 func runAudit(cmd *cobra.Command, args []string) error {
+	if err := validateConcurrency(cmd); err != nil {
+		return err
+	}
 	organization := viper.GetString("organization")
 	if organization == "" && org != "" {
 		organization = org
@@ -43,6 +97,9 @@ func runAudit(cmd *cobra.Command, args []string) error {
 	if organization == "" {
 		return fmt.Errorf("organization required: use --org flag or set in config")
 	}
+	if auditDeleteExtra && !auditFix {
+		return fmt.Errorf("--delete-extra requires --fix")
+	}
 
 	// Load config
 	cfg, err := config.Load()
@@ -56,99 +113,220 @@ func runAudit(cmd *cobra.Command, args []string) error {
 		expectedMap[l.Name] = l
 	}
 
-	client := github.NewClient()
+	client := newGitHubClient(timeoutCtx)
 
 	// Determine target repos
-	var repos []string
-	if repo != "" {
-		repos = []string{repo}
-	} else if allRepos {
-		repos, err = client.ListRepos(organization)
-		if err != nil {
-			return err
-		}
-		repos = cfg.FilterRepos(repos)
-	} else {
-		return fmt.Errorf("specify --repo or --all")
+	repos, _, err := resolveRepos(cfg, client, organization)
+	if err != nil {
+		return err
 	}
 
+	sem := make(chan struct{}, workerConcurrency())
+	var wg sync.WaitGroup
+	var mu sync.Mutex
 	var results []AuditResult
 
 	for _, r := range repos {
-		current, err := client.ListLabels(organization, r)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to audit %s: %v\n", r, err)
-			continue
-		}
+		wg.Add(1)
+		go func(repoName string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
 
-		currentMap := make(map[string]config.Label)
-		for _, l := range current {
-			currentMap[l.Name] = l
-		}
-
-		result := AuditResult{Repo: r}
+			current, err := client.ListLabels(organization, repoName)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to audit %s: %v\n", repoName, err)
+				return
+			}
 
-		// Find missing and modified
-		for name, expected := range expectedMap {
-			if actual, exists := currentMap[name]; !exists {
-				result.Missing = append(result.Missing, name)
-			} else if actual.Color != expected.Color || actual.Description != expected.Description {
-				result.Modified = append(result.Modified, name)
+			currentMap := make(map[string]config.Label)
+			for _, l := range current {
+				currentMap[l.Name] = l
 			}
-		}
 
-		// Find extra (only if preserve_unknown is false)
-		if !viper.GetBool("settings.preserve_unknown") {
-			for name := range currentMap {
-				if _, exists := expectedMap[name]; !exists {
-					result.Extra = append(result.Extra, name)
+			result := AuditResult{Repo: repoName}
+
+			// Find missing and modified
+			for name, expected := range expectedMap {
+				if actual, exists := currentMap[name]; !exists {
+					result.Missing = append(result.Missing, name)
+				} else if actual.Color != expected.Color || actual.Description != expected.Description {
+					result.Modified = append(result.Modified, name)
 				}
 			}
+
+			result.Extra = extraLabels(expectedMap, currentMap, cfg.Settings.PreserveUnknown)
+
+			sort.Strings(result.Missing)
+			sort.Strings(result.Modified)
+			sort.Strings(result.Extra)
+
+			for _, name := range result.Missing {
+				result.MissingLabels = append(result.MissingLabels, expectedMap[name])
+			}
+			for _, name := range result.Modified {
+				result.ModifiedLabels = append(result.ModifiedLabels, ModifiedLabel{
+					Name:     name,
+					Expected: expectedMap[name],
+					Actual:   currentMap[name],
+				})
+			}
+
+			mu.Lock()
+			results = append(results, result)
+			mu.Unlock()
+		}(r)
+	}
+
+	wg.Wait()
+
+	// Repos finish in any order
+	sort.Slice(results, func(i, j int) bool { return results[i].Repo < results[j].Repo })
+
+	if auditFix {
+		if auditDeleteExtra && !dryRun && !confirmExtraDeletes(results) {
+			return fmt.Errorf("audit fix aborted")
 		}
+		for i := range results {
+			results[i].Fix = fixAuditResult(client, organization, results[i])
+		}
+	}
 
-		results = append(results, result)
+	w, closeOutput, err := openOutput()
+	if err != nil {
+		return err
 	}
+	defer closeOutput()
 
 	// Output results
 	switch format {
 	case "json":
 		output, _ := json.MarshalIndent(results, "", "  ")
-		fmt.Println(string(output))
+		fmt.Fprintln(w, string(output))
 	default:
-		printAuditTable(results)
+		printAuditTable(w, results)
 	}
 
 	return nil
 }
 
-func printAuditTable(results []AuditResult) {
+// extraLabels returns the live labels missing from the config, which
+// --delete-extra deletes. settings.preserve_unknown (on unless the config
+// turns it off) keeps them, so there are none.
+func extraLabels(expected, current map[string]config.Label, preserveUnknown bool) []string {
+	if preserveUnknown {
+		return nil
+	}
+	var extra []string
+	for name := range current {
+		if _, exists := expected[name]; !exists {
+			extra = append(extra, name)
+		}
+	}
+	return extra
+}
+
+// confirmExtraDeletes lists the extra labels --delete-extra would delete and
+// asks before deleting them; with none to delete there is nothing to ask.
+// The list goes to stderr so it stays out of a --format json report.
+func confirmExtraDeletes(results []AuditResult) bool {
+	total, repos := 0, 0
 	for _, r := range results {
-		fmt.Printf("\n%s:\n", r.Repo)
+		if len(r.Extra) == 0 {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "%s:\n", r.Repo)
+		for _, name := range r.Extra {
+			fmt.Fprintf(os.Stderr, "  - %s\n", name)
+		}
+		total += len(r.Extra)
+		repos++
+	}
+	if total == 0 {
+		return true
+	}
+	return confirm(fmt.Sprintf("Delete %d extra labels from %d repositories?", total, repos))
+}
+
+// fixAuditResult brings a repository's labels in line with the config,
+// continuing past labels that fail so one bad label doesn't block the rest
+func fixAuditResult(client *github.Client, organization string, r AuditResult) *AuditFix {
+	fix := &AuditFix{DryRun: dryRun}
+
+	modified := make([]config.Label, 0, len(r.ModifiedLabels))
+	for _, m := range r.ModifiedLabels {
+		modified = append(modified, m.Expected)
+	}
+
+	created, updated, err := client.ApplyLabelFixes(organization, r.Repo, r.MissingLabels, modified, dryRun)
+	fix.Created, fix.Updated = created, updated
+	if err != nil {
+		fix.Errors = append(fix.Errors, strings.Split(err.Error(), "\n")...)
+	}
+
+	if auditDeleteExtra {
+		for _, name := range r.Extra {
+			if !dryRun {
+				if err := client.DeleteLabel(organization, r.Repo, name, false); err != nil {
+					fix.Errors = append(fix.Errors, fmt.Sprintf("delete %s: %v", name, err))
+					continue
+				}
+			}
+			fix.Deleted = append(fix.Deleted, name)
+		}
+	}
+
+	if len(fix.Errors) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %d label fix(es) failed in %s\n", len(fix.Errors), r.Repo)
+	}
+	return fix
+}
+
+func printAuditTable(w io.Writer, results []AuditResult) {
+	for _, r := range results {
+		fmt.Fprintf(w, "\n%s:\n", r.Repo)
+		printAuditFix(w, r.Fix)
 
 		if len(r.Missing) == 0 && len(r.Extra) == 0 && len(r.Modified) == 0 {
-			fmt.Println("  ✓ All labels match config")
+			fmt.Fprintln(w, "  ✓ All labels match config")
 			continue
 		}
 
 		if len(r.Missing) > 0 {
-			fmt.Println("  Missing labels:")
+			fmt.Fprintln(w, "  Missing labels:")
 			for _, l := range r.Missing {
-				fmt.Printf("    - %s\n", l)
+				fmt.Fprintf(w, "    - %s\n", l)
 			}
 		}
 
 		if len(r.Modified) > 0 {
-			fmt.Println("  Modified labels (color/description differs):")
+			fmt.Fprintln(w, "  Modified labels (color/description differs):")
 			for _, l := range r.Modified {
-				fmt.Printf("    ~ %s\n", l)
+				fmt.Fprintf(w, "    ~ %s\n", l)
 			}
 		}
 
 		if len(r.Extra) > 0 {
-			fmt.Println("  Extra labels (not in config):")
+			fmt.Fprintln(w, "  Extra labels (not in config):")
 			for _, l := range r.Extra {
-				fmt.Printf("    + %s\n", l)
+				fmt.Fprintf(w, "    + %s\n", l)
 			}
 		}
 	}
 }
+
+// printAuditFix prints the one-line summary of what --fix changed
+func printAuditFix(w io.Writer, fix *AuditFix) {
+	if fix == nil || len(fix.Created)+len(fix.Updated)+len(fix.Deleted)+len(fix.Errors) == 0 {
+		return
+	}
+	verb := "Fixed"
+	if fix.DryRun {
+		verb = "Would fix"
+	}
+	fmt.Fprintf(w, "  %s: %d created, %d updated, %d deleted\n",
+		verb, len(fix.Created), len(fix.Updated), len(fix.Deleted))
+	for _, e := range fix.Errors {
+		fmt.Fprintf(w, "    ! %s\n", e)
+	}
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kiracore/kanban/internal/config"
+	"github.com/kiracore/kanban/internal/github"
+	"github.com/spf13/viper"
+)
+
+func TestFixAuditResult_PreserveUnknownByDefault(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	defer func(fix, del, dry bool) { auditFix, auditDeleteExtra, dryRun = fix, del, dry }(auditFix, auditDeleteExtra, dryRun)
+	auditFix, auditDeleteExtra, dryRun = true, true, false
+
+	// No settings.preserve_unknown in the config
+	path := filepath.Join(t.TempDir(), "kanban.yaml")
+	data := []byte(`organization: acme
+labels:
+  type:
+    - name: "type: bug"
+      color: d73a4a
+`)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	viper.SetConfigFile(path)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	expected := map[string]config.Label{"type: bug": {Name: "type: bug", Color: "d73a4a"}}
+	current := map[string]config.Label{
+		"type: bug":   {Name: "type: bug", Color: "d73a4a"},
+		"good first":  {Name: "good first", Color: "7057ff"},
+		"help wanted": {Name: "help wanted", Color: "008672"},
+	}
+
+	r := AuditResult{Repo: "app", Extra: extraLabels(expected, current, cfg.Settings.PreserveUnknown)}
+	if len(r.Extra) != 0 {
+		t.Fatalf("extra labels = %v, want none with preserve_unknown unset", r.Extra)
+	}
+
+	// Any gh call would fail: nothing on PATH
+	t.Setenv("PATH", t.TempDir())
+	fix := fixAuditResult(github.NewClient(context.Background(), github.Options{}), "acme", r)
+	if len(fix.Deleted) != 0 || len(fix.Errors) != 0 {
+		t.Errorf("fix = %+v, want nothing deleted", fix)
+	}
+
+	// Turned off explicitly, the unknown labels are extra
+	if extra := extraLabels(expected, current, false); len(extra) != 2 {
+		t.Errorf("extra labels = %v, want 2 with preserve_unknown false", extra)
+	}
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/kiracore/kanban/internal/db"
+)
+
+// AuthorArrivals is the number of issues an author opened in the period.
+// Issues without a recorded author are counted under db.UnknownAuthorBucket.
+type AuthorArrivals struct {
+	Author string `json:"author"`
+	Opened int    `json:"opened"`
+}
+
+// authorArrivals orders per-author counts most issues opened first, then by name
+func authorArrivals(byAuthor map[string]int) []AuthorArrivals {
+	arrivals := make([]AuthorArrivals, 0, len(byAuthor))
+	for author, opened := range byAuthor {
+		arrivals = append(arrivals, AuthorArrivals{Author: author, Opened: opened})
+	}
+	sort.Slice(arrivals, func(i, j int) bool {
+		if arrivals[i].Opened != arrivals[j].Opened {
+			return arrivals[i].Opened > arrivals[j].Opened
+		}
+		return arrivals[i].Author < arrivals[j].Author
+	})
+	return arrivals
+}
+
+// runAuthorArrivals reports the issues opened per author over the last days
+// days. Arrivals are counted across every cached repository, so the report
+// covers all synced organizations rather than one repo.
+func runAuthorArrivals() error {
+	database, err := db.OpenReadOnly(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w (run 'kanban sync' first)", err)
+	}
+	defer database.Close()
+	excludeIssues(database)
+
+	byAuthor, err := database.GetArrivalByAuthor(days)
+	if err != nil {
+		return fmt.Errorf("failed to get arrivals by author: %w", err)
+	}
+	arrivals := authorArrivals(byAuthor)
+
+	w, closeOutput, err := openOutput()
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		output, _ := json.MarshalIndent(arrivals, "", "  ")
+		fmt.Fprintln(w, string(output))
+		return closeOutput()
+	}
+
+	reset := "\033[0m"
+	bold := "\033[1m"
+	cyan := "\033[36m"
+	dim := "\033[90m"
+
+	fmt.Fprintf(w, "\n%s%s  ARRIVALS BY AUTHOR%s %s(last %d days, all cached repositories)%s\n\n", bold, cyan, reset, dim, days, reset)
+	if len(arrivals) == 0 {
+		fmt.Fprintf(w, "  No issues opened in the period\n\n")
+		return closeOutput()
+	}
+
+	total := 0
+	for _, a := range arrivals {
+		label := a.Author
+		if a.Author != db.UnknownAuthorBucket {
+			label = "@" + a.Author
+		}
+		fmt.Fprintf(w, "  %-20s %3d opened\n", truncate(label, 20), a.Opened)
+		total += a.Opened
+	}
+	fmt.Fprintf(w, "\n%d issue(s) opened by %d author(s)\n\n", total, len(arrivals))
+	return closeOutput()
+}
@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kiracore/kanban/internal/config"
+	"github.com/kiracore/kanban/internal/db"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	vsBaseline   string
+	saveBaseline string
+)
+
+var metricsBaselineCmd = &cobra.Command{
+	Use:   "baseline",
+	Short: "Save named metric baselines to compare against later",
+	Long: `Save the current metrics under a name, e.g. before a process change,
+and compare against it later with 'kanban metrics --baseline <name>'.
+'kanban metrics --save-baseline <name>' saves the metrics it reports the same way.
+
+Examples:
+  kanban metrics baseline save before-wip-limits --org myorg
+  kanban metrics --org myorg --all --save-baseline start-of-q3
+  kanban metrics --org myorg --all --baseline before-wip-limits`,
+}
+
+var metricsBaselineSaveCmd = &cobra.Command{
+	Use:   "save <name>",
+	Short: "Save current cached metrics as a named baseline",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runBaselineSave,
+}
+
+func init() {
+	metricsCmd.AddCommand(metricsBaselineCmd)
+	metricsBaselineCmd.AddCommand(metricsBaselineSaveCmd)
+	metricsBaselineSaveCmd.Flags().StringVarP(&repo, "repo", "r", "", "specific repository (default: all cached repositories)")
+	metricsBaselineSaveCmd.Flags().IntVar(&days, "days", 30, "time period in days")
+	metricsCmd.Flags().StringVar(&vsBaseline, "vs-baseline", "", "compare current metrics against a saved baseline")
+	metricsCmd.Flags().StringVar(&vsBaseline, "baseline", "", "same as --vs-baseline")
+	metricsCmd.Flags().StringVar(&saveBaseline, "save-baseline", "", "also save the reported metrics as a named baseline")
+}
+
+// MetricDelta is one metric's baseline and current value
+type MetricDelta struct {
+	Name     string  `json:"name"`
+	Unit     string  `json:"unit,omitempty"`
+	Baseline float64 `json:"baseline"`
+	Current  float64 `json:"current"`
+	Change   float64 `json:"change"`
+}
+
+// BaselineComparison compares a repo's current metrics against a saved baseline
+type BaselineComparison struct {
+	Repo     string        `json:"repo"`
+	Baseline string        `json:"baseline"`
+	SavedAt  time.Time     `json:"saved_at"`
+	Metrics  []MetricDelta `json:"metrics"`
+}
+
+// baselineMetric extracts a comparable value. better is -1 when lower is
+// better, 1 when higher is better and 0 when neither. windowed metrics are
+// computed from closed issues alone, so they can be measured for any past
+// period (see --compare).
+type baselineMetric struct {
+	name     string
+	unit     string
+	better   int
+	windowed bool
+	value    func(m KanbanMetrics) float64
+}
+
+var baselineMetrics = []baselineMetric{
+	{"Lead time (avg)", "d", -1, true, func(m KanbanMetrics) float64 { return m.LeadTime.Average }},
+	{"Lead time (P85)", "d", -1, true, func(m KanbanMetrics) float64 { return m.LeadTime.P85 }},
+	{"Cycle time (avg)", "d", -1, true, func(m KanbanMetrics) float64 { return m.CycleTime.Average }},
+	{"Cycle time (P85)", "d", -1, true, func(m KanbanMetrics) float64 { return m.CycleTime.P85 }},
+	{"Throughput", "/wk", 1, true, func(m KanbanMetrics) float64 { return m.Throughput.PerWeek }},
+	{"Flow efficiency", "%", 1, true, func(m KanbanMetrics) float64 { return m.FlowEfficiency }},
+	{"Flow load (WIP)", "", -1, false, func(m KanbanMetrics) float64 { return float64(m.FlowLoad) }},
+	{"WIP age (avg)", "d", -1, false, func(m KanbanMetrics) float64 { return m.WIPAge.Average }},
+	{"Arrival rate", "/day", 0, false, func(m KanbanMetrics) float64 { return m.ArrivalRate }},
+	{"Departure rate", "/day", 1, true, func(m KanbanMetrics) float64 { return m.DepartureRate }},
+}
+
+func runBaselineSave(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	organization := viper.GetString("organization")
+	if organization == "" && org != "" {
+		organization = org
+	}
+	if organization == "" {
+		return fmt.Errorf("organization required: use --org flag or set in config")
+	}
+
+	wipLimits := make(map[string]int)
+	activeStart := config.DefaultActiveStartStatus
+	if cfg, _ := config.Load(); cfg != nil {
+		wipLimits = cfg.Settings.WIPLimits
+		activeStart = cfg.Settings.ActiveStart()
+	}
+
+	allMetrics, err := collectMetricsCached(organization, days, wipLimits)
+	if err != nil {
+		return err
+	}
+	if len(allMetrics) == 0 {
+		return fmt.Errorf("no cached metrics to save (run 'kanban sync' first)")
+	}
+
+	for i := range allMetrics {
+		allMetrics[i].ActiveStartStatus = activeStart
+	}
+	if err := saveMetricsBaseline(name, allMetrics); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Saved baseline %q (%d repositories, %d-day period)\n", name, len(allMetrics), days)
+	return nil
+}
+
+// saveMetricsBaseline stores metrics under name, one entry per repo,
+// replacing any baseline already saved under it
+func saveMetricsBaseline(name string, metrics []KanbanMetrics) error {
+	now := time.Now().UTC()
+	var baselines []db.MetricBaseline
+	for _, m := range metrics {
+		data, err := json.Marshal(m)
+		if err != nil {
+			return fmt.Errorf("failed to encode metrics for %s: %w", m.Repo, err)
+		}
+		baselines = append(baselines, db.MetricBaseline{Name: name, Repo: m.Repo, Metrics: data, CreatedAt: now})
+	}
+
+	database, err := openBaselineDB()
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	if err := database.SaveBaselines(name, baselines); err != nil {
+		return fmt.Errorf("failed to save baseline: %w", err)
+	}
+	return nil
+}
+
+// openBaselineDB opens the database and makes sure the baselines table exists
+func openBaselineDB() (*db.DB, error) {
+	database, err := db.Open(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w (run 'kanban sync' first)", err)
+	}
+	if err := database.Init(); err != nil {
+		database.Close()
+		return nil, fmt.Errorf("failed to initialize database: %w", err)
+	}
+	return database, nil
+}
+
+// runBaselineComparison prints current metrics against a saved baseline
+func runBaselineComparison(name string, current []KanbanMetrics) error {
+	database, err := openBaselineDB()
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	saved, err := database.GetBaseline(name)
+	if err != nil {
+		return fmt.Errorf("failed to load baseline: %w", err)
+	}
+	if len(saved) == 0 {
+		names, _ := database.GetBaselineNames()
+		if len(names) == 0 {
+			return fmt.Errorf("baseline %q not found: none saved yet (save one with 'kanban metrics --save-baseline %s')", name, name)
+		}
+		return fmt.Errorf("baseline %q not found (saved: %s)", name, strings.Join(names, ", "))
+	}
+
+	baselineByRepo := make(map[string]db.MetricBaseline)
+	for _, b := range saved {
+		baselineByRepo[b.Repo] = b
+	}
+
+	var comparisons []BaselineComparison
+	for _, m := range current {
+		b, ok := baselineByRepo[m.Repo]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Warning: baseline %q has no data for %s\n", name, m.Repo)
+			continue
+		}
+		var before KanbanMetrics
+		if err := json.Unmarshal(b.Metrics, &before); err != nil {
+			return fmt.Errorf("failed to decode baseline for %s: %w", m.Repo, err)
+		}
+		comparisons = append(comparisons, compareToBaseline(name, b.CreatedAt, before, m))
+	}
+
+	w, closeOutput, err := openOutput()
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		output, _ := json.MarshalIndent(comparisons, "", "  ")
+		fmt.Fprintln(w, string(output))
+		return closeOutput()
+	}
+
+	for _, c := range comparisons {
+		printBaselineComparison(w, c)
+	}
+	return closeOutput()
+}
+
+func compareToBaseline(name string, savedAt time.Time, before, after KanbanMetrics) BaselineComparison {
+	c := BaselineComparison{Repo: after.Repo, Baseline: name, SavedAt: savedAt}
+	for _, bm := range baselineMetrics {
+		b, a := bm.value(before), bm.value(after)
+		c.Metrics = append(c.Metrics, MetricDelta{
+			Name:     bm.name,
+			Unit:     bm.unit,
+			Baseline: b,
+			Current:  a,
+			Change:   a - b,
+		})
+	}
+	return c
+}
+
+func printBaselineComparison(w io.Writer, c BaselineComparison) {
+	reset := "\033[0m"
+	bold := "\033[1m"
+	cyan := "\033[36m"
+	dim := "\033[90m"
+
+	fmt.Fprintf(w, "\n%s%s  %s vs baseline %q%s\n", bold, cyan, c.Repo, c.Baseline, reset)
+	fmt.Fprintf(w, "%sSaved %s%s\n\n", dim, c.SavedAt.Format("2006-01-02 15:04 UTC"), reset)
+	fmt.Fprintf(w, "  %-18s %10s %10s %10s\n", "", "BASELINE", "NOW", "CHANGE")
+
+	for i, d := range c.Metrics {
+		color := deltaColor(d.Change, baselineMetrics[i].better)
+		fmt.Fprintf(w, "  %-18s %10s %10s %s%+10.1f%s\n",
+			d.Name, formatMetricValue(d.Baseline, d.Unit), formatMetricValue(d.Current, d.Unit), color, d.Change, reset)
+	}
+	fmt.Fprintln(w)
+}
+
+// deltaColor is green for a change in the better direction, red for the worse
+// one and uncolored when there is no change or no better direction
+func deltaColor(change float64, better int) string {
+	switch {
+	case change == 0 || better == 0:
+		return ""
+	case (change < 0) == (better < 0):
+		return "\033[32m"
+	default:
+		return "\033[31m"
+	}
+}
+
+func formatMetricValue(v float64, unit string) string {
+	return fmt.Sprintf("%.1f%s", v, unit)
+}
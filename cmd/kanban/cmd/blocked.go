@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kiracore/kanban/internal/config"
+	"github.com/kiracore/kanban/internal/db"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var blockedAssignee string
+
+var blockedCmd = &cobra.Command{
+	Use:   "blocked",
+	Short: "List currently blocked issues",
+	Long: `List open issues that are blocked right now, longest blocked first.
+
+Blocked duration comes from the blocked periods recorded by
+'kanban sync --with-timeline'. Issues blocked longer than
+settings.blocked_threshold_hours (default 72) are flagged.
+
+Examples:
+  kanban blocked --org myorg --repo myrepo
+  kanban blocked --org myorg --all --assignee username
+  kanban blocked --org myorg --all --format json`,
+	RunE: runBlocked,
+}
+
+func init() {
+	rootCmd.AddCommand(blockedCmd)
+	blockedCmd.Flags().StringVarP(&repo, "repo", "r", "", "specific repository")
+	blockedCmd.Flags().BoolVar(&allRepos, "all", false, "blocked issues in all repositories")
+	blockedCmd.Flags().StringVarP(&blockedAssignee, "assignee", "a", "", "filter by assignee username")
+	blockedCmd.Flags().StringVarP(&format, "format", "f", "table", "output format (table|json)")
+}
+
+func runBlocked(cmd *cobra.Command, args []string) error {
+	organization := viper.GetString("organization")
+	if organization == "" && org != "" {
+		organization = org
+	}
+
+	if organization == "" {
+		return fmt.Errorf("organization required: use --org flag or set in config")
+	}
+	if repo == "" && !allRepos {
+		return fmt.Errorf("specify --repo or --all")
+	}
+
+	threshold := float64(config.DefaultBlockedThresholdHours)
+	if cfg, _ := config.Load(); cfg != nil {
+		threshold = cfg.Settings.BlockedThreshold()
+	}
+
+	database, err := db.Open(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w (run 'kanban sync' first)", err)
+	}
+	defer database.Close()
+
+	repoFilter := ""
+	if repo != "" {
+		repoFilter = fmt.Sprintf("%s/%s", organization, repo)
+	}
+
+	issues, err := database.GetBlockedIssues(repoFilter)
+	if err != nil {
+		return fmt.Errorf("failed to get blocked issues: %w", err)
+	}
+
+	if blockedAssignee != "" {
+		filtered := []db.BlockedIssue{}
+		for _, issue := range issues {
+			if strings.EqualFold(issue.Assignee, blockedAssignee) {
+				filtered = append(filtered, issue)
+			}
+		}
+		issues = filtered
+	}
+
+	w, closeOutput, err := openOutput()
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		if issues == nil {
+			issues = []db.BlockedIssue{}
+		}
+		output, _ := json.MarshalIndent(issues, "", "  ")
+		fmt.Fprintln(w, string(output))
+		return closeOutput()
+	}
+
+	printBlocked(w, issues, organization, threshold)
+	return closeOutput()
+}
+
+func printBlocked(w io.Writer, issues []db.BlockedIssue, organization string, threshold float64) {
+	reset := "\033[0m"
+	bold := "\033[1m"
+	red := "\033[31m"
+	yellow := "\033[33m"
+	dim := "\033[90m"
+
+	fmt.Fprintf(w, "\n%s  BLOCKED ISSUES (%d)%s\n\n", bold, len(issues), reset)
+
+	if len(issues) == 0 {
+		fmt.Fprintf(w, "%sNothing is blocked.%s\n\n", dim, reset)
+		return
+	}
+
+	overThreshold := 0
+	for _, issue := range issues {
+		duration, color := "unknown", dim
+		if issue.BlockedSince != nil {
+			duration, color = formatAge(issue.BlockedHours), yellow
+			if issue.BlockedHours > threshold {
+				color = red
+				overThreshold++
+			}
+		}
+
+		assignee := ""
+		if issue.Assignee != "" {
+			assignee = fmt.Sprintf(" \033[36m@%s%s", issue.Assignee, reset)
+		}
+
+		fmt.Fprintf(w, "  %s%-7s%s %s#%-4d %s[%s]%s %s%s\n",
+			color, duration, reset, strings.TrimPrefix(issue.Repo, organization+"/"), issue.Number,
+			dim, issue.Status, reset, truncate(displayTitle(issue.Title), 50), assignee)
+		if issue.Reason != "" {
+			fmt.Fprintf(w, "          %s↳ %s%s\n", dim, issue.Reason, reset)
+		}
+	}
+	fmt.Fprintln(w)
+
+	if overThreshold > 0 {
+		fmt.Fprintf(w, "%s⚠ %d issue(s) blocked longer than %.0fh (settings.blocked_threshold_hours)%s\n\n",
+			red, overThreshold, threshold, reset)
+	}
+}
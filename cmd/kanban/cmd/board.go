@@ -1,16 +1,18 @@
 package cmd
 
 import (
+	"encoding/csv"
 	"fmt"
+	"io"
+	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/kiracore/kanban/internal/config"
 	"github.com/kiracore/kanban/internal/db"
-	"github.com/kiracore/kanban/internal/github"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var (
@@ -19,6 +21,11 @@ var (
 	liveMode    bool
 	sortBy      string
 	filterAssignee string
+	filterTypes    string
+	filterPriority string
+	blockedOnly    bool
+	enforceWIP     bool
+	groupBy        string
 )
 
 var boardCmd = &cobra.Command{
@@ -45,34 +52,75 @@ Examples:
   # Filter by assignee
   kanban board --org myorg --repo myrepo --assignee username
 
+  # Show every issue in each column (default is 10 per column)
+  kanban board --org myorg --repo myrepo --limit 0
+
+  # Only bugs and features
+  kanban board --org myorg --repo myrepo --type bug,feature
+
+  # Triage: everything blocked, or only the fires
+  kanban board --org myorg --all --blocked-only
+  kanban board --org myorg --all --priority critical,high
+
   # View board directly from GitHub
-  kanban board --org myorg --repo myrepo --live`,
+  kanban board --org myorg --repo myrepo --live
+
+  # Stream one card per line for jq
+  kanban board --org myorg --all --format ndjson | jq -r .title
+
+  # Share the board as a spreadsheet
+  kanban board --org myorg --all --format csv --output board.csv
+
+  # Fail (exit 1) when a column exceeds settings.wip_limits, e.g. in CI
+  kanban board --org myorg --repo myrepo --enforce-wip
+
+  # One column per issue type (or priority) for triage
+  kanban board --org myorg --all --group-by type
+
+  # Write the board to a file (without colors) for a scheduled report
+  kanban board --org myorg --all --output board.txt`,
 	RunE: runBoard,
 }
 
 func init() {
 	rootCmd.AddCommand(boardCmd)
 	boardCmd.Flags().StringVarP(&repo, "repo", "r", "", "specific repository")
+	boardCmd.Flags().StringVar(&repoList, "repos", "", "comma-separated repositories (overrides --repo and the config list)")
 	boardCmd.Flags().BoolVar(&allRepos, "all", false, "show board for all repositories")
 	boardCmd.Flags().BoolVar(&showClosed, "closed", false, "include closed issues")
-	boardCmd.Flags().IntVarP(&maxIssues, "limit", "n", 10, "max issues per column")
+	boardCmd.Flags().IntVarP(&maxIssues, "limit", "n", 10, "max issues per column (0 = no limit)")
 	boardCmd.Flags().BoolVar(&liveMode, "live", false, "fetch directly from GitHub API")
 	boardCmd.Flags().StringVarP(&sortBy, "sort", "s", "priority", "sort by: priority, updated, age, assignee, created")
 	boardCmd.Flags().StringVarP(&filterAssignee, "assignee", "a", "", "filter by assignee username")
+	boardCmd.Flags().StringVar(&filterTypes, "type", "", "filter by issue type, comma-separated (e.g. bug,feature)")
+	boardCmd.Flags().StringVar(&filterPriority, "priority", "", "filter by priority, comma-separated (e.g. critical,high)")
+	boardCmd.Flags().BoolVar(&blockedOnly, "blocked-only", false, "only show blocked issues")
+	boardCmd.Flags().StringVarP(&format, "format", "f", "table", "output format (table|ndjson|csv)")
+	boardCmd.Flags().BoolVar(&enforceWIP, "enforce-wip", false, "exit non-zero when a column exceeds its WIP limit")
+	boardCmd.Flags().StringVar(&groupBy, "group-by", "status", "columns by: status, type, priority")
+	boardCmd.Flags().StringVar(&outputFile, "output", "", "write the board to this file instead of stdout")
+	boardCmd.Flags().BoolVar(&forceColor, "color", false, "keep ANSI colors when output isn't a terminal (pipes, --output)")
 }
 
 // DisplayIssue represents an issue for board display with repo info
 type DisplayIssue struct {
-	Number    int
-	Title     string
-	Repo      string
-	Priority  string
-	Type      string
-	Assignee  string
-	IsBlocked bool
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	AgeHours  float64
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	Repo      string    `json:"repo"`
+	Status    string    `json:"-"` // carried on BoardCard instead
+	Priority  string    `json:"priority,omitempty"`
+	Type      string    `json:"type,omitempty"`
+	Assignee  string    `json:"assignee,omitempty"`
+	IsBlocked bool      `json:"is_blocked"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	AgeHours  float64   `json:"age_hours"`
+}
+
+// BoardCard is a single board card for streaming output
+type BoardCard struct {
+	Status string `json:"status"`
+	DisplayIssue
 }
 
 // BoardColumn represents a kanban column
@@ -82,50 +130,207 @@ type BoardColumn struct {
 	Issues []DisplayIssue
 }
 
-func runBoard(cmd *cobra.Command, args []string) error {
-	organization := viper.GetString("organization")
-	if organization == "" && org != "" {
-		organization = org
+// statusColors are the column colors of the default workflow
+var statusColors = map[string]string{
+	"backlog":     "\033[90m", // Gray
+	"ready":       "\033[34m", // Blue
+	"in-progress": "\033[33m", // Yellow
+	"review":      "\033[31m", // Red/Orange
+	"testing":     "\033[35m", // Purple
+	"done":        "\033[32m", // Green
+}
+
+// priorityOrder ranks priority labels, most urgent first
+var priorityOrder = map[string]int{
+	"critical": 0,
+	"high":     1,
+	"medium":   2,
+	"low":      3,
+	"":         4,
+}
+
+// typeOrder ranks type labels for --group-by type, defects first
+var typeOrder = map[string]int{
+	"bug":         0,
+	"security":    1,
+	"feature":     2,
+	"improvement": 3,
+	"refactor":    4,
+	"docs":        5,
+	"test":        6,
+	"chore":       7,
+}
+
+// priorityColors are the column colors for --group-by priority
+var priorityColors = map[string]string{
+	"critical": "\033[91m", // Bright red
+	"high":     "\033[33m", // Yellow
+	"medium":   "\033[34m", // Blue
+	"low":      "\033[90m", // Gray
+}
+
+// statusColor returns the column color for status; custom statuses are cyan
+func statusColor(status string) string {
+	if color, ok := statusColors[status]; ok {
+		return color
+	}
+	return "\033[36m"
+}
+
+// sortByWorkflow sorts statuses in the order of workflow, unknown ones last by name
+func sortByWorkflow(statuses, workflow []string) {
+	rank := func(status string) int {
+		for i, s := range workflow {
+			if s == status {
+				return i
+			}
+		}
+		return len(workflow)
 	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if a, b := rank(statuses[i]), rank(statuses[j]); a != b {
+			return a < b
+		}
+		return statuses[i] < statuses[j]
+	})
+}
 
-	if organization == "" {
-		return fmt.Errorf("organization required: use --org flag or set in config")
+func runBoard(cmd *cobra.Command, args []string) error {
+	if err := validateGroupBy(); err != nil {
+		return err
 	}
 
-	// Define columns (status labels)
-	columns := []BoardColumn{
-		{Name: "backlog", Color: "\033[90m"},      // Gray
-		{Name: "ready", Color: "\033[34m"},        // Blue
-		{Name: "in-progress", Color: "\033[33m"},  // Yellow
-		{Name: "review", Color: "\033[31m"},       // Red/Orange
-		{Name: "testing", Color: "\033[35m"},      // Purple
-		{Name: "done", Color: "\033[32m"},         // Green
+	orgs, err := resolveOrganizations()
+	if err != nil {
+		return err
 	}
 
-	var repos []string
-	var err error
+	sw := newStopwatch()
+	defer sw.print()
 
-	if liveMode {
-		// Live mode: fetch directly from GitHub
-		columns, repos, err = runBoardLive(organization, columns)
-	} else {
-		// Cached mode: use database
-		columns, repos, err = runBoardCached(organization, columns)
+	columns, repos, wipViolations, err := loadBoard(orgs, sw)
+	if err != nil {
+		return err
 	}
 
+	w, closeOutput, err := openOutput()
 	if err != nil {
 		return err
 	}
+	defer closeOutput()
+
+	switch format {
+	case "ndjson":
+		var cards []BoardCard
+		for _, col := range columns {
+			for _, issue := range col.Issues {
+				cards = append(cards, BoardCard{Status: issue.Status, DisplayIssue: issue})
+			}
+		}
+		if err := printNDJSON(w, cards); err != nil {
+			return err
+		}
+		return reportWIPViolations(wipViolations, os.Stderr)
+	case "csv":
+		if err := writeBoardCSV(w, columns); err != nil {
+			return err
+		}
+		return reportWIPViolations(wipViolations, os.Stderr)
+	}
+
+	renderBoard(w, columns, repos, orgs)
+	return reportWIPViolations(wipViolations, w)
+}
+
+// writeBoardCSV writes one row per card shown on the board, column by
+// column. Live listings carry no update time, so age_hours is left empty.
+func writeBoardCSV(w io.Writer, columns []BoardColumn) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"repo", "status", "number", "title", "priority", "type", "assignee", "blocked", "age_hours"})
+
+	for _, col := range columns {
+		for _, issue := range col.Issues {
+			age := ""
+			if !liveMode {
+				age = strconv.FormatFloat(issue.AgeHours, 'f', 1, 64)
+			}
+			cw.Write([]string{issue.Repo, issue.Status, strconv.Itoa(issue.Number), issue.Title, issue.Priority,
+				issue.Type, issue.Assignee, strconv.FormatBool(issue.IsBlocked), age})
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// loadBoard fills one column per workflow status from the cache (or GitHub
+// with --live), regroups them for --group-by, then applies --assignee,
+// --priority, --blocked-only, --sort and --limit. WIP limits are checked with --enforce-wip before columns are
+// regrouped, filtered or shortened.
+func loadBoard(orgs []string, sw *stopwatch) (columns []BoardColumn, repos []string, wipViolations []string, err error) {
+	if maxIssues < 0 {
+		return nil, nil, nil, fmt.Errorf("--limit must be 0 (no limit) or more")
+	}
+	var settings config.Settings
+	if cfg, _ := config.Load(); cfg != nil {
+		settings = cfg.Settings
+	}
+	for _, status := range settings.Statuses() {
+		columns = append(columns, BoardColumn{Name: status, Color: statusColor(status)})
+	}
+
+	// Issues from every organization land on one board
+	for _, organization := range orgs {
+		var orgRepos []string
+		if liveMode {
+			// Live mode: fetch directly from GitHub
+			stop := sw.start("github fetch")
+			columns, orgRepos, err = runBoardLive(organization, columns)
+			stop()
+		} else {
+			// Cached mode: use database
+			stop := sw.start("db query")
+			columns, orgRepos, err = runBoardCached(organization, columns)
+			stop()
+		}
+
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		repos = append(repos, orgRepos...)
+	}
+
+	// Check limits before --type and other filters and --limit shrink the columns
+	if enforceWIP {
+		wipViolations = checkWIPLimits(columns, settings.WIPLimits)
+	}
+
+	if groupBy != "status" {
+		columns = regroupColumns(columns, groupBy)
+	}
+
+	types := parseTypes(filterTypes)
+	priorities := parsePriorities(filterPriority)
 
 	// Apply filtering and sorting to each column
 	for i := range columns {
-		// Filter by assignee if specified
-		if filterAssignee != "" {
+		// Filter by type, assignee, priority and blocked if specified
+		if len(types) > 0 || filterAssignee != "" || len(priorities) > 0 || blockedOnly {
 			filtered := []DisplayIssue{}
 			for _, issue := range columns[i].Issues {
-				if strings.EqualFold(issue.Assignee, filterAssignee) {
-					filtered = append(filtered, issue)
+				if !matchesType(types, issue.Type) {
+					continue
+				}
+				if filterAssignee != "" && !strings.EqualFold(issue.Assignee, filterAssignee) {
+					continue
+				}
+				if len(priorities) > 0 && !hasLabelInList(priorities, issue.Priority) {
+					continue
 				}
+				if blockedOnly && !issue.IsBlocked {
+					continue
+				}
+				filtered = append(filtered, issue)
 			}
 			columns[i].Issues = filtered
 		}
@@ -138,7 +343,74 @@ func runBoard(cmd *cobra.Command, args []string) error {
 			columns[i].Issues = columns[i].Issues[:maxIssues]
 		}
 	}
+	return columns, repos, wipViolations, nil
+}
 
+// validateGroupBy checks the --group-by value
+func validateGroupBy() error {
+	switch groupBy {
+	case "status", "type", "priority":
+		return nil
+	}
+	return fmt.Errorf("invalid --group-by %q: use status, type or priority", groupBy)
+}
+
+// regroupColumns rebuilds status columns as one column per distinct issue
+// type or priority, in the known label order. Unknown values follow by name
+// and issues without the label come last.
+func regroupColumns(columns []BoardColumn, field string) []BoardColumn {
+	value := func(issue DisplayIssue) string { return issue.Type }
+	order := typeOrder
+	if field == "priority" {
+		value = func(issue DisplayIssue) string { return issue.Priority }
+		order = priorityOrder
+	}
+
+	groups := make(map[string][]DisplayIssue)
+	for _, col := range columns {
+		for _, issue := range col.Issues {
+			v := strings.ToLower(value(issue))
+			groups[v] = append(groups[v], issue)
+		}
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	rank := func(name string) int {
+		if name == "" {
+			return len(order) + 1
+		}
+		if i, ok := order[name]; ok {
+			return i
+		}
+		return len(order)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if a, b := rank(names[i]), rank(names[j]); a != b {
+			return a < b
+		}
+		return names[i] < names[j]
+	})
+
+	regrouped := make([]BoardColumn, 0, len(names))
+	for _, name := range names {
+		col := BoardColumn{Name: name, Color: "\033[36m", Issues: groups[name]}
+		if color, ok := priorityColors[name]; ok && field == "priority" {
+			col.Color = color
+		}
+		if name == "" {
+			col.Name = "no " + field
+			col.Color = "\033[90m"
+		}
+		regrouped = append(regrouped, col)
+	}
+	return regrouped
+}
+
+// renderBoard prints the board as a table, one section per column
+func renderBoard(w io.Writer, columns []BoardColumn, repos []string, orgs []string) {
 	// Print board header
 	reset := "\033[0m"
 	bold := "\033[1m"
@@ -158,21 +430,41 @@ func runBoard(cmd *cobra.Command, args []string) error {
 	if filterAssignee != "" {
 		filterInfo = fmt.Sprintf(", @%s only", filterAssignee)
 	}
+	if filterTypes != "" {
+		filterInfo += fmt.Sprintf(", type: %s", strings.Join(parseTypes(filterTypes), ","))
+	}
+	if filterPriority != "" {
+		filterInfo += fmt.Sprintf(", priority: %s", strings.Join(parsePriorities(filterPriority), ","))
+	}
+	if blockedOnly {
+		filterInfo += ", blocked only"
+	}
+	if groupBy != "status" {
+		filterInfo += fmt.Sprintf(", by %s", groupBy)
+	}
 
 	if len(repos) == 1 {
-		fmt.Printf("\n%s%s/%s - Kanban Board%s %s(%s%s%s)%s\n", bold, organization, repos[0], reset, dim, source, sortInfo, filterInfo, reset)
+		boardName := repos[0]
+		if !multipleOrgs {
+			boardName = orgs[0] + "/" + repos[0]
+		}
+		fmt.Fprintf(w, "\n%s%s - Kanban Board%s %s(%s%s%s)%s\n", bold, boardName, reset, dim, source, sortInfo, filterInfo, reset)
 	} else {
-		fmt.Printf("\n%s%s - Kanban Board (%d repos)%s %s(%s%s%s)%s\n", bold, organization, len(repos), reset, dim, source, sortInfo, filterInfo, reset)
+		fmt.Fprintf(w, "\n%s%s - Kanban Board (%d repos)%s %s(%s%s%s)%s\n", bold, strings.Join(orgs, ", "), len(repos), reset, dim, source, sortInfo, filterInfo, reset)
+	}
+	fmt.Fprintln(w, strings.Repeat("─", 80))
+
+	if len(columns) == 0 {
+		fmt.Fprintf(w, "\n  %s(no issues)%s\n", dim, reset)
 	}
-	fmt.Println(strings.Repeat("─", 80))
 
 	// Print each column
 	for _, col := range columns {
 		count := len(col.Issues)
-		fmt.Printf("\n%s%s● %s%s (%d)\n", col.Color, bold, strings.ToUpper(col.Name), reset, count)
+		fmt.Fprintf(w, "\n%s%s● %s%s (%d)\n", col.Color, bold, strings.ToUpper(col.Name), reset, count)
 
 		if count == 0 {
-			fmt.Printf("  %s(empty)%s\n", "\033[90m", reset)
+			fmt.Fprintf(w, "  %s(empty)%s\n", "\033[90m", reset)
 			continue
 		}
 
@@ -208,13 +500,13 @@ func runBoard(cmd *cobra.Command, args []string) error {
 				agePart = fmt.Sprintf(" %s%s%s", dim, formatAge(issue.AgeHours), reset)
 			}
 
-			fmt.Printf("  %s#%-4d %s%s%s%s%s%s\n", repoPrefix, issue.Number, blockedBadge, priorityBadge, issue.Title, assigneePart, agePart, reset)
+			fmt.Fprintf(w, "  %s#%-4d %s%s%s%s%s%s\n", repoPrefix, issue.Number, blockedBadge, priorityBadge, issue.Title, assigneePart, agePart, reset)
 		}
 	}
 
 	// Print summary
-	fmt.Println()
-	fmt.Println(strings.Repeat("─", 80))
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, strings.Repeat("─", 80))
 
 	total := 0
 	summaryParts := []string{}
@@ -226,41 +518,99 @@ func runBoard(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	fmt.Printf("Total: %d issues  │  %s\n\n", total, strings.Join(summaryParts, "  "))
+	fmt.Fprintf(w, "Total: %d issues  │  %s\n\n", total, strings.Join(summaryParts, "  "))
+}
 
-	return nil
+// checkWIPLimits returns a message for each column over its WIP limit.
+// Limits apply per repository, as in metrics.
+func checkWIPLimits(columns []BoardColumn, limits map[string]int) []string {
+	var violations []string
+	for _, col := range columns {
+		limit, ok := config.WIPLimit(limits, col.Name)
+		if !ok {
+			continue
+		}
+
+		counts := make(map[string]int)
+		for _, issue := range col.Issues {
+			counts[issue.Repo]++
+		}
+		repoNames := make([]string, 0, len(counts))
+		for r := range counts {
+			repoNames = append(repoNames, r)
+		}
+		sort.Strings(repoNames)
+
+		for _, r := range repoNames {
+			if counts[r] > limit {
+				violations = append(violations, fmt.Sprintf("%s: %s has %d items (limit: %d)", r, col.Name, counts[r], limit))
+			}
+		}
+	}
+	return violations
+}
+
+// reportWIPViolations prints WIP limit violations and fails when there are any
+func reportWIPViolations(violations []string, w io.Writer) error {
+	if len(violations) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(w, "\033[31m⚠ WIP limits exceeded:\033[0m\n")
+	for _, v := range violations {
+		fmt.Fprintf(w, "  - %s\n", v)
+	}
+	fmt.Fprintln(w)
+	return fmt.Errorf("%d column(s) over WIP limit", len(violations))
 }
 
 // runBoardCached fetches board data from the local database
 func runBoardCached(organization string, columns []BoardColumn) ([]BoardColumn, []string, error) {
-	database, err := db.Open(dbPath)
+	database, err := db.OpenReadOnly(dbPath)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to open database: %w (run 'kanban sync' first or use --live)", err)
 	}
 	defer database.Close()
+	excludeIssues(database)
 
-	// Determine repo filter
+	// Determine repo filter; --repos picks several, so filter those below
+	selected := selectedRepos(organization)
 	repoFilter := ""
-	if repo != "" {
-		repoFilter = fmt.Sprintf("%s/%s", organization, repo)
+	if selected == nil {
+		repoName, ok := repoForOrg(organization)
+		if !ok {
+			return columns, nil, nil
+		}
+		if repoName != "" {
+			repoFilter = fmt.Sprintf("%s/%s", organization, repoName)
+		}
 	}
 
 	// Get issues from database for each status
 	repoSet := make(map[string]bool)
+	seen := make(map[string]bool)
 	for i := range columns {
 		issues, err := database.GetBoardIssues(repoFilter, columns[i].Name)
 		if err != nil {
 			continue
 		}
 		for _, issue := range issues {
+			seen[strings.ToLower(issue.Repo)] = true
 			if !showClosed && columns[i].Name == "done" {
 				// Skip done issues unless --closed is specified
 				continue
 			}
+			if !inOrg(organization, issue.Repo) {
+				continue
+			}
+			if selected != nil && !selected[strings.ToLower(issue.Repo)] {
+				continue
+			}
 			columns[i].Issues = append(columns[i].Issues, DisplayIssue{
 				Number:    issue.Number,
-				Title:     truncate(issue.Title, 40),
-				Repo:      strings.TrimPrefix(issue.Repo, organization+"/"),
+				Title:     truncate(displayTitle(issue.Title), 40),
+				Repo:      displayRepo(organization, issue.Repo),
+				Status:    columns[i].Name,
 				Priority:  issue.Priority,
 				Type:      issue.Type,
 				Assignee:  issue.Assignee,
@@ -273,12 +623,14 @@ func runBoardCached(organization string, columns []BoardColumn) ([]BoardColumn,
 		}
 	}
 
+	warnUncached(selected, seen)
+
 	var repos []string
-	if repo != "" {
-		repos = []string{repo}
+	if repoFilter != "" {
+		repos = []string{displayRepo(organization, repoFilter)}
 	} else {
 		for r := range repoSet {
-			repos = append(repos, strings.TrimPrefix(r, organization+"/"))
+			repos = append(repos, displayRepo(organization, r))
 		}
 	}
 
@@ -287,49 +639,79 @@ func runBoardCached(organization string, columns []BoardColumn) ([]BoardColumn,
 
 // runBoardLive fetches board data directly from GitHub API
 func runBoardLive(organization string, columns []BoardColumn) ([]BoardColumn, []string, error) {
-	client := github.NewClient()
+	client := newGitHubClient(timeoutCtx)
 
 	// Determine target repos
-	var repos []string
-	var err error
-	if repo != "" {
-		repos = []string{repo}
-	} else if allRepos {
-		repos, err = client.ListRepos(organization)
-		if err != nil {
-			return nil, nil, err
-		}
-		cfg, _ := config.Load()
-		if cfg != nil {
-			repos = cfg.FilterRepos(repos)
-		}
-	} else {
-		return nil, nil, fmt.Errorf("specify --repo or --all")
+	cfg, _ := config.Load()
+	repos, ok, err := resolveRepos(cfg, client, organization)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return columns, nil, nil
 	}
 
-	// Collect issues for each column
+	var settings config.Settings
+	if cfg != nil {
+		settings = cfg.Settings
+	}
+
+	// Collect issues for each column. WIP limits count the whole column, so
+	// --enforce-wip fetches past --limit.
+	limit := maxIssues
+	if enforceWIP {
+		limit = 0
+	}
+	delimiter := settings.Delimiter()
 	for i := range columns {
-		label := "status: " + columns[i].Name
+		labels := settings.StatusLabels(columns[i].Name)
 		for _, r := range repos {
-			issues, err := client.ListIssuesForBoard(organization, r, label, showClosed, maxIssues)
+			issues, err := client.ListIssuesForBoard(organization, r, labels, showClosed, limit)
 			if err != nil {
 				continue
 			}
 			for _, issue := range issues {
+				if settings.IsExcludedIssue(organization+"/"+r, issue.Number, issue.Labels) {
+					continue
+				}
+				issueType := extractLabel(issue.Labels, "type", delimiter)
 				columns[i].Issues = append(columns[i].Issues, DisplayIssue{
 					Number:    issue.Number,
-					Title:     truncate(issue.Title, 40),
-					Repo:      r,
-					Priority:  extractLabel(issue.Labels, "priority:"),
-					Type:      extractLabel(issue.Labels, "type:"),
+					Title:     truncate(displayTitle(issue.Title), 40),
+					Repo:      displayRepo(organization, organization+"/"+r),
+					Status:    columns[i].Name,
+					Priority:  extractLabel(issue.Labels, "priority", delimiter),
+					Type:      issueType,
 					Assignee:  issue.Assignee,
 					IsBlocked: hasLabelInList(issue.Labels, "blocked"),
+					CreatedAt: issue.CreatedAt,
 				})
 			}
 		}
 	}
 
-	return columns, repos, nil
+	names := make([]string, len(repos))
+	for i, r := range repos {
+		names[i] = displayRepo(organization, organization+"/"+r)
+	}
+	return columns, names, nil
+}
+
+// labelDelimiter returns settings.label_delimiter, defaulting to ": "
+func labelDelimiter() string {
+	var settings config.Settings
+	if cfg, _ := config.Load(); cfg != nil {
+		settings = cfg.Settings
+	}
+	return settings.Delimiter()
+}
+
+// excludeIssues keeps settings.exclude_labels and exclude_issues off the
+// database's board, WIP and metrics queries
+func excludeIssues(database *db.DB) {
+	if cfg, _ := config.Load(); cfg != nil {
+		database.SetExclusions(db.Exclusions{Labels: cfg.Settings.ExcludeLabels, Issues: cfg.Settings.ExcludeIssues})
+	}
 }
 
 func hasLabelInList(labels []string, target string) bool {
@@ -348,20 +730,60 @@ func truncate(s string, maxLen int) string {
 	return s[:maxLen-3] + "..."
 }
 
-func extractLabel(labels []string, prefix string) string {
+// displayTitle returns a placeholder for empty titles so cards are never blank
+func displayTitle(title string) string {
+	if strings.TrimSpace(title) == "" {
+		return "(no title)"
+	}
+	return title
+}
+
+// extractLabel returns the value of the first category label, e.g. "bug"
+// for "type: bug", written with delimiter
+func extractLabel(labels []string, category, delimiter string) string {
 	for _, l := range labels {
-		if strings.HasPrefix(l, prefix) {
-			return strings.TrimPrefix(l, prefix)
-		}
-		// Also check with space after colon
-		prefixWithSpace := strings.TrimSuffix(prefix, ":") + ": "
-		if strings.HasPrefix(l, prefixWithSpace) {
-			return strings.TrimPrefix(l, prefixWithSpace)
+		if value, ok := config.LabelValue(l, category, delimiter); ok {
+			return value
 		}
 	}
 	return ""
 }
 
+// parseTypes splits a comma-separated --type value into lowercase types
+func parseTypes(s string) []string {
+	var types []string
+	for _, t := range strings.Split(s, ",") {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// parsePriorities splits a comma-separated --priority value into priorities;
+// each may be written as its label, e.g. "priority: high"
+func parsePriorities(s string) []string {
+	if s == "" {
+		return nil
+	}
+	delimiter := labelDelimiter()
+	var priorities []string
+	for _, p := range strings.Split(s, ",") {
+		if p = trimLabelPrefix(p, "priority", delimiter); p != "" {
+			priorities = append(priorities, p)
+		}
+	}
+	return priorities
+}
+
+// matchesType reports whether issueType is one of types; no types matches all
+func matchesType(types []string, issueType string) bool {
+	if len(types) == 0 {
+		return true
+	}
+	return hasLabelInList(types, strings.TrimSpace(issueType))
+}
+
 // sortIssues sorts issues based on the specified sort method
 func sortIssues(issues []DisplayIssue, sortMethod string) {
 	switch sortMethod {
@@ -395,13 +817,6 @@ func sortIssues(issues []DisplayIssue, sortMethod string) {
 		fallthrough
 	default:
 		// Priority order: critical > high > medium > low > none
-		priorityOrder := map[string]int{
-			"critical": 0,
-			"high":     1,
-			"medium":   2,
-			"low":      3,
-			"":         4,
-		}
 		sort.Slice(issues, func(i, j int) bool {
 			pi := priorityOrder[issues[i].Priority]
 			pj := priorityOrder[issues[j].Priority]
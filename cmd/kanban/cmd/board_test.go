@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kiracore/kanban/internal/db"
+	"github.com/spf13/viper"
+)
+
+func TestCheckWIPLimits(t *testing.T) {
+	issues := func(repo string, n int) []DisplayIssue {
+		list := make([]DisplayIssue, n)
+		for i := range list {
+			list[i] = DisplayIssue{Number: i + 1, Repo: repo}
+		}
+		return list
+	}
+	limits := map[string]int{"status: ready": 3, "in-progress": 2, "review": 1}
+
+	tests := []struct {
+		name    string
+		columns []BoardColumn
+		want    []string
+	}{
+		{"under the limit", []BoardColumn{{Name: "ready", Issues: issues("app", 2)}}, nil},
+		{"at the limit", []BoardColumn{{Name: "in-progress", Issues: issues("app", 2)}}, nil},
+		{"over the limit", []BoardColumn{{Name: "in-progress", Issues: issues("app", 3)}},
+			[]string{"app: in-progress has 3 items (limit: 2)"}},
+		{"no limit", []BoardColumn{{Name: "backlog", Issues: issues("app", 50)}}, nil},
+		{"per repository", []BoardColumn{{Name: "review", Issues: append(issues("app", 1), issues("web", 2)...)}},
+			[]string{"web: review has 2 items (limit: 1)"}},
+	}
+	for _, tt := range tests {
+		if got := checkWIPLimits(tt.columns, limits); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: checkWIPLimits() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLoadBoard_WIPCountsWholeColumn(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("settings.wip_limits", map[string]int{"in-progress": 2})
+
+	path := filepath.Join(t.TempDir(), "kanban.db")
+	database, err := db.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := database.Init(); err != nil {
+		t.Fatal(err)
+	}
+	org, _ := database.GetOrCreateOrg("acme")
+	repo, _ := database.GetOrCreateRepo(org.ID, "app", "acme/app")
+	now := time.Now()
+	for i, issueType := range []string{"bug", "feature", "feature", "feature"} {
+		database.UpsertIssue(&db.Issue{RepoID: repo.ID, Number: i + 1, Title: "Work", State: "open",
+			CurrentStatus: "in-progress", CurrentType: issueType, GHCreatedAt: now, GHUpdatedAt: now})
+	}
+	database.Close()
+
+	defer func(path string, limit int, types, group string, enforce, live bool) {
+		dbPath, maxIssues, filterTypes, groupBy, enforceWIP, liveMode = path, limit, types, group, enforce, live
+	}(dbPath, maxIssues, filterTypes, groupBy, enforceWIP, liveMode)
+	dbPath, maxIssues, filterTypes, groupBy, enforceWIP, liveMode = path, 1, "bug", "status", true, false
+
+	columns, _, violations, err := loadBoard([]string{"acme"}, newStopwatch())
+	if err != nil {
+		t.Fatalf("loadBoard() error: %v", err)
+	}
+	want := []string{"app: in-progress has 4 items (limit: 2)"}
+	if !reflect.DeepEqual(violations, want) {
+		t.Errorf("violations = %v, want %v", violations, want)
+	}
+
+	// --type and --limit still shape what is shown
+	for _, col := range columns {
+		if col.Name != "in-progress" {
+			continue
+		}
+		if len(col.Issues) != 1 || col.Issues[0].Type != "bug" {
+			t.Errorf("in-progress shows %+v, want the one bug", col.Issues)
+		}
+	}
+}
+
+func TestRegroupColumns(t *testing.T) {
+	columns := []BoardColumn{
+		{Name: "ready", Issues: []DisplayIssue{{Number: 1, Type: "feature", Priority: "low"}, {Number: 3}}},
+		{Name: "review", Issues: []DisplayIssue{{Number: 2, Type: "bug", Priority: "critical"}, {Number: 4, Type: "spike", Priority: "high"}}},
+		{Name: "done", Issues: []DisplayIssue{{Number: 5, Type: "Bug", Priority: "urgent"}}},
+	}
+
+	tests := []struct {
+		field string
+		want  map[string][]int // column name -> issue numbers
+		order []string
+	}{
+		{"type",
+			map[string][]int{"bug": {2, 5}, "feature": {1}, "spike": {4}, "no type": {3}},
+			[]string{"bug", "feature", "spike", "no type"}},
+		{"priority",
+			map[string][]int{"critical": {2}, "high": {4}, "low": {1}, "urgent": {5}, "no priority": {3}},
+			[]string{"critical", "high", "low", "urgent", "no priority"}},
+	}
+	for _, tt := range tests {
+		got := regroupColumns(columns, tt.field)
+		var names []string
+		for _, col := range got {
+			names = append(names, col.Name)
+			var numbers []int
+			for _, issue := range col.Issues {
+				numbers = append(numbers, issue.Number)
+			}
+			if !reflect.DeepEqual(numbers, tt.want[col.Name]) {
+				t.Errorf("%s: column %q has %v, want %v", tt.field, col.Name, numbers, tt.want[col.Name])
+			}
+		}
+		if !reflect.DeepEqual(names, tt.order) {
+			t.Errorf("%s: columns = %v, want %v", tt.field, names, tt.order)
+		}
+	}
+}
+
+func TestWriteBoardCSV(t *testing.T) {
+	defer func(live bool) { liveMode = live }(liveMode)
+
+	columns := []BoardColumn{
+		{Name: "ready", Issues: []DisplayIssue{{Number: 7, Title: "Fix login, again", Repo: "app", Status: "ready",
+			Priority: "high", Type: "bug", Assignee: "alice", AgeHours: 26.5}}},
+		{Name: "review"},
+		{Name: "done", Issues: []DisplayIssue{{Number: 9, Title: "Docs", Repo: "web", Status: "done", IsBlocked: true, AgeHours: 3}}},
+	}
+	header := "repo,status,number,title,priority,type,assignee,blocked,age_hours\n"
+
+	tests := []struct {
+		name string
+		live bool
+		want string
+	}{
+		{"cached", false, header +
+			"app,ready,7,\"Fix login, again\",high,bug,alice,false,26.5\n" +
+			"web,done,9,Docs,,,,true,3.0\n"},
+		{"live has no age", true, header +
+			"app,ready,7,\"Fix login, again\",high,bug,alice,false,\n" +
+			"web,done,9,Docs,,,,true,\n"},
+	}
+	for _, tt := range tests {
+		liveMode = tt.live
+		var buf bytes.Buffer
+		if err := writeBoardCSV(&buf, columns); err != nil {
+			t.Fatalf("%s: writeBoardCSV() error: %v", tt.name, err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("%s: writeBoardCSV() =\n%s\nwant\n%s", tt.name, buf.String(), tt.want)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kiracore/kanban/internal/db"
+)
+
+// BurndownPoint is the remaining work at the end of a day
+type BurndownPoint struct {
+	Date      string `json:"date"`
+	Remaining int    `json:"remaining"`
+	Done      int    `json:"done"`
+}
+
+// Burndown is a milestone's remaining-work-over-time series
+type Burndown struct {
+	Milestone string          `json:"milestone"`
+	Repo      string          `json:"repo,omitempty"`
+	Total     int             `json:"total"`
+	Points    []BurndownPoint `json:"points"`
+}
+
+// runMilestoneBurndown renders the burndown for --milestone from cached data
+func runMilestoneBurndown(organization string) error {
+	if liveMode {
+		return fmt.Errorf("--burndown uses cached data; run 'kanban sync' instead of --live")
+	}
+
+	database, err := db.OpenReadOnly(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w (run 'kanban sync' first)", err)
+	}
+	defer database.Close()
+
+	repoFilter := ""
+	if repo != "" {
+		repoFilter = fmt.Sprintf("%s/%s", organization, repo)
+	}
+
+	issues, err := database.GetMilestoneIssues(repoFilter, metricsMilestone)
+	if err != nil {
+		return fmt.Errorf("failed to get milestone issues: %w", err)
+	}
+	if len(issues) == 0 {
+		return fmt.Errorf("no cached issues in milestone %q (run 'kanban sync' to fetch milestones)", metricsMilestone)
+	}
+
+	// Start at the first issue or the --days window, whichever is later
+	today := time.Now().Truncate(24 * time.Hour)
+	start := today.AddDate(0, 0, -days)
+	if first := issues[0].CreatedAt.Truncate(24 * time.Hour); first.After(start) {
+		start = first
+	}
+
+	b := Burndown{
+		Milestone: metricsMilestone,
+		Repo:      repo,
+		Total:     len(issues),
+		Points:    calculateBurndown(issues, start, today),
+	}
+
+	w, closeOutput, err := openOutput()
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		output, _ := json.MarshalIndent(b, "", "  ")
+		fmt.Fprintln(w, string(output))
+		return closeOutput()
+	}
+
+	printBurndown(w, b)
+	return closeOutput()
+}
+
+// calculateBurndown counts issues not yet done at the end of each day from start to end
+func calculateBurndown(issues []db.MilestoneIssue, start, end time.Time) []BurndownPoint {
+	var points []BurndownPoint
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		dayEnd := day.AddDate(0, 0, 1)
+		p := BurndownPoint{Date: day.Format("2006-01-02")}
+		for _, issue := range issues {
+			if issue.CreatedAt.After(dayEnd) {
+				continue
+			}
+			if issue.DoneAt != nil && issue.DoneAt.Before(dayEnd) {
+				p.Done++
+			} else {
+				p.Remaining++
+			}
+		}
+		points = append(points, p)
+	}
+	return points
+}
+
+func printBurndown(w io.Writer, b Burndown) {
+	reset := "\033[0m"
+	bold := "\033[1m"
+	cyan := "\033[36m"
+	green := "\033[32m"
+	dim := "\033[90m"
+
+	title := b.Milestone
+	if b.Repo != "" {
+		title = fmt.Sprintf("%s (%s)", b.Milestone, b.Repo)
+	}
+	fmt.Fprintf(w, "\n%s%s  BURNDOWN: %s%s\n", bold, cyan, title, reset)
+	fmt.Fprintf(w, "%s%d issues in milestone%s\n\n", dim, b.Total, reset)
+
+	maxRemaining := 0
+	for _, p := range b.Points {
+		if p.Remaining > maxRemaining {
+			maxRemaining = p.Remaining
+		}
+	}
+
+	const width = 40
+	for _, p := range b.Points {
+		bar := 0
+		if maxRemaining > 0 {
+			bar = p.Remaining * width / maxRemaining
+		}
+		fmt.Fprintf(w, "%s │ %s%s%s %3d remaining %s(%d done)%s\n",
+			p.Date, green, strings.Repeat("█", bar)+strings.Repeat(" ", width-bar), reset, p.Remaining, dim, p.Done, reset)
+	}
+	fmt.Fprintln(w)
+}
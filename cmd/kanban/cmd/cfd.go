@@ -11,7 +11,6 @@ import (
 
 	"github.com/kiracore/kanban/internal/config"
 	"github.com/kiracore/kanban/internal/db"
-	"github.com/kiracore/kanban/internal/github"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -32,18 +31,34 @@ var cfdSnapshotCmd = &cobra.Command{
 var cfdShowCmd = &cobra.Command{
 	Use:   "show",
 	Short: "Display CFD data",
-	Long:  `Show cumulative flow data as ASCII chart.`,
-	RunE:  runCFDShow,
+	Long: `Show cumulative flow data as ASCII chart.
+
+With --all (or --repo all), snapshots of every repository in the organization
+are summed per date and status into one org-wide chart. Take snapshots with
+'kanban cfd snapshot --all' so each date covers every repo.
+
+Examples:
+  kanban cfd show --org myorg --repo myrepo
+  kanban cfd show --org myorg --all --days 90`,
+	RunE: runCFDShow,
 }
 
 var cfdExportCmd = &cobra.Command{
 	Use:   "export",
 	Short: "Export CFD data",
-	Long:  `Export CFD data to CSV or JSON.`,
-	RunE:  runCFDExport,
+	Long: `Export CFD data to CSV or JSON, or as a self-contained HTML page
+with a stacked-area chart for sharing.
+
+Examples:
+  kanban cfd export --org myorg --repo myrepo > cfd.csv
+  kanban cfd export --org myorg --repo myrepo --days 90 --format html > cfd.html`,
+	RunE: runCFDExport,
 }
 
-var cfdDays int
+var (
+	cfdDays         int
+	cfdExportFormat string
+)
 
 func init() {
 	rootCmd.AddCommand(cfdCmd)
@@ -54,12 +69,13 @@ func init() {
 	cfdSnapshotCmd.Flags().StringVarP(&repo, "repo", "r", "", "repository")
 	cfdSnapshotCmd.Flags().BoolVar(&allRepos, "all", false, "all repositories")
 
-	cfdShowCmd.Flags().StringVarP(&repo, "repo", "r", "", "repository")
+	cfdShowCmd.Flags().StringVarP(&repo, "repo", "r", "", "repository (\"all\" for every repository)")
+	cfdShowCmd.Flags().BoolVar(&allRepos, "all", false, "sum all repositories into one chart")
 	cfdShowCmd.Flags().IntVar(&cfdDays, "days", 30, "days of history")
 
 	cfdExportCmd.Flags().StringVarP(&repo, "repo", "r", "", "repository")
 	cfdExportCmd.Flags().IntVar(&cfdDays, "days", 30, "days of history")
-	cfdExportCmd.Flags().StringVar(&format, "format", "csv", "output format (csv, json)")
+	cfdExportCmd.Flags().StringVar(&cfdExportFormat, "format", "csv", "output format (csv, json, html)")
 }
 
 func runCFDSnapshot(cmd *cobra.Command, args []string) error {
@@ -78,20 +94,11 @@ func runCFDSnapshot(cmd *cobra.Command, args []string) error {
 	defer database.Close()
 
 	cfg, _ := config.Load()
-	client := github.NewClient()
-
-	var repos []string
-	if repo != "" {
-		repos = []string{repo}
-	} else if cfg != nil && cfg.HasExplicitRepos() {
-		repos = cfg.GetRepos()
-	} else if allRepos {
-		repos, err = client.ListRepos(organization)
-		if err != nil {
-			return err
-		}
-	} else {
-		return fmt.Errorf("specify --repo, --all, or define repositories.list in config")
+	client := newGitHubClient(timeoutCtx)
+
+	repos, _, err := resolveRepos(cfg, client, organization)
+	if err != nil {
+		return err
 	}
 
 	today := time.Now().Truncate(24 * time.Hour)
@@ -140,8 +147,9 @@ func runCFDShow(cmd *cobra.Command, args []string) error {
 	if organization == "" {
 		return fmt.Errorf("organization required")
 	}
-	if repo == "" {
-		return fmt.Errorf("--repo required")
+	aggregate := allRepos || repo == "all"
+	if repo == "" && !aggregate {
+		return fmt.Errorf("--repo or --all required")
 	}
 
 	database, err := db.Open(dbPath)
@@ -150,17 +158,28 @@ func runCFDShow(cmd *cobra.Command, args []string) error {
 	}
 	defer database.Close()
 
-	fullName := fmt.Sprintf("%s/%s", organization, repo)
 	dbOrg, err := database.GetOrCreateOrg(organization)
 	if err != nil {
 		return err
 	}
-	dbRepo, err := database.GetOrCreateRepo(dbOrg.ID, repo, fullName)
-	if err != nil {
-		return err
-	}
 
-	data, err := database.GetCFDData(dbRepo.ID, cfdDays)
+	var title string
+	var data []struct {
+		Date   string
+		Status string
+		Count  int
+	}
+	if aggregate {
+		title = organization + " (all repositories)"
+		data, err = database.GetAggregateCFDData(dbOrg.ID, cfdDays)
+	} else {
+		title = fmt.Sprintf("%s/%s", organization, repo)
+		dbRepo, repoErr := database.GetOrCreateRepo(dbOrg.ID, repo, title)
+		if repoErr != nil {
+			return repoErr
+		}
+		data, err = database.GetCFDData(dbRepo.ID, cfdDays)
+	}
 	if err != nil {
 		return err
 	}
@@ -170,33 +189,14 @@ func runCFDShow(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Group by date
-	byDate := make(map[string]map[string]int)
-	statuses := make(map[string]bool)
-	var dates []string
-
-	for _, d := range data {
-		if byDate[d.Date] == nil {
-			byDate[d.Date] = make(map[string]int)
-			dates = append(dates, d.Date)
-		}
-		byDate[d.Date][d.Status] = d.Count
-		statuses[d.Status] = true
-	}
-
-	sort.Strings(dates)
-
-	// Get ordered status list
-	statusOrder := []string{"backlog", "ready", "in-progress", "review", "testing", "done", "none"}
-	var orderedStatuses []string
-	for _, s := range statusOrder {
-		if statuses[s] {
-			orderedStatuses = append(orderedStatuses, s)
-		}
+	var settings config.Settings
+	if cfg, _ := config.Load(); cfg != nil {
+		settings = cfg.Settings
 	}
+	dates, byDate, orderedStatuses := groupCFDData(data, settings.Statuses())
 
 	// Print header
-	fmt.Printf("\n%s - Cumulative Flow (%d days)\n", fullName, cfdDays)
+	fmt.Printf("\n%s - Cumulative Flow (%d days)\n", title, cfdDays)
 	fmt.Println(strings.Repeat("─", 60))
 
 	// Simple ASCII chart
@@ -248,6 +248,40 @@ func runCFDShow(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// groupCFDData groups snapshot rows by date and returns the sorted dates
+// and the statuses present, in workflow order
+func groupCFDData(data []struct {
+	Date   string
+	Status string
+	Count  int
+}, workflow []string) ([]string, map[string]map[string]int, []string) {
+	byDate := make(map[string]map[string]int)
+	statuses := make(map[string]bool)
+	var dates []string
+
+	for _, d := range data {
+		if byDate[d.Date] == nil {
+			byDate[d.Date] = make(map[string]int)
+			dates = append(dates, d.Date)
+		}
+		byDate[d.Date][d.Status] = d.Count
+		statuses[d.Status] = true
+	}
+
+	sort.Strings(dates)
+
+	// Get ordered status list
+	statusOrder := append(append([]string{}, workflow...), "none")
+	var orderedStatuses []string
+	for _, s := range statusOrder {
+		if statuses[s] {
+			orderedStatuses = append(orderedStatuses, s)
+		}
+	}
+
+	return dates, byDate, orderedStatuses
+}
+
 func getStatusChar(status string) string {
 	switch status {
 	case "backlog":
@@ -300,18 +334,31 @@ func runCFDExport(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	if format == "json" {
+	switch cfdExportFormat {
+	case "json":
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
 		return enc.Encode(data)
+	case "html":
+		if len(data) == 0 {
+			return fmt.Errorf("no CFD data for %s (run 'kanban cfd snapshot' first)", fullName)
+		}
+		var settings config.Settings
+		if cfg, _ := config.Load(); cfg != nil {
+			settings = cfg.Settings
+		}
+		dates, byDate, statuses := groupCFDData(data, settings.Statuses())
+		title := fmt.Sprintf("%s - Cumulative Flow (%d days)", fullName, cfdDays)
+		return writeCFDHTML(os.Stdout, title, dates, byDate, statuses)
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"date", "status", "count"})
+		for _, d := range data {
+			w.Write([]string{d.Date, d.Status, fmt.Sprintf("%d", d.Count)})
+		}
+		w.Flush()
+		return w.Error()
+	default:
+		return fmt.Errorf("unsupported format: %s (use csv, json or html)", cfdExportFormat)
 	}
-
-	// CSV output
-	w := csv.NewWriter(os.Stdout)
-	w.Write([]string{"date", "status", "count"})
-	for _, d := range data {
-		w.Write([]string{d.Date, d.Status, fmt.Sprintf("%d", d.Count)})
-	}
-	w.Flush()
-	return nil
 }
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kiracore/kanban/internal/db"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// sparseHistoryRatio is the share of issue-days without a recorded status
+// above which backfill warns that the history is too thin to trust
+const sparseHistoryRatio = 0.2
+
+var cfdBackfillCmd = &cobra.Command{
+	Use:   "backfill",
+	Short: "Rebuild past CFD snapshots from status history",
+	Long: `Reconstruct daily CFD snapshots for days before snapshots were taken.
+
+Each issue's status at the end of every past day is replayed from its cached
+status changes (status_transitions, plus the status entry times a
+'kanban sync --with-timeline' records), counting open issues only, as
+'kanban cfd snapshot' does. Every issue lands in exactly one status per day;
+days with no recorded status count as "none". Days that already have a
+snapshot are left alone, and today is left to 'kanban cfd snapshot'.
+
+Examples:
+  kanban cfd backfill --org myorg --repo myrepo
+  kanban cfd backfill --org myorg --repo myrepo --days 180`,
+	RunE: runCFDBackfill,
+}
+
+var cfdBackfillDays int
+
+func init() {
+	cfdCmd.AddCommand(cfdBackfillCmd)
+	cfdBackfillCmd.Flags().StringVarP(&repo, "repo", "r", "", "repository")
+	cfdBackfillCmd.Flags().IntVar(&cfdBackfillDays, "days", 90, "days of history to rebuild")
+}
+
+func runCFDBackfill(cmd *cobra.Command, args []string) error {
+	organization := viper.GetString("organization")
+	if organization == "" && org != "" {
+		organization = org
+	}
+	if organization == "" {
+		return fmt.Errorf("organization required")
+	}
+	if repo == "" {
+		return fmt.Errorf("--repo required")
+	}
+	if cfdBackfillDays < 1 {
+		return fmt.Errorf("--days must be at least 1")
+	}
+
+	database, err := db.Open(dbPath)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	fullName := fmt.Sprintf("%s/%s", organization, repo)
+	repoID, err := database.GetRepoID(fullName)
+	if err != nil {
+		return fmt.Errorf("%s is not cached; run 'kanban sync' first", fullName)
+	}
+
+	history, err := database.GetIssueStatusHistory(repoID)
+	if err != nil {
+		return err
+	}
+	existing, err := database.GetCFDSnapshotDates(repoID)
+	if err != nil {
+		return err
+	}
+
+	today := time.Now().Truncate(24 * time.Hour)
+	saved, skipped := 0, 0
+	issueDays, unknownDays := 0, 0
+	for d := cfdBackfillDays; d >= 1; d-- {
+		day := today.AddDate(0, 0, -d)
+		if existing[day.Format("2006-01-02")] {
+			skipped++
+			continue
+		}
+
+		counts, unknown := statusCountsAt(history, day.Add(24*time.Hour))
+		for _, c := range counts {
+			issueDays += c
+		}
+		unknownDays += unknown
+		if len(counts) == 0 {
+			continue
+		}
+
+		if err := database.SaveCFDSnapshot(repoID, day, counts); err != nil {
+			return fmt.Errorf("failed to save snapshot for %s: %w", day.Format("2006-01-02"), err)
+		}
+		saved++
+	}
+
+	fmt.Printf("%s: %d day(s) backfilled, %d already had a snapshot\n", fullName, saved, skipped)
+	if issueDays > 0 && float64(unknownDays)/float64(issueDays) > sparseHistoryRatio {
+		fmt.Fprintf(os.Stderr, "Warning: %d of %d issue-days had no recorded status and count as \"none\"; "+
+			"status history is sparse (run 'kanban sync --with-timeline' for fuller history)\n", unknownDays, issueDays)
+	}
+	return nil
+}
+
+// statusCountsAt counts the issues open at t per status, each issue in
+// exactly one status. It also returns how many had no recorded status.
+func statusCountsAt(history []db.IssueStatusHistory, t time.Time) (map[string]int, int) {
+	counts := make(map[string]int)
+	unknown := 0
+	for _, h := range history {
+		if !h.OpenAt(t) {
+			continue
+		}
+		status, known := h.StatusAt(t)
+		if !known {
+			unknown++
+		}
+		counts[status]++
+	}
+	return counts, unknown
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// cfdStatusColors match the default status label colors
+var cfdStatusColors = map[string]string{
+	"backlog":     "#d4d4d4",
+	"ready":       "#0075ca",
+	"in-progress": "#fbca04",
+	"review":      "#d93f0b",
+	"testing":     "#8250df",
+	"done":        "#0e8a16",
+	"none":        "#f0f0f0",
+}
+
+const (
+	cfdChartWidth  = 900
+	cfdChartHeight = 420
+	cfdMarginLeft  = 50
+	cfdMarginRight = 130
+	cfdMarginTop   = 20
+	cfdMarginBot   = 40
+	cfdMaxXLabels  = 8
+)
+
+// writeCFDHTML writes a self-contained HTML page with the CFD as an inline
+// SVG stacked-area chart. Done is stacked at the bottom, backlog on top.
+func writeCFDHTML(w io.Writer, title string, dates []string, byDate map[string]map[string]int, statuses []string) error {
+	plotW := float64(cfdChartWidth - cfdMarginLeft - cfdMarginRight)
+	plotH := float64(cfdChartHeight - cfdMarginTop - cfdMarginBot)
+
+	maxTotal := 0
+	for _, counts := range byDate {
+		total := 0
+		for _, c := range counts {
+			total += c
+		}
+		maxTotal = max(maxTotal, total)
+	}
+	if maxTotal == 0 {
+		maxTotal = 1
+	}
+
+	x := func(i int) float64 {
+		if len(dates) < 2 {
+			return cfdMarginLeft + plotW/2
+		}
+		return cfdMarginLeft + plotW*float64(i)/float64(len(dates)-1)
+	}
+	y := func(v int) float64 {
+		return cfdMarginTop + plotH - plotH*float64(v)/float64(maxTotal)
+	}
+
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="11">`+"\n",
+		cfdChartWidth, cfdChartHeight, cfdChartWidth, cfdChartHeight)
+
+	// Horizontal grid lines with y-axis counts
+	for i := 0; i <= 4; i++ {
+		v := maxTotal * i / 4
+		fmt.Fprintf(&svg, `<line x1="%d" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#eee"/>`+"\n",
+			cfdMarginLeft, y(v), cfdMarginLeft+plotW, y(v))
+		fmt.Fprintf(&svg, `<text x="%d" y="%.1f" text-anchor="end" fill="#666">%d</text>`+"\n",
+			cfdMarginLeft-6, y(v)+4, v)
+	}
+
+	// One band per status, stacked from the bottom up
+	lower := make([]int, len(dates))
+	for si := len(statuses) - 1; si >= 0; si-- {
+		status := statuses[si]
+		upper := make([]int, len(dates))
+		for i, date := range dates {
+			upper[i] = lower[i] + byDate[date][status]
+		}
+
+		var path strings.Builder
+		for i := range dates {
+			cmd := "L"
+			if i == 0 {
+				cmd = "M"
+			}
+			fmt.Fprintf(&path, "%s%.1f,%.1f ", cmd, x(i), y(upper[i]))
+		}
+		for i := len(dates) - 1; i >= 0; i-- {
+			fmt.Fprintf(&path, "L%.1f,%.1f ", x(i), y(lower[i]))
+		}
+		path.WriteString("Z")
+
+		fmt.Fprintf(&svg, `<path d="%s" fill="%s" fill-opacity="0.85" stroke="#fff" stroke-width="0.5"><title>%s</title></path>`+"\n",
+			path.String(), cfdColor(status), html.EscapeString(status))
+		lower = upper
+	}
+
+	// X-axis dates, thinned out so labels don't overlap
+	step := max(1, (len(dates)+cfdMaxXLabels-1)/cfdMaxXLabels)
+	for i, date := range dates {
+		if i%step != 0 && i != len(dates)-1 {
+			continue
+		}
+		fmt.Fprintf(&svg, `<text x="%.1f" y="%d" text-anchor="middle" fill="#666">%s</text>`+"\n",
+			x(i), cfdChartHeight-cfdMarginBot+16, html.EscapeString(cfdDateLabel(date)))
+	}
+	fmt.Fprintf(&svg, `<line x1="%d" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#999"/>`+"\n",
+		cfdMarginLeft, y(0), cfdMarginLeft+plotW, y(0))
+
+	// Legend in the same top-to-bottom order as the bands
+	legendX := cfdChartWidth - cfdMarginRight + 20
+	for i, status := range statuses {
+		ly := cfdMarginTop + i*18
+		fmt.Fprintf(&svg, `<rect x="%d" y="%d" width="12" height="12" fill="%s"/>`+"\n", legendX, ly, cfdColor(status))
+		fmt.Fprintf(&svg, `<text x="%d" y="%d">%s</text>`+"\n", legendX+18, ly+10, html.EscapeString(status))
+	}
+	svg.WriteString("</svg>")
+
+	_, err := fmt.Fprintf(w, `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>%[1]s</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #24292f; }
+h1 { font-size: 1.3em; }
+p { color: #666; }
+</style>
+</head>
+<body>
+<h1>%[1]s</h1>
+<p>%[2]d snapshots, %[3]s to %[4]s</p>
+%[5]s
+</body>
+</html>
+`, html.EscapeString(title), len(dates), html.EscapeString(cfdDateLabel(dates[0])),
+		html.EscapeString(cfdDateLabel(dates[len(dates)-1])), svg.String())
+	return err
+}
+
+func cfdColor(status string) string {
+	if c, ok := cfdStatusColors[status]; ok {
+		return c
+	}
+	return cfdStatusColors["none"]
+}
+
+// cfdDateLabel trims a snapshot date to YYYY-MM-DD
+func cfdDateLabel(date string) string {
+	if len(date) > 10 {
+		return date[:10]
+	}
+	return date
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCFDExport_DefaultFormat(t *testing.T) {
+	// Other commands register the shared format flag with a "table" default;
+	// cfd export has its own so it still defaults to csv
+	if cfdExportFormat != "csv" {
+		t.Errorf("cfdExportFormat = %q, want csv", cfdExportFormat)
+	}
+	if def := cfdExportCmd.Flags().Lookup("format").DefValue; def != "csv" {
+		t.Errorf("--format default = %q, want csv", def)
+	}
+}
+
+func TestWriteCFDHTML(t *testing.T) {
+	dates := []string{"2026-01-01T00:00:00Z", "2026-01-02T00:00:00Z", "2026-01-03T00:00:00Z"}
+	byDate := map[string]map[string]int{
+		dates[0]: {"backlog": 5, "in-progress": 1},
+		dates[1]: {"backlog": 4, "in-progress": 2, "done": 1},
+		dates[2]: {"backlog": 3, "in-progress": 1, "done": 3},
+	}
+	statuses := []string{"backlog", "in-progress", "done"}
+
+	var b strings.Builder
+	if err := writeCFDHTML(&b, "acme/app <CFD>", dates, byDate, statuses); err != nil {
+		t.Fatalf("writeCFDHTML() error: %v", err)
+	}
+	out := b.String()
+
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"<title>acme/app &lt;CFD&gt;</title>",
+		"3 snapshots, 2026-01-01 to 2026-01-03",
+		"<svg ",
+		"</svg>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	if got := strings.Count(out, "<path "); got != len(statuses) {
+		t.Errorf("got %d bands, want %d", got, len(statuses))
+	}
+	for _, status := range statuses {
+		if !strings.Contains(out, "<title>"+status+"</title>") {
+			t.Errorf("no band for %q", status)
+		}
+	}
+}
+
+func TestWriteCFDHTML_SingleSnapshot(t *testing.T) {
+	dates := []string{"2026-01-01"}
+	byDate := map[string]map[string]int{dates[0]: {"ready": 2}}
+
+	var b strings.Builder
+	if err := writeCFDHTML(&b, "one", dates, byDate, []string{"ready"}); err != nil {
+		t.Fatalf("writeCFDHTML() error: %v", err)
+	}
+	if !strings.Contains(b.String(), "1 snapshots, 2026-01-01 to 2026-01-01") {
+		t.Error("single snapshot not summarised")
+	}
+	if strings.Contains(b.String(), "NaN") {
+		t.Error("single snapshot produced NaN coordinates")
+	}
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/kiracore/kanban/internal/db"
+)
+
+var compareWindows bool
+
+func init() {
+	metricsCmd.Flags().BoolVar(&compareWindows, "compare", false, "compare the last --days against the --days before them")
+}
+
+// PeriodComparison compares a repo's flow metrics over two consecutive periods
+type PeriodComparison struct {
+	Repo          string        `json:"repo"`
+	PeriodDays    int           `json:"period_days"`
+	PreviousStart time.Time     `json:"previous_start"`
+	CurrentStart  time.Time     `json:"current_start"`
+	End           time.Time     `json:"end"`
+	Metrics       []MetricDelta `json:"metrics"` // Baseline holds the previous period
+}
+
+// runMetricsComparison compares [now-days, now] with [now-2*days, now-days]
+// for every cached repo. Only metrics computed from closed issues are
+// compared: WIP and aging describe the board now, not a past period.
+func runMetricsComparison(orgs []string) error {
+	if liveMode {
+		return fmt.Errorf("--compare uses cached data; run 'kanban sync' instead of --live")
+	}
+
+	database, err := db.OpenReadOnly(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w (run 'kanban sync' first)", err)
+	}
+	defer database.Close()
+	database.SetIncludeNotPlanned(includeNotPlanned)
+	excludeIssues(database)
+
+	end := time.Now().UTC().Truncate(time.Second)
+	currentStart := end.AddDate(0, 0, -days)
+	previousStart := currentStart.AddDate(0, 0, -days)
+
+	var comparisons []PeriodComparison
+	for _, organization := range orgs {
+		repos, err := cachedRepos(database, organization)
+		if err != nil {
+			return err
+		}
+
+		for _, fullName := range repos {
+			previous, err := windowMetrics(database, fullName, previousStart, currentStart)
+			if err != nil {
+				return err
+			}
+			current, err := windowMetrics(database, fullName, currentStart, end)
+			if err != nil {
+				return err
+			}
+
+			c := PeriodComparison{
+				Repo:          displayRepo(organization, fullName),
+				PeriodDays:    days,
+				PreviousStart: previousStart,
+				CurrentStart:  currentStart,
+				End:           end,
+			}
+			for _, bm := range baselineMetrics {
+				if !bm.windowed {
+					continue
+				}
+				b, a := bm.value(previous), bm.value(current)
+				c.Metrics = append(c.Metrics, MetricDelta{Name: bm.name, Unit: bm.unit, Baseline: b, Current: a, Change: a - b})
+			}
+			comparisons = append(comparisons, c)
+		}
+	}
+
+	if len(comparisons) == 0 {
+		return fmt.Errorf("no data found. Run 'kanban sync' first to populate the database")
+	}
+
+	w, closeOutput, err := openOutput()
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		output, _ := json.MarshalIndent(comparisons, "", "  ")
+		fmt.Fprintln(w, string(output))
+		return closeOutput()
+	}
+
+	for _, c := range comparisons {
+		printPeriodComparison(w, c)
+	}
+	return closeOutput()
+}
+
+// cachedRepos returns the synced repos of organization, narrowed to --repo
+// when it belongs to this organization
+func cachedRepos(database *db.DB, organization string) ([]string, error) {
+	repoName, ok := repoForOrg(organization)
+	if !ok {
+		return nil, nil
+	}
+
+	// Repository rows exist for every synced repo
+	firstSync, err := database.GetRepoFirstSync()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cached repositories: %w", err)
+	}
+
+	var repos []string
+	for fullName := range firstSync {
+		if !inOrg(organization, fullName) {
+			continue
+		}
+		if repoName != "" && fullName != organization+"/"+repoName {
+			continue
+		}
+		repos = append(repos, fullName)
+	}
+	sort.Strings(repos)
+	return repos, nil
+}
+
+// windowMetrics computes the flow metrics of issues closed in [start, end)
+func windowMetrics(database *db.DB, fullName string, start, end time.Time) (KanbanMetrics, error) {
+	m := KanbanMetrics{Repo: fullName, Period: days}
+	closed, err := database.GetClosedIssuesInWindow(fullName, start, end)
+	if err != nil {
+		return m, fmt.Errorf("failed to get closed issues for %s: %w", fullName, err)
+	}
+	applyClosedIssueMetrics(&m, closed, days)
+	return m, nil
+}
+
+func printPeriodComparison(w io.Writer, c PeriodComparison) {
+	reset := "\033[0m"
+	bold := "\033[1m"
+	cyan := "\033[36m"
+	dim := "\033[90m"
+
+	fmt.Fprintf(w, "\n%s%s  %s: last %d days vs previous %d%s\n", bold, cyan, c.Repo, c.PeriodDays, c.PeriodDays, reset)
+	fmt.Fprintf(w, "%s%s → %s vs %s → %s%s\n\n", dim,
+		c.CurrentStart.Format("2006-01-02"), c.End.Format("2006-01-02"),
+		c.PreviousStart.Format("2006-01-02"), c.CurrentStart.Format("2006-01-02"), reset)
+	fmt.Fprintf(w, "  %-18s %10s %10s %12s\n", "", "PREVIOUS", "CURRENT", "CHANGE")
+
+	i := 0
+	for _, bm := range baselineMetrics {
+		if !bm.windowed {
+			continue
+		}
+		d := c.Metrics[i]
+		i++
+
+		arrow := " "
+		switch {
+		case d.Change > 0:
+			arrow = "▲"
+		case d.Change < 0:
+			arrow = "▼"
+		}
+		fmt.Fprintf(w, "  %-18s %10s %10s %s%s %+9.1f%s\n",
+			d.Name, formatMetricValue(d.Baseline, d.Unit), formatMetricValue(d.Current, d.Unit),
+			deltaColor(d.Change, bm.better), arrow, d.Change, reset)
+	}
+	fmt.Fprintln(w)
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/kiracore/kanban/internal/config"
 	"github.com/spf13/cobra"
@@ -33,10 +34,26 @@ var showCmd = &cobra.Command{
 	RunE:  runShowConfig,
 }
 
+var migrateSchemaCmd = &cobra.Command{
+	Use:   "migrate-schema [file]",
+	Short: "Upgrade a configuration file to the current schema",
+	Long: `Upgrade an older configuration file: set the current version and add
+missing settings with their defaults, keeping existing values and comments.
+The original is saved next to it with a .bak suffix. Running it on an
+up-to-date file changes nothing.
+
+Examples:
+  kanban config migrate-schema
+  kanban config migrate-schema .kanban.yaml --dry-run`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMigrateSchema,
+}
+
 func init() {
 	rootCmd.AddCommand(configCmd)
 	configCmd.AddCommand(validateCmd)
 	configCmd.AddCommand(showCmd)
+	configCmd.AddCommand(migrateSchemaCmd)
 }
 
 func runValidate(cmd *cobra.Command, args []string) error {
@@ -55,52 +72,112 @@ func runValidate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	fmt.Printf("Validating: %s\n\n", configFile)
+	w, _, err := openOutput()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Validating: %s\n\n", configFile)
 
 	// Validate
 	result := cfg.Validate()
 
 	// Print errors
 	if len(result.Errors) > 0 {
-		fmt.Printf("\033[31m✗ %d error(s):\033[0m\n", len(result.Errors))
+		fmt.Fprintf(w, "\033[31m✗ %d error(s):\033[0m\n", len(result.Errors))
 		for _, e := range result.Errors {
-			fmt.Printf("  \033[31m• %s\033[0m\n", e.Error())
+			fmt.Fprintf(w, "  \033[31m• %s\033[0m\n", e.Error())
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
 	}
 
 	// Print warnings
 	if len(result.Warnings) > 0 {
-		fmt.Printf("\033[33m⚠ %d warning(s):\033[0m\n", len(result.Warnings))
-		for _, w := range result.Warnings {
-			fmt.Printf("  \033[33m• %s\033[0m\n", w.Error())
+		fmt.Fprintf(w, "\033[33m⚠ %d warning(s):\033[0m\n", len(result.Warnings))
+		for _, warning := range result.Warnings {
+			fmt.Fprintf(w, "  \033[33m• %s\033[0m\n", warning.Error())
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
 	}
 
 	// Summary
 	labels := cfg.AllLabels()
-	fmt.Printf("Configuration summary:\n")
-	fmt.Printf("  Organization: %s\n", cfg.Organization)
-	fmt.Printf("  Labels: %d\n", len(labels))
-	fmt.Printf("  Repositories: %d explicit, %d include patterns, %d exclude patterns\n",
+	fmt.Fprintf(w, "Configuration summary:\n")
+	fmt.Fprintf(w, "  Organization: %s\n", strings.Join(cfg.OrgList(), ", "))
+	fmt.Fprintf(w, "  Labels: %d\n", len(labels))
+	fmt.Fprintf(w, "  Repositories: %d explicit, %d include patterns, %d exclude patterns\n",
 		len(cfg.Repositories.List),
 		len(cfg.Repositories.Include),
 		len(cfg.Repositories.Exclude))
-	fmt.Printf("  Migrations: %d\n", len(cfg.Migrations))
-	fmt.Printf("  Maintainers: %d\n", len(cfg.Maintainers))
-	fmt.Println()
+	fmt.Fprintf(w, "  Migrations: %d\n", len(cfg.Migrations))
+	fmt.Fprintf(w, "  Maintainers: %d\n", len(cfg.Maintainers))
+	fmt.Fprintln(w)
 
 	if result.IsValid() {
-		fmt.Printf("\033[32m✓ Configuration is valid\033[0m\n")
+		fmt.Fprintf(w, "\033[32m✓ Configuration is valid\033[0m\n")
 		return nil
 	}
 
-	fmt.Printf("\033[31m✗ Configuration has errors\033[0m\n")
+	fmt.Fprintf(w, "\033[31m✗ Configuration has errors\033[0m\n")
 	os.Exit(1)
 	return nil
 }
 
+func runMigrateSchema(cmd *cobra.Command, args []string) error {
+	configFile := cfgFile
+	if len(args) > 0 {
+		configFile = args[0]
+	}
+	if configFile == "" {
+		configFile = ".kanban.yaml"
+	}
+
+	info, err := os.Stat(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	migrated, changes, err := config.MigrateSchema(data)
+	if err != nil {
+		return fmt.Errorf("failed to migrate %s: %w", configFile, err)
+	}
+
+	w, _, err := openOutput()
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Fprintf(w, "✓ %s is up to date (version %s)\n", configFile, config.CurrentVersion)
+		return nil
+	}
+
+	fmt.Fprintf(w, "Migrating: %s\n\n", configFile)
+	for _, c := range changes {
+		fmt.Fprintf(w, "  + %s\n", c)
+	}
+	fmt.Fprintln(w)
+
+	if dryRun {
+		fmt.Fprintln(w, "[DRY RUN - no changes will be made]")
+		return nil
+	}
+
+	backup := configFile + ".bak"
+	if err := os.WriteFile(backup, data, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := os.WriteFile(configFile, migrated, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+
+	fmt.Fprintf(w, "\033[32m✓ Migrated to version %s\033[0m (original saved as %s)\n", config.CurrentVersion, backup)
+	return nil
+}
+
 func runShowConfig(cmd *cobra.Command, args []string) error {
 	cfg, err := config.Load()
 	if err != nil {
@@ -108,6 +185,9 @@ func runShowConfig(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("Organization: %s\n", cfg.Organization)
+	if len(cfg.Organizations) > 0 {
+		fmt.Printf("Organizations: %s\n", strings.Join(cfg.Organizations, ", "))
+	}
 	fmt.Printf("Version: %s\n", cfg.Version)
 	fmt.Println()
 
@@ -1,19 +1,25 @@
 package cmd
 
 import (
+	"compress/gzip"
 	"fmt"
 	"os"
 	"path/filepath"
 	"time"
 
+	"github.com/kiracore/kanban/internal/config"
 	"github.com/kiracore/kanban/internal/db"
 	"github.com/kiracore/kanban/internal/paths"
 	"github.com/spf13/cobra"
 )
 
 var (
-	dbPath     string
-	backupPath string
+	dbPath        string
+	backupPath    string
+	noBackup      bool
+	exportCompact bool
+	exportGzip    bool
+	exportNDJSON  bool
 )
 
 // dbCmd represents the db command
@@ -146,7 +152,11 @@ If no output path is specified, creates a timestamped backup in the XDG data dir
 var dbRestoreCmd = &cobra.Command{
 	Use:   "restore",
 	Short: "Restore database from backup",
-	Long:  `Restores the database from a backup file.`,
+	Long: `Restores the database from a backup file. The backup is checked first;
+if it is damaged the current database is left unchanged.
+
+If the current database contains data, shows what it holds and asks for
+confirmation first (skip with --yes).`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if backupPath == "" {
 			return fmt.Errorf("backup path required: use --input or -i")
@@ -162,8 +172,30 @@ var dbRestoreCmd = &cobra.Command{
 			return fmt.Errorf("failed to open database: %w", err)
 		}
 
+		hasData, err := describeDatabase(database, database.Path())
+		if err != nil {
+			database.Close()
+			return err
+		}
+		if hasData && !confirm(fmt.Sprintf("Replace it with %s?", backupPath)) {
+			database.Close()
+			return fmt.Errorf("restore aborted")
+		}
+
 		if err := database.Restore(backupPath); err != nil {
-			return fmt.Errorf("failed to restore database: %w", err)
+			database.Close()
+			return fmt.Errorf("failed to restore database (current database left unchanged): %w", err)
+		}
+
+		// Restore closes the handle; reopen to verify the restored file
+		restored, err := db.Open(database.Path())
+		if err != nil {
+			return fmt.Errorf("failed to reopen restored database: %w", err)
+		}
+		defer restored.Close()
+
+		if err := restored.IntegrityCheck(); err != nil {
+			return fmt.Errorf("restored database is corrupt: %w", err)
 		}
 
 		fmt.Printf("✓ Database restored from: %s\n", backupPath)
@@ -171,6 +203,64 @@ var dbRestoreCmd = &cobra.Command{
 	},
 }
 
+// dbVerifyCmd checks the database, or a backup, for corruption
+var dbVerifyCmd = &cobra.Command{
+	Use:   "verify [file]",
+	Short: "Check the database or a backup for corruption",
+	Long: `Runs SQLite's integrity and foreign key checks on the database, or on the
+given file (e.g. a backup before restoring it). Prints OK or the problems
+found, and exits with status 1 if there are any.
+
+Examples:
+  kanban db verify
+  kanban db verify ~/backups/kanban-2026-01-01.db`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := dbPath
+		if len(args) > 0 {
+			path = args[0]
+		}
+		if path == "" {
+			path = db.DefaultDBPath()
+		}
+		// Verifying must not create a missing database, or touch one that exists
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("file not found: %s", path)
+		}
+
+		database, err := db.OpenReadOnly(path)
+		if err != nil {
+			return fmt.Errorf("failed to open database: %w", err)
+		}
+
+		// A file too damaged to check at all is reported like any other problem
+		problems, err := database.Verify()
+		database.Close()
+		if err != nil {
+			problems = []string{err.Error()}
+		}
+
+		w, closeOutput, err := openOutput()
+		if err != nil {
+			return err
+		}
+		defer closeOutput()
+
+		if len(problems) == 0 {
+			fmt.Fprintf(w, "\033[32m✓ OK: %s\033[0m\n", path)
+			return nil
+		}
+
+		fmt.Fprintf(w, "\033[31m✗ %d problem(s) in %s:\033[0m\n", len(problems), path)
+		for _, problem := range problems {
+			fmt.Fprintf(w, "  • %s\n", problem)
+		}
+		// The problems are already listed; don't follow them with usage
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%s failed verification", path)
+	},
+}
+
 // dbExportCmd exports database to JSON
 var dbExportCmd = &cobra.Command{
 	Use:   "export",
@@ -178,7 +268,15 @@ var dbExportCmd = &cobra.Command{
 	Long: `Exports all database data to JSON format.
 
 Output goes to stdout by default. Redirect to a file:
-  kanban db export > backup.json`,
+  kanban db export > backup.json
+
+Smaller output for storage or transfer:
+  kanban db export --compact > backup.json
+  kanban db export --compact --gzip > backup.json.gz
+
+Stream issues as newline-delimited JSON, one issue per line with its
+repo_full_name, for external pipelines (not importable with 'db import'):
+  kanban db export --ndjson | jq -c 'select(.is_blocked)'`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		database, err := db.Open(dbPath)
 		if err != nil {
@@ -186,7 +284,29 @@ Output goes to stdout by default. Redirect to a file:
 		}
 		defer database.Close()
 
-		if err := database.Export(os.Stdout); err != nil {
+		if exportGzip {
+			if fi, err := os.Stdout.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+				return fmt.Errorf("refusing to write gzip data to a terminal; redirect to a file (kanban db export --gzip > backup.json.gz)")
+			}
+		}
+
+		if exportNDJSON {
+			if !exportGzip {
+				if err := database.ExportStream(os.Stdout, "ndjson"); err != nil {
+					return fmt.Errorf("failed to export issues: %w", err)
+				}
+				return nil
+			}
+			gz := gzip.NewWriter(os.Stdout)
+			if err := database.ExportStream(gz, "ndjson"); err != nil {
+				gz.Close()
+				return fmt.Errorf("failed to export issues: %w", err)
+			}
+			return gz.Close()
+		}
+
+		opts := db.ExportOptions{Compact: exportCompact, Gzip: exportGzip}
+		if err := database.ExportWithOptions(os.Stdout, opts); err != nil {
 			return fmt.Errorf("failed to export database: %w", err)
 		}
 
@@ -201,7 +321,10 @@ var dbImportCmd = &cobra.Command{
 	Long: `Imports data from JSON format.
 
 Input comes from stdin by default:
-  kanban db import < backup.json`,
+  kanban db import < backup.json
+
+Gzipped exports are detected automatically:
+  kanban db import < backup.json.gz`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		database, err := db.Open(dbPath)
 		if err != nil {
@@ -227,13 +350,22 @@ Input comes from stdin by default:
 var dbResetCmd = &cobra.Command{
 	Use:   "reset",
 	Short: "Reset the database (destroys all data)",
-	Long:  `Removes and reinitializes the database. All data will be lost!`,
+	Long: `Removes and reinitializes the database. All data will be lost!
+
+If the database contains data, asks for confirmation (skip with --yes)
+and first backs it up to the backup directory (skip with --no-backup).`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		path := dbPath
 		if path == "" {
 			path = db.DefaultDBPath()
 		}
 
+		if _, err := os.Stat(path); err == nil {
+			if err := guardReset(path); err != nil {
+				return err
+			}
+		}
+
 		// Remove existing database files
 		os.Remove(path)
 		os.Remove(path + "-wal")
@@ -301,6 +433,64 @@ Run this periodically (weekly) or after large data changes.`,
 	},
 }
 
+// dbMigrateTimestampsCmd rewrites timestamps stored by older versions
+var dbMigrateTimestampsCmd = &cobra.Command{
+	Use:   "migrate-timestamps",
+	Short: "Repair timestamps stored in older formats",
+	Long: `Rewrites issue and status transition timestamps that earlier versions
+stored in other formats (e.g. "2024-03-01 08:00:00 +0000 UTC" or RFC3339)
+to the format SQLite's date functions read, then recalculates lead and cycle
+time for every issue. Run it if cycle times are missing after an upgrade.
+
+Everything runs in one transaction. Use --dry-run to see the counts
+without changing anything.
+
+Examples:
+  kanban db migrate-timestamps --dry-run
+  kanban db migrate-timestamps`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.Open(dbPath)
+		if err != nil {
+			return fmt.Errorf("failed to open database: %w", err)
+		}
+		defer database.Close()
+
+		if err := database.Init(); err != nil {
+			return fmt.Errorf("failed to initialize database: %w", err)
+		}
+
+		// Cycle time starts at the configured active status
+		if cfg, err := config.Load(); err == nil {
+			if err := database.SetActiveStartStatus(cfg.Settings.ActiveStart()); err != nil {
+				return fmt.Errorf("invalid settings.active_start_status: %w", err)
+			}
+		}
+
+		if dryRun {
+			fmt.Println("[DRY RUN - no changes will be made]")
+		}
+		repair, err := database.NormalizeTimestamps(dryRun)
+		if err != nil {
+			return fmt.Errorf("failed to migrate timestamps: %w", err)
+		}
+
+		verb := "Normalized"
+		if dryRun {
+			verb = "Would normalize"
+		}
+		fmt.Printf("%s timestamps in %d issue(s) and %d status transition(s)\n", verb, repair.Issues, repair.Transitions)
+		if repair.Unparsed > 0 {
+			fmt.Printf("⚠ %d timestamp(s) in an unknown format were left as they are\n", repair.Unparsed)
+		}
+		if dryRun {
+			fmt.Printf("Would recalculate lead and cycle time for %d issue(s)\n", repair.Recalculated)
+		} else {
+			fmt.Printf("✓ Recalculated lead and cycle time for %d issue(s)\n", repair.Recalculated)
+		}
+		return nil
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(dbCmd)
 
@@ -310,15 +500,67 @@ func init() {
 	dbCmd.AddCommand(dbPathCmd)
 	dbCmd.AddCommand(dbBackupCmd)
 	dbCmd.AddCommand(dbRestoreCmd)
+	dbCmd.AddCommand(dbVerifyCmd)
 	dbCmd.AddCommand(dbExportCmd)
 	dbCmd.AddCommand(dbImportCmd)
 	dbCmd.AddCommand(dbResetCmd)
 	dbCmd.AddCommand(dbOptimizeCmd)
+	dbCmd.AddCommand(dbMigrateTimestampsCmd)
 
 	// Flags
 	dbCmd.PersistentFlags().StringVar(&dbPath, "db", "", "database path (default ~/.local/share/kanban/kanban.db)")
 	dbBackupCmd.Flags().StringVar(&backupPath, "output", "", "backup output path")
 	dbRestoreCmd.Flags().StringVar(&backupPath, "input", "", "backup input path")
+	dbExportCmd.Flags().BoolVar(&exportCompact, "compact", false, "compact JSON without indentation")
+	dbExportCmd.Flags().BoolVar(&exportGzip, "gzip", false, "gzip-compress the output (.json.gz)")
+	dbExportCmd.Flags().BoolVar(&exportNDJSON, "ndjson", false, "stream issues as newline-delimited JSON")
+	dbResetCmd.Flags().BoolVar(&noBackup, "no-backup", false, "don't back up the database before resetting")
+}
+
+// guardReset confirms and backs up a non-empty database before it is reset
+func guardReset(path string) error {
+	database, err := db.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	hasData, err := describeDatabase(database, path)
+	if err != nil || !hasData {
+		return err
+	}
+	if !confirm("Reset database?") {
+		return fmt.Errorf("reset aborted")
+	}
+
+	if !noBackup {
+		timestamp := time.Now().Format("20060102-150405")
+		dest := filepath.Join(paths.BackupDir(), fmt.Sprintf("kanban-pre-reset-%s.db", timestamp))
+		if err := database.Backup(dest); err != nil {
+			return fmt.Errorf("failed to back up before reset (use --no-backup to skip): %w", err)
+		}
+		fmt.Printf("✓ Backed up to: %s\n", dest)
+	}
+
+	return nil
+}
+
+// describeDatabase prints what a command is about to destroy: the database
+// at path and its row counts. It returns false, printing nothing, when the
+// database holds no data and so needs no confirmation.
+func describeDatabase(database *db.DB, path string) (bool, error) {
+	stats, err := database.GetStats()
+	if err != nil {
+		return false, fmt.Errorf("failed to get stats: %w", err)
+	}
+	if stats.Organizations+stats.Repositories+stats.Issues+stats.PullRequests == 0 {
+		return false, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "This will destroy %s:\n", path)
+	fmt.Fprintf(os.Stderr, "  %d organizations, %d repositories\n", stats.Organizations, stats.Repositories)
+	fmt.Fprintf(os.Stderr, "  %d issues, %d pull requests, %d transitions\n", stats.Issues, stats.PullRequests, stats.Transitions)
+	return true, nil
 }
 
 // Helper functions
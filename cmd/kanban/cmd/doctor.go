@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/kiracore/kanban/internal/config"
+	"github.com/kiracore/kanban/internal/db"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that kanban's prerequisites are in place",
+	Long: `Check the environment kanban depends on and suggest fixes.
+
+Checks that gh is installed and authenticated, that the configured
+organizations are accessible, that the database location is writable
+and that the config file parses and validates.
+
+Exits non-zero if any critical check fails.
+
+Examples:
+  kanban doctor
+  kanban doctor --org myorg --db /tmp/kanban.db`,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().StringVar(&dbPath, "db", "", "database path (default ~/.local/share/kanban/kanban.db)")
+}
+
+// doctorCheck is one line of the doctor checklist
+type doctorCheck struct {
+	Name     string
+	OK       bool
+	Critical bool   // a failure makes doctor exit non-zero
+	Detail   string // what was found
+	Hint     string // how to fix a failure
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	// Failed checks are already explained; don't follow them with usage
+	cmd.SilenceUsage = true
+
+	checks := []doctorCheck{}
+
+	ghPath, err := exec.LookPath("gh")
+	if err != nil {
+		checks = append(checks, doctorCheck{
+			Name: "gh installed", Critical: true,
+			Detail: "gh not found on PATH",
+			Hint:   "install the GitHub CLI: https://cli.github.com",
+		})
+	} else {
+		checks = append(checks, doctorCheck{Name: "gh installed", OK: true, Detail: ghPath})
+		checks = append(checks, checkGHAuth())
+		checks = append(checks, checkOrgAccess()...)
+	}
+
+	checks = append(checks, checkDatabaseWritable())
+	checks = append(checks, checkConfigFile())
+
+	w, closeOutput, err := openOutput()
+	if err != nil {
+		return err
+	}
+	if err := reportDoctorChecks(w, checks); err != nil {
+		closeOutput()
+		return err
+	}
+	return closeOutput()
+}
+
+func checkGHAuth() doctorCheck {
+	check := doctorCheck{Name: "gh authenticated", Critical: true}
+
+	client := newGitHubClient(timeoutCtx)
+	if err := client.AuthStatus(); err != nil {
+		check.Detail = err.Error()
+		check.Hint = "run 'gh auth login', or set GITHUB_TOKEN or GH_TOKEN (see settings.use_gh_token)"
+		return check
+	}
+
+	check.OK = true
+	if info, err := client.AuthInfo(); err == nil {
+		check.Detail = fmt.Sprintf("%s via %s", info.Login, info.Backend)
+	}
+	return check
+}
+
+func checkOrgAccess() []doctorCheck {
+	orgs, err := resolveOrganizations()
+	if err != nil {
+		return []doctorCheck{{
+			Name: "organization configured", Critical: true,
+			Detail: "no organization configured",
+			Hint:   "pass --org or run 'kanban init --org <org>'",
+		}}
+	}
+
+	client := newGitHubClient(timeoutCtx)
+	checks := []doctorCheck{}
+	for _, organization := range orgs {
+		check := doctorCheck{Name: fmt.Sprintf("organization %s accessible", organization), Critical: true}
+		if err := client.CheckOrgAccess(organization); err != nil {
+			check.Detail = err.Error()
+			check.Hint = "check the org name and that your token has the read:org scope ('gh auth refresh -s read:org')"
+		} else {
+			check.OK = true
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// checkDatabaseWritable checks the database, or its directory if it doesn't
+// exist yet, can be written without creating the database itself
+func checkDatabaseWritable() doctorCheck {
+	path := dbPath
+	if path == "" {
+		path = db.DefaultDBPath()
+	}
+	check := doctorCheck{Name: "database writable", Critical: true, Detail: path}
+
+	if _, err := os.Stat(path); err == nil {
+		f, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			check.Detail = err.Error()
+			check.Hint = "fix the file's permissions or pass --db with another path"
+			return check
+		}
+		f.Close()
+		check.OK = true
+		return check
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		check.Detail = err.Error()
+		check.Hint = "create the directory or set XDG_DATA_HOME to a writable location"
+		return check
+	}
+	probe, err := os.CreateTemp(dir, ".kanban-doctor-*")
+	if err != nil {
+		check.Detail = err.Error()
+		check.Hint = "fix the directory's permissions or set XDG_DATA_HOME to a writable location"
+		return check
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	check.OK = true
+	check.Detail = path + " (not created yet; run 'kanban sync')"
+	return check
+}
+
+func checkConfigFile() doctorCheck {
+	check := doctorCheck{Name: "config valid", Critical: true}
+
+	path := viper.ConfigFileUsed()
+	if path == "" {
+		check.Critical = false
+		check.Detail = "no config file found"
+		check.Hint = "run 'kanban init' to create .kanban.yaml"
+		return check
+	}
+
+	cfg, err := config.LoadLabelsFromFile(path)
+	if err != nil {
+		check.Detail = fmt.Sprintf("%s: %v", path, err)
+		check.Hint = "fix the YAML syntax"
+		return check
+	}
+
+	result := cfg.Validate()
+	if !result.IsValid() {
+		check.Detail = fmt.Sprintf("%s: %d error(s), first: %s", path, len(result.Errors), result.Errors[0].Error())
+		check.Hint = fmt.Sprintf("run 'kanban config validate %s' for details", path)
+		return check
+	}
+
+	check.OK = true
+	check.Detail = path
+	if result.HasWarnings() {
+		check.Detail = fmt.Sprintf("%s (%d warning(s))", path, len(result.Warnings))
+	}
+	return check
+}
+
+func reportDoctorChecks(w io.Writer, checks []doctorCheck) error {
+	reset := "\033[0m"
+	green := "\033[32m"
+	yellow := "\033[33m"
+	red := "\033[31m"
+	dim := "\033[90m"
+
+	failed := 0
+	fmt.Fprintln(w)
+	for _, c := range checks {
+		switch {
+		case c.OK:
+			fmt.Fprintf(w, "  %s✓%s %s %s%s%s\n", green, reset, c.Name, dim, c.Detail, reset)
+			continue
+		case c.Critical:
+			failed++
+			fmt.Fprintf(w, "  %s✗%s %s %s%s%s\n", red, reset, c.Name, dim, c.Detail, reset)
+		default:
+			fmt.Fprintf(w, "  %s!%s %s %s%s%s\n", yellow, reset, c.Name, dim, c.Detail, reset)
+		}
+		if c.Hint != "" {
+			fmt.Fprintf(w, "      → %s\n", c.Hint)
+		}
+	}
+	fmt.Fprintln(w)
+
+	if failed > 0 {
+		return fmt.Errorf("%d critical check(s) failed", failed)
+	}
+	fmt.Fprintf(w, "%s✓ All checks passed%s\n", green, reset)
+	return nil
+}
@@ -0,0 +1,311 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kiracore/kanban/internal/db"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportState   string
+	exportSince   string
+	exportColumns string
+	exportFormat  string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export cached data for other tools",
+	Long: `Export cached data in flat formats for spreadsheets and BI tools.
+
+Unlike 'kanban db export', which backs up the whole database as JSON,
+these exports pick one kind of record and only the fields asked for.`,
+}
+
+var exportIssuesCmd = &cobra.Command{
+	Use:   "issues",
+	Short: "Export cached issues with their timings as CSV or JSON",
+	Long: `Export cached issues, one row per issue, with status entry times and
+lead, cycle and blocked hours. Rows are written as they are read, so large
+caches export without being held in memory.
+
+Fields (all by default, in this order; pick some with --columns):
+  ` + strings.Join(exportFieldNames(), ", ") + `
+
+Timestamps are RFC3339 in UTC. Fields without a value (an open issue's
+closed_at or lead time) are empty in CSV and null in JSON.
+
+Examples:
+  kanban export issues --repo myrepo > issues.csv
+  kanban export issues --all --state closed --since 90d --output closed.csv
+  kanban export issues --repo myrepo --columns number,title,lead_time_hours,cycle_time_hours
+  kanban export issues --all --format json | jq '.[] | select(.blocked)'`,
+	RunE: runExportIssues,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.AddCommand(exportIssuesCmd)
+	exportIssuesCmd.Flags().StringVarP(&repo, "repo", "r", "", "specific repository")
+	exportIssuesCmd.Flags().BoolVar(&allRepos, "all", false, "export all cached repositories")
+	exportIssuesCmd.Flags().StringVar(&exportState, "state", "all", "issue state (open|closed|all)")
+	exportIssuesCmd.Flags().StringVar(&exportSince, "since", "", "only issues updated since duration (24h, 7d) or date (2006-01-02)")
+	exportIssuesCmd.Flags().StringVar(&exportColumns, "columns", "", "comma-separated fields to export (default all)")
+	exportIssuesCmd.Flags().StringVarP(&exportFormat, "format", "f", "csv", "output format (csv|json)")
+	exportIssuesCmd.Flags().StringVar(&outputFile, "output", "", "write the export to this file instead of stdout")
+}
+
+// exportField is one exportable issue field; value returns nil when the
+// issue has none
+type exportField struct {
+	name  string
+	value func(db.StreamIssue) any
+}
+
+var exportFields = []exportField{
+	{"repo", func(i db.StreamIssue) any { return i.RepoFullName }},
+	{"number", func(i db.StreamIssue) any { return i.Number }},
+	{"title", func(i db.StreamIssue) any { return i.Title }},
+	{"state", func(i db.StreamIssue) any { return i.State }},
+	{"state_reason", func(i db.StreamIssue) any { return optionalString(i.StateReason) }},
+	{"status", func(i db.StreamIssue) any { return optionalString(i.CurrentStatus) }},
+	{"priority", func(i db.StreamIssue) any { return optionalString(i.CurrentPriority) }},
+	{"type", func(i db.StreamIssue) any { return optionalString(i.CurrentType) }},
+	{"size", func(i db.StreamIssue) any { return optionalString(i.CurrentSize) }},
+	{"assignee", func(i db.StreamIssue) any { return optionalString(i.Assignee) }},
+	{"milestone", func(i db.StreamIssue) any { return optionalString(i.Milestone) }},
+	{"author", func(i db.StreamIssue) any { return optionalString(i.Author) }},
+	{"blocked", func(i db.StreamIssue) any { return i.IsBlocked }},
+	{"created_at", func(i db.StreamIssue) any { return optionalTime(&i.GHCreatedAt) }},
+	{"updated_at", func(i db.StreamIssue) any { return optionalTime(&i.GHUpdatedAt) }},
+	{"closed_at", func(i db.StreamIssue) any { return optionalTime(i.GHClosedAt) }},
+	{"entered_ready_at", func(i db.StreamIssue) any { return optionalTime(i.EnteredReadyAt) }},
+	{"entered_progress_at", func(i db.StreamIssue) any { return optionalTime(i.EnteredProgressAt) }},
+	{"entered_review_at", func(i db.StreamIssue) any { return optionalTime(i.EnteredReviewAt) }},
+	{"entered_testing_at", func(i db.StreamIssue) any { return optionalTime(i.EnteredTestingAt) }},
+	{"entered_done_at", func(i db.StreamIssue) any { return optionalTime(i.EnteredDoneAt) }},
+	{"lead_time_hours", func(i db.StreamIssue) any { return optionalHours(i.LeadTimeHours) }},
+	{"cycle_time_hours", func(i db.StreamIssue) any { return optionalHours(i.CycleTimeHours) }},
+	{"blocked_time_hours", func(i db.StreamIssue) any { return optionalHours(i.BlockedTimeHours) }},
+	{"size_points", func(i db.StreamIssue) any {
+		if i.SizePoints == nil {
+			return nil
+		}
+		return *i.SizePoints
+	}},
+}
+
+func exportFieldNames() []string {
+	return fieldNames(exportFields)
+}
+
+// selectExportFields returns the fields named by --columns, in its order,
+// or every field without it
+func selectExportFields(columns string) ([]exportField, error) {
+	if strings.TrimSpace(columns) == "" {
+		return exportFields, nil
+	}
+	var fields []exportField
+	for _, name := range strings.Split(columns, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		found := false
+		for _, f := range exportFields {
+			if f.name == name {
+				fields = append(fields, f)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown column %q (available: %s)", name, strings.Join(exportFieldNames(), ", "))
+		}
+	}
+	return fields, nil
+}
+
+func optionalString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func optionalTime(t *time.Time) any {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// optionalHours rounds to hundredths; the cache stores missing times as 0
+func optionalHours(h float64) any {
+	if h == 0 {
+		return nil
+	}
+	return math.Round(h*100) / 100
+}
+
+func runExportIssues(cmd *cobra.Command, args []string) error {
+	filter := db.IssueFilter{}
+	switch exportState {
+	case "open", "closed":
+		filter.State = exportState
+	case "all":
+	default:
+		return fmt.Errorf("invalid --state %q: use open, closed or all", exportState)
+	}
+	if exportFormat != "csv" && exportFormat != "json" {
+		return fmt.Errorf("unsupported format: %s (use csv or json)", exportFormat)
+	}
+	if exportSince != "" {
+		since, err := parseSince(exportSince, time.Now())
+		if err != nil {
+			return err
+		}
+		filter.Since = since
+	}
+	if repo == "" && !allRepos {
+		return fmt.Errorf("specify --repo or --all")
+	}
+
+	fields, err := selectExportFields(exportColumns)
+	if err != nil {
+		return err
+	}
+
+	orgs, err := resolveOrganizations()
+	if err != nil {
+		return err
+	}
+
+	database, err := db.OpenReadOnly(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w (run 'kanban sync' first)", err)
+	}
+	defer database.Close()
+
+	w, closeOutput, err := openOutput()
+	if err != nil {
+		return err
+	}
+	defer closeOutput()
+
+	exporter := newIssueExporter(w, fields, exportFormat)
+	for _, organization := range orgs {
+		repoName, ok := repoForOrg(organization)
+		if !ok {
+			continue
+		}
+		filter.Org = organization
+		filter.Repo = ""
+		if repoName != "" {
+			filter.Repo = fmt.Sprintf("%s/%s", organization, repoName)
+		}
+		if err := database.StreamIssues(filter, exporter.write); err != nil {
+			return fmt.Errorf("failed to export issues: %w", err)
+		}
+	}
+	return exporter.close()
+}
+
+// issueExporter writes issues as CSV rows or elements of one JSON array
+type issueExporter struct {
+	fields []exportField
+	csv    *csv.Writer
+	json   *bufio.Writer
+	n      int
+}
+
+func newIssueExporter(w io.Writer, fields []exportField, format string) *issueExporter {
+	e := &issueExporter{fields: fields}
+	if format == "json" {
+		e.json = bufio.NewWriter(w)
+		return e
+	}
+	e.csv = csv.NewWriter(w)
+	e.csv.Write(fieldNames(fields))
+	return e
+}
+
+func fieldNames(fields []exportField) []string {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.name
+	}
+	return names
+}
+
+func (e *issueExporter) write(issue db.StreamIssue) error {
+	e.n++
+	if e.csv != nil {
+		row := make([]string, len(e.fields))
+		for i, f := range e.fields {
+			row[i] = csvValue(f.value(issue))
+		}
+		return e.csv.Write(row)
+	}
+
+	// Objects are written by hand to keep the fields in --columns order
+	if e.n == 1 {
+		e.json.WriteString("[\n  {")
+	} else {
+		e.json.WriteString(",\n  {")
+	}
+	for i, f := range e.fields {
+		if i > 0 {
+			e.json.WriteString(", ")
+		}
+		key, _ := json.Marshal(f.name)
+		value, err := json.Marshal(f.value(issue))
+		if err != nil {
+			return err
+		}
+		e.json.Write(key)
+		e.json.WriteString(": ")
+		e.json.Write(value)
+	}
+	e.json.WriteString("}")
+	return nil
+}
+
+func (e *issueExporter) close() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		return e.csv.Error()
+	}
+	if e.n == 0 {
+		e.json.WriteString("[]\n")
+	} else {
+		e.json.WriteString("\n]\n")
+	}
+	return e.json.Flush()
+}
+
+func csvValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
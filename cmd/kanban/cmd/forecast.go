@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kiracore/kanban/internal/db"
+	"github.com/kiracore/kanban/internal/forecast"
+	"github.com/spf13/cobra"
+)
+
+var (
+	forecastItems  int
+	forecastDate   string
+	forecastTrials int
+	forecastSeed   int64
+)
+
+// forecastPercentiles are the confidence levels reported
+var forecastPercentiles = []int{50, 70, 85, 95}
+
+var forecastCmd = &cobra.Command{
+	Use:   "forecast",
+	Short: "Forecast delivery with Monte Carlo simulation",
+	Long: `Forecast when work will be done by replaying historical throughput.
+
+Each trial samples random days from the last --days of daily completions
+until the target is reached. Results are reported at several confidence
+levels: "85%" means 85% of simulated futures did at least that well.
+
+Examples:
+  # When will the next 20 items be done?
+  kanban forecast --org myorg --repo myrepo --items 20
+
+  # How many items will be done by a date?
+  kanban forecast --org myorg --all --date 2026-12-31
+
+  # Repeatable output
+  kanban forecast --org myorg --repo myrepo --items 20 --seed 42`,
+	RunE: runForecast,
+}
+
+func init() {
+	rootCmd.AddCommand(forecastCmd)
+	forecastCmd.Flags().StringVarP(&repo, "repo", "r", "", "specific repository")
+	forecastCmd.Flags().BoolVar(&allRepos, "all", false, "pool throughput of all repositories")
+	forecastCmd.Flags().IntVar(&forecastItems, "items", 0, "forecast when this many items will be done")
+	forecastCmd.Flags().StringVar(&forecastDate, "date", "", "forecast how many items will be done by this UTC date (2006-01-02)")
+	forecastCmd.Flags().IntVar(&days, "days", 30, "days of throughput history to sample")
+	forecastCmd.Flags().IntVar(&forecastTrials, "trials", forecast.DefaultTrials, "number of simulated trials")
+	forecastCmd.Flags().Int64Var(&forecastSeed, "seed", 0, "random seed for repeatable results (0 = random)")
+	forecastCmd.Flags().StringVarP(&format, "format", "f", "table", "output format (table|json)")
+	forecastCmd.Flags().BoolVar(&includeNotPlanned, "include-not-planned", false, "count issues closed as not planned or duplicate as done")
+}
+
+// ForecastPoint is one confidence level of a forecast
+type ForecastPoint struct {
+	Confidence int        `json:"confidence"`
+	Days       int        `json:"days,omitempty"`
+	Date       *time.Time `json:"date,omitempty"`
+	Items      *int       `json:"items,omitempty"`
+}
+
+// Forecast is the result of a Monte Carlo forecast
+type Forecast struct {
+	Scope       string          `json:"scope"`
+	HistoryDays int             `json:"history_days"`
+	Completed   int             `json:"completed_in_history"`
+	Trials      int             `json:"trials"`
+	Items       int             `json:"items,omitempty"`
+	TargetDate  *time.Time      `json:"target_date,omitempty"`
+	Points      []ForecastPoint `json:"points"`
+}
+
+func runForecast(cmd *cobra.Command, args []string) error {
+	orgs, err := resolveOrganizations()
+	if err != nil {
+		return err
+	}
+	if repo == "" && !allRepos {
+		return fmt.Errorf("specify --repo or --all")
+	}
+	if (forecastItems > 0) == (forecastDate != "") {
+		return fmt.Errorf("specify exactly one of --items or --date")
+	}
+	if forecastItems < 0 {
+		return fmt.Errorf("--items must be positive")
+	}
+	if days < 1 {
+		return fmt.Errorf("--days must be at least 1")
+	}
+
+	// Throughput is bucketed by UTC day, so --date is a UTC date too
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	var target time.Time
+	if forecastDate != "" {
+		t, err := time.Parse("2006-01-02", forecastDate)
+		if err != nil {
+			return fmt.Errorf("invalid --date %q: use YYYY-MM-DD", forecastDate)
+		}
+		if !t.After(today) {
+			return fmt.Errorf("--date must be in the future")
+		}
+		target = t
+	}
+
+	database, err := db.Open(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w (run 'kanban sync' first)", err)
+	}
+	defer database.Close()
+	database.SetIncludeNotPlanned(includeNotPlanned)
+	excludeIssues(database)
+
+	throughput := make([]int, days)
+	var scopes []string
+	for _, organization := range orgs {
+		repos, err := cachedRepos(database, organization)
+		if err != nil {
+			return err
+		}
+		for _, fullName := range repos {
+			daily, err := database.GetDailyThroughput(fullName, days)
+			if err != nil {
+				return fmt.Errorf("failed to get throughput for %s: %w", fullName, err)
+			}
+			for i, c := range daily {
+				throughput[i] += c
+			}
+		}
+		if len(repos) == 0 {
+			continue
+		}
+		if repo != "" {
+			scopes = append(scopes, repos...)
+		} else {
+			scopes = append(scopes, organization+" (all repositories)")
+		}
+	}
+	if len(scopes) == 0 {
+		return fmt.Errorf("no cached repositories found. Run 'kanban sync' first")
+	}
+	scope := strings.Join(scopes, ", ")
+
+	sim, err := forecast.New(throughput, forecastTrials, forecastSeed)
+	if errors.Is(err, forecast.ErrNoThroughput) {
+		return fmt.Errorf("no issues closed in the last %d days; try a longer --days", days)
+	} else if err != nil {
+		return err
+	}
+
+	completed := 0
+	for _, c := range throughput {
+		completed += c
+	}
+
+	result := Forecast{
+		Scope:       scope,
+		HistoryDays: days,
+		Completed:   completed,
+		Trials:      forecastTrials,
+		Items:       forecastItems,
+	}
+
+	if forecastItems > 0 {
+		results := sim.DaysToComplete(forecastItems)
+		for _, p := range forecastPercentiles {
+			d := forecast.Percentile(results, p)
+			date := today.AddDate(0, 0, d)
+			result.Points = append(result.Points, ForecastPoint{Confidence: p, Days: d, Date: &date})
+		}
+	} else {
+		result.TargetDate = &target
+		horizon := int(target.Sub(today).Hours()/24 + 0.5)
+		results := sim.ItemsWithin(horizon)
+		for _, p := range forecastPercentiles {
+			// At p% confidence at least this many are done: the (100-p)th percentile
+			n := forecast.Percentile(results, 100-p)
+			result.Points = append(result.Points, ForecastPoint{Confidence: p, Days: horizon, Items: &n})
+		}
+	}
+
+	w, closeOutput, err := openOutput()
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		output, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Fprintln(w, string(output))
+		return closeOutput()
+	}
+
+	printForecast(w, result)
+	return closeOutput()
+}
+
+func printForecast(w io.Writer, f Forecast) {
+	reset := "\033[0m"
+	bold := "\033[1m"
+	cyan := "\033[36m"
+	green := "\033[32m"
+	yellow := "\033[33m"
+	red := "\033[31m"
+	dim := "\033[90m"
+
+	if f.TargetDate == nil {
+		fmt.Fprintf(w, "\n%s%s  FORECAST: %d items%s\n", bold, cyan, f.Items, reset)
+	} else {
+		fmt.Fprintf(w, "\n%s%s  FORECAST: items done by %s%s\n", bold, cyan, f.TargetDate.Format("2006-01-02"), reset)
+	}
+	fmt.Fprintf(w, "%s%s · %d closed in last %d days (%.1f/day) · %d trials%s\n\n",
+		dim, f.Scope, f.Completed, f.HistoryDays, float64(f.Completed)/float64(f.HistoryDays), f.Trials, reset)
+
+	for _, p := range f.Points {
+		color := green
+		switch {
+		case p.Confidence >= 95:
+			color = red
+		case p.Confidence >= 85:
+			color = yellow
+		}
+
+		if p.Items != nil {
+			fmt.Fprintf(w, "  %s%3d%%%s  at least %s%d%s items\n", color, p.Confidence, reset, bold, *p.Items, reset)
+			continue
+		}
+
+		when := p.Date.Format("2006-01-02")
+		if p.Days >= forecast.MaxDays {
+			when = "not within 10 years"
+		}
+		fmt.Fprintf(w, "  %s%3d%%%s  %s%s%s %s(%d days)%s\n", color, p.Confidence, reset, bold, when, reset, dim, p.Days, reset)
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,492 @@
+package cmd
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kiracore/kanban/internal/config"
+	"github.com/kiracore/kanban/internal/db"
+	"github.com/spf13/cobra"
+)
+
+var blockReason string
+
+var issueCmd = &cobra.Command{
+	Use:   "issue <number> [block|unblock]",
+	Short: "Show one issue's fields, status history, blocked periods and PRs",
+	Long: `Show a single issue for debugging its flow: current fields, every status
+transition with the time spent in each status, blocked periods, linked pull
+requests and lead/cycle time.
+
+Data comes from the cache; --live fetches the issue and its timeline from
+GitHub instead (linked PRs still come from the cache).
+
+'block' marks a cached issue blocked from now, for blocking that isn't
+tracked with the blocked label, and 'unblock' ends it. The blocked period,
+with its --reason, shows up in blocked, board and metrics like a labeled
+one, and sync keeps the issue blocked until it is unblocked here.
+
+Examples:
+  kanban issue 42 --org myorg --repo myrepo
+  kanban issue 42 --org myorg --repo myrepo --live
+  kanban issue 42 --org myorg --repo myrepo --format json
+  kanban issue 42 block --reason "waiting on API" --org myorg --repo myrepo
+  kanban issue 42 unblock --org myorg --repo myrepo`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.RangeArgs(1, 2)(cmd, args); err != nil {
+			return err
+		}
+		if len(args) == 2 && args[1] != "block" && args[1] != "unblock" {
+			return fmt.Errorf("unknown action %q (use block or unblock)", args[1])
+		}
+		return nil
+	},
+	RunE: runIssue,
+}
+
+func init() {
+	rootCmd.AddCommand(issueCmd)
+	issueCmd.Flags().StringVarP(&repo, "repo", "r", "", "repository")
+	issueCmd.Flags().BoolVar(&liveMode, "live", false, "fetch the issue and its timeline from GitHub")
+	issueCmd.Flags().StringVarP(&format, "format", "f", "table", "output format (table|json)")
+	issueCmd.Flags().StringVar(&blockReason, "reason", "", "why the issue is blocked (with block)")
+}
+
+// IssueReport is everything known about one issue's flow
+type IssueReport struct {
+	Repo      string     `json:"repo"`
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	State     string     `json:"state"`
+	Status    string     `json:"status,omitempty"`
+	Priority  string     `json:"priority,omitempty"`
+	Type      string     `json:"type,omitempty"`
+	Size      string     `json:"size,omitempty"`
+	Assignee  string     `json:"assignee,omitempty"`
+	IsBlocked bool       `json:"is_blocked"`
+	CreatedAt time.Time  `json:"created_at"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`
+	Source    string     `json:"source"` // cache or live
+
+	LeadTimeHours    float64 `json:"lead_time_hours,omitempty"`
+	CycleTimeHours   float64 `json:"cycle_time_hours,omitempty"`
+	BlockedTimeHours float64 `json:"blocked_time_hours,omitempty"`
+
+	Transitions    []IssueTransition  `json:"transitions"`
+	HoursInStatus  map[string]float64 `json:"hours_in_status"`
+	BlockedPeriods []db.BlockedPeriod `json:"blocked_periods"`
+	PullRequests   []db.PullRequest   `json:"pull_requests"`
+}
+
+// IssueTransition is a status change and how long the issue then stayed in
+// the new status (until the next change, closing, or now)
+type IssueTransition struct {
+	From  string    `json:"from,omitempty"`
+	To    string    `json:"to"`
+	At    time.Time `json:"at"`
+	Hours float64   `json:"hours"`
+}
+
+func runIssue(cmd *cobra.Command, args []string) error {
+	number, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+	if err != nil {
+		return fmt.Errorf("invalid issue number %q", args[0])
+	}
+
+	orgs, err := resolveOrganizations()
+	if err != nil {
+		return err
+	}
+	if repo == "" {
+		return fmt.Errorf("--repo required")
+	}
+
+	// An issue lives in one repo: --repo must pick out a single organization
+	var organization, repoName string
+	for _, o := range orgs {
+		name, ok := repoForOrg(o)
+		if !ok {
+			continue
+		}
+		if organization != "" {
+			return fmt.Errorf("--repo %s matches more than one organization: use --org or --repo org/%s", repo, repo)
+		}
+		organization, repoName = o, name
+	}
+	if organization == "" {
+		return fmt.Errorf("--repo %s is not in %s", repo, strings.Join(orgs, ", "))
+	}
+	fullName := fmt.Sprintf("%s/%s", organization, repoName)
+
+	if len(args) == 2 {
+		if liveMode {
+			return fmt.Errorf("%s records to the cache; it can't be used with --live", args[1])
+		}
+		if blockReason != "" && args[1] != "block" {
+			return fmt.Errorf("--reason is only used with block")
+		}
+		return runIssueBlock(fullName, number, args[1] == "block")
+	}
+	if blockReason != "" {
+		return fmt.Errorf("--reason is only used with block")
+	}
+
+	var report *IssueReport
+	if liveMode {
+		report, err = issueReportLive(organization, repoName, number)
+	} else {
+		report, err = issueReportCached(fullName, number)
+	}
+	if err != nil {
+		return err
+	}
+
+	w, closeOutput, err := openOutput()
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		output, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Fprintln(w, string(output))
+		return closeOutput()
+	}
+
+	var settings config.Settings
+	if cfg, _ := config.Load(); cfg != nil {
+		settings = cfg.Settings
+	}
+	printIssueReport(w, report, settings.Statuses())
+	return closeOutput()
+}
+
+// runIssueBlock starts (block) or ends a manual blocked period on a cached issue
+func runIssueBlock(fullName string, number int, block bool) error {
+	database, err := db.Open(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w (run 'kanban sync' first)", err)
+	}
+	defer database.Close()
+
+	repoID, err := database.GetRepoID(fullName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s is not cached (run 'kanban sync' first)", fullName)
+	} else if err != nil {
+		return err
+	}
+	issue, err := database.GetIssueByRepoAndNumber(repoID, number)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s#%d is not cached (run 'kanban sync' first)", fullName, number)
+	} else if err != nil {
+		return fmt.Errorf("failed to get issue: %w", err)
+	}
+
+	if dryRun {
+		action := "unblock"
+		if block {
+			action = "block"
+		}
+		fmt.Printf("Would %s %s#%d\n", action, fullName, number)
+		return nil
+	}
+
+	now := time.Now()
+	if !block {
+		if err := database.UnblockIssue(issue.ID, now); errors.Is(err, db.ErrNotBlocked) {
+			return fmt.Errorf("%s#%d has no manual block to end (blocked labels are removed on GitHub)", fullName, number)
+		} else if err != nil {
+			return fmt.Errorf("failed to unblock issue: %w", err)
+		}
+		fmt.Printf("✓ Unblocked %s#%d\n", fullName, number)
+		return nil
+	}
+
+	if err := database.BlockIssue(issue.ID, now, blockReason); errors.Is(err, db.ErrAlreadyBlocked) {
+		return fmt.Errorf("%s#%d is already blocked (unblock it first)", fullName, number)
+	} else if err != nil {
+		return fmt.Errorf("failed to block issue: %w", err)
+	}
+	if blockReason != "" {
+		fmt.Printf("✓ Blocked %s#%d: %s\n", fullName, number, blockReason)
+	} else {
+		fmt.Printf("✓ Blocked %s#%d\n", fullName, number)
+	}
+	return nil
+}
+
+// issueReportCached builds the report from the cache
+func issueReportCached(fullName string, number int) (*IssueReport, error) {
+	database, err := db.Open(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w (run 'kanban sync' first)", err)
+	}
+	defer database.Close()
+
+	repoID, err := database.GetRepoID(fullName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%s is not cached (run 'kanban sync' first or use --live)", fullName)
+	} else if err != nil {
+		return nil, err
+	}
+
+	issue, err := database.GetIssueByRepoAndNumber(repoID, number)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%s#%d is not cached (run 'kanban sync' or use --live)", fullName, number)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get issue: %w", err)
+	}
+
+	report := newIssueReport(fullName, issue, "cache")
+
+	transitions, err := database.GetStatusTransitions(issue.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status transitions: %w", err)
+	}
+	for _, t := range transitions {
+		report.Transitions = append(report.Transitions, IssueTransition{From: t.FromStatus, To: t.ToStatus, At: t.TransitionedAt})
+	}
+
+	if report.BlockedPeriods, err = database.GetBlockedPeriods(issue.ID); err != nil {
+		return nil, fmt.Errorf("failed to get blocked periods: %w", err)
+	}
+	if report.PullRequests, err = database.GetPRsForIssue(repoID, number); err != nil {
+		return nil, fmt.Errorf("failed to get linked pull requests: %w", err)
+	}
+
+	report.finish()
+	return report, nil
+}
+
+// issueReportLive builds the report from the issue and its timeline on
+// GitHub. Linked PRs come from the cache when there is one.
+func issueReportLive(organization, repoName string, number int) (*IssueReport, error) {
+	client := newGitHubClient(timeoutCtx)
+	fullName := fmt.Sprintf("%s/%s", organization, repoName)
+
+	details, err := client.GetIssueDetails(organization, repoName, number)
+	if err != nil {
+		return nil, err
+	}
+	timeline, err := client.GetIssueTimeline(organization, repoName, number)
+	if err != nil {
+		return nil, err
+	}
+
+	delimiter := labelDelimiter()
+	issue := buildDBIssue(0, fullName, *details, nil, delimiter)
+	issue.BlockedTimeHours = timeline.TotalBlocked
+	report := newIssueReport(fullName, issue, "live")
+
+	// Status labels added, in order; moving columns adds the new label
+	status := ""
+	for _, e := range timeline.Events {
+		next, ok := config.LabelValue(e.Label, "status", delimiter)
+		if e.Event != "labeled" || !ok {
+			continue
+		}
+		report.Transitions = append(report.Transitions, IssueTransition{From: status, To: next, At: e.CreatedAt})
+		status = next
+	}
+
+	for _, bp := range timeline.BlockedPeriods {
+		period := db.BlockedPeriod{BlockedAt: bp.Start, DurationHours: bp.Duration, Reason: bp.Reason}
+		if !bp.End.IsZero() {
+			end := bp.End
+			period.UnblockedAt = &end
+		}
+		report.BlockedPeriods = append(report.BlockedPeriods, period)
+	}
+
+	// Cycle time from the first entry into the active start status
+	activeStart := config.DefaultActiveStartStatus
+	if cfg, _ := config.Load(); cfg != nil {
+		activeStart = cfg.Settings.ActiveStart()
+	}
+	if started, ok := timeline.StatusChanges[activeStart]; ok && report.ClosedAt != nil {
+		report.CycleTimeHours = report.ClosedAt.Sub(started).Hours()
+	}
+
+	if database, err := db.Open(dbPath); err == nil {
+		if repoID, err := database.GetRepoID(fullName); err == nil {
+			report.PullRequests, _ = database.GetPRsForIssue(repoID, number)
+		}
+		database.Close()
+	}
+
+	report.finish()
+	return report, nil
+}
+
+func newIssueReport(fullName string, issue *db.Issue, source string) *IssueReport {
+	return &IssueReport{
+		Repo:             fullName,
+		Number:           issue.Number,
+		Title:            issue.Title,
+		State:            issue.State,
+		Status:           issue.CurrentStatus,
+		Priority:         issue.CurrentPriority,
+		Type:             issue.CurrentType,
+		Size:             issue.CurrentSize,
+		Assignee:         issue.Assignee,
+		IsBlocked:        issue.IsBlocked,
+		CreatedAt:        issue.GHCreatedAt,
+		ClosedAt:         issue.GHClosedAt,
+		Source:           source,
+		LeadTimeHours:    issue.LeadTimeHours,
+		CycleTimeHours:   issue.CycleTimeHours,
+		BlockedTimeHours: issue.BlockedTimeHours,
+	}
+}
+
+// finish works out how long each transition's status lasted, the totals per
+// status, and defaults empty lists for JSON
+func (r *IssueReport) finish() {
+	end := time.Now()
+	if r.ClosedAt != nil {
+		end = *r.ClosedAt
+	}
+	if r.LeadTimeHours == 0 && r.ClosedAt != nil {
+		r.LeadTimeHours = r.ClosedAt.Sub(r.CreatedAt).Hours()
+	}
+
+	sort.SliceStable(r.Transitions, func(i, j int) bool { return r.Transitions[i].At.Before(r.Transitions[j].At) })
+	r.HoursInStatus = make(map[string]float64)
+	for i := range r.Transitions {
+		until := end
+		if i+1 < len(r.Transitions) {
+			until = r.Transitions[i+1].At
+		}
+		if hours := until.Sub(r.Transitions[i].At).Hours(); hours > 0 {
+			r.Transitions[i].Hours = hours
+			r.HoursInStatus[r.Transitions[i].To] += hours
+		}
+	}
+
+	if r.Transitions == nil {
+		r.Transitions = []IssueTransition{}
+	}
+	if r.BlockedPeriods == nil {
+		r.BlockedPeriods = []db.BlockedPeriod{}
+	}
+	if r.PullRequests == nil {
+		r.PullRequests = []db.PullRequest{}
+	}
+}
+
+// issueEvent is one line of the rendered timeline
+type issueEvent struct {
+	at    time.Time
+	text  string
+	color string
+}
+
+// printIssueReport prints the report, with time in status in workflow order
+func printIssueReport(w io.Writer, r *IssueReport, workflow []string) {
+	reset := "\033[0m"
+	bold := "\033[1m"
+	dim := "\033[90m"
+	red := "\033[31m"
+	green := "\033[32m"
+	purple := "\033[35m"
+	cyan := "\033[36m"
+
+	fmt.Fprintf(w, "\n%s#%d %s%s\n", bold, r.Number, displayTitle(r.Title), reset)
+
+	fields := []string{r.Repo, r.State}
+	if r.Status != "" {
+		fields = append(fields, statusColor(r.Status)+r.Status+reset)
+	}
+	if r.Assignee != "" {
+		fields = append(fields, cyan+"@"+r.Assignee+reset)
+	}
+	for _, f := range []struct{ name, value string }{{"priority", r.Priority}, {"type", r.Type}, {"size", r.Size}} {
+		if f.value != "" {
+			fields = append(fields, f.name+": "+f.value)
+		}
+	}
+	if r.IsBlocked {
+		fields = append(fields, red+"blocked"+reset)
+	}
+	fmt.Fprintf(w, "%s\n", strings.Join(fields, " · "))
+
+	hours := func(h float64) string {
+		if h <= 0 {
+			return "-"
+		}
+		return formatAge(h)
+	}
+	fmt.Fprintf(w, "%sLead time %s · cycle time %s · blocked %s · from %s%s\n",
+		dim, hours(r.LeadTimeHours), hours(r.CycleTimeHours), hours(r.BlockedTimeHours), r.Source, reset)
+
+	// One chronological timeline of transitions, blocked periods and PRs
+	events := []issueEvent{{at: r.CreatedAt, text: "created"}}
+	for _, t := range r.Transitions {
+		move := statusColor(t.To) + t.To + reset
+		if t.From != "" {
+			move = t.From + " → " + move
+		}
+		if t.Hours > 0 {
+			move += fmt.Sprintf("  %s(%s)%s", dim, formatAge(t.Hours), reset)
+		}
+		events = append(events, issueEvent{at: t.At, text: move})
+	}
+	for _, bp := range r.BlockedPeriods {
+		text := "blocked"
+		if bp.Manual {
+			text += " (manual)"
+		}
+		if bp.Reason != "" {
+			text += ": " + bp.Reason
+		}
+		events = append(events, issueEvent{at: bp.BlockedAt, text: text, color: red})
+		if bp.UnblockedAt != nil {
+			events = append(events, issueEvent{at: *bp.UnblockedAt, text: fmt.Sprintf("unblocked after %s", formatAge(bp.DurationHours)), color: green})
+		}
+	}
+	for _, pr := range r.PullRequests {
+		events = append(events, issueEvent{at: pr.GHCreatedAt, text: fmt.Sprintf("PR #%d opened: %s", pr.Number, truncate(displayTitle(pr.Title), 50)), color: purple})
+		if pr.GHMergedAt != nil {
+			events = append(events, issueEvent{at: *pr.GHMergedAt, text: fmt.Sprintf("PR #%d merged", pr.Number), color: purple})
+		}
+	}
+	if r.ClosedAt != nil {
+		events = append(events, issueEvent{at: *r.ClosedAt, text: "closed", color: green})
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].at.Before(events[j].at) })
+
+	fmt.Fprintf(w, "\n%sTimeline%s\n", bold, reset)
+	for i, e := range events {
+		color := e.color
+		if color == "" {
+			color = reset
+		}
+		fmt.Fprintf(w, "  %s*%s %s%s%s  %s%s%s\n", color, reset, dim, e.at.Local().Format("2006-01-02 15:04"), reset, color, e.text, reset)
+		if i < len(events)-1 {
+			fmt.Fprintf(w, "  %s|%s\n", dim, reset)
+		}
+	}
+	if r.ClosedAt == nil && r.Status != "" && len(r.Transitions) > 0 {
+		fmt.Fprintf(w, "  %s|%s\n  * %s%-16s%s  in %s for %s\n",
+			dim, reset, dim, "now", reset, r.Status, formatAge(r.Transitions[len(r.Transitions)-1].Hours))
+	}
+
+	if len(r.HoursInStatus) > 0 {
+		fmt.Fprintf(w, "\n%sTime in status%s\n", bold, reset)
+		var statuses []string
+		for status := range r.HoursInStatus {
+			statuses = append(statuses, status)
+		}
+		sortByWorkflow(statuses, workflow)
+		for _, status := range statuses {
+			fmt.Fprintf(w, "  %s%-14s%s %s\n", statusColor(status), status, reset, formatAge(r.HoursInStatus[status]))
+		}
+	}
+	fmt.Fprintln(w)
+}
@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 
 	"github.com/kiracore/kanban/internal/config"
+	"github.com/kiracore/kanban/internal/db"
 	"github.com/kiracore/kanban/internal/github"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -14,9 +16,12 @@ import (
 
 var (
 	repo             string
+	repoList         string
 	allRepos         bool
 	labelsFormat     string
 	labelsOutputFile string
+	labelsWithCounts bool
+	labelsSort       string
 )
 
 var labelsCmd = &cobra.Command{
@@ -28,7 +33,19 @@ var labelsCmd = &cobra.Command{
 var labelsListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List labels in repositories",
-	RunE:  runLabelsList,
+	Long: `List the labels of one or all repositories.
+
+With --with-counts each label shows how many issues, open or closed, carry
+it. Counts come from the local cache once 'kanban sync' has recorded issue
+labels (run 'kanban sync --full' once so every cached issue has them);
+otherwise each label is counted through GitHub's search API, which allows
+30 requests a minute. --sort usage lists the most used labels first.
+
+Examples:
+  kanban labels list --repo myrepo
+  kanban labels list --repo myrepo --with-counts
+  kanban labels list --all --sort usage`,
+	RunE: runLabelsList,
 }
 
 var labelsExportCmd = &cobra.Command{
@@ -54,6 +71,10 @@ func init() {
 	labelsCmd.PersistentFlags().StringVarP(&repo, "repo", "r", "", "specific repository")
 	labelsCmd.PersistentFlags().BoolVar(&allRepos, "all", false, "apply to all repositories")
 
+	// List specific flags
+	labelsListCmd.Flags().BoolVar(&labelsWithCounts, "with-counts", false, "show how many issues use each label")
+	labelsListCmd.Flags().StringVar(&labelsSort, "sort", "", "sort labels by name or usage (usage implies --with-counts)")
+
 	// Export specific flags
 	labelsExportCmd.Flags().StringVarP(&labelsFormat, "format", "f", "yaml", "output format (yaml|json)")
 	labelsExportCmd.Flags().StringVar(&labelsOutputFile, "output", "", "output file (default stdout)")
@@ -68,8 +89,38 @@ func runLabelsList(cmd *cobra.Command, args []string) error {
 	if organization == "" {
 		return fmt.Errorf("organization required: use --org flag or set in config")
 	}
+	switch labelsSort {
+	case "", "name":
+	case "usage":
+		labelsWithCounts = true
+	default:
+		return fmt.Errorf("invalid --sort %q: use name or usage", labelsSort)
+	}
+
+	client := newGitHubClient(timeoutCtx)
+
+	// Cached counts are preferred; without a database every count goes to GitHub
+	var database *db.DB
+	if labelsWithCounts {
+		if d, err := db.OpenReadOnly(dbPath); err == nil {
+			database = d
+			defer database.Close()
+		}
+	}
 
-	client := github.NewClient()
+	list := func(repoName string, labels []config.Label) {
+		var counts map[string]int
+		if labelsWithCounts {
+			var err error
+			counts, err = labelUsage(database, client, organization, repoName, labels)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to count label usage for %s: %v\n", repoName, err)
+				counts = nil
+			}
+		}
+		sortLabels(labels, counts)
+		printLabels(organization, repoName, labels, counts)
+	}
 
 	if repo != "" {
 		// List labels for specific repo
@@ -77,7 +128,7 @@ func runLabelsList(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
-		printLabels(organization, repo, labels)
+		list(repo, labels)
 	} else if allRepos {
 		// List labels for all repos
 		repos, err := client.ListRepos(organization)
@@ -90,7 +141,7 @@ func runLabelsList(cmd *cobra.Command, args []string) error {
 				fmt.Fprintf(os.Stderr, "Warning: failed to list labels for %s: %v\n", r, err)
 				continue
 			}
-			printLabels(organization, r, labels)
+			list(r, labels)
 		}
 	} else {
 		return fmt.Errorf("specify --repo or --all")
@@ -113,7 +164,7 @@ func runLabelsExport(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("repository required: use --repo flag")
 	}
 
-	client := github.NewClient()
+	client := newGitHubClient(timeoutCtx)
 	labels, err := client.ListLabels(organization, repo)
 	if err != nil {
 		return err
@@ -162,7 +213,7 @@ func runLabelsImport(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := github.NewClient()
+	client := newGitHubClient(timeoutCtx)
 	labels := cfg.AllLabels()
 
 	if dryRun {
@@ -195,9 +246,54 @@ func runLabelsImport(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func printLabels(org, repo string, labels []config.Label) {
+// labelUsage counts the issues carrying each label, from the cache when
+// sync has recorded issue labels for the repo, else from GitHub
+func labelUsage(database *db.DB, client *github.Client, org, repoName string, labels []config.Label) (map[string]int, error) {
+	if database != nil {
+		if repoID, err := database.GetRepoID(fmt.Sprintf("%s/%s", org, repoName)); err == nil {
+			if usage, err := database.GetLabelUsage(repoID); err == nil && len(usage) > 0 {
+				return usage, nil
+			}
+		}
+	}
+
+	usage := make(map[string]int, len(labels))
+	for _, l := range labels {
+		n, err := client.CountIssuesWithLabel(org, repoName, l.Name)
+		if err != nil {
+			return nil, err
+		}
+		usage[l.Name] = n
+	}
+	return usage, nil
+}
+
+// sortLabels orders labels for --sort: most used first (then by name) for
+// usage, by name for name, and as GitHub listed them otherwise
+func sortLabels(labels []config.Label, counts map[string]int) {
+	switch labelsSort {
+	case "name":
+		sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+	case "usage":
+		sort.Slice(labels, func(i, j int) bool {
+			if counts[labels[i].Name] != counts[labels[j].Name] {
+				return counts[labels[i].Name] > counts[labels[j].Name]
+			}
+			return labels[i].Name < labels[j].Name
+		})
+	}
+}
+
+// printLabels lists a repo's labels, with their issue counts when counts
+// isn't nil
+func printLabels(org, repo string, labels []config.Label, counts map[string]int) {
 	fmt.Printf("\n%s/%s (%d labels):\n", org, repo, len(labels))
 	for _, l := range labels {
+		if counts != nil {
+			name := fmt.Sprintf("%s (%d issues)", l.Name, counts[l.Name])
+			fmt.Printf("  - %-42s #%s  %s\n", name, l.Color, l.Description)
+			continue
+		}
 		fmt.Printf("  - %-30s #%s  %s\n", l.Name, l.Color, l.Description)
 	}
 }
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kiracore/kanban/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var labelsDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show color and description differences between config and GitHub labels",
+	Long: `For each label whose color or description differs from the config, show
+the live value next to the config value, with colors as swatches. Labels
+missing from the repo are reported by 'kanban audit'.
+
+Examples:
+  kanban labels diff --org myorg --repo myrepo
+  kanban labels diff --org myorg --all --format json`,
+	RunE: runLabelsDiff,
+}
+
+func init() {
+	labelsCmd.AddCommand(labelsDiffCmd)
+	labelsDiffCmd.Flags().StringVarP(&format, "format", "f", "table", "output format (table|json)")
+}
+
+// LabelDiff is one field of a label that differs between config and GitHub
+type LabelDiff struct {
+	Repo        string `json:"repo"`
+	Name        string `json:"name"`
+	Field       string `json:"field"` // color or description
+	ConfigValue string `json:"config_value"`
+	LiveValue   string `json:"live_value"`
+}
+
+func runLabelsDiff(cmd *cobra.Command, args []string) error {
+	orgs, err := resolveOrganizations()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	client := newGitHubClient(timeoutCtx)
+
+	expected := cfg.AllLabels()
+	diffs := []LabelDiff{}
+	var compared []string
+	for _, organization := range orgs {
+		repos, ok, err := resolveRepos(cfg, client, organization)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		for _, r := range repos {
+			fullName := organization + "/" + r
+			live, err := client.ListLabels(organization, r)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to list labels for %s: %v\n", fullName, err)
+				continue
+			}
+			diffs = append(diffs, diffLabels(displayRepo(organization, fullName), expected, live)...)
+			compared = append(compared, fullName)
+		}
+	}
+
+	w, closeOutput, err := openOutput()
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		output, _ := json.MarshalIndent(diffs, "", "  ")
+		fmt.Fprintln(w, string(output))
+		return closeOutput()
+	}
+
+	printLabelDiffs(w, compared, diffs)
+	return closeOutput()
+}
+
+// diffLabels compares the config labels present in a repo with their live
+// versions, sorted by label name. Colors compare case-insensitively.
+func diffLabels(repoName string, expected, live []config.Label) []LabelDiff {
+	liveMap := make(map[string]config.Label)
+	for _, l := range live {
+		liveMap[l.Name] = l
+	}
+
+	sorted := append([]config.Label(nil), expected...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	var diffs []LabelDiff
+	for _, want := range sorted {
+		got, ok := liveMap[want.Name]
+		if !ok {
+			continue
+		}
+		if !strings.EqualFold(got.Color, want.Color) {
+			diffs = append(diffs, LabelDiff{Repo: repoName, Name: want.Name, Field: "color", ConfigValue: want.Color, LiveValue: got.Color})
+		}
+		if got.Description != want.Description {
+			diffs = append(diffs, LabelDiff{Repo: repoName, Name: want.Name, Field: "description", ConfigValue: want.Description, LiveValue: got.Description})
+		}
+	}
+	return diffs
+}
+
+// colorSwatch renders a hex color as a truecolor block, or blanks if the
+// value isn't a valid color
+func colorSwatch(hex string) string {
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 6 {
+		return "  "
+	}
+	return fmt.Sprintf("\033[48;2;%d;%d;%dm  \033[0m", v>>16, v>>8&0xff, v&0xff)
+}
+
+// printLabelDiffs prints the diffs of each compared repo, given by full name
+func printLabelDiffs(w io.Writer, repos []string, diffs []LabelDiff) {
+	reset := "\033[0m"
+	bold := "\033[1m"
+	dim := "\033[90m"
+	red := "\033[31m"
+	green := "\033[32m"
+
+	byRepo := make(map[string][]LabelDiff)
+	for _, d := range diffs {
+		byRepo[d.Repo] = append(byRepo[d.Repo], d)
+	}
+
+	for _, fullName := range repos {
+		fmt.Fprintf(w, "\n%s%s%s\n", bold, fullName, reset)
+		organization, _, _ := strings.Cut(fullName, "/")
+		repoDiffs := byRepo[displayRepo(organization, fullName)]
+		if len(repoDiffs) == 0 {
+			fmt.Fprintln(w, "  ✓ Colors and descriptions match config")
+			continue
+		}
+
+		fmt.Fprintf(w, "  %s%-30s %-12s %-34s %s%s\n", dim, "LABEL", "FIELD", "LIVE", "CONFIG", reset)
+		lastName := ""
+		for _, d := range repoDiffs {
+			name := d.Name
+			if name == lastName {
+				name = ""
+			}
+			lastName = d.Name
+
+			if d.Field == "color" {
+				fmt.Fprintf(w, "  %-30s %-12s %s %s#%-30s%s %s %s#%s%s\n",
+					truncate(name, 30), d.Field,
+					colorSwatch(d.LiveValue), red, d.LiveValue, reset,
+					colorSwatch(d.ConfigValue), green, d.ConfigValue, reset)
+				continue
+			}
+			fmt.Fprintf(w, "  %-30s %-12s %s%-34s%s %s%s%s\n",
+				truncate(name, 30), d.Field,
+				red, truncate(strconv.Quote(d.LiveValue), 34), reset,
+				green, strconv.Quote(d.ConfigValue), reset)
+		}
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/kiracore/kanban/internal/config"
+	"github.com/kiracore/kanban/internal/db"
+	"github.com/kiracore/kanban/internal/github"
+)
+
+// labelPrune is what sync --prune-labels does to one repository: labels not
+// in the config to delete, and ones kept because open issues still use them
+type labelPrune struct {
+	repo   string
+	delete []string
+	inUse  []string
+}
+
+// pruneRepoLabels deletes the labels of repos that aren't in labels, after
+// listing them and asking for confirmation unless --yes was given
+func pruneRepoLabels(organization string, repos []string, labels []config.Label, settings config.Settings,
+	database *db.DB, client *github.Client) error {
+	fmt.Println("\nPruning labels not in config...")
+
+	var plans []labelPrune
+	total := 0
+	for _, repoName := range repos {
+		plan, err := planLabelPrune(client, organization, repoName, labels, settings)
+		if err != nil {
+			return err
+		}
+		if len(plan.delete)+len(plan.inUse) == 0 {
+			continue
+		}
+		plans = append(plans, plan)
+		total += len(plan.delete)
+	}
+
+	for _, plan := range plans {
+		fmt.Printf("\n%s/%s:\n", organization, plan.repo)
+		for _, name := range plan.delete {
+			fmt.Printf("  - %s\n", name)
+		}
+		for _, name := range plan.inUse {
+			fmt.Printf("  = %s (kept: on open issues)\n", name)
+		}
+	}
+
+	if total == 0 {
+		fmt.Println("  No labels to prune")
+		return nil
+	}
+	if dryRun {
+		fmt.Printf("\nWould delete %d labels from %d repositories\n", total, len(plans))
+		return nil
+	}
+	if !confirm(fmt.Sprintf("Delete %d labels from %d repositories?", total, len(plans))) {
+		return fmt.Errorf("label prune aborted")
+	}
+
+	// One failed delete doesn't stop the rest
+	deleted, failed := 0, 0
+	for _, plan := range plans {
+		repoID, _ := database.GetRepoID(fmt.Sprintf("%s/%s", organization, plan.repo))
+		for _, name := range plan.delete {
+			if err := client.DeleteLabel(organization, plan.repo, name, false); err != nil {
+				fmt.Fprintf(os.Stderr, "  Warning: failed to delete %s from %s: %v\n", name, plan.repo, err)
+				failed++
+				continue
+			}
+			if repoID > 0 {
+				if err := database.DeleteLabel(repoID, name); err != nil {
+					fmt.Fprintf(os.Stderr, "  Warning: failed to uncache %s of %s: %v\n", name, plan.repo, err)
+				}
+			}
+			deleted++
+		}
+	}
+
+	fmt.Printf("✓ Deleted %d labels\n", deleted)
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d labels", failed)
+	}
+	return nil
+}
+
+// planLabelPrune finds the labels of repoName that are neither in labels
+// nor matched by settings.preserve_labels. Status and priority labels that
+// open issues still carry are kept, since deleting them would move those
+// issues off their column or priority.
+func planLabelPrune(client *github.Client, organization, repoName string, labels []config.Label,
+	settings config.Settings) (labelPrune, error) {
+	plan := labelPrune{repo: repoName}
+
+	current, err := client.ListLabels(organization, repoName)
+	if err != nil {
+		return plan, fmt.Errorf("failed to list labels of %s: %w", repoName, err)
+	}
+
+	// GitHub label names are case-insensitive
+	configured := make(map[string]bool, len(labels))
+	for _, l := range labels {
+		configured[strings.ToLower(l.Name)] = true
+	}
+
+	var extra []string
+	for _, l := range current {
+		if !configured[strings.ToLower(l.Name)] && !settings.IsPreservedLabel(l.Name) {
+			extra = append(extra, l.Name)
+		}
+	}
+	if len(extra) == 0 {
+		return plan, nil
+	}
+	sort.Strings(extra)
+
+	var openCounts map[string]int
+	for _, name := range extra {
+		if !isWorkflowLabel(name, settings) {
+			plan.delete = append(plan.delete, name)
+			continue
+		}
+		if openCounts == nil {
+			if openCounts, err = client.OpenIssueLabelCounts(organization, repoName); err != nil {
+				return plan, fmt.Errorf("failed to check label use in %s: %w", repoName, err)
+			}
+		}
+		if openCounts[strings.ToLower(name)] > 0 {
+			plan.inUse = append(plan.inUse, name)
+		} else {
+			plan.delete = append(plan.delete, name)
+		}
+	}
+	return plan, nil
+}
+
+// isWorkflowLabel reports whether name is a status or priority label,
+// including status_label_aliases
+func isWorkflowLabel(name string, settings config.Settings) bool {
+	delimiter := settings.Delimiter()
+	if _, ok := config.LabelValue(name, "status", delimiter); ok {
+		return true
+	}
+	if _, ok := config.LabelValue(name, "priority", delimiter); ok {
+		return true
+	}
+	for _, aliases := range settings.StatusLabelAliases {
+		if hasLabelInList(aliases, name) {
+			return true
+		}
+	}
+	return false
+}
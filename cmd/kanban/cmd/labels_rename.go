@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kiracore/kanban/internal/config"
+	"github.com/kiracore/kanban/internal/db"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var labelsRenameCmd = &cobra.Command{
+	Use:   "rename",
+	Short: "Rename a label, keeping it on its issues",
+	Long: `Rename a label in place on GitHub. Issues carrying it keep it under the
+new name, in one call per repository instead of one per issue.
+
+Where the repo already has a label named --to, the two can't be merged by
+a rename, so the issues are moved over as 'kanban migrate' does and the old
+label is left for you to delete. Repos without the --from label are skipped.
+
+Rename the label in the config too, or 'kanban sync' will create it again.
+
+Examples:
+  kanban labels rename --from bug --to "type: bug" --repo myrepo
+  kanban labels rename --from enhancement --to "type: feature" --all --dry-run`,
+	RunE: runLabelsRename,
+}
+
+func init() {
+	labelsCmd.AddCommand(labelsRenameCmd)
+	labelsRenameCmd.Flags().StringVar(&fromLabel, "from", "", "current label name")
+	labelsRenameCmd.Flags().StringVar(&toLabel, "to", "", "new label name")
+}
+
+func runLabelsRename(cmd *cobra.Command, args []string) error {
+	organization := viper.GetString("organization")
+	if organization == "" && org != "" {
+		organization = org
+	}
+
+	if organization == "" {
+		return fmt.Errorf("organization required: use --org flag or set in config")
+	}
+	from, to := strings.TrimSpace(fromLabel), strings.TrimSpace(toLabel)
+	if from == "" || to == "" {
+		return fmt.Errorf("both --from and --to are required")
+	}
+	if from == to {
+		return fmt.Errorf("--from and --to are the same label")
+	}
+
+	cfg, _ := config.Load()
+	client := newGitHubClient(timeoutCtx)
+
+	repos, _, err := resolveRepos(cfg, client, organization)
+	if err != nil {
+		return err
+	}
+	if len(repos) == 0 {
+		return fmt.Errorf("no repositories to rename labels in")
+	}
+
+	if dryRun {
+		fmt.Println("[DRY RUN - no changes will be made]")
+	}
+
+	// The cache follows renames so cached label counts stay right; without
+	// a database there is nothing to update, and none is created
+	var database *db.DB
+	path := dbPath
+	if path == "" {
+		path = db.DefaultDBPath()
+	}
+	if _, err := os.Stat(path); err == nil && !dryRun {
+		if d, err := db.Open(path); err == nil {
+			database = d
+			defer database.Close()
+		}
+	}
+
+	var errors []string
+	renamed, migrated := 0, 0
+	for _, r := range repos {
+		fmt.Printf("\n%s/%s:\n", organization, r)
+
+		labels, err := client.ListLabels(organization, r)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", r, err))
+			fmt.Printf("  Error listing labels: %v\n", err)
+			continue
+		}
+
+		// GitHub label names are case-insensitive, so "Bug" -> "bug" is a rename
+		var hasFrom, hasTo bool
+		for _, l := range labels {
+			hasFrom = hasFrom || strings.EqualFold(l.Name, from)
+			hasTo = hasTo || (strings.EqualFold(l.Name, to) && !strings.EqualFold(l.Name, from))
+		}
+
+		switch {
+		case !hasFrom:
+			fmt.Printf("  No label %q, skipped\n", from)
+		case hasTo:
+			fmt.Printf("  %q already exists; moving issues over instead\n", to)
+			count, err := client.MigrateIssueLabels(organization, r, from, to, dryRun)
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("%s: %s->%s: %v", r, from, to, err))
+				fmt.Printf("  Error migrating %s -> %s: %v\n", from, to, err)
+				continue
+			}
+			fmt.Printf("  %s -> %s: %d issue(s); %q was kept\n", from, to, count, from)
+			migrated += count
+		default:
+			if err := client.RenameLabel(organization, r, from, to, dryRun); err != nil {
+				errors = append(errors, fmt.Sprintf("%s: %s->%s: %v", r, from, to, err))
+				fmt.Printf("  Error renaming %s -> %s: %v\n", from, to, err)
+				continue
+			}
+			renamed++
+			if dryRun {
+				continue
+			}
+			fmt.Printf("  ✓ Renamed %s -> %s\n", from, to)
+			if database != nil {
+				if repoID, err := database.GetRepoID(fmt.Sprintf("%s/%s", organization, r)); err == nil {
+					if err := database.RenameLabel(repoID, from, to, config.LabelCategory(to)); err != nil {
+						fmt.Fprintf(os.Stderr, "  Warning: failed to rename cached label: %v\n", err)
+					}
+				}
+			}
+		}
+	}
+
+	if dryRun {
+		fmt.Printf("\nWould rename the label in %d repositories and move %d issue(s)\n", renamed, migrated)
+	} else {
+		fmt.Printf("\nRenamed the label in %d repositories, moved %d issue(s)\n", renamed, migrated)
+	}
+
+	if cfg != nil {
+		for _, l := range cfg.AllLabels() {
+			if strings.EqualFold(l.Name, from) {
+				fmt.Printf("Note: %q is still in the config; rename it there too or 'kanban sync' will recreate it\n", from)
+				break
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		fmt.Fprintf(os.Stderr, "\nCompleted with %d error(s):\n", len(errors))
+		for _, e := range errors {
+			fmt.Fprintf(os.Stderr, "  - %s\n", e)
+		}
+		return fmt.Errorf("rename completed with errors")
+	}
+	return nil
+}
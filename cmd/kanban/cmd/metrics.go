@@ -1,18 +1,22 @@
 package cmd
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math"
+	"os"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/kiracore/kanban/internal/config"
 	"github.com/kiracore/kanban/internal/db"
 	"github.com/kiracore/kanban/internal/github"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var days int
@@ -24,7 +28,7 @@ var metricsCmd = &cobra.Command{
 
 FLOW METRICS:
   - Lead Time: Time from creation to completion
-  - Cycle Time: Time from in-progress to completion
+  - Cycle Time: Time from in-progress (settings.active_start_status) to completion
   - Throughput: Items completed per time period
   - Flow Efficiency: Active time vs total time
 
@@ -36,6 +40,9 @@ WIP METRICS:
 RATE METRICS:
   - Arrival Rate: New items entering per period
   - Departure Rate: Items completed per period
+  - Daily Closed: Sparkline of items completed per day (cached mode)
+  - Velocity: Story points completed per week, from size labels and
+    settings.size_points (cached mode)
   - Blocked Time: Time items spent blocked
 
 DISTRIBUTION:
@@ -54,26 +61,91 @@ Examples:
   kanban metrics --org myorg --repo myrepo --aging --sort assignee
 
   # Filter by assignee
-  kanban metrics --org myorg --repo myrepo --assignee username`,
+  kanban metrics --org myorg --repo myrepo --assignee username
+
+  # Only bugs in the aging list
+  kanban metrics --org myorg --repo myrepo --type bug
+
+  # Export flow stats or aging issues for spreadsheets
+  kanban metrics --org myorg --all --format csv > flow.csv
+  kanban metrics --org myorg --all --format csv --csv-target aging > aging.csv
+
+  # GitHub-flavored Markdown for a PR comment or wiki page
+  kanban metrics --org myorg --repo myrepo --format markdown | gh pr comment 42 --body-file -
+
+  # Write the report to a file for a scheduled job (colors stripped unless --color)
+  kanban metrics --org myorg --all --output weekly.txt
+
+  # Sprint burndown for a milestone
+  kanban metrics --org myorg --repo myrepo --milestone "Sprint 5" --burndown
+
+  # Count as arrivals only new issues that have had a board status, even if
+  # since closed or moved off the board (unlabelled issues aren't counted)
+  kanban metrics --org myorg --repo myrepo --arrival-from-board
+
+  # Is flow improving? Last 14 days vs the 14 before
+  kanban metrics --org myorg --all --days 14 --compare
+
+  # Keep one stale 400-day issue from skewing average lead/cycle time
+  kanban metrics --org myorg --all --exclude-outliers
+
+  # Lead, cycle and aging times in working days (settings.business_hours,
+  # Mon-Fri 09:00-17:00 UTC by default), so weekends don't count
+  kanban metrics --org myorg --all --business-time
+
+  # Issues without a status change for settings.stale_threshold_days
+  kanban metrics --org myorg --all --stalled
+
+  # Days each column spent over its WIP limit in the last quarter
+  kanban metrics --org myorg --all --days 90 --wip-history`,
 	RunE: runMetrics,
 }
 
 var (
-	metricsSortBy      string
-	metricsAssignee    string
-	showAgingOnly      bool
+	metricsSortBy     string
+	metricsAssignee   string
+	metricsTypes      string
+	showAgingOnly     bool
+	arrivalFromBoard  bool
+	csvTarget         string
+	metricsMilestone  string
+	metricsBurndown   bool
+	timelineLimit     int
+	metricsByAssignee bool
+	metricsByAuthor   bool
+	showRegressions   bool
+	excludeOutliers   bool
+	businessTime      bool
+	includeNotPlanned bool
 )
 
 func init() {
 	rootCmd.AddCommand(metricsCmd)
 	metricsCmd.Flags().StringVarP(&repo, "repo", "r", "", "specific repository")
+	metricsCmd.Flags().StringVar(&repoList, "repos", "", "comma-separated repositories (overrides --repo and the config list)")
 	metricsCmd.Flags().BoolVar(&allRepos, "all", false, "metrics for all repositories")
 	metricsCmd.Flags().IntVar(&days, "days", 30, "time period in days")
-	metricsCmd.Flags().StringVarP(&format, "format", "f", "table", "output format (table|json)")
+	metricsCmd.Flags().StringVarP(&format, "format", "f", "table", "output format (table|json|ndjson|csv|markdown)")
 	metricsCmd.Flags().BoolVar(&liveMode, "live", false, "fetch directly from GitHub API")
 	metricsCmd.Flags().StringVarP(&metricsSortBy, "sort", "s", "age", "sort aging issues by: age, assignee, status, repo")
 	metricsCmd.Flags().StringVarP(&metricsAssignee, "assignee", "a", "", "filter by assignee username")
+	metricsCmd.Flags().StringVar(&metricsTypes, "type", "", "filter aging issues by type, comma-separated (e.g. bug,feature)")
 	metricsCmd.Flags().BoolVar(&showAgingOnly, "aging", false, "show only aging issues (skip other metrics)")
+	metricsCmd.Flags().BoolVar(&arrivalFromBoard, "arrival-from-board", false, "count as arrivals only new issues that have had a status (from cached status history)")
+	metricsCmd.Flags().StringVar(&csvTarget, "csv-target", "flow", "csv content: flow (one row per repo) or aging (aging issues)")
+	metricsCmd.Flags().StringVar(&metricsMilestone, "milestone", "", "milestone title (used with --burndown)")
+	metricsCmd.Flags().BoolVar(&metricsBurndown, "burndown", false, "show remaining issues per day for --milestone")
+	metricsCmd.Flags().BoolVar(&metricsByAssignee, "by-assignee", false, "break down throughput and lead time per assignee")
+	metricsCmd.Flags().BoolVar(&metricsByAuthor, "by-author", false, "report issues opened per author across all cached repositories")
+	metricsCmd.Flags().BoolVar(&showRegressions, "regressions", false, "report backward status transitions (e.g. review -> in-progress)")
+	metricsCmd.Flags().BoolVar(&withTimeline, "with-timeline", false, "live mode: fetch timelines of closed issues for cycle time (slower)")
+	metricsCmd.Flags().IntVar(&timelineLimit, "timeline-limit", 50, "live mode: max timelines to fetch per repo (most recent closures first)")
+	metricsCmd.Flags().IntVar(&concurrencyOverride, "concurrency", 0, "live mode: repos and timelines fetched in parallel, in total (overrides settings.concurrency)")
+	metricsCmd.Flags().StringVar(&outputFile, "output", "", "write the report to this file instead of stdout (respects --format)")
+	metricsCmd.Flags().BoolVar(&forceColor, "color", false, "keep ANSI colors when output isn't a terminal (pipes, --output)")
+	metricsCmd.Flags().BoolVar(&excludeOutliers, "exclude-outliers", false, "leave lead/cycle times beyond 1.5×IQR out of averages (median and P85 keep them)")
+	metricsCmd.Flags().BoolVar(&businessTime, "business-time", false, "lead, cycle and aging times in working days per settings.business_hours")
+	metricsCmd.Flags().BoolVar(&includeNotPlanned, "include-not-planned", false, "count issues closed as not planned or duplicate in throughput and lead time")
 }
 
 // KanbanMetrics holds all kanban metrics
@@ -81,24 +153,54 @@ type KanbanMetrics struct {
 	Repo      string    `json:"repo"`
 	Generated time.Time `json:"generated"`
 	Period    int       `json:"period_days"`
+	Workflow  []string  `json:"workflow,omitempty"`
 
 	// Flow Metrics
-	LeadTime       TimeStats `json:"lead_time"`
-	CycleTime      TimeStats `json:"cycle_time"`
-	Throughput     RateStats `json:"throughput"`
-	FlowEfficiency float64   `json:"flow_efficiency_percent"`
+	LeadTime          TimeStats `json:"lead_time"`
+	CycleTime         TimeStats `json:"cycle_time"`
+	ActiveStartStatus string    `json:"active_start_status,omitempty"`
+	Throughput        RateStats `json:"throughput"`
+	FlowEfficiency    float64   `json:"flow_efficiency_percent"`
 
 	// WIP Metrics
-	WIP          map[string]int `json:"wip"`
-	WIPLimits    map[string]int `json:"wip_limits,omitempty"`
-	WIPAge       TimeStats      `json:"wip_age"`
-	LittlesLaw   LittlesLaw     `json:"littles_law"`
+	WIP        map[string]int `json:"wip"`
+	WIPLimits  map[string]int `json:"wip_limits,omitempty"`
+	WIPAge     TimeStats      `json:"wip_age"`
+	LittlesLaw LittlesLaw     `json:"littles_law"`
+
+	// Time in status (cached mode only): how long issues stayed in each
+	// column before moving on, from status transitions
+	TimeInStatus map[string]TimeStats `json:"time_in_status,omitempty"`
 
 	// Rate Metrics
 	ArrivalRate   float64 `json:"arrival_rate_per_day"`
 	DepartureRate float64 `json:"departure_rate_per_day"`
 	BlockedTime   float64 `json:"blocked_time_hours"`
 
+	// Story points closed in the period (cached mode, settings.size_points)
+	Velocity *VelocityStats `json:"velocity,omitempty"`
+
+	// Issues closed per day over the period, oldest first (cached mode only)
+	DailyThroughput []int `json:"daily_throughput,omitempty"`
+
+	// Triage (cached mode only): creation → first status
+	TriageLatency TimeStats        `json:"triage_latency"`
+	Untriaged     []UntriagedIssue `json:"untriaged,omitempty"`
+
+	// Stalled (cached mode only): no status change in stale_threshold_days
+	Stalled            []StalledIssue `json:"stalled,omitempty"`
+	StaleThresholdDays float64        `json:"stale_threshold_days,omitempty"`
+
+	// Per-assignee breakdown (--by-assignee)
+	ByAssignee map[string]AssigneeStats `json:"by_assignee,omitempty"`
+
+	// Working week that lead, cycle and aging days count (--business-time)
+	BusinessTime string `json:"business_time,omitempty"`
+
+	// Data coverage (cached mode only)
+	CoverageDays int      `json:"data_coverage_days,omitempty"`
+	Caveats      []string `json:"caveats,omitempty"`
+
 	// Distribution
 	FlowLoad int                `json:"flow_load"`
 	Density  map[string]float64 `json:"density_percent"`
@@ -110,6 +212,28 @@ type KanbanMetrics struct {
 	Bottlenecks []string `json:"bottlenecks"`
 }
 
+// statuses returns the workflow the metrics were collected for; saved
+// baselines from before settings.workflow have the default one
+func (m KanbanMetrics) statuses() []string {
+	if len(m.Workflow) == 0 {
+		return config.DefaultWorkflow
+	}
+	return m.Workflow
+}
+
+// UntriagedIssue is an open issue still waiting for its first status
+type UntriagedIssue struct {
+	Number      int     `json:"number"`
+	Title       string  `json:"title"`
+	WaitingDays float64 `json:"waiting_days"`
+}
+
+// AssigneeStats holds flow metrics for one assignee
+type AssigneeStats struct {
+	Throughput int       `json:"throughput"`
+	LeadTime   TimeStats `json:"lead_time"`
+}
+
 type TimeStats struct {
 	Average float64 `json:"average_days"`
 	Median  float64 `json:"median_days"`
@@ -118,6 +242,9 @@ type TimeStats struct {
 	Max     float64 `json:"max_days"`
 	StdDev  float64 `json:"std_dev_days"`
 	Count   int     `json:"sample_count"`
+
+	// Values left out by --exclude-outliers; Count excludes them too
+	OutliersExcluded int `json:"outliers_excluded,omitempty"`
 }
 
 type RateStats struct {
@@ -126,6 +253,13 @@ type RateStats struct {
 	PerWeek float64 `json:"per_week"`
 }
 
+// VelocityStats is throughput in story points rather than issue count
+type VelocityStats struct {
+	Points  float64 `json:"points"`
+	PerWeek float64 `json:"points_per_week"`
+	Unsized int     `json:"unsized"` // closed issues without a mapped size label, counted as 0
+}
+
 type LittlesLaw struct {
 	CalculatedWIP float64 `json:"calculated_wip"`
 	ActualWIP     int     `json:"actual_wip"`
@@ -133,50 +267,123 @@ type LittlesLaw struct {
 }
 
 type AgingIssue struct {
-	Repo         string  `json:"repo,omitempty"`
-	Number       int     `json:"number"`
-	Title        string  `json:"title"`
-	Status       string  `json:"status"`
-	Assignee     string  `json:"assignee,omitempty"`
-	AgeDays      float64 `json:"age_days"`
-	BlockedHours float64 `json:"blocked_hours,omitempty"`
-	IsBlocked    bool    `json:"is_blocked,omitempty"`
+	Repo          string  `json:"repo,omitempty"`
+	Number        int     `json:"number"`
+	Title         string  `json:"title"`
+	Status        string  `json:"status"`
+	Type          string  `json:"type,omitempty"`
+	Assignee      string  `json:"assignee,omitempty"`
+	AgeDays       float64 `json:"age_days"`
+	BlockedHours  float64 `json:"blocked_hours,omitempty"`
+	IsBlocked     bool    `json:"is_blocked,omitempty"`
+	BlockedReason string  `json:"blocked_reason,omitempty"`
 }
 
 func runMetrics(cmd *cobra.Command, args []string) error {
-	organization := viper.GetString("organization")
-	if organization == "" && org != "" {
-		organization = org
+	if err := validateConcurrency(cmd); err != nil {
+		return err
+	}
+	orgs, err := resolveOrganizations()
+	if err != nil {
+		return err
+	}
+	organization := orgs[0]
+
+	if multipleOrgs && (metricsBurndown || metricsMilestone != "" || showRegressions) {
+		return fmt.Errorf("--burndown and --regressions cover one organization: use --org")
+	}
+
+	if outputFile != "" && (metricsBurndown || showRegressions || showStalled || showWIPHistory || compareWindows || vsBaseline != "") {
+		return fmt.Errorf("--output writes the main metrics report; redirect stdout for --burndown, --regressions, --stalled, --wip-history, --compare and --vs-baseline")
+	}
+
+	if saveBaseline != "" && saveBaseline == vsBaseline {
+		return fmt.Errorf("--save-baseline would overwrite the baseline being compared against: pick another name")
+	}
+	if saveBaseline != "" && (metricsBurndown || showRegressions || showStalled || metricsByAuthor || showWIPHistory || compareWindows) {
+		return fmt.Errorf("--save-baseline saves the main metrics report; it can't be used with --burndown, --regressions, --stalled, --by-author, --wip-history or --compare")
+	}
+
+	if metricsBurndown || metricsMilestone != "" {
+		if !metricsBurndown || metricsMilestone == "" {
+			return fmt.Errorf("--milestone and --burndown must be used together")
+		}
+		return runMilestoneBurndown(organization)
+	}
+
+	if showRegressions {
+		return runStatusRegressions(organization)
+	}
+
+	if metricsByAssignee && liveMode {
+		return fmt.Errorf("--by-assignee uses cached data; run 'kanban sync' instead of --live")
+	}
+	if businessTime && liveMode {
+		return fmt.Errorf("--business-time uses cached timestamps; run 'kanban sync' instead of --live")
+	}
+
+	if showStalled {
+		return runStalledReport(orgs)
+	}
+
+	if metricsByAuthor {
+		if liveMode {
+			return fmt.Errorf("--by-author uses cached data; run 'kanban sync' instead of --live")
+		}
+		return runAuthorArrivals()
+	}
+
+	if showWIPHistory {
+		if liveMode {
+			return fmt.Errorf("--wip-history uses cached snapshots; run 'kanban sync' instead of --live")
+		}
+		return runWIPHistory(orgs)
 	}
 
-	if organization == "" {
-		return fmt.Errorf("organization required: use --org flag or set in config")
+	if compareWindows {
+		if vsBaseline != "" {
+			return fmt.Errorf("--compare and --vs-baseline cannot be used together")
+		}
+		return runMetricsComparison(orgs)
 	}
 
 	// Load WIP limits
 	wipLimits := make(map[string]int)
+	activeStart := config.DefaultActiveStartStatus
 	cfg, _ := config.Load()
 	if cfg != nil {
 		wipLimits = cfg.Settings.WIPLimits
+		activeStart = cfg.Settings.ActiveStart()
 	}
 
 	var allMetrics []KanbanMetrics
-	var err error
-
-	if liveMode {
-		// Live mode: fetch directly from GitHub
-		allMetrics, err = collectMetricsLive(organization, days, wipLimits)
-	} else {
-		// Cached mode: use database
-		allMetrics, err = collectMetricsCached(organization, days, wipLimits)
-	}
+	sw := newStopwatch()
+	defer sw.print()
+
+	for _, organization := range orgs {
+		var orgMetrics []KanbanMetrics
+		if liveMode {
+			// Live mode: fetch directly from GitHub
+			stop := sw.start("github fetch")
+			orgMetrics, err = collectMetricsLive(organization, days, wipLimits)
+			stop()
+		} else {
+			// Cached mode: use database
+			stop := sw.start("db query")
+			orgMetrics, err = collectMetricsCached(organization, days, wipLimits)
+			stop()
+		}
 
-	if err != nil {
-		return err
+		if err != nil {
+			return err
+		}
+		allMetrics = append(allMetrics, orgMetrics...)
 	}
 
 	// Apply filtering and sorting to aging issues
 	for i := range allMetrics {
+		allMetrics[i].ActiveStartStatus = activeStart
+
 		// Filter by assignee if specified
 		if metricsAssignee != "" {
 			filtered := []AgingIssue{}
@@ -188,8 +395,33 @@ func runMetrics(cmd *cobra.Command, args []string) error {
 			allMetrics[i].AgingIssues = filtered
 		}
 
+		// Filter by type if specified
+		if types := parseTypes(metricsTypes); len(types) > 0 {
+			filtered := []AgingIssue{}
+			for _, issue := range allMetrics[i].AgingIssues {
+				if matchesType(types, issue.Type) {
+					filtered = append(filtered, issue)
+				}
+			}
+			allMetrics[i].AgingIssues = filtered
+		}
+
 		// Sort aging issues
-		sortAgingIssues(allMetrics[i].AgingIssues, metricsSortBy)
+		sortAgingIssues(allMetrics[i].AgingIssues, metricsSortBy, activeStart, allMetrics[i].statuses())
+	}
+
+	if saveBaseline != "" {
+		if len(allMetrics) == 0 {
+			return fmt.Errorf("no metrics to save as baseline %q", saveBaseline)
+		}
+		if err := saveMetricsBaseline(saveBaseline, allMetrics); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "✓ Saved baseline %q (%d repositories, %d-day period)\n", saveBaseline, len(allMetrics), days)
+	}
+
+	if vsBaseline != "" {
+		return runBaselineComparison(vsBaseline, allMetrics)
 	}
 
 	source := "cached"
@@ -197,10 +429,32 @@ func runMetrics(cmd *cobra.Command, args []string) error {
 		source = "live"
 	}
 
-	if format == "json" {
+	w, closeOutput, err := openOutput()
+	if err != nil {
+		return err
+	}
+	defer closeOutput()
+
+	switch format {
+	case "json":
 		output, _ := json.MarshalIndent(allMetrics, "", "  ")
-		fmt.Println(string(output))
-	} else {
+		fmt.Fprintln(w, string(output))
+	case "ndjson":
+		return printNDJSON(w, allMetrics)
+	case "markdown":
+		for _, m := range allMetrics {
+			printKanbanMetricsMarkdown(w, m)
+		}
+	case "csv":
+		switch csvTarget {
+		case "flow":
+			return writeMetricsCSV(w, allMetrics)
+		case "aging":
+			return writeAgingCSV(w, allMetrics)
+		default:
+			return fmt.Errorf("unsupported csv target: %s (use flow or aging)", csvTarget)
+		}
+	default:
 		sortInfo := ""
 		if metricsSortBy != "age" {
 			sortInfo = fmt.Sprintf(", sorted by %s", metricsSortBy)
@@ -209,13 +463,19 @@ func runMetrics(cmd *cobra.Command, args []string) error {
 		if metricsAssignee != "" {
 			filterInfo = fmt.Sprintf(", @%s", metricsAssignee)
 		}
-		fmt.Printf("\n[Data source: %s%s%s]\n", source, sortInfo, filterInfo)
+		if metricsTypes != "" {
+			filterInfo += fmt.Sprintf(", type: %s", strings.Join(parseTypes(metricsTypes), ","))
+		}
+		if businessTime && len(allMetrics) > 0 {
+			filterInfo += fmt.Sprintf(", working days: %s", allMetrics[0].BusinessTime)
+		}
+		fmt.Fprintf(w, "\n[Data source: %s%s%s]\n", source, sortInfo, filterInfo)
 
 		for _, m := range allMetrics {
 			if showAgingOnly {
-				printAgingIssuesOnly(m)
+				printAgingIssuesOnly(w, m)
 			} else {
-				printKanbanMetrics(m)
+				printKanbanMetrics(w, m)
 			}
 		}
 	}
@@ -224,7 +484,7 @@ func runMetrics(cmd *cobra.Command, args []string) error {
 }
 
 // sortAgingIssues sorts aging issues based on the specified sort method
-func sortAgingIssues(issues []AgingIssue, sortMethod string) {
+func sortAgingIssues(issues []AgingIssue, sortMethod, activeStart string, workflow []string) {
 	switch sortMethod {
 	case "repo":
 		// Group by repo alphabetically
@@ -250,13 +510,7 @@ func sortAgingIssues(issues []AgingIssue, sortMethod string) {
 		})
 	case "status":
 		// Group by status
-		statusOrder := map[string]int{
-			"in-progress": 0,
-			"review":      1,
-			"testing":     2,
-			"ready":       3,
-			"backlog":     4,
-		}
+		statusOrder := agingStatusOrder(activeStart, workflow)
 		sort.Slice(issues, func(i, j int) bool {
 			si := statusOrder[issues[i].Status]
 			sj := statusOrder[issues[j].Status]
@@ -275,19 +529,48 @@ func sortAgingIssues(issues []AgingIssue, sortMethod string) {
 	}
 }
 
+// agingStatusOrder ranks active work (from activeStart towards done) first,
+// then the statuses before it, nearest to active work first
+func agingStatusOrder(activeStart string, statuses []string) map[string]int {
+	start := 0
+	for i, status := range statuses {
+		if status == activeStart {
+			start = i
+			break
+		}
+	}
+
+	order := make(map[string]int)
+	for i := start; i < len(statuses); i++ {
+		order[statuses[i]] = len(order)
+	}
+	for i := start - 1; i >= 0; i-- {
+		order[statuses[i]] = len(order)
+	}
+	return order
+}
+
+// outlierNote tells how many values --exclude-outliers left out
+func outlierNote(s TimeStats) string {
+	if s.OutliersExcluded == 0 {
+		return ""
+	}
+	return fmt.Sprintf("  \033[90m%d outlier(s) excluded\033[0m", s.OutliersExcluded)
+}
+
 // printAgingIssuesOnly prints just the aging issues section
-func printAgingIssuesOnly(m KanbanMetrics) {
+func printAgingIssuesOnly(w io.Writer, m KanbanMetrics) {
 	reset := "\033[0m"
 	bold := "\033[1m"
 	yellow := "\033[33m"
 	dim := "\033[90m"
 
-	fmt.Printf("\n%s%s══════════════════════════════════════════════════════════════%s\n", bold, yellow, reset)
-	fmt.Printf("%s%s  AGING ISSUES: %s%s\n", bold, yellow, m.Repo, reset)
-	fmt.Printf("%s%s══════════════════════════════════════════════════════════════%s\n", bold, yellow, reset)
+	fmt.Fprintf(w, "\n%s%s══════════════════════════════════════════════════════════════%s\n", bold, yellow, reset)
+	fmt.Fprintf(w, "%s%s  AGING ISSUES: %s%s\n", bold, yellow, m.Repo, reset)
+	fmt.Fprintf(w, "%s%s══════════════════════════════════════════════════════════════%s\n", bold, yellow, reset)
 
 	if len(m.AgingIssues) == 0 {
-		fmt.Printf("%sNo aging issues%s\n", dim, reset)
+		fmt.Fprintf(w, "%sNo aging issues%s\n", dim, reset)
 		return
 	}
 
@@ -298,14 +581,14 @@ func printAgingIssuesOnly(m KanbanMetrics) {
 			if issue.Assignee != currentAssignee {
 				currentAssignee = issue.Assignee
 				if currentAssignee == "" {
-					fmt.Printf("\n%s%s@unassigned%s\n", bold, dim, reset)
+					fmt.Fprintf(w, "\n%s%s@unassigned%s\n", bold, dim, reset)
 				} else {
-					fmt.Printf("\n%s@%s%s\n", bold, currentAssignee, reset)
+					fmt.Fprintf(w, "\n%s@%s%s\n", bold, currentAssignee, reset)
 				}
 			}
 			ageColor := getAgeColor(issue.AgeDays)
-			blockedStr := formatBlockedTime(issue.BlockedHours, issue.IsBlocked)
-			fmt.Printf("  #%-4d %s%5.1fd%s %-11s %s%s\n",
+			blockedStr := formatBlockedTime(issue.BlockedHours, issue.IsBlocked, issue.BlockedReason)
+			fmt.Fprintf(w, "  #%-4d %s%5.1fd%s %-11s %s%s\n",
 				issue.Number, ageColor, issue.AgeDays, reset, issue.Status, issue.Title, blockedStr)
 		}
 	} else {
@@ -315,28 +598,34 @@ func printAgingIssuesOnly(m KanbanMetrics) {
 				assignee = fmt.Sprintf(" @%s", issue.Assignee)
 			}
 			ageColor := getAgeColor(issue.AgeDays)
-			blockedStr := formatBlockedTime(issue.BlockedHours, issue.IsBlocked)
-			fmt.Printf("#%-4d %s%5.1fd%s %-11s %-30s%s%s%s%s\n",
+			blockedStr := formatBlockedTime(issue.BlockedHours, issue.IsBlocked, issue.BlockedReason)
+			fmt.Fprintf(w, "#%-4d %s%5.1fd%s %-11s %-30s%s%s%s%s\n",
 				issue.Number, ageColor, issue.AgeDays, reset,
 				issue.Status, issue.Title, blockedStr, dim, assignee, reset)
 		}
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 }
 
-// formatBlockedTime returns a formatted string for blocked time
-func formatBlockedTime(hours float64, isCurrentlyBlocked bool) string {
+// formatBlockedTime returns a formatted string for blocked time.
+// The reason is only shown while the issue is still blocked.
+func formatBlockedTime(hours float64, isCurrentlyBlocked bool, reason string) string {
 	if hours == 0 && !isCurrentlyBlocked {
 		return ""
 	}
 	red := "\033[31m"
+	dim := "\033[90m"
 	reset := "\033[0m"
 
 	if isCurrentlyBlocked {
+		why := ""
+		if reason != "" {
+			why = fmt.Sprintf(" %s%q%s", dim, truncate(reason, 40), reset)
+		}
 		if hours > 0 {
-			return fmt.Sprintf(" %s[⊘ blocked %.0fh]%s", red, hours, reset)
+			return fmt.Sprintf(" %s[⊘ blocked %.0fh]%s%s", red, hours, reset, why)
 		}
-		return fmt.Sprintf(" %s[⊘ blocked]%s", red, reset)
+		return fmt.Sprintf(" %s[⊘ blocked]%s%s", red, reset, why)
 	}
 
 	// Was blocked but not anymore
@@ -348,16 +637,25 @@ func formatBlockedTime(hours float64, isCurrentlyBlocked bool) string {
 
 // collectMetricsCached collects metrics from the local database
 func collectMetricsCached(organization string, days int, wipLimits map[string]int) ([]KanbanMetrics, error) {
-	database, err := db.Open(dbPath)
+	database, err := db.OpenReadOnly(dbPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w (run 'kanban sync' first or use --live)", err)
 	}
 	defer database.Close()
+	database.SetIncludeNotPlanned(includeNotPlanned)
+	excludeIssues(database)
 
-	// Get WIP summary from database
+	// Get WIP summary from database; --repos picks several, so filter those below
+	selected := selectedRepos(organization)
 	repoFilter := ""
-	if repo != "" {
-		repoFilter = fmt.Sprintf("%s/%s", organization, repo)
+	if selected == nil {
+		repoName, ok := repoForOrg(organization)
+		if !ok {
+			return nil, nil
+		}
+		if repoName != "" {
+			repoFilter = fmt.Sprintf("%s/%s", organization, repoName)
+		}
 	}
 
 	wipSummary, err := database.GetWIPSummary(repoFilter)
@@ -389,36 +687,83 @@ func collectMetricsCached(organization string, days int, wipLimits map[string]in
 	// Get arrival data (new issues created in period)
 	arrivalByRepo, _ := database.GetArrivalByRepo(days)
 
+	// Authors (bots) left out of the per-assignee breakdown
+	var settings config.Settings
+	if cfg, _ := config.Load(); cfg != nil {
+		settings = cfg.Settings
+	}
+
+	// Working week for --business-time
+	week := settings.WorkWeek()
+	if businessTime {
+		if err := week.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid settings.business_hours: %w", err)
+		}
+	}
+
+	// First sync per repo, to detect periods that exceed cached data coverage
+	firstSync, _ := database.GetRepoFirstSync()
+	periodStart := time.Now().AddDate(0, 0, -days)
+
 	var allMetrics []KanbanMetrics
 
+	seen := make(map[string]bool)
+	for repoName := range repoWIP {
+		seen[strings.ToLower(repoName)] = true
+	}
+	warnUncached(selected, seen)
+
 	for repoName, wip := range repoWIP {
+		if !inOrg(organization, repoName) {
+			continue
+		}
+		if selected != nil && !selected[strings.ToLower(repoName)] {
+			continue
+		}
 		m := KanbanMetrics{
-			Repo:      strings.TrimPrefix(repoName, organization+"/"),
+			Repo:      displayRepo(organization, repoName),
 			Generated: time.Now().UTC(),
 			Period:    days,
 			WIP:       wip,
 			WIPLimits: wipLimits,
 			Density:   make(map[string]float64),
+			Workflow:  settings.Statuses(),
+		}
+		if businessTime {
+			m.BusinessTime = week.String()
+		}
+
+		// Reasons for currently blocked issues, from recorded blocked periods
+		blockedReasons := make(map[int]string)
+		if blocked, err := database.GetBlockedIssues(repoName); err == nil {
+			for _, b := range blocked {
+				blockedReasons[b.Number] = b.Reason
+			}
 		}
 
 		// Calculate metrics from cached data
-		statuses := []string{"backlog", "ready", "in-progress", "review", "testing", "done"}
+		statuses := m.Workflow
 		var allAges []float64
 
 		for _, issue := range repoIssues[repoName] {
-			if issue.Status != "done" && issue.Status != "backlog" && issue.Status != "" {
+			if issue.Status != config.DoneStatus && issue.Status != statuses[0] && issue.Status != "" {
 				age := issue.AgeHours / 24
+				if businessTime {
+					age = workingDays(issue.UpdatedAt, time.Now(), week)
+				}
 				allAges = append(allAges, age)
 
 				m.AgingIssues = append(m.AgingIssues, AgingIssue{
-					Repo:         m.Repo,
-					Number:       issue.Number,
-					Title:        truncate(issue.Title, 35),
-					Status:       issue.Status,
-					Assignee:     issue.Assignee,
-					AgeDays:      math.Round(age*10) / 10,
-					BlockedHours: issue.BlockedTimeHours,
-					IsBlocked:    issue.IsBlocked,
+					Repo:          m.Repo,
+					Number:        issue.Number,
+					Title:         truncate(displayTitle(issue.Title), 35),
+					Status:        issue.Status,
+					Type:          issue.Type,
+					Assignee:      issue.Assignee,
+					AgeDays:       math.Round(age*10) / 10,
+					BlockedHours:  issue.BlockedTimeHours,
+					IsBlocked:     issue.IsBlocked,
+					BlockedReason: blockedReasons[issue.Number],
 				})
 			}
 		}
@@ -448,53 +793,107 @@ func collectMetricsCached(organization string, days int, wipLimits map[string]in
 
 		// Calculate flow metrics from cached data
 		closedIssues, err := database.GetClosedIssuesInPeriod(repoName, days)
-		if err == nil && len(closedIssues) > 0 {
-			// Throughput
-			m.Throughput.Total = len(closedIssues)
-			m.Throughput.PerDay = float64(len(closedIssues)) / float64(days)
-			m.Throughput.PerWeek = m.Throughput.PerDay * 7
-
-			// Departure Rate
-			m.DepartureRate = m.Throughput.PerDay
-
-			// Lead Time
-			var leadTimes []float64
-			for _, issue := range closedIssues {
-				if issue.LeadTimeHours > 0 {
-					leadTimes = append(leadTimes, issue.LeadTimeHours/24)
-				}
+		if err == nil {
+			if businessTime {
+				toWorkingTime(closedIssues, week)
 			}
-			if len(leadTimes) > 0 {
-				m.LeadTime = calculateTimeStats(leadTimes)
+			if inconsistent := applyClosedIssueMetrics(&m, closedIssues, days); len(inconsistent) > 0 {
+				fmt.Fprintf(os.Stderr, "Warning: %s: excluded %d issue(s) with cycle time > lead time from flow efficiency: %s\n",
+					m.Repo, len(inconsistent), strings.Join(inconsistent, ", "))
 			}
+		}
 
-			// Cycle Time (only for issues that went through workflow)
-			var cycleTimes []float64
-			var workflowLeadTimes []float64
-			for _, issue := range closedIssues {
-				if issue.CycleTimeHours > 0 {
-					cycleTimes = append(cycleTimes, issue.CycleTimeHours/24)
-					// Also track lead time for these same issues (for accurate flow efficiency)
-					if issue.LeadTimeHours > 0 {
-						workflowLeadTimes = append(workflowLeadTimes, issue.LeadTimeHours/24)
-					}
+		if daily, err := database.GetDailyThroughput(repoName, days); err == nil {
+			m.DailyThroughput = daily
+		}
+
+		// Velocity: story points closed, for teams that size with labels
+		if len(settings.SizePoints) > 0 {
+			points, err := database.GetVelocity(repoName, days)
+			if err == nil {
+				unsized, _ := database.GetUnsizedClosedCount(repoName, days)
+				m.Velocity = &VelocityStats{
+					Points:  points,
+					PerWeek: points / float64(days) * 7,
+					Unsized: unsized,
 				}
 			}
-			if len(cycleTimes) > 0 {
-				m.CycleTime = calculateTimeStats(cycleTimes)
-				// Flow Efficiency: compare cycle/lead for SAME issues only
-				if len(workflowLeadTimes) > 0 {
-					workflowLead := calculateTimeStats(workflowLeadTimes)
-					if workflowLead.Average > 0 {
-						m.FlowEfficiency = math.Round(m.CycleTime.Average / workflowLead.Average * 100)
+		}
+
+		// Triage latency: creation → first status
+		if latencies, err := database.GetTriageLatencies(repoName, days); err == nil && len(latencies) > 0 {
+			for i := range latencies {
+				latencies[i] /= 24
+			}
+			m.TriageLatency = calculateTimeStats(latencies)
+		}
+		if untriaged, err := database.GetUntriagedIssues(repoName, 5); err == nil {
+			for _, issue := range untriaged {
+				m.Untriaged = append(m.Untriaged, UntriagedIssue{
+					Number:      issue.Number,
+					Title:       truncate(displayTitle(issue.Title), 35),
+					WaitingDays: math.Round(issue.WaitingHours/24*10) / 10,
+				})
+			}
+		}
+
+		// Dwell time per column from consecutive status transitions
+		if repoID, err := database.GetRepoID(repoName); err == nil {
+			if dwell, err := database.GetTimeInStatus(repoID, days); err == nil && len(dwell) > 0 {
+				m.TimeInStatus = make(map[string]TimeStats)
+				for status, s := range dwell {
+					m.TimeInStatus[status] = TimeStats{
+						Average: math.Round(s.Average*10) / 10,
+						Median:  math.Round(s.Median*10) / 10,
+						P85:     math.Round(s.P85*10) / 10,
+						Count:   s.Count,
 					}
 				}
 			}
 		}
 
+		// Issues sitting in one status, whatever their other activity
+		m.StaleThresholdDays = settings.StaleThreshold()
+		if inStatus, err := database.GetTimeInCurrentStatus(repoName); err == nil {
+			m.Stalled = stalledIssues(organization, inStatus, m.StaleThresholdDays, m.Workflow)
+		}
+
+		// Per-assignee breakdown
+		if metricsByAssignee {
+			byAssignee, err := database.GetClosedIssuesByAssignee(repoName, days)
+			if err == nil {
+				m.ByAssignee = calculateAssigneeStats(byAssignee, settings)
+			}
+		}
+
 		// Arrival Rate (new issues created in period)
-		if arrivalCount, ok := arrivalByRepo[repoName]; ok {
+		coverageStart, covered := firstSync[repoName]
+		coverage := "first synced"
+		if arrivalFromBoard {
+			// Issues that have been on the board, whatever their status now
+			arrivalCount, err := database.GetBoardArrivals(repoName, days)
+			if err != nil {
+				return nil, fmt.Errorf("failed to count board arrivals for %s: %w", repoName, err)
+			}
 			m.ArrivalRate = float64(arrivalCount) / float64(days)
+
+			// Only issues with a recorded status count, so coverage starts
+			// with the repo's status history rather than its first sync
+			coverageStart, covered, err = database.GetBoardHistoryStart(repoName)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read status history for %s: %w", repoName, err)
+			}
+			coverage = "first board status recorded"
+		} else if arrivalCount, ok := arrivalByRepo[repoName]; ok {
+			m.ArrivalRate = float64(arrivalCount) / float64(days)
+		}
+
+		// Arrivals before coverage starts may not be cached or counted
+		if covered && coverageStart.After(periodStart) {
+			m.CoverageDays = int(time.Since(coverageStart).Hours() / 24)
+			m.Caveats = append(m.Caveats, fmt.Sprintf(
+				"period (%d days) exceeds data coverage (%s %d days ago); arrival rate may be understated",
+				days, coverage, m.CoverageDays))
 		}
 
 		// Identify bottlenecks based on WIP
@@ -510,80 +909,283 @@ func collectMetricsCached(organization string, days int, wipLimits map[string]in
 	return allMetrics, nil
 }
 
+// workingDays returns the working time from start to end in week, in
+// working days of week's length
+func workingDays(start, end time.Time, week config.BusinessHours) float64 {
+	return config.BusinessHoursBetween(start, end, week) / week.DayHours()
+}
+
+// toWorkingTime restates closed issues' lead and cycle times as working time
+// for --business-time, keeping hours as the unit so that downstream /24
+// conversions yield working days. Done is when lead time ends; cycle time
+// started its own hours plus the blocked hours taken out of it before that,
+// and the blocked share is taken out of the working span in proportion.
+func toWorkingTime(issues []db.ClosedIssueStats, week config.BusinessHours) {
+	for i := range issues {
+		issue := &issues[i]
+		if issue.CreatedAt.IsZero() || issue.LeadTimeHours <= 0 {
+			issue.LeadTimeHours, issue.CycleTimeHours = 0, 0
+			continue
+		}
+		done := issue.CreatedAt.Add(time.Duration(issue.LeadTimeHours * float64(time.Hour)))
+		issue.LeadTimeHours = workingDays(issue.CreatedAt, done, week) * 24
+
+		if issue.CycleTimeHours > 0 {
+			span := issue.CycleTimeHours + issue.BlockedHours
+			started := done.Add(-time.Duration(span * float64(time.Hour)))
+			issue.CycleTimeHours = workingDays(started, done, week) * 24 * issue.CycleTimeHours / span
+		}
+	}
+}
+
+// applyClosedIssueMetrics fills throughput, departure rate, lead/cycle time
+// and flow efficiency from issues closed during a days-long period. It
+// returns the issues left out because their cycle time exceeds lead time.
+func applyClosedIssueMetrics(m *KanbanMetrics, closedIssues []db.ClosedIssueStats, days int) []string {
+	if len(closedIssues) == 0 {
+		return nil
+	}
+
+	// Throughput
+	m.Throughput.Total = len(closedIssues)
+	m.Throughput.PerDay = float64(len(closedIssues)) / float64(days)
+	m.Throughput.PerWeek = m.Throughput.PerDay * 7
+
+	// Departure Rate
+	m.DepartureRate = m.Throughput.PerDay
+
+	// Lead Time
+	var leadTimes []float64
+	for _, issue := range closedIssues {
+		if issue.LeadTimeHours > 0 {
+			leadTimes = append(leadTimes, issue.LeadTimeHours/24)
+		}
+	}
+	if len(leadTimes) > 0 {
+		m.LeadTime = flowTimeStats(leadTimes)
+	}
+
+	// Cycle Time (only for issues that went through workflow)
+	// Issues with cycle > lead have bad timeline data and are excluded
+	var cycleTimes []float64
+	var consistent []db.ClosedIssueStats
+	var inconsistent []string
+	for _, issue := range closedIssues {
+		if issue.CycleExceedsLead() {
+			inconsistent = append(inconsistent, fmt.Sprintf("#%d", issue.Number))
+			continue
+		}
+		if issue.CycleTimeHours > 0 {
+			cycleTimes = append(cycleTimes, issue.CycleTimeHours/24)
+			consistent = append(consistent, issue)
+		}
+	}
+	if len(cycleTimes) > 0 {
+		m.CycleTime = flowTimeStats(cycleTimes)
+		// Flow Efficiency: per-issue cycle/lead, averaged over the same
+		// issues as the cycle time average
+		if excludeOutliers {
+			consistent = dropIndexes(consistent, outlierIndexes(cycleTimes))
+		}
+		m.FlowEfficiency = math.Round(db.FlowEfficiency(consistent))
+	}
+	return inconsistent
+}
+
 // collectMetricsLive collects metrics directly from GitHub API
 func collectMetricsLive(organization string, days int, wipLimits map[string]int) ([]KanbanMetrics, error) {
-	client := github.NewClient()
+	client := newGitHubClient(timeoutCtx)
 	cfg, _ := config.Load()
 
-	var repos []string
-	var err error
-	if repo != "" {
-		repos = []string{repo}
-	} else if cfg != nil && cfg.HasExplicitRepos() {
-		repos = cfg.GetRepos()
-	} else if allRepos {
-		repos, err = client.ListRepos(organization)
-		if err != nil {
-			return nil, err
-		}
-		if cfg != nil {
-			repos = cfg.FilterRepos(repos)
-		}
-	} else {
-		return nil, fmt.Errorf("specify --repo, --all, or define repositories.list in config")
+	repos, ok, err := resolveRepos(cfg, client, organization)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	var settings config.Settings
+	if cfg != nil {
+		settings = cfg.Settings
 	}
 
+	// Collect repos in parallel. Repos and their timeline fetches share one
+	// budget of workerConcurrency() gh calls: each repo worker gets an equal
+	// share of it for its timelines.
+	repoWorkers := max(1, min(workerConcurrency(), len(repos)))
+	timelineWorkers := max(1, workerConcurrency()/repoWorkers)
+	sem := make(chan struct{}, repoWorkers)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
 	var allMetrics []KanbanMetrics
 
 	for _, r := range repos {
-		m, err := collectKanbanMetrics(client, organization, r, days, wipLimits)
-		if err != nil {
-			fmt.Printf("Warning: %s: %v\n", r, err)
+		wg.Add(1)
+		go func(repoName string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			m, err := collectKanbanMetrics(client, organization, repoName, days, wipLimits, settings, timelineWorkers)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				fmt.Printf("Warning: %s: %v\n", repoName, err)
+				return
+			}
+			allMetrics = append(allMetrics, m)
+		}(r)
+	}
+	wg.Wait()
+
+	// Repos finish in any order
+	sort.Slice(allMetrics, func(i, j int) bool {
+		return allMetrics[i].Repo < allMetrics[j].Repo
+	})
+	return allMetrics, nil
+}
+
+// calculateAssigneeStats builds per-assignee throughput and lead time, leaving
+// out issues opened by settings.ignore_authors
+func calculateAssigneeStats(byAssignee map[string][]db.ClosedIssueStats, settings config.Settings) map[string]AssigneeStats {
+	result := make(map[string]AssigneeStats)
+	for assignee, issues := range byAssignee {
+		var leadTimes []float64
+		throughput := 0
+		for _, issue := range issues {
+			if settings.IsIgnoredAuthor(issue.Author) {
+				continue
+			}
+			throughput++
+			if issue.LeadTimeHours > 0 {
+				leadTimes = append(leadTimes, issue.LeadTimeHours/24)
+			}
+		}
+		if throughput == 0 {
 			continue
 		}
-		allMetrics = append(allMetrics, m)
+		stats := AssigneeStats{Throughput: throughput}
+		if len(leadTimes) > 0 {
+			stats.LeadTime = calculateTimeStats(leadTimes)
+		}
+		result[assignee] = stats
 	}
+	return result
+}
 
-	return allMetrics, nil
+// collectLiveCycleTimes fetches timelines for the most recently closed issues
+// (up to --timeline-limit) and returns cycle and lead times in days for those
+// that entered the active start status, at most workers at a time. Issues
+// with cycle > lead are skipped.
+func collectLiveCycleTimes(client *github.Client, org, repo string, closedIssues []github.IssueWithTimes, workers int) (cycleTimes, leadTimes []float64) {
+	activeStart := config.DefaultActiveStartStatus
+	if cfg, _ := config.Load(); cfg != nil {
+		activeStart = cfg.Settings.ActiveStart()
+	}
+
+	recent := make([]github.IssueWithTimes, len(closedIssues))
+	copy(recent, closedIssues)
+	sort.Slice(recent, func(i, j int) bool {
+		return recent[i].ClosedAt.After(recent[j].ClosedAt)
+	})
+	if timelineLimit > 0 && len(recent) > timelineLimit {
+		recent = recent[:timelineLimit]
+	}
+
+	sem := make(chan struct{}, max(1, workers))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	for _, issue := range recent {
+		wg.Add(1)
+		go func(issue github.IssueWithTimes) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			timeline, err := client.GetIssueTimeline(org, repo, issue.Number)
+			if err != nil || timeline == nil {
+				return
+			}
+			started, ok := timeline.StatusChanges[activeStart]
+			if !ok {
+				return
+			}
+			done := issue.ClosedAt
+			if t, ok := timeline.StatusChanges["done"]; ok {
+				done = t
+			}
+
+			cycle := done.Sub(started).Hours() - timeline.TotalBlocked
+			lead := done.Sub(issue.CreatedAt).Hours()
+			if cycle <= 0 || lead <= 0 || cycle > lead {
+				return
+			}
+
+			mu.Lock()
+			cycleTimes = append(cycleTimes, cycle/24)
+			leadTimes = append(leadTimes, lead/24)
+			mu.Unlock()
+		}(issue)
+	}
+
+	wg.Wait()
+	return cycleTimes, leadTimes
 }
 
-func collectKanbanMetrics(client *github.Client, org, repo string, days int, wipLimits map[string]int) (KanbanMetrics, error) {
+// collectKanbanMetrics collects one repo's metrics from GitHub. Issues opened
+// by settings.ignore_authors don't count toward throughput, lead time or
+// arrival rate, as in cached mode where sync skips them. Timelines are
+// fetched timelineWorkers at a time.
+func collectKanbanMetrics(client *github.Client, org, repo string, days int, wipLimits map[string]int, settings config.Settings,
+	timelineWorkers int) (KanbanMetrics, error) {
+	fetchLimit := settings.FetchLimit
+	fullName := org + "/" + repo
 	m := KanbanMetrics{
-		Repo:      repo,
+		Repo:      displayRepo(org, fullName),
 		Generated: time.Now().UTC(),
 		Period:    days,
 		WIP:       make(map[string]int),
 		WIPLimits: wipLimits,
 		Density:   make(map[string]float64),
+		Workflow:  settings.Statuses(),
 	}
 
-	statuses := []string{"backlog", "ready", "in-progress", "review", "testing", "done"}
+	statuses := m.Workflow
 
 	// Collect WIP and aging for each status
 	var allAges []float64
 	for _, status := range statuses {
-		label := "status: " + status
-		issues, err := client.ListIssuesForBoard(org, repo, label, false, 500)
+		labels := settings.StatusLabels(status)
+		issues, err := client.ListIssuesForBoard(org, repo, labels, false, fetchLimit)
 		if err != nil {
 			continue
 		}
+		if fetchTruncated(len(issues), fetchLimit, len(labels) > 1) {
+			warnTruncated(fullName, len(issues))
+		}
+		var included []github.BoardIssue
+		for _, issue := range issues {
+			if !settings.IsExcludedIssue(fullName, issue.Number, issue.Labels) {
+				included = append(included, issue)
+			}
+		}
+		issues = included
 		m.WIP[status] = len(issues)
 
 		// Collect aging for active items
-		if status != "done" && status != "backlog" {
+		if status != config.DoneStatus && status != statuses[0] {
 			for _, issue := range issues {
-				details, err := client.GetIssueDetails(org, repo, issue.Number)
-				if err != nil {
-					continue
-				}
-				age := time.Since(details.CreatedAt).Hours() / 24
+				age := time.Since(issue.CreatedAt).Hours() / 24
 				allAges = append(allAges, age)
 
 				m.AgingIssues = append(m.AgingIssues, AgingIssue{
 					Repo:     m.Repo,
 					Number:   issue.Number,
-					Title:    truncate(issue.Title, 35),
+					Title:    truncate(displayTitle(issue.Title), 35),
 					Status:   status,
+					Type:     extractLabel(issue.Labels, "type", settings.Delimiter()),
 					Assignee: issue.Assignee,
 					AgeDays:  math.Round(age*10) / 10,
 				})
@@ -615,7 +1217,23 @@ func collectKanbanMetrics(client *github.Client, org, repo string, days int, wip
 	}
 
 	// Get closed issues for throughput and lead time
-	closedIssues, err := client.ListClosedIssuesWithTimes(org, repo, days)
+	closedIssues, err := client.ListClosedIssuesWithTimes(org, repo, days, fetchLimit)
+	if err == nil && fetchTruncated(len(closedIssues), fetchLimit, true) {
+		warnTruncated(fullName, len(closedIssues))
+	}
+	var counted []github.IssueWithTimes
+	for _, issue := range closedIssues {
+		if issue.NotPlanned() && !includeNotPlanned {
+			continue
+		}
+		if settings.IsExcludedIssue(fullName, issue.Number, issue.Labels) {
+			continue
+		}
+		if !settings.IsIgnoredAuthor(issue.Author) {
+			counted = append(counted, issue)
+		}
+	}
+	closedIssues = counted
 	if err == nil && len(closedIssues) > 0 {
 		// Throughput
 		m.Throughput.Total = len(closedIssues)
@@ -636,32 +1254,51 @@ func collectKanbanMetrics(client *github.Client, org, repo string, days int, wip
 			}
 		}
 		if len(leadTimes) > 0 {
-			m.LeadTime = calculateTimeStats(leadTimes)
+			m.LeadTime = flowTimeStats(leadTimes)
+		}
+
+		// Cycle time needs timeline data; without --with-timeline use
+		// cached mode with 'kanban sync --with-timeline'
+		if withTimeline {
+			cycleTimes, workflowLeadTimes := collectLiveCycleTimes(client, org, repo, closedIssues, timelineWorkers)
+			if len(cycleTimes) > 0 {
+				m.CycleTime = flowTimeStats(cycleTimes)
+				// Flow Efficiency: per-issue cycle/lead, averaged over the
+				// same issues as the cycle time average
+				pairs := make([]db.ClosedIssueStats, len(cycleTimes))
+				for i := range cycleTimes {
+					pairs[i] = db.ClosedIssueStats{CycleTimeHours: cycleTimes[i] * 24, LeadTimeHours: workflowLeadTimes[i] * 24}
+				}
+				if excludeOutliers {
+					pairs = dropIndexes(pairs, outlierIndexes(cycleTimes))
+				}
+				m.FlowEfficiency = math.Round(db.FlowEfficiency(pairs))
+			}
 		}
-		// Cycle time not available in live mode - requires timeline data
-		// Use cached mode with 'kanban sync --with-timeline' for cycle time
 	}
 
 	// Arrival Rate (new issues created in period)
-	allIssues, err := client.ListAllIssues(org, repo, 500)
+	allIssues, err := client.ListAllIssues(org, repo, fetchLimit)
 	if err == nil {
+		if fetchTruncated(len(allIssues), fetchLimit, false) {
+			warnTruncated(fullName, len(allIssues))
+		}
 		cutoff := time.Now().AddDate(0, 0, -days)
 		newCount := 0
 		for _, issue := range allIssues {
-			if issue.CreatedAt.After(cutoff) {
+			if issue.CreatedAt.After(cutoff) && !settings.IsIgnoredAuthor(issue.Author) &&
+				!settings.IsExcludedIssue(fullName, issue.Number, issue.Labels) {
 				newCount++
 			}
 		}
 		m.ArrivalRate = float64(newCount) / float64(days)
 	}
 
-	// Flow Efficiency (only when we have real cycle time data)
-	if m.LeadTime.Average > 0 && m.CycleTime.Count > 0 {
-		m.FlowEfficiency = math.Round((m.CycleTime.Average/m.LeadTime.Average)*1000) / 10
-	}
-
 	// Little's Law: WIP = Throughput × Lead Time
-	activeWIP := m.WIP["ready"] + m.WIP["in-progress"] + m.WIP["review"] + m.WIP["testing"]
+	activeWIP := 0
+	for _, status := range settings.ActiveStatuses() {
+		activeWIP += m.WIP[status]
+	}
 	if m.Throughput.PerDay > 0 && m.LeadTime.Average > 0 {
 		m.LittlesLaw.CalculatedWIP = m.Throughput.PerDay * m.LeadTime.Average
 		m.LittlesLaw.ActualWIP = activeWIP
@@ -676,6 +1313,77 @@ func collectKanbanMetrics(client *github.Client, org, repo string, days int, wip
 	return m, nil
 }
 
+// flowTimeStats computes lead or cycle time stats. With --exclude-outliers
+// the average, min, max and spread leave out Tukey outliers, while median
+// and P85 are still taken from every value for comparison.
+func flowTimeStats(values []float64) TimeStats {
+	// calculateTimeStats sorts; callers pair values with issues by position
+	values = append([]float64(nil), values...)
+	if !excludeOutliers {
+		return calculateTimeStats(values)
+	}
+
+	kept, removed := filterOutliers(values)
+	full := calculateTimeStats(values)
+	stats := calculateTimeStats(kept)
+	stats.Median = full.Median
+	stats.P85 = full.P85
+	stats.OutliersExcluded = removed
+	return stats
+}
+
+// filterOutliers drops values outside the Tukey fences [Q1-1.5×IQR,
+// Q3+1.5×IQR]. Fewer than 4 values are returned as is. values isn't modified.
+func filterOutliers(values []float64) (kept []float64, removed int) {
+	outliers := outlierIndexes(values)
+	return dropIndexes(values, outliers), len(outliers)
+}
+
+// outlierIndexes returns the positions of values outside the Tukey fences,
+// so the same issues can be dropped from values paired with them
+func outlierIndexes(values []float64) map[int]bool {
+	outliers := make(map[int]bool)
+	if len(values) < 4 {
+		return outliers
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	q1, q3 := quantile(sorted, 0.25), quantile(sorted, 0.75)
+	iqr := q3 - q1
+	low, high := q1-1.5*iqr, q3+1.5*iqr
+
+	for i, v := range values {
+		if v < low || v > high {
+			outliers[i] = true
+		}
+	}
+	return outliers
+}
+
+// dropIndexes returns items without those at the positions in drop
+func dropIndexes[T any](items []T, drop map[int]bool) []T {
+	if len(drop) == 0 {
+		return items
+	}
+	kept := make([]T, 0, len(items)-len(drop))
+	for i, item := range items {
+		if !drop[i] {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// quantile returns the q-th quantile of sorted values, interpolating
+// linearly between the closest ranks
+func quantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(pos-float64(lower))
+}
+
 func calculateTimeStats(values []float64) TimeStats {
 	if len(values) == 0 {
 		return TimeStats{}
@@ -729,7 +1437,7 @@ func identifyBottlenecks(m KanbanMetrics) []string {
 
 	// WIP limit violations
 	for status, count := range m.WIP {
-		if limit, ok := m.WIPLimits["status: "+status]; ok && count > limit {
+		if limit, ok := config.WIPLimit(m.WIPLimits, status); ok && count > limit {
 			bottlenecks = append(bottlenecks, fmt.Sprintf("WIP LIMIT: %s has %d items (limit: %d)", status, count, limit))
 		}
 	}
@@ -760,6 +1468,11 @@ func identifyBottlenecks(m KanbanMetrics) []string {
 		bottlenecks = append(bottlenecks, fmt.Sprintf("STALE ITEMS: %d issues stuck >14 days", staleCount))
 	}
 
+	// No status change in a long time, even if commented on
+	if len(m.Stalled) > 0 {
+		bottlenecks = append(bottlenecks, fmt.Sprintf("STALLED: %d issues without a status change for >%.0f days", len(m.Stalled), m.StaleThresholdDays))
+	}
+
 	// Little's Law variance
 	if math.Abs(m.LittlesLaw.Variance) > 50 {
 		bottlenecks = append(bottlenecks, fmt.Sprintf("FLOW INSTABILITY: Actual WIP deviates %.0f%% from predicted", m.LittlesLaw.Variance))
@@ -768,7 +1481,7 @@ func identifyBottlenecks(m KanbanMetrics) []string {
 	return bottlenecks
 }
 
-func printKanbanMetrics(m KanbanMetrics) {
+func printKanbanMetrics(w io.Writer, m KanbanMetrics) {
 	reset := "\033[0m"
 	bold := "\033[1m"
 	cyan := "\033[36m"
@@ -777,52 +1490,56 @@ func printKanbanMetrics(m KanbanMetrics) {
 	green := "\033[32m"
 	dim := "\033[90m"
 
-	fmt.Printf("\n%s%s══════════════════════════════════════════════════════════════%s\n", bold, cyan, reset)
-	fmt.Printf("%s%s  KANBAN METRICS: %s%s\n", bold, cyan, m.Repo, reset)
-	fmt.Printf("%s%s══════════════════════════════════════════════════════════════%s\n", bold, cyan, reset)
-	fmt.Printf("%sGenerated: %s │ Period: %d days%s\n\n", dim, m.Generated.Format("2006-01-02 15:04 UTC"), m.Period, reset)
+	fmt.Fprintf(w, "\n%s%s══════════════════════════════════════════════════════════════%s\n", bold, cyan, reset)
+	fmt.Fprintf(w, "%s%s  KANBAN METRICS: %s%s\n", bold, cyan, m.Repo, reset)
+	fmt.Fprintf(w, "%s%s══════════════════════════════════════════════════════════════%s\n", bold, cyan, reset)
+	fmt.Fprintf(w, "%sGenerated: %s │ Period: %d days%s\n\n", dim, m.Generated.Format("2006-01-02 15:04 UTC"), m.Period, reset)
 
 	// ═══ FLOW METRICS ═══
-	fmt.Printf("%s%s┌─ FLOW METRICS ─────────────────────────────────────────────┐%s\n", bold, cyan, reset)
+	fmt.Fprintf(w, "%s%s┌─ FLOW METRICS ─────────────────────────────────────────────┐%s\n", bold, cyan, reset)
 
-	fmt.Printf("│ %sLead Time%s (creation → done):\n", bold, reset)
+	fmt.Fprintf(w, "│ %sLead Time%s (creation → done):\n", bold, reset)
 	if m.LeadTime.Count > 0 {
-		fmt.Printf("│   Average: %s%.1f days%s  Median: %.1f  P85: %.1f  (n=%d)\n",
-			bold, m.LeadTime.Average, reset, m.LeadTime.Median, m.LeadTime.P85, m.LeadTime.Count)
+		fmt.Fprintf(w, "│   Average: %s%.1f days%s  Median: %.1f  P85: %.1f  (n=%d)%s\n",
+			bold, m.LeadTime.Average, reset, m.LeadTime.Median, m.LeadTime.P85, m.LeadTime.Count, outlierNote(m.LeadTime))
 	} else {
-		fmt.Printf("│   %sNo completed issues in period%s\n", dim, reset)
+		fmt.Fprintf(w, "│   %sNo completed issues in period%s\n", dim, reset)
 	}
 
-	fmt.Printf("│ %sCycle Time%s (in-progress → done):\n", bold, reset)
+	activeStart := m.ActiveStartStatus
+	if activeStart == "" {
+		activeStart = config.DefaultActiveStartStatus
+	}
+	fmt.Fprintf(w, "│ %sCycle Time%s (%s → done):\n", bold, reset, activeStart)
 	if m.CycleTime.Count > 0 {
-		fmt.Printf("│   Average: %s%.1f days%s  Median: %.1f  P85: %.1f\n",
-			bold, m.CycleTime.Average, reset, m.CycleTime.Median, m.CycleTime.P85)
+		fmt.Fprintf(w, "│   Average: %s%.1f days%s  Median: %.1f  P85: %.1f%s\n",
+			bold, m.CycleTime.Average, reset, m.CycleTime.Median, m.CycleTime.P85, outlierNote(m.CycleTime))
 	} else {
-		fmt.Printf("│   %sNo data%s\n", dim, reset)
+		fmt.Fprintf(w, "│   %sNo data%s\n", dim, reset)
 	}
 
-	fmt.Printf("│ %sThroughput%s:\n", bold, reset)
-	fmt.Printf("│   %s%d items%s completed │ %.2f/day │ %.1f/week\n",
+	fmt.Fprintf(w, "│ %sThroughput%s:\n", bold, reset)
+	fmt.Fprintf(w, "│   %s%d items%s completed │ %.2f/day │ %.1f/week\n",
 		bold, m.Throughput.Total, reset, m.Throughput.PerDay, m.Throughput.PerWeek)
 
 	if m.CycleTime.Count > 0 {
-		fmt.Printf("│ %sFlow Efficiency%s: %s%.0f%%%s\n", bold, reset, bold, m.FlowEfficiency, reset)
+		fmt.Fprintf(w, "│ %sFlow Efficiency%s: %s%.0f%%%s\n", bold, reset, bold, m.FlowEfficiency, reset)
 	} else {
-		fmt.Printf("│ %sFlow Efficiency%s: %sN/A%s (need cycle time data)\n", bold, reset, dim, reset)
+		fmt.Fprintf(w, "│ %sFlow Efficiency%s: %sN/A%s (need cycle time data)\n", bold, reset, dim, reset)
 	}
-	fmt.Printf("%s└────────────────────────────────────────────────────────────┘%s\n\n", cyan, reset)
+	fmt.Fprintf(w, "%s└────────────────────────────────────────────────────────────┘%s\n\n", cyan, reset)
 
 	// ═══ WIP METRICS ═══
-	fmt.Printf("%s%s┌─ WORK IN PROGRESS (WIP) ───────────────────────────────────┐%s\n", bold, yellow, reset)
+	fmt.Fprintf(w, "%s%s┌─ WORK IN PROGRESS (WIP) ───────────────────────────────────┐%s\n", bold, yellow, reset)
 
 	totalWIP := 0
-	for _, status := range []string{"backlog", "ready", "in-progress", "review", "testing", "done"} {
+	for _, status := range m.statuses() {
 		count := m.WIP[status]
 		totalWIP += count
 
 		limitStr := ""
 		barColor := ""
-		if limit, ok := m.WIPLimits["status: "+status]; ok {
+		if limit, ok := config.WIPLimit(m.WIPLimits, status); ok {
 			if count > limit {
 				barColor = red
 				limitStr = fmt.Sprintf(" %s⚠ OVER LIMIT (%d)%s", red, limit, reset)
@@ -833,74 +1550,183 @@ func printKanbanMetrics(m KanbanMetrics) {
 
 		bar := strings.Repeat("█", minInt(count, 20))
 		density := m.Density[status]
-		fmt.Printf("│ %-12s %s%3d%s %s%-20s%s %5.1f%%%s\n",
+		fmt.Fprintf(w, "│ %-12s %s%3d%s %s%-20s%s %5.1f%%%s\n",
 			status, barColor+bold, count, reset, barColor, bar, reset, density, limitStr)
 	}
-	fmt.Printf("│ %s%-12s %3d%s (Flow Load)\n", bold, "TOTAL", totalWIP, reset)
+	fmt.Fprintf(w, "│ %s%-12s %3d%s (Flow Load)\n", bold, "TOTAL", totalWIP, reset)
 
 	if m.WIPAge.Count > 0 {
-		fmt.Printf("│\n│ %sWIP Age%s: avg %.1f days │ median %.1f │ max %.1f\n",
+		fmt.Fprintf(w, "│\n│ %sWIP Age%s: avg %.1f days │ median %.1f │ max %.1f\n",
 			bold, reset, m.WIPAge.Average, m.WIPAge.Median, m.WIPAge.Max)
 	}
-	fmt.Printf("%s└────────────────────────────────────────────────────────────┘%s\n\n", yellow, reset)
+	fmt.Fprintf(w, "%s└────────────────────────────────────────────────────────────┘%s\n\n", yellow, reset)
+
+	// ═══ TIME IN STATUS ═══
+	if len(m.TimeInStatus) > 0 {
+		fmt.Fprintf(w, "%s%s┌─ TIME IN STATUS ───────────────────────────────────────────┐%s\n", bold, yellow, reset)
+		fmt.Fprintf(w, "│ %s%-12s %8s %8s %8s %6s%s\n", dim, "STATUS", "AVG", "MEDIAN", "P85", "N", reset)
+		var statuses []string
+		for status := range m.TimeInStatus {
+			statuses = append(statuses, status)
+		}
+		sortByWorkflow(statuses, m.statuses())
+		for _, status := range statuses {
+			s := m.TimeInStatus[status]
+			fmt.Fprintf(w, "│ %-12s %s%7.1fd%s %7.1fd %7.1fd %6d\n",
+				status, bold, s.Average, reset, s.Median, s.P85, s.Count)
+		}
+		fmt.Fprintf(w, "%s└────────────────────────────────────────────────────────────┘%s\n\n", yellow, reset)
+	}
 
 	// ═══ RATE METRICS ═══
-	fmt.Printf("%s%s┌─ RATE METRICS ─────────────────────────────────────────────┐%s\n", bold, green, reset)
-	fmt.Printf("│ %sArrival Rate%s:   %.2f items/day (new issues entering)\n", bold, reset, m.ArrivalRate)
-	fmt.Printf("│ %sDeparture Rate%s: %.2f items/day (issues completed)\n", bold, reset, m.DepartureRate)
+	fmt.Fprintf(w, "%s%s┌─ RATE METRICS ─────────────────────────────────────────────┐%s\n", bold, green, reset)
+	fmt.Fprintf(w, "│ %sArrival Rate%s:   %.2f items/day (new issues entering)\n", bold, reset, m.ArrivalRate)
+	fmt.Fprintf(w, "│ %sDeparture Rate%s: %.2f items/day (issues completed)\n", bold, reset, m.DepartureRate)
+	if v := m.Velocity; v != nil {
+		fmt.Fprintf(w, "│ %sVelocity%s:       %.1f points/week (%g points closed", bold, reset, v.PerWeek, v.Points)
+		if v.Unsized > 0 {
+			fmt.Fprintf(w, ", %s%d unsized%s", dim, v.Unsized, reset)
+		}
+		fmt.Fprintf(w, ")\n")
+	}
 
 	// Balance indicator
 	if m.ArrivalRate > 0 || m.DepartureRate > 0 {
 		balance := m.DepartureRate - m.ArrivalRate
 		if balance > 0.1 {
-			fmt.Printf("│ %s→ System draining (good)%s\n", green, reset)
+			fmt.Fprintf(w, "│ %s→ System draining (good)%s\n", green, reset)
 		} else if balance < -0.1 {
-			fmt.Printf("│ %s→ System accumulating (watch WIP)%s\n", yellow, reset)
+			fmt.Fprintf(w, "│ %s→ System accumulating (watch WIP)%s\n", yellow, reset)
 		} else {
-			fmt.Printf("│ → System balanced\n")
+			fmt.Fprintf(w, "│ → System balanced\n")
+		}
+	}
+	if len(m.DailyThroughput) > 0 {
+		values := make([]float64, len(m.DailyThroughput))
+		peak := 0
+		for i, n := range m.DailyThroughput {
+			values[i] = float64(n)
+			if n > peak {
+				peak = n
+			}
 		}
+		fmt.Fprintf(w, "│ %sDaily Closed%s:   %s%s%s (peak %d/day)\n", bold, reset, cyan, sparkline(values), reset, peak)
+	}
+	for _, caveat := range m.Caveats {
+		fmt.Fprintf(w, "│ %s⚠ %s%s\n", yellow, caveat, reset)
+	}
+	fmt.Fprintf(w, "%s└────────────────────────────────────────────────────────────┘%s\n\n", green, reset)
+
+	// ═══ TRIAGE ═══
+	if m.TriageLatency.Count > 0 || len(m.Untriaged) > 0 {
+		fmt.Fprintf(w, "%s%s┌─ TRIAGE ───────────────────────────────────────────────────┐%s\n", bold, cyan, reset)
+		fmt.Fprintf(w, "│ %sTriage Latency%s (creation → first status):\n", bold, reset)
+		if m.TriageLatency.Count > 0 {
+			fmt.Fprintf(w, "│   Average: %s%.1f days%s  Median: %.1f  (n=%d)\n",
+				bold, m.TriageLatency.Average, reset, m.TriageLatency.Median, m.TriageLatency.Count)
+		} else {
+			fmt.Fprintf(w, "│   %sNo issues triaged in period%s\n", dim, reset)
+		}
+		if len(m.Untriaged) > 0 {
+			fmt.Fprintf(w, "│ %sStill untriaged%s (longest waiting):\n", bold, reset)
+			for _, issue := range m.Untriaged {
+				fmt.Fprintf(w, "│   #%-4d %s%5.1fd%s %s\n",
+					issue.Number, getAgeColor(issue.WaitingDays), issue.WaitingDays, reset, issue.Title)
+			}
+		}
+		fmt.Fprintf(w, "%s└────────────────────────────────────────────────────────────┘%s\n\n", cyan, reset)
 	}
-	fmt.Printf("%s└────────────────────────────────────────────────────────────┘%s\n\n", green, reset)
 
 	// ═══ LITTLE'S LAW ═══
 	if m.LittlesLaw.CalculatedWIP > 0 {
-		fmt.Printf("%s%s┌─ LITTLE'S LAW ─────────────────────────────────────────────┐%s\n", bold, cyan, reset)
-		fmt.Printf("│ WIP = Throughput × Lead Time\n")
-		fmt.Printf("│ Predicted WIP: %.1f │ Actual WIP: %d │ Variance: %s%.0f%%%s\n",
+		fmt.Fprintf(w, "%s%s┌─ LITTLE'S LAW ─────────────────────────────────────────────┐%s\n", bold, cyan, reset)
+		fmt.Fprintf(w, "│ WIP = Throughput × Lead Time\n")
+		fmt.Fprintf(w, "│ Predicted WIP: %.1f │ Actual WIP: %d │ Variance: %s%.0f%%%s\n",
 			m.LittlesLaw.CalculatedWIP, m.LittlesLaw.ActualWIP,
 			getVarianceColor(m.LittlesLaw.Variance), m.LittlesLaw.Variance, reset)
-		fmt.Printf("%s└────────────────────────────────────────────────────────────┘%s\n\n", cyan, reset)
+		fmt.Fprintf(w, "%s└────────────────────────────────────────────────────────────┘%s\n\n", cyan, reset)
+	}
+
+	// ═══ BY ASSIGNEE ═══
+	if len(m.ByAssignee) > 0 {
+		fmt.Fprintf(w, "%s%s┌─ BY ASSIGNEE ──────────────────────────────────────────────┐%s\n", bold, cyan, reset)
+		names := make([]string, 0, len(m.ByAssignee))
+		for name := range m.ByAssignee {
+			names = append(names, name)
+		}
+		// Highest throughput first
+		sort.Slice(names, func(i, j int) bool {
+			a, b := m.ByAssignee[names[i]], m.ByAssignee[names[j]]
+			if a.Throughput != b.Throughput {
+				return a.Throughput > b.Throughput
+			}
+			return names[i] < names[j]
+		})
+		for _, name := range names {
+			s := m.ByAssignee[name]
+			label := name
+			if name != db.UnassignedBucket {
+				label = "@" + name
+			}
+			fmt.Fprintf(w, "│ %-20s %3d done │ Lead avg %5.1fd  median %5.1fd  P85 %5.1fd\n",
+				truncate(label, 20), s.Throughput, s.LeadTime.Average, s.LeadTime.Median, s.LeadTime.P85)
+		}
+		fmt.Fprintf(w, "%s└────────────────────────────────────────────────────────────┘%s\n\n", cyan, reset)
 	}
 
 	// ═══ AGING ISSUES ═══
 	if len(m.AgingIssues) > 0 {
-		fmt.Printf("%s%s┌─ AGING ISSUES (oldest first) ─────────────────────────────┐%s\n", bold, yellow, reset)
+		fmt.Fprintf(w, "%s%s┌─ AGING ISSUES (oldest first) ─────────────────────────────┐%s\n", bold, yellow, reset)
 		for _, issue := range m.AgingIssues {
 			assignee := ""
 			if issue.Assignee != "" {
 				assignee = fmt.Sprintf(" @%s", issue.Assignee)
 			}
 			ageColor := getAgeColor(issue.AgeDays)
-			blockedStr := formatBlockedTime(issue.BlockedHours, issue.IsBlocked)
-			fmt.Printf("│ #%-4d %s%5.1fd%s %-11s %-25s%s%s%s\n",
+			blockedStr := formatBlockedTime(issue.BlockedHours, issue.IsBlocked, issue.BlockedReason)
+			fmt.Fprintf(w, "│ #%-4d %s%5.1fd%s %-11s %-25s%s%s%s\n",
 				issue.Number, ageColor, issue.AgeDays, reset,
 				issue.Status, issue.Title, blockedStr, dim, assignee+reset)
 		}
-		fmt.Printf("%s└────────────────────────────────────────────────────────────┘%s\n\n", yellow, reset)
+		fmt.Fprintf(w, "%s└────────────────────────────────────────────────────────────┘%s\n\n", yellow, reset)
 	}
 
 	// ═══ BOTTLENECKS ═══
 	if len(m.Bottlenecks) > 0 {
-		fmt.Printf("%s%s┌─ ⚠ BOTTLENECKS & WARNINGS ─────────────────────────────────┐%s\n", bold, red, reset)
+		fmt.Fprintf(w, "%s%s┌─ ⚠ BOTTLENECKS & WARNINGS ─────────────────────────────────┐%s\n", bold, red, reset)
 		for _, b := range m.Bottlenecks {
-			fmt.Printf("│ %s⚠%s %s\n", red, reset, b)
+			fmt.Fprintf(w, "│ %s⚠%s %s\n", red, reset, b)
 		}
-		fmt.Printf("%s└────────────────────────────────────────────────────────────┘%s\n", red, reset)
+		fmt.Fprintf(w, "%s└────────────────────────────────────────────────────────────┘%s\n", red, reset)
 	} else {
-		fmt.Printf("%s%s✓ No bottlenecks detected - flow is healthy%s\n", bold, green, reset)
+		fmt.Fprintf(w, "%s%s✓ No bottlenecks detected - flow is healthy%s\n", bold, green, reset)
+	}
+
+	fmt.Fprintln(w)
+}
+
+// sparkBars are the eight bar heights a sparkline is drawn with
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws values as a one-line bar chart scaled to the largest
+// value. Zero and negative values are left blank so idle days (weekends)
+// show as gaps.
+func sparkline(values []float64) string {
+	peak := 0.0
+	for _, v := range values {
+		peak = math.Max(peak, v)
 	}
 
-	fmt.Println()
+	var b strings.Builder
+	for _, v := range values {
+		if v <= 0 {
+			b.WriteRune(' ')
+			continue
+		}
+		level := int(math.Ceil(v/peak*float64(len(sparkBars)))) - 1
+		b.WriteRune(sparkBars[minInt(level, len(sparkBars)-1)])
+	}
+	return b.String()
 }
 
 func getAgeColor(days float64) string {
@@ -927,3 +1753,58 @@ func minInt(a, b int) int {
 	}
 	return b
 }
+
+// writeMetricsCSV writes one row of flow stats per repo
+func writeMetricsCSV(w io.Writer, metrics []KanbanMetrics) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{"repo", "period_days",
+		"lead_time_avg_days", "lead_time_median_days", "lead_time_p85_days",
+		"cycle_time_avg_days", "cycle_time_median_days", "cycle_time_p85_days",
+		"throughput_total", "throughput_per_week",
+		"arrival_rate_per_day", "departure_rate_per_day", "flow_efficiency_percent"}
+	workflow := config.DefaultWorkflow
+	if len(metrics) > 0 {
+		workflow = metrics[0].statuses()
+	}
+	for _, status := range workflow {
+		header = append(header, "wip_"+status)
+	}
+	cw.Write(header)
+
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
+	for _, m := range metrics {
+		row := []string{m.Repo, strconv.Itoa(m.Period),
+			f(m.LeadTime.Average), f(m.LeadTime.Median), f(m.LeadTime.P85),
+			f(m.CycleTime.Average), f(m.CycleTime.Median), f(m.CycleTime.P85),
+			strconv.Itoa(m.Throughput.Total), f(m.Throughput.PerWeek),
+			f(m.ArrivalRate), f(m.DepartureRate), f(m.FlowEfficiency)}
+		for _, status := range workflow {
+			row = append(row, strconv.Itoa(m.WIP[status]))
+		}
+		cw.Write(row)
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeAgingCSV writes one row per aging issue across all repos
+func writeAgingCSV(w io.Writer, metrics []KanbanMetrics) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"repo", "number", "title", "status", "assignee", "age_days", "blocked_hours"})
+
+	for _, m := range metrics {
+		for _, issue := range m.AgingIssues {
+			repoName := issue.Repo
+			if repoName == "" {
+				repoName = m.Repo
+			}
+			cw.Write([]string{repoName, strconv.Itoa(issue.Number), issue.Title, issue.Status, issue.Assignee,
+				strconv.FormatFloat(issue.AgeDays, 'f', 1, 64), strconv.FormatFloat(issue.BlockedHours, 'f', 1, 64)})
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/kiracore/kanban/internal/db"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyMetricName string
+	historyDays       int
+)
+
+var metricsHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Chart one metric over time from daily snapshots",
+	Long: `Chart how one metric changed over the last --days, from the daily snapshots
+saved by 'kanban metrics snapshot'. Days without a snapshot are skipped.
+
+Metrics (the metrics_daily columns; lead_time, cycle_time and throughput
+are short for their 30-day columns):
+  ` + strings.Join(historyMetricNames(), ", ") + `
+
+Lead and cycle times are in days, rates per day and flow efficiency in percent.
+
+Examples:
+  kanban metrics history --org myorg --repo myrepo --metric lead_time
+  kanban metrics history --org myorg --all --metric wip_total --days 90
+  kanban metrics history --org myorg --repo myrepo --metric throughput_30d --format csv > throughput.csv`,
+	RunE: runMetricsHistory,
+}
+
+func init() {
+	metricsCmd.AddCommand(metricsHistoryCmd)
+	metricsHistoryCmd.Flags().StringVarP(&repo, "repo", "r", "", "specific repository")
+	metricsHistoryCmd.Flags().BoolVar(&allRepos, "all", false, "chart all cached repositories")
+	metricsHistoryCmd.Flags().StringVarP(&historyMetricName, "metric", "m", "lead_time_avg_30d", "metric to chart (see the list above)")
+	metricsHistoryCmd.Flags().IntVar(&historyDays, "days", 90, "days of snapshots to chart")
+	metricsHistoryCmd.Flags().StringVarP(&format, "format", "f", "table", "output format (table|csv|json)")
+	metricsHistoryCmd.Flags().StringVar(&outputFile, "output", "", "write the chart to this file instead of stdout")
+}
+
+// historyMetric is one metrics_daily column that can be charted
+type historyMetric struct {
+	name  string
+	alias string
+	unit  string
+	value func(m db.MetricsDaily) float64
+}
+
+var historyMetrics = []historyMetric{
+	{"wip_backlog", "", "", func(m db.MetricsDaily) float64 { return float64(m.WIPBacklog) }},
+	{"wip_ready", "", "", func(m db.MetricsDaily) float64 { return float64(m.WIPReady) }},
+	{"wip_in_progress", "", "", func(m db.MetricsDaily) float64 { return float64(m.WIPInProgress) }},
+	{"wip_review", "", "", func(m db.MetricsDaily) float64 { return float64(m.WIPReview) }},
+	{"wip_testing", "", "", func(m db.MetricsDaily) float64 { return float64(m.WIPTesting) }},
+	{"wip_done", "", "", func(m db.MetricsDaily) float64 { return float64(m.WIPDone) }},
+	{"wip_total", "", "", func(m db.MetricsDaily) float64 { return float64(m.WIPTotal) }},
+	{"throughput_30d", "throughput", "", func(m db.MetricsDaily) float64 { return float64(m.Throughput30d) }},
+	{"lead_time_avg_30d", "lead_time", "d", func(m db.MetricsDaily) float64 { return m.LeadTimeAvg30d }},
+	{"lead_time_p85_30d", "", "d", func(m db.MetricsDaily) float64 { return m.LeadTimeP8530d }},
+	{"cycle_time_avg_30d", "cycle_time", "d", func(m db.MetricsDaily) float64 { return m.CycleTimeAvg30d }},
+	{"cycle_time_p85_30d", "", "d", func(m db.MetricsDaily) float64 { return m.CycleTimeP8530d }},
+	{"arrival_rate", "", "/day", func(m db.MetricsDaily) float64 { return m.ArrivalRate }},
+	{"departure_rate", "", "/day", func(m db.MetricsDaily) float64 { return m.DepartureRate }},
+	{"littles_law_wip", "", "", func(m db.MetricsDaily) float64 { return m.LittlesLawWIP }},
+	{"littles_law_variance", "", "%", func(m db.MetricsDaily) float64 { return m.LittlesLawVariance }},
+	{"flow_efficiency", "", "%", func(m db.MetricsDaily) float64 { return m.FlowEfficiency }},
+}
+
+func historyMetricNames() []string {
+	names := make([]string, len(historyMetrics))
+	for i, hm := range historyMetrics {
+		names[i] = hm.name
+	}
+	return names
+}
+
+// findHistoryMetric looks a metric up by column name or alias
+func findHistoryMetric(name string) (historyMetric, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, hm := range historyMetrics {
+		if name == hm.name || (hm.alias != "" && name == hm.alias) {
+			return hm, nil
+		}
+	}
+	return historyMetric{}, fmt.Errorf("unknown metric %q (available: %s)", name, strings.Join(historyMetricNames(), ", "))
+}
+
+// MetricHistory is one metric of a repo over its daily snapshots
+type MetricHistory struct {
+	Repo   string         `json:"repo"`
+	Metric string         `json:"metric"`
+	Points []HistoryPoint `json:"points"`
+}
+
+// HistoryPoint is a metric's value in one daily snapshot
+type HistoryPoint struct {
+	Date  string  `json:"date"`
+	Value float64 `json:"value"`
+}
+
+func runMetricsHistory(cmd *cobra.Command, args []string) error {
+	metric, err := findHistoryMetric(historyMetricName)
+	if err != nil {
+		return err
+	}
+	if format != "table" && format != "csv" && format != "json" {
+		return fmt.Errorf("unsupported format: %s (use table, csv or json)", format)
+	}
+	if historyDays <= 0 {
+		return fmt.Errorf("--days must be positive")
+	}
+	if repo == "" && !allRepos {
+		return fmt.Errorf("specify --repo or --all")
+	}
+
+	orgs, err := resolveOrganizations()
+	if err != nil {
+		return err
+	}
+
+	database, err := db.OpenReadOnly(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w (run 'kanban sync' first)", err)
+	}
+	defer database.Close()
+
+	histories := []MetricHistory{}
+	for _, organization := range orgs {
+		repos, err := cachedRepos(database, organization)
+		if err != nil {
+			return err
+		}
+
+		for _, fullName := range repos {
+			repoID, err := database.GetRepoID(fullName)
+			if err != nil {
+				return fmt.Errorf("failed to look up %s: %w", fullName, err)
+			}
+			snapshots, err := database.GetMetricsHistory(repoID, historyDays)
+			if err != nil {
+				return fmt.Errorf("failed to get metrics history for %s: %w", fullName, err)
+			}
+
+			h := MetricHistory{Repo: displayRepo(organization, fullName), Metric: metric.name, Points: []HistoryPoint{}}
+			for _, s := range snapshots {
+				h.Points = append(h.Points, HistoryPoint{
+					Date:  s.SnapshotDate.Format("2006-01-02"),
+					Value: math.Round(metric.value(s)*100) / 100,
+				})
+			}
+			histories = append(histories, h)
+		}
+	}
+
+	if len(histories) == 0 {
+		return fmt.Errorf("no data found. Run 'kanban sync' first to populate the database")
+	}
+
+	w, closeOutput, err := openOutput()
+	if err != nil {
+		return err
+	}
+	defer closeOutput()
+
+	switch format {
+	case "json":
+		output, _ := json.MarshalIndent(histories, "", "  ")
+		fmt.Fprintln(w, string(output))
+		return nil
+	case "csv":
+		return writeHistoryCSV(w, histories)
+	}
+
+	for _, h := range histories {
+		printMetricHistory(w, h, metric.unit)
+	}
+	return nil
+}
+
+// writeHistoryCSV writes one row per repo and snapshot day
+func writeHistoryCSV(w io.Writer, histories []MetricHistory) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"repo", "date", histories[0].Metric})
+	for _, h := range histories {
+		for _, p := range h.Points {
+			cw.Write([]string{h.Repo, p.Date, strconv.FormatFloat(p.Value, 'f', -1, 64)})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func printMetricHistory(w io.Writer, h MetricHistory, unit string) {
+	reset := "\033[0m"
+	bold := "\033[1m"
+	cyan := "\033[36m"
+	green := "\033[32m"
+	dim := "\033[90m"
+
+	fmt.Fprintf(w, "\n%s%s  %s: %s%s\n", bold, cyan, strings.ToUpper(h.Metric), h.Repo, reset)
+	if len(h.Points) == 0 {
+		fmt.Fprintf(w, "%sNo snapshots in the last %d days (save them daily with 'kanban metrics snapshot')%s\n\n", dim, historyDays, reset)
+		return
+	}
+
+	values := make([]float64, len(h.Points))
+	low, high := math.Inf(1), math.Inf(-1)
+	for i, p := range h.Points {
+		values[i] = p.Value
+		low = math.Min(low, p.Value)
+		high = math.Max(high, p.Value)
+	}
+	fmt.Fprintf(w, "%s%d snapshots, %s to %s%s\n\n", dim, len(h.Points), h.Points[0].Date, h.Points[len(h.Points)-1].Date, reset)
+	fmt.Fprintf(w, "  %s%s%s  min %s, max %s, latest %s\n\n", green, sparkline(values), reset,
+		formatMetricValue(low, unit), formatMetricValue(high, unit), formatMetricValue(values[len(values)-1], unit))
+
+	// Negative values (Little's law variance) draw no bar
+	const width = 40
+	for _, p := range h.Points {
+		bar := 0
+		if high > 0 && p.Value > 0 {
+			bar = int(math.Round(p.Value / high * width))
+		}
+		fmt.Fprintf(w, "%s │ %s%s%s %s\n",
+			p.Date, green, strings.Repeat("█", bar)+strings.Repeat(" ", width-bar), reset, formatMetricValue(p.Value, unit))
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kiracore/kanban/internal/config"
+)
+
+// markdownEscaper keeps issue titles from breaking tables or turning into markup
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`",
+	"[", `\[`, "]", `\]`, "<", "&lt;", ">", "&gt;",
+)
+
+// printKanbanMetricsMarkdown renders metrics as GitHub-flavored Markdown for
+// PR comments and wiki pages: flow, WIP and rate tables, aging issues as a
+// list of #number references (auto-linked within the repo) and bottlenecks
+// as a blockquote
+func printKanbanMetricsMarkdown(w io.Writer, m KanbanMetrics) {
+	source := "cached"
+	if liveMode {
+		source = "live"
+	}
+
+	fmt.Fprintf(w, "## Kanban metrics: %s\n\n", markdownEscaper.Replace(m.Repo))
+	fmt.Fprintf(w, "_Generated %s from %s data · period %d days_\n\n",
+		m.Generated.Format("2006-01-02 15:04 UTC"), source, m.Period)
+
+	// Flow
+	activeStart := m.ActiveStartStatus
+	if activeStart == "" {
+		activeStart = config.DefaultActiveStartStatus
+	}
+	fmt.Fprintf(w, "### Flow\n\n")
+	fmt.Fprintf(w, "| Metric | Value |\n|---|---|\n")
+	fmt.Fprintf(w, "| Lead time (creation → done) | %s |\n", markdownTimeStats(m.LeadTime, true))
+	fmt.Fprintf(w, "| Cycle time (%s → done) | %s |\n", activeStart, markdownTimeStats(m.CycleTime, false))
+	fmt.Fprintf(w, "| Throughput | **%d** completed · %.2f/day · %.1f/week |\n",
+		m.Throughput.Total, m.Throughput.PerDay, m.Throughput.PerWeek)
+	if m.CycleTime.Count > 0 {
+		fmt.Fprintf(w, "| Flow efficiency | **%.0f%%** |\n", m.FlowEfficiency)
+	} else {
+		fmt.Fprintf(w, "| Flow efficiency | N/A (need cycle time data) |\n")
+	}
+	fmt.Fprintln(w)
+
+	// WIP
+	fmt.Fprintf(w, "### Work in progress\n\n")
+	fmt.Fprintf(w, "| Status | Issues | Share | Limit |\n|---|---:|---:|---|\n")
+	totalWIP := 0
+	for _, status := range m.statuses() {
+		count := m.WIP[status]
+		totalWIP += count
+
+		limitStr := ""
+		if limit, ok := config.WIPLimit(m.WIPLimits, status); ok {
+			limitStr = fmt.Sprintf("%d", limit)
+			if count > limit {
+				limitStr = fmt.Sprintf("⚠️ over (%d)", limit)
+			}
+		}
+		fmt.Fprintf(w, "| %s | %d | %.1f%% | %s |\n", status, count, m.Density[status], limitStr)
+	}
+	fmt.Fprintf(w, "| **Total** | **%d** | | |\n\n", totalWIP)
+	if m.WIPAge.Count > 0 {
+		fmt.Fprintf(w, "WIP age: avg %.1f days · median %.1f · max %.1f\n\n",
+			m.WIPAge.Average, m.WIPAge.Median, m.WIPAge.Max)
+	}
+
+	// Time in status
+	if len(m.TimeInStatus) > 0 {
+		fmt.Fprintf(w, "### Time in status\n\n")
+		fmt.Fprintf(w, "| Status | Average | Median | P85 | n |\n|---|---:|---:|---:|---:|\n")
+		var statuses []string
+		for status := range m.TimeInStatus {
+			statuses = append(statuses, status)
+		}
+		sortByWorkflow(statuses, m.statuses())
+		for _, status := range statuses {
+			s := m.TimeInStatus[status]
+			fmt.Fprintf(w, "| %s | %.1fd | %.1fd | %.1fd | %d |\n", status, s.Average, s.Median, s.P85, s.Count)
+		}
+		fmt.Fprintln(w)
+	}
+
+	// Rates
+	fmt.Fprintf(w, "### Rates\n\n")
+	fmt.Fprintf(w, "| Metric | Value |\n|---|---|\n")
+	fmt.Fprintf(w, "| Arrival rate | %.2f items/day |\n", m.ArrivalRate)
+	fmt.Fprintf(w, "| Departure rate | %.2f items/day |\n", m.DepartureRate)
+	if len(m.DailyThroughput) > 0 {
+		values := make([]float64, len(m.DailyThroughput))
+		for i, n := range m.DailyThroughput {
+			values[i] = float64(n)
+		}
+		fmt.Fprintf(w, "| Daily closed | `%s` |\n", sparkline(values))
+	}
+	if m.ArrivalRate > 0 || m.DepartureRate > 0 {
+		balance := m.DepartureRate - m.ArrivalRate
+		switch {
+		case balance > 0.1:
+			fmt.Fprintf(w, "| Balance | draining (good) |\n")
+		case balance < -0.1:
+			fmt.Fprintf(w, "| Balance | accumulating (watch WIP) |\n")
+		default:
+			fmt.Fprintf(w, "| Balance | balanced |\n")
+		}
+	}
+	fmt.Fprintln(w)
+	for _, caveat := range m.Caveats {
+		fmt.Fprintf(w, "_⚠️ %s_\n\n", markdownEscaper.Replace(caveat))
+	}
+
+	// Aging issues
+	if len(m.AgingIssues) > 0 {
+		fmt.Fprintf(w, "### Aging issues (oldest first)\n\n")
+		for _, issue := range m.AgingIssues {
+			// A code span shows the assignee without notifying them
+			assignee := ""
+			if issue.Assignee != "" {
+				assignee = " `@" + issue.Assignee + "`"
+			}
+			blocked := stripANSI(formatBlockedTime(issue.BlockedHours, issue.IsBlocked, issue.BlockedReason))
+			fmt.Fprintf(w, "- #%d **%.1fd** in %s: %s%s%s\n",
+				issue.Number, issue.AgeDays, issue.Status, markdownEscaper.Replace(issue.Title),
+				markdownEscaper.Replace(blocked), assignee)
+		}
+		fmt.Fprintln(w)
+	}
+
+	// Bottlenecks
+	fmt.Fprintf(w, "### Bottlenecks\n\n")
+	if len(m.Bottlenecks) == 0 {
+		fmt.Fprintf(w, "> ✅ No bottlenecks detected - flow is healthy\n\n")
+		return
+	}
+	for _, b := range m.Bottlenecks {
+		fmt.Fprintf(w, "> - ⚠️ %s\n", markdownEscaper.Replace(b))
+	}
+	fmt.Fprintln(w)
+}
+
+// markdownTimeStats formats average, median and P85 for a table cell
+func markdownTimeStats(s TimeStats, withCount bool) string {
+	if s.Count == 0 {
+		return "no data"
+	}
+	cell := fmt.Sprintf("**%.1f days** avg · median %.1f · P85 %.1f", s.Average, s.Median, s.P85)
+	if withCount {
+		cell += fmt.Sprintf(" (n=%d)", s.Count)
+	}
+	if s.OutliersExcluded > 0 {
+		cell += fmt.Sprintf(" · %d outlier(s) excluded", s.OutliersExcluded)
+	}
+	return cell
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kiracore/kanban/internal/config"
+	"github.com/kiracore/kanban/internal/db"
+	"github.com/kiracore/kanban/internal/github"
+)
+
+func TestFilterOutliers(t *testing.T) {
+	tests := []struct {
+		name        string
+		values      []float64
+		wantKept    []float64
+		wantRemoved int
+	}{
+		{"empty", nil, nil, 0},
+		{"single value", []float64{40}, []float64{40}, 0},
+		{"under four values", []float64{1, 2, 90}, []float64{1, 2, 90}, 0},
+		{"all equal", []float64{3, 3, 3, 3, 3}, []float64{3, 3, 3, 3, 3}, 0},
+		{"high outlier", []float64{2, 3, 60, 3, 4, 2}, []float64{2, 3, 3, 4, 2}, 1},
+		{"both fences", []float64{-50, 10, 11, 12, 13, 12, 11, 90}, []float64{10, 11, 12, 13, 12, 11}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := append([]float64(nil), tt.values...)
+			kept, removed := filterOutliers(tt.values)
+			if removed != tt.wantRemoved || !reflect.DeepEqual(kept, tt.wantKept) {
+				t.Errorf("filterOutliers(%v) = %v, %d; want %v, %d", tt.values, kept, removed, tt.wantKept, tt.wantRemoved)
+			}
+			if !reflect.DeepEqual(tt.values, orig) {
+				t.Errorf("filterOutliers modified its input: %v, was %v", tt.values, orig)
+			}
+		})
+	}
+}
+
+func TestApplyClosedIssueMetrics_OutliersDropWholeIssues(t *testing.T) {
+	defer func(v bool) { excludeOutliers = v }(excludeOutliers)
+	excludeOutliers = true
+
+	// #5's cycle time is an outlier; its efficiency (50%) must go with it
+	// while the others, all at 100%, stay
+	issues := []db.ClosedIssueStats{
+		{Number: 1, LeadTimeHours: 48, CycleTimeHours: 48},
+		{Number: 2, LeadTimeHours: 72, CycleTimeHours: 72},
+		{Number: 3, LeadTimeHours: 48, CycleTimeHours: 48},
+		{Number: 4, LeadTimeHours: 72, CycleTimeHours: 72},
+		{Number: 5, LeadTimeHours: 2400, CycleTimeHours: 1200},
+	}
+	var m KanbanMetrics
+	applyClosedIssueMetrics(&m, issues, 30)
+
+	if m.CycleTime.OutliersExcluded != 1 || m.CycleTime.Count != 4 {
+		t.Errorf("cycle time = %+v, want 4 values with 1 outlier excluded", m.CycleTime)
+	}
+	if m.FlowEfficiency != 100 {
+		t.Errorf("flow efficiency = %v, want 100 (outlier issue dropped)", m.FlowEfficiency)
+	}
+}
+
+func TestCalculateAssigneeStats_IgnoresAuthorsNotAssignees(t *testing.T) {
+	settings := config.Settings{IgnoreAuthors: []string{"*[bot]"}}
+	byAssignee := map[string][]db.ClosedIssueStats{
+		"alice": {
+			{Number: 1, LeadTimeHours: 24, Author: "bob"},
+			{Number: 2, LeadTimeHours: 240, Author: "renovate[bot]"},
+		},
+		// A bot assignee still counts for issues people opened
+		"copilot[bot]":      {{Number: 3, LeadTimeHours: 48, Author: "bob"}},
+		db.UnassignedBucket: {{Number: 4, LeadTimeHours: 24, Author: "dependabot[bot]"}},
+	}
+
+	stats := calculateAssigneeStats(byAssignee, settings)
+	if got := stats["alice"]; got.Throughput != 1 || got.LeadTime.Average != 1 {
+		t.Errorf("alice = %+v, want 1 issue at 1 day (bot-opened #2 left out)", got)
+	}
+	if got := stats["copilot[bot]"]; got.Throughput != 1 {
+		t.Errorf("copilot[bot] = %+v, want 1 issue", got)
+	}
+	if _, ok := stats[db.UnassignedBucket]; ok {
+		t.Errorf("%s only has a bot-opened issue and should be left out", db.UnassignedBucket)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   string
+	}{
+		{"empty", nil, ""},
+		{"all zero", []float64{0, 0, 0}, "   "},
+		{"single point", []float64{3}, "█"},
+		{"scaled to the peak", []float64{1, 2, 4, 8}, "▁▂▄█"},
+		{"idle days are gaps", []float64{4, 0, 8, -1, 0.1}, "▄ █ ▁"},
+	}
+	for _, tt := range tests {
+		if got := sparkline(tt.values); got != tt.want {
+			t.Errorf("%s: sparkline(%v) = %q, want %q", tt.name, tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestCollectKanbanMetrics_FlowEfficiencyIsPerIssueOnly(t *testing.T) {
+	defer func(timeline bool, limit int) { withTimeline, timelineLimit = timeline, limit }(withTimeline, timelineLimit)
+	withTimeline, timelineLimit = true, 0
+
+	// One issue: 500h from creation to close, the last two in progress.
+	// Its own efficiency (0.4%) rounds to 0; the lead and cycle averages
+	// are both non-zero and must not be divided into a figure instead.
+	closed := time.Now().Add(-time.Hour).UTC()
+	dir := t.TempDir()
+	files := map[string]string{
+		"closed.json": fmt.Sprintf(`[{"number": 1, "title": "Done", "state": "CLOSED", "stateReason": "COMPLETED",
+			"createdAt": %q, "closedAt": %q, "labels": [], "author": {"login": "alice"}}]`,
+			closed.Add(-500*time.Hour).Format(time.RFC3339), closed.Format(time.RFC3339)),
+		"timeline.json": fmt.Sprintf(`[{"event": "labeled", "created_at": %q, "label": {"name": "status: in-progress"}}]`,
+			closed.Add(-2*time.Hour).Format(time.RFC3339)),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	script := "#!/bin/sh\ncase \"$*\" in\n*--state\\ closed*) cat " + filepath.Join(dir, "closed.json") + " ;;\n" +
+		"api*) cat " + filepath.Join(dir, "timeline.json") + " ;;\n*) echo '[]' ;;\nesac\n"
+	if err := os.WriteFile(filepath.Join(dir, "gh"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("GH_TOKEN", "")
+
+	client := github.NewClient(context.Background(), github.Options{})
+	m, err := collectKanbanMetrics(client, "acme", "app", 30, nil, config.Settings{}, 1)
+	if err != nil {
+		t.Fatalf("collectKanbanMetrics() error: %v", err)
+	}
+	if m.LeadTime.Average == 0 || m.CycleTime.Count != 1 || m.CycleTime.Average == 0 {
+		t.Fatalf("lead time = %+v, cycle time = %+v; want one issue with both", m.LeadTime, m.CycleTime)
+	}
+	if m.FlowEfficiency != 0 {
+		t.Errorf("flow efficiency = %v, want 0 from the per-issue ratio, not cycle/lead averages", m.FlowEfficiency)
+	}
+}
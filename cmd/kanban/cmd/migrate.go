@@ -5,7 +5,6 @@ import (
 	"os"
 
 	"github.com/kiracore/kanban/internal/config"
-	"github.com/kiracore/kanban/internal/github"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -79,25 +78,13 @@ func runMigrate(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  %s -> %s\n", m.From, m.To)
 	}
 
-	client := github.NewClient()
+	client := newGitHubClient(timeoutCtx)
 
 	// Determine target repos
-	var repos []string
-	var err error
-	if repo != "" {
-		repos = []string{repo}
-	} else if allRepos {
-		repos, err = client.ListRepos(organization)
-		if err != nil {
-			return err
-		}
-		// Filter repos based on config
-		cfg, _ := config.Load()
-		if cfg != nil {
-			repos = cfg.FilterRepos(repos)
-		}
-	} else {
-		return fmt.Errorf("specify --repo or --all")
+	cfg, _ := config.Load()
+	repos, _, err := resolveRepos(cfg, client, organization)
+	if err != nil {
+		return err
 	}
 
 	if len(repos) == 0 {
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kiracore/kanban/internal/config"
+	"github.com/kiracore/kanban/internal/db"
+	"github.com/kiracore/kanban/internal/notify"
+	"github.com/spf13/cobra"
+)
+
+var slackWebhook string
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Post bottleneck alerts to Slack",
+	Long: `Check the cached board for WIP-limit violations, stalled issues and
+blocked issues, and post a summary to a Slack incoming webhook. Nothing is
+sent when there are no alerts.
+
+Run it after a sync, e.g. from cron. The webhook comes from --slack-webhook
+or settings.slack_webhook; use --dry-run to print the message instead.
+
+Examples:
+  kanban sync --org myorg && kanban notify --org myorg
+  kanban notify --org myorg --repo myrepo --slack-webhook https://hooks.slack.com/services/...
+  kanban notify --org myorg --dry-run`,
+	RunE: runNotify,
+}
+
+func init() {
+	rootCmd.AddCommand(notifyCmd)
+	notifyCmd.Flags().StringVarP(&repo, "repo", "r", "", "specific repository (default: all synced repositories)")
+	notifyCmd.Flags().StringVar(&slackWebhook, "slack-webhook", "", "Slack incoming webhook URL (default: settings.slack_webhook)")
+}
+
+func runNotify(cmd *cobra.Command, args []string) error {
+	orgs, err := resolveOrganizations()
+	if err != nil {
+		return err
+	}
+
+	settings := config.Settings{}
+	if cfg, _ := config.Load(); cfg != nil {
+		settings = cfg.Settings
+	}
+	webhook := slackWebhook
+	if webhook == "" {
+		webhook = settings.SlackWebhook
+	}
+	if webhook == "" && !dryRun {
+		return fmt.Errorf("no Slack webhook: use --slack-webhook or set settings.slack_webhook")
+	}
+
+	report, err := buildAlertReport(orgs, settings)
+	if err != nil {
+		return err
+	}
+
+	if report.Alerts() == 0 {
+		fmt.Println("✓ No alerts, nothing sent")
+		return nil
+	}
+
+	slack := notify.NewSlack(webhook)
+	if dryRun {
+		payload, err := slack.Payload(report)
+		if err != nil {
+			return fmt.Errorf("failed to build slack message: %w", err)
+		}
+		fmt.Printf("Would post %d alert(s) to Slack:\n%s\n", report.Alerts(), payload)
+		return nil
+	}
+
+	if err := slack.Send(report); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Posted %d alert(s) to Slack\n", report.Alerts())
+	return nil
+}
+
+// buildAlertReport collects WIP-limit violations, stalled issues and blocked
+// issues from the cache
+func buildAlertReport(orgs []string, settings config.Settings) (notify.Report, error) {
+	report := notify.Report{Title: "Kanban alerts: " + strings.Join(orgs, ", ")}
+
+	var wipViolations []string
+	for _, organization := range orgs {
+		var columns []BoardColumn
+		for _, status := range settings.Statuses() {
+			columns = append(columns, BoardColumn{Name: status})
+		}
+		columns, _, err := runBoardCached(organization, columns)
+		if err != nil {
+			return report, err
+		}
+		wipViolations = append(wipViolations, checkWIPLimits(columns, settings.WIPLimits)...)
+	}
+
+	database, err := db.Open(dbPath)
+	if err != nil {
+		return report, fmt.Errorf("failed to open database: %w (run 'kanban sync' first)", err)
+	}
+	defer database.Close()
+	excludeIssues(database)
+
+	var stalledLines, blockedLines []string
+	for _, organization := range orgs {
+		repos, err := cachedRepos(database, organization)
+		if err != nil {
+			return report, err
+		}
+		for _, fullName := range repos {
+			inStatus, err := database.GetTimeInCurrentStatus(fullName)
+			if err != nil {
+				return report, fmt.Errorf("failed to get issues for %s: %w", fullName, err)
+			}
+			for _, s := range stalledIssues(organization, inStatus, settings.StaleThreshold(), settings.Statuses()) {
+				stalledLines = append(stalledLines, fmt.Sprintf("%s#%d %s: %s for %.0f days%s",
+					s.Repo, s.Number, s.Title, s.Status, s.DaysInStatus, assigneeSuffix(s.Assignee)))
+			}
+
+			blocked, err := database.GetBlockedIssues(fullName)
+			if err != nil {
+				return report, fmt.Errorf("failed to get blocked issues for %s: %w", fullName, err)
+			}
+			for _, b := range blocked {
+				line := fmt.Sprintf("%s#%d %s", displayRepo(organization, b.Repo), b.Number, b.Title)
+				if b.BlockedSince != nil {
+					line += ": blocked for " + formatAge(b.BlockedHours)
+				}
+				if b.Reason != "" {
+					line += " (" + b.Reason + ")"
+				}
+				blockedLines = append(blockedLines, line+assigneeSuffix(b.Assignee))
+			}
+		}
+	}
+
+	report.Add("WIP limits exceeded", wipViolations)
+	report.Add(fmt.Sprintf("Stalled issues (no status change in >%.0f days)", settings.StaleThreshold()), stalledLines)
+	report.Add("Blocked issues", blockedLines)
+	return report, nil
+}
+
+func assigneeSuffix(assignee string) string {
+	if assignee == "" {
+		return ""
+	}
+	return " @" + assignee
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kiracore/kanban/internal/config"
+	"github.com/kiracore/kanban/internal/github"
+	"github.com/spf13/viper"
+)
+
+// multipleOrgs is set when a command covers more than one organization, so
+// repo names are shown with their org prefix
+var multipleOrgs bool
+
+// resolveOrganizations returns the organizations a command covers: --org
+// when given, otherwise every organization in the config
+func resolveOrganizations() ([]string, error) {
+	orgs := []string{}
+	if org != "" {
+		orgs = append(orgs, org)
+	} else if cfg, _ := config.Load(); cfg != nil {
+		orgs = cfg.OrgList()
+	}
+	if len(orgs) == 0 {
+		if organization := viper.GetString("organization"); organization != "" {
+			orgs = append(orgs, organization)
+		}
+	}
+
+	if len(orgs) == 0 {
+		return nil, fmt.Errorf("organization required: use --org flag or set in config")
+	}
+	multipleOrgs = len(orgs) > 1
+	return orgs, nil
+}
+
+// repoForOrg returns the --repo name to use within organization. --repo may
+// be qualified as org/repo, in which case other organizations are skipped.
+func repoForOrg(organization string) (string, bool) {
+	if owner, name, ok := strings.Cut(repo, "/"); ok {
+		return name, strings.EqualFold(owner, organization)
+	}
+	return repo, true
+}
+
+// reposForOrg returns the --repos names to use within organization. Like
+// --repo, each may be qualified as org/repo to apply to one organization only.
+func reposForOrg(organization string) []string {
+	var names []string
+	for _, r := range strings.Split(repoList, ",") {
+		r = strings.TrimSpace(r)
+		if owner, name, ok := strings.Cut(r, "/"); ok {
+			if !strings.EqualFold(owner, organization) {
+				continue
+			}
+			r = name
+		}
+		if r != "" {
+			names = append(names, r)
+		}
+	}
+	return names
+}
+
+// selectedRepos returns the full names picked by --repos within
+// organization, lowercased, or nil without --repos
+func selectedRepos(organization string) map[string]bool {
+	if repoList == "" {
+		return nil
+	}
+	selected := make(map[string]bool)
+	for _, name := range reposForOrg(organization) {
+		selected[strings.ToLower(organization+"/"+name)] = true
+	}
+	return selected
+}
+
+// warnUncached warns about --repos entries with no cached data; seen holds
+// the lowercased full names that had some
+func warnUncached(selected, seen map[string]bool) {
+	for fullName := range selected {
+		if !seen[fullName] {
+			fmt.Fprintf(os.Stderr, "Warning: no cached data for %s, skipping (run 'kanban sync' first)\n", fullName)
+		}
+	}
+}
+
+// resolveRepos returns the repos a command works on in organization, in
+// order of precedence: --repos, --repo, repositories.list in the config,
+// then every repo with --all. The config's include/exclude patterns apply to
+// both the explicit list and --all. --repos entries that don't exist or
+// can't be accessed are skipped with a warning. ok is false when --repo or
+// --repos name no repo in this organization.
+func resolveRepos(cfg *config.LabelConfig, client *github.Client, organization string) (repos []string, ok bool, err error) {
+	switch {
+	case repoList != "":
+		for _, name := range reposForOrg(organization) {
+			if err := client.RepoExists(organization, name); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v, skipping\n", err)
+				continue
+			}
+			repos = append(repos, name)
+		}
+		return repos, len(repos) > 0, nil
+	case repo != "":
+		name, ok := repoForOrg(organization)
+		if !ok {
+			return nil, false, nil
+		}
+		return []string{name}, true, nil
+	case cfg != nil && cfg.HasExplicitRepos():
+		return cfg.FilterRepos(organization, cfg.GetRepos(organization)), true, nil
+	case allRepos:
+		repos, err := client.ListRepos(organization)
+		if err != nil {
+			return nil, false, err
+		}
+		if cfg != nil {
+			repos = cfg.FilterRepos(organization, repos)
+		}
+		return repos, true, nil
+	}
+	return nil, false, fmt.Errorf("specify --repo, --repos, --all, or define repositories.list in config")
+}
+
+// displayRepo returns how a repo is labelled in output: its bare name for a
+// single organization, org/name when several are covered
+func displayRepo(organization, fullName string) string {
+	if multipleOrgs {
+		return fullName
+	}
+	return strings.TrimPrefix(fullName, organization+"/")
+}
+
+// inOrg reports whether a cached repo full name belongs to organization
+func inOrg(organization, fullName string) bool {
+	return strings.HasPrefix(strings.ToLower(fullName), strings.ToLower(organization)+"/")
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+)
+
+var (
+	outputFile string
+	forceColor bool
+	noColor    bool
+)
+
+// printNDJSON writes each item as one JSON object per line (newline-delimited JSON)
+func printNDJSON[T any](w io.Writer, items []T) error {
+	enc := json.NewEncoder(w)
+	for _, item := range items {
+		if err := enc.Encode(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openOutput returns where a report is written, stdout or the --output file,
+// with ANSI colors stripped unless useColor allows them. Call closeOutput
+// when done.
+func openOutput() (w io.Writer, closeOutput func() error, err error) {
+	if forceColor && noColor {
+		return nil, nil, fmt.Errorf("--color and --no-color cannot be used together")
+	}
+
+	f, closeOutput := os.Stdout, func() error { return nil }
+	if outputFile != "" {
+		if f, err = os.Create(outputFile); err != nil {
+			return nil, nil, fmt.Errorf("failed to create output file: %w", err)
+		}
+		closeOutput = f.Close
+	}
+
+	if useColor(f) {
+		return f, closeOutput, nil
+	}
+	return ansiStripper{f}, closeOutput, nil
+}
+
+// useColor reports whether output to f keeps ANSI colors. --color forces
+// them; --no-color, a non-empty NO_COLOR and output that isn't a terminal
+// (pipes, files, CI logs) turn them off.
+func useColor(f *os.File) bool {
+	if noColor {
+		return false
+	}
+	if forceColor {
+		return true
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+var ansiEscape = regexp.MustCompile("\033\\[[0-9;]*[A-Za-z]")
+
+// stripANSI removes terminal color and cursor escape sequences from s
+func stripANSI(s string) string {
+	return ansiEscape.ReplaceAllString(s, "")
+}
+
+// ansiStripper writes through to w with escape sequences removed. Each
+// write must hold whole sequences, as fmt.Fprintf calls do.
+type ansiStripper struct {
+	w io.Writer
+}
+
+func (a ansiStripper) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(a.w, stripANSI(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
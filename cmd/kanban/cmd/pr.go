@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kiracore/kanban/internal/db"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	prState       string
+	prLinkedState string
+)
+
+var prCmd = &cobra.Command{
+	Use:   "pr",
+	Short: "Show cached pull requests and their linked issues",
+	Long: `Show pull requests cached by 'kanban sync --with-prs'.
+
+Examples:
+  kanban pr list --org myorg --repo myrepo
+  kanban pr list --org myorg --repo myrepo --state merged --format json
+  kanban pr summary --org myorg --repo myrepo
+  kanban pr linked 42 --org myorg --repo myrepo`,
+}
+
+var prListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List pull requests with linked issues",
+	RunE:  runPRList,
+}
+
+var prSummaryCmd = &cobra.Command{
+	Use:   "summary",
+	Short: "Show PR counts and average review/merge time",
+	RunE:  runPRSummary,
+}
+
+var prLinkedCmd = &cobra.Command{
+	Use:   "linked <issue-number>",
+	Short: "List pull requests linked to an issue",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPRLinked,
+}
+
+func init() {
+	rootCmd.AddCommand(prCmd)
+	prCmd.AddCommand(prListCmd)
+	prCmd.AddCommand(prSummaryCmd)
+	prCmd.AddCommand(prLinkedCmd)
+
+	prCmd.PersistentFlags().StringVarP(&repo, "repo", "r", "", "repository")
+	prCmd.PersistentFlags().StringVarP(&format, "format", "f", "table", "output format (table|json)")
+	prListCmd.Flags().StringVar(&prState, "state", "open", "PR state: open, merged, closed, all")
+	prLinkedCmd.Flags().StringVar(&prLinkedState, "state", "all", "PR state: open, merged, closed, all")
+}
+
+// PRListItem is a pull request with the issue numbers it links
+type PRListItem struct {
+	db.PullRequest
+	LinkedIssues []int `json:"linked_issues"`
+}
+
+// openPRRepo opens the database and resolves --repo to its cached ID
+func openPRRepo() (*db.DB, string, int64, error) {
+	organization := viper.GetString("organization")
+	if organization == "" && org != "" {
+		organization = org
+	}
+	if organization == "" {
+		return nil, "", 0, fmt.Errorf("organization required: use --org flag or set in config")
+	}
+	if repo == "" {
+		return nil, "", 0, fmt.Errorf("--repo required")
+	}
+
+	database, err := db.Open(dbPath)
+	if err != nil {
+		return nil, "", 0, fmt.Errorf("failed to open database: %w (run 'kanban sync --with-prs' first)", err)
+	}
+
+	fullName := fmt.Sprintf("%s/%s", organization, repo)
+	repoID, err := database.GetRepoID(fullName)
+	if errors.Is(err, sql.ErrNoRows) {
+		database.Close()
+		return nil, "", 0, fmt.Errorf("%s is not cached (run 'kanban sync --with-prs' first)", fullName)
+	} else if err != nil {
+		database.Close()
+		return nil, "", 0, err
+	}
+	return database, fullName, repoID, nil
+}
+
+// prStateFilter maps --state to the stored GitHub state
+func prStateFilter(state string) (string, error) {
+	switch strings.ToLower(state) {
+	case "open", "merged", "closed":
+		return strings.ToUpper(state), nil
+	case "all", "":
+		return "all", nil
+	default:
+		return "", fmt.Errorf("invalid --state %q (use open, merged, closed or all)", state)
+	}
+}
+
+func runPRList(cmd *cobra.Command, args []string) error {
+	state, err := prStateFilter(prState)
+	if err != nil {
+		return err
+	}
+
+	database, fullName, repoID, err := openPRRepo()
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	prs, err := database.GetPRsByRepo(repoID, state)
+	if err != nil {
+		return fmt.Errorf("failed to get pull requests: %w", err)
+	}
+
+	return printPRs(database, fmt.Sprintf("%s pull requests (%s)", fullName, strings.ToLower(state)), prs)
+}
+
+func runPRLinked(cmd *cobra.Command, args []string) error {
+	number, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+	if err != nil {
+		return fmt.Errorf("invalid issue number %q", args[0])
+	}
+	state, err := prStateFilter(prLinkedState)
+	if err != nil {
+		return err
+	}
+
+	database, fullName, repoID, err := openPRRepo()
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	prs, err := database.GetPRsForIssue(repoID, number)
+	if err != nil {
+		return fmt.Errorf("failed to get linked pull requests: %w", err)
+	}
+	if state != "all" {
+		filtered := []db.PullRequest{}
+		for _, pr := range prs {
+			if pr.State == state {
+				filtered = append(filtered, pr)
+			}
+		}
+		prs = filtered
+	}
+
+	return printPRs(database, fmt.Sprintf("Pull requests linked to %s#%d", fullName, number), prs)
+}
+
+func printPRs(database *db.DB, heading string, prs []db.PullRequest) error {
+	items := []PRListItem{}
+	for _, pr := range prs {
+		linked, err := database.GetLinkedIssues(pr.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get linked issues for #%d: %w", pr.Number, err)
+		}
+		if linked == nil {
+			linked = []int{}
+		}
+		items = append(items, PRListItem{PullRequest: pr, LinkedIssues: linked})
+	}
+
+	w, closeOutput, err := openOutput()
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		output, _ := json.MarshalIndent(items, "", "  ")
+		fmt.Fprintln(w, string(output))
+		return closeOutput()
+	}
+
+	reset := "\033[0m"
+	bold := "\033[1m"
+	cyan := "\033[36m"
+	green := "\033[32m"
+	purple := "\033[35m"
+	red := "\033[31m"
+	dim := "\033[90m"
+
+	fmt.Fprintf(w, "\n%s  %s (%d)%s\n\n", bold, heading, len(items), reset)
+	if len(items) == 0 {
+		fmt.Fprintf(w, "%sNo pull requests.%s\n\n", dim, reset)
+		return closeOutput()
+	}
+
+	for _, item := range items {
+		state, color := strings.ToLower(item.State), green
+		switch item.State {
+		case "MERGED":
+			color = purple
+		case "CLOSED":
+			color = red
+		}
+		if item.IsDraft && item.State == "OPEN" {
+			state, color = "draft", dim
+		}
+
+		author := ""
+		if item.Author != "" {
+			author = fmt.Sprintf(" %s@%s%s", cyan, item.Author, reset)
+		}
+
+		links := ""
+		if len(item.LinkedIssues) > 0 {
+			nums := make([]string, len(item.LinkedIssues))
+			for i, n := range item.LinkedIssues {
+				nums[i] = fmt.Sprintf("#%d", n)
+			}
+			links = fmt.Sprintf(" %s→ %s%s", dim, strings.Join(nums, ", "), reset)
+		}
+
+		fmt.Fprintf(w, "  #%-5d %s%-7s%s %s%s %s+%d/-%d%s%s\n",
+			item.Number, color, state, reset, truncate(displayTitle(item.Title), 50), author,
+			dim, item.Additions, item.Deletions, reset, links)
+	}
+	fmt.Fprintln(w)
+	return closeOutput()
+}
+
+func runPRSummary(cmd *cobra.Command, args []string) error {
+	database, fullName, _, err := openPRRepo()
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	summary, err := database.GetPRSummary(fullName)
+	if err != nil {
+		return fmt.Errorf("failed to get PR summary: %w", err)
+	}
+
+	w, closeOutput, err := openOutput()
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		output, _ := json.MarshalIndent(summary, "", "  ")
+		fmt.Fprintln(w, string(output))
+		return closeOutput()
+	}
+
+	reset := "\033[0m"
+	bold := "\033[1m"
+	cyan := "\033[36m"
+	dim := "\033[90m"
+
+	fmt.Fprintf(w, "\n%s%s  %s - PULL REQUESTS%s\n\n", bold, cyan, fullName, reset)
+	fmt.Fprintf(w, "  Open:              %d %s(%d draft)%s\n", summary.OpenPRs, dim, summary.DraftPRs, reset)
+	fmt.Fprintf(w, "  Merged (30d):      %d\n", summary.MergedLast30d)
+	fmt.Fprintf(w, "  Avg review time:   %s\n", formatPRDuration(summary.AvgReviewTimeHrs))
+	fmt.Fprintf(w, "  Avg merge time:    %s\n", formatPRDuration(summary.AvgMergeTimeHrs))
+	fmt.Fprintf(w, "  Avg size:          %s+%.0f/-%.0f%s\n\n", dim, summary.AvgAdditions, summary.AvgDeletions, reset)
+	return closeOutput()
+}
+
+// formatPRDuration colors a review/merge time like aging in metrics
+func formatPRDuration(hours float64) string {
+	if hours <= 0 {
+		return "\033[90mn/a\033[0m"
+	}
+	color := getAgeColor(hours / 24)
+	if color == "" {
+		color = "\033[32m"
+	}
+	return fmt.Sprintf("%s%s\033[0m", color, formatAge(hours))
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// confirm asks a yes/no question on stderr and reads the answer from stdin.
+// --yes answers for the user. Without a terminal to ask on the answer is
+// no, so scripts have to pass --yes; so is anything other than y/yes.
+func confirm(question string) bool {
+	if assumeYes {
+		return true
+	}
+	if fi, err := os.Stdin.Stat(); err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		fmt.Fprintf(os.Stderr, "%s [y/N]: no (stdin is not a terminal; pass --yes to confirm)\n", question)
+		return false
+	}
+
+	fmt.Fprintf(os.Stderr, "%s [y/N]: ", question)
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		fmt.Fprintln(os.Stderr)
+		return false
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/kiracore/kanban/internal/config"
+	"github.com/kiracore/kanban/internal/db"
+)
+
+// StatusRegression is a backward move, e.g. review -> in-progress
+type StatusRegression struct {
+	Repo   string    `json:"repo"`
+	Number int       `json:"number"`
+	Title  string    `json:"title"`
+	From   string    `json:"from"`
+	To     string    `json:"to"`
+	At     time.Time `json:"at"`
+}
+
+// RegressionReport lists backward transitions and their count per repo
+type RegressionReport struct {
+	Days        int                `json:"days"`
+	ByRepo      map[string]int     `json:"by_repo"`
+	Regressions []StatusRegression `json:"regressions"`
+}
+
+// runStatusRegressions reports backward status transitions from cached data
+func runStatusRegressions(organization string) error {
+	if liveMode {
+		return fmt.Errorf("--regressions uses cached data; run 'kanban sync' instead of --live")
+	}
+
+	database, err := db.OpenReadOnly(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w (run 'kanban sync' first)", err)
+	}
+	defer database.Close()
+
+	repoFilter := ""
+	if repo != "" {
+		repoFilter = fmt.Sprintf("%s/%s", organization, repo)
+	}
+
+	issues, err := database.GetTransitionedIssues(repoFilter)
+	if err != nil {
+		return fmt.Errorf("failed to get issues: %w", err)
+	}
+
+	var settings config.Settings
+	if cfg, _ := config.Load(); cfg != nil {
+		settings = cfg.Settings
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	report := RegressionReport{Days: days, ByRepo: make(map[string]int)}
+	for _, issue := range issues {
+		transitions, err := database.GetStatusTransitions(issue.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get transitions for %s#%d: %w", issue.Repo, issue.Number, err)
+		}
+		for _, t := range transitions {
+			if t.TransitionedAt.Before(since) || !settings.IsRegression(t.FromStatus, t.ToStatus) {
+				continue
+			}
+			report.Regressions = append(report.Regressions, StatusRegression{
+				Repo:   issue.Repo,
+				Number: issue.Number,
+				Title:  issue.Title,
+				From:   t.FromStatus,
+				To:     t.ToStatus,
+				At:     t.TransitionedAt,
+			})
+			report.ByRepo[issue.Repo]++
+		}
+	}
+
+	// Most recent first
+	sort.SliceStable(report.Regressions, func(i, j int) bool {
+		return report.Regressions[i].At.After(report.Regressions[j].At)
+	})
+
+	w, closeOutput, err := openOutput()
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		output, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Fprintln(w, string(output))
+		return closeOutput()
+	}
+
+	printRegressions(w, report)
+	return closeOutput()
+}
+
+func printRegressions(w io.Writer, r RegressionReport) {
+	reset := "\033[0m"
+	bold := "\033[1m"
+	cyan := "\033[36m"
+	yellow := "\033[33m"
+	dim := "\033[90m"
+
+	fmt.Fprintf(w, "\n%s%s  STATUS REGRESSIONS (last %d days)%s\n\n", bold, cyan, r.Days, reset)
+
+	if len(r.Regressions) == 0 {
+		fmt.Fprintf(w, "%sNo backward transitions.%s\n\n", dim, reset)
+		return
+	}
+
+	for _, reg := range r.Regressions {
+		fmt.Fprintf(w, "  %s#%-4d %s%-11s → %-11s%s %s %s%s%s\n",
+			reg.Repo, reg.Number, yellow, reg.From, reg.To, reset,
+			truncate(displayTitle(reg.Title), 40), dim, reg.At.Local().Format("2006-01-02 15:04"), reset)
+	}
+
+	repos := make([]string, 0, len(r.ByRepo))
+	for name := range r.ByRepo {
+		repos = append(repos, name)
+	}
+	sort.Slice(repos, func(i, j int) bool {
+		if r.ByRepo[repos[i]] != r.ByRepo[repos[j]] {
+			return r.ByRepo[repos[i]] > r.ByRepo[repos[j]]
+		}
+		return repos[i] < repos[j]
+	})
+
+	fmt.Fprintf(w, "\n%sBy repository:%s\n", bold, reset)
+	for _, name := range repos {
+		fmt.Fprintf(w, "  %-40s %3d\n", name, r.ByRepo[name])
+	}
+	fmt.Fprintln(w)
+}
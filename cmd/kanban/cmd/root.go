@@ -1,9 +1,14 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/kiracore/kanban/internal/config"
+	"github.com/kiracore/kanban/internal/github"
 	"github.com/kiracore/kanban/internal/paths"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -16,15 +21,26 @@ var (
 	BuildDate = "unknown"
 
 	// Global flags
-	cfgFile string
-	org     string
-	dryRun  bool
-	verbose bool
+	cfgFile     string
+	org         string
+	dryRun      bool
+	verbose     bool
+	showTimings bool
+	ghTimeout   time.Duration
+	assumeYes   bool
+
+	// timeoutCtx expires when --timeout elapses; cancelTimeout releases it
+	timeoutCtx                       = context.Background()
+	cancelTimeout context.CancelFunc = func() {}
 
 	// Shared command flags
-	format string
+	format              string
+	concurrencyOverride int // --concurrency on sync, audit and metrics; 0 = settings.concurrency
 )
 
+// defaultConcurrency is used when neither --concurrency nor settings.concurrency is set
+const defaultConcurrency = 5
+
 // rootCmd represents the base command
 var rootCmd = &cobra.Command{
 	Use:   "kanban",
@@ -42,6 +58,7 @@ Example:
 
 // Execute runs the root command
 func Execute() error {
+	defer func() { cancelTimeout() }()
 	return rootCmd.Execute()
 }
 
@@ -53,6 +70,10 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&org, "org", "o", "", "GitHub organization")
 	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "show what would happen without making changes")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().BoolVar(&showTimings, "timings", false, "print how long each phase took")
+	rootCmd.PersistentFlags().DurationVar(&ghTimeout, "timeout", 0, "abort GitHub calls after this long, e.g. 10m (0 = no limit)")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "plain output without ANSI colors (also set by NO_COLOR)")
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "answer yes to confirmation prompts (for scripts)")
 
 	// Bind flags to viper
 	viper.BindPFlag("organization", rootCmd.PersistentFlags().Lookup("org"))
@@ -74,8 +95,10 @@ func initConfig() {
 		viper.SetConfigName(".kanban") // matches .kanban.yaml in current dir
 	}
 
-	// Read environment variables
+	// Read environment variables; nested keys use underscores,
+	// e.g. KANBAN_SETTINGS_CONCURRENCY for settings.concurrency
 	viper.SetEnvPrefix("KANBAN")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.AutomaticEnv()
 
 	// Try to read config file
@@ -92,4 +115,49 @@ func initConfig() {
 			}
 		}
 	}
+
+	if ghTimeout > 0 {
+		timeoutCtx, cancelTimeout = context.WithTimeout(context.Background(), ghTimeout)
+	}
+}
+
+// newGitHubClient creates a client whose gh calls are bounded by ctx and use
+// settings.max_retries, settings.github_host, settings.use_gh_token and
+// settings.label_delimiter
+func newGitHubClient(ctx context.Context) *github.Client {
+	opts := github.Options{MaxRetries: config.DefaultMaxRetries}
+	if cfg, err := config.Load(); err == nil {
+		opts.MaxRetries = cfg.Settings.MaxRetries
+		opts.Host = cfg.Settings.GitHubHost
+		opts.UseGHToken = cfg.Settings.UseGHToken
+		opts.LabelDelimiter = cfg.Settings.LabelDelimiter
+	}
+	return github.NewClient(ctx, opts)
+}
+
+// validateConcurrency checks a --concurrency given on the command line,
+// warning above the threshold the config validator warns at
+func validateConcurrency(cmd *cobra.Command) error {
+	if !cmd.Flags().Changed("concurrency") {
+		return nil
+	}
+	if concurrencyOverride < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+	if concurrencyOverride > 20 {
+		fmt.Fprintln(os.Stderr, "Warning: --concurrency > 20 may cause rate limiting")
+	}
+	return nil
+}
+
+// workerConcurrency returns how many GitHub calls to run in parallel:
+// --concurrency, else settings.concurrency, else defaultConcurrency
+func workerConcurrency() int {
+	if concurrencyOverride > 0 {
+		return concurrencyOverride
+	}
+	if n := viper.GetInt("settings.concurrency"); n > 0 {
+		return n
+	}
+	return defaultConcurrency
 }
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/kiracore/kanban/internal/config"
+	"github.com/kiracore/kanban/internal/db"
+	"github.com/spf13/cobra"
+)
+
+var (
+	searchLabels   []string
+	searchStatus   string
+	searchPriority string
+	searchType     string
+	searchAssignee string
+	searchBlocked  bool
+	searchState    string
+	searchLimit    int
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search [text]",
+	Short: "Search cached issues by title, labels and fields",
+	Long: `Search the issues cached by 'kanban sync' without calling GitHub.
+
+The text matches anywhere in the title, ignoring case. Every filter given
+must match. --label may be repeated; the issue must carry all of them, and
+spaces and case are ignored, so "type:bug" finds "type: bug". Label filters
+need the issue labels recorded by sync ('kanban sync --full' fills them in
+for issues cached before). Results are most recently updated first.
+
+Examples:
+  kanban search auth
+  kanban search "auth" --label type:bug --status in-progress --assignee alice
+  kanban search --blocked --state all --repo myrepo
+  kanban search login --format json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSearch,
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	searchCmd.Flags().StringVarP(&repo, "repo", "r", "", "only search this repository")
+	searchCmd.Flags().StringSliceVarP(&searchLabels, "label", "l", nil, "only issues with this label (repeatable)")
+	searchCmd.Flags().StringVarP(&searchStatus, "status", "s", "", "filter by status, e.g. in-progress")
+	searchCmd.Flags().StringVar(&searchPriority, "priority", "", "filter by priority, e.g. high")
+	searchCmd.Flags().StringVar(&searchType, "type", "", "filter by type, e.g. bug")
+	searchCmd.Flags().StringVarP(&searchAssignee, "assignee", "a", "", "filter by assignee username")
+	searchCmd.Flags().BoolVar(&searchBlocked, "blocked", false, "only blocked issues")
+	searchCmd.Flags().StringVar(&searchState, "state", "open", "issue state (open|closed|all)")
+	searchCmd.Flags().IntVar(&searchLimit, "limit", 50, "maximum results (0 for all)")
+	searchCmd.Flags().StringVarP(&format, "format", "f", "table", "output format (table|json)")
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	state := strings.ToLower(searchState)
+	switch state {
+	case "open", "closed":
+	case "all":
+		state = ""
+	default:
+		return fmt.Errorf("invalid --state %q: use open, closed or all", searchState)
+	}
+	if searchLimit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+
+	orgs, err := resolveOrganizations()
+	if err != nil {
+		return err
+	}
+
+	database, err := db.OpenReadOnly(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w (run 'kanban sync' first)", err)
+	}
+	defer database.Close()
+
+	delimiter := labelDelimiter()
+	search := db.IssueSearch{
+		Labels:   searchLabels,
+		Status:   trimLabelPrefix(searchStatus, "status", delimiter),
+		Priority: trimLabelPrefix(searchPriority, "priority", delimiter),
+		Type:     trimLabelPrefix(searchType, "type", delimiter),
+		Assignee: strings.TrimPrefix(searchAssignee, "@"),
+		Blocked:  searchBlocked,
+		State:    state,
+		Limit:    searchLimit,
+	}
+	if len(args) > 0 {
+		search.Text = args[0]
+	}
+
+	var results []db.SearchedIssue
+	for _, organization := range orgs {
+		repoName, ok := repoForOrg(organization)
+		if !ok {
+			continue
+		}
+		search.Org = organization
+		search.Repo = ""
+		if repoName != "" {
+			search.Repo = fmt.Sprintf("%s/%s", organization, repoName)
+		}
+
+		issues, err := database.SearchIssues(search)
+		if err != nil {
+			return fmt.Errorf("failed to search %s: %w", organization, err)
+		}
+		for i := range issues {
+			issues[i].Repo = displayRepo(organization, issues[i].Repo)
+		}
+		results = append(results, issues...)
+	}
+
+	// Each organization was limited on its own
+	sort.SliceStable(results, func(i, j int) bool { return results[i].UpdatedAt.After(results[j].UpdatedAt) })
+	if searchLimit > 0 && len(results) > searchLimit {
+		results = results[:searchLimit]
+	}
+
+	w, closeOutput, err := openOutput()
+	if err != nil {
+		return err
+	}
+	defer closeOutput()
+
+	if format == "json" {
+		if results == nil {
+			results = []db.SearchedIssue{}
+		}
+		output, _ := json.MarshalIndent(results, "", "  ")
+		fmt.Fprintln(w, string(output))
+		return nil
+	}
+
+	printSearchResults(w, results)
+	return nil
+}
+
+// trimLabelPrefix accepts a field filter written as its label, so
+// "status: review" and "review" both search for review
+func trimLabelPrefix(value, category, delimiter string) string {
+	if v, ok := config.LabelValue(value, category, delimiter); ok {
+		return v
+	}
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+func printSearchResults(w io.Writer, results []db.SearchedIssue) {
+	reset := "\033[0m"
+	red := "\033[31m"
+	dim := "\033[90m"
+
+	if len(results) == 0 {
+		fmt.Fprintf(w, "%sNo matching issues.%s\n", dim, reset)
+		return
+	}
+
+	fmt.Fprintf(w, "\n  %-28s %-7s %-12s %-14s %s\n", "ISSUE", "STATE", "STATUS", "ASSIGNEE", "TITLE")
+	for _, issue := range results {
+		status := issue.Status
+		if status == "" {
+			status = "-"
+		}
+		assignee := "-"
+		if issue.Assignee != "" {
+			assignee = "@" + issue.Assignee
+		}
+		blocked := ""
+		if issue.IsBlocked {
+			blocked = red + " [blocked]" + reset
+		}
+		fmt.Fprintf(w, "  %-28s %-7s %-12s %-14s %s%s\n",
+			truncate(fmt.Sprintf("%s#%d", issue.Repo, issue.Number), 28), issue.State, truncate(status, 12),
+			truncate(assignee, 14), truncate(displayTitle(issue.Title), 50), blocked)
+	}
+	fmt.Fprintf(w, "\n%d issue(s)", len(results))
+	if searchLimit > 0 && len(results) == searchLimit {
+		fmt.Fprintf(w, " %s(limit reached; use --limit to see more)%s", dim, reset)
+	}
+	fmt.Fprintln(w)
+}
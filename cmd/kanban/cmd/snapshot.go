@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kiracore/kanban/internal/config"
+	"github.com/kiracore/kanban/internal/db"
+	"github.com/spf13/cobra"
+)
+
+// snapshotDays is the period daily snapshots measure flow over; the
+// metrics_daily columns are named for it
+const snapshotDays = 30
+
+var metricsSnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save today's cached metrics as a daily snapshot",
+	Long: `Compute the cached metrics of each repository over the last 30 days and
+save them as today's daily snapshot: WIP per status, throughput, lead and
+cycle time (average and P85, in days), arrival and departure rates, Little's
+law and flow efficiency. Snapshots feed history reports such as
+'kanban metrics history'.
+
+Run it once a day after a sync, e.g. from cron. Running it again the same day
+(UTC) replaces that day's snapshot.
+
+Examples:
+  kanban metrics snapshot --org myorg --all
+  kanban metrics snapshot --org myorg --repo myrepo
+
+  # crontab: sync and snapshot every night
+  30 2 * * * kanban sync --all && kanban metrics snapshot --all`,
+	RunE: runMetricsSnapshot,
+}
+
+func init() {
+	metricsCmd.AddCommand(metricsSnapshotCmd)
+	metricsSnapshotCmd.Flags().StringVarP(&repo, "repo", "r", "", "specific repository")
+	metricsSnapshotCmd.Flags().BoolVar(&allRepos, "all", false, "snapshot all cached repositories")
+}
+
+func runMetricsSnapshot(cmd *cobra.Command, args []string) error {
+	if repo == "" && !allRepos {
+		return fmt.Errorf("specify --repo or --all")
+	}
+
+	orgs, err := resolveOrganizations()
+	if err != nil {
+		return err
+	}
+
+	wipLimits := make(map[string]int)
+	if cfg, _ := config.Load(); cfg != nil {
+		wipLimits = cfg.Settings.WIPLimits
+	}
+
+	database, err := db.Open(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w (run 'kanban sync' first)", err)
+	}
+	defer database.Close()
+
+	today := time.Now().UTC()
+	saved := 0
+	for _, organization := range orgs {
+		allMetrics, err := collectMetricsCached(organization, snapshotDays, wipLimits)
+		if err != nil {
+			return err
+		}
+		for _, m := range allMetrics {
+			fullName := m.Repo
+			if !inOrg(organization, fullName) {
+				fullName = organization + "/" + m.Repo
+			}
+			repoID, err := database.GetRepoID(fullName)
+			if err != nil {
+				return fmt.Errorf("failed to look up %s: %w", fullName, err)
+			}
+
+			snapshot := dailySnapshot(m)
+			snapshot.RepoID = repoID
+			snapshot.SnapshotDate = today
+			if dryRun {
+				saved++
+				continue
+			}
+			if err := database.SaveMetricsSnapshot(&snapshot); err != nil {
+				return fmt.Errorf("failed to save snapshot for %s: %w", fullName, err)
+			}
+			saved++
+		}
+	}
+
+	if saved == 0 {
+		return fmt.Errorf("no data found. Run 'kanban sync' first to populate the database")
+	}
+	if dryRun {
+		fmt.Printf("Would save metrics snapshot for %s (%d repositories)\n", today.Format("2006-01-02"), saved)
+		return nil
+	}
+	fmt.Printf("✓ Saved metrics snapshot for %s (%d repositories)\n", today.Format("2006-01-02"), saved)
+	return nil
+}
+
+// dailySnapshot is the metrics_daily row for a repo's metrics; it's the
+// inverse of snapshotWIP for the WIP columns
+func dailySnapshot(m KanbanMetrics) db.MetricsDaily {
+	return db.MetricsDaily{
+		WIPBacklog:    m.WIP["backlog"],
+		WIPReady:      m.WIP["ready"],
+		WIPInProgress: m.WIP["in-progress"],
+		WIPReview:     m.WIP["review"],
+		WIPTesting:    m.WIP["testing"],
+		WIPDone:       m.WIP["done"],
+		WIPTotal:      m.FlowLoad,
+
+		Throughput30d:   m.Throughput.Total,
+		LeadTimeAvg30d:  m.LeadTime.Average,
+		LeadTimeP8530d:  m.LeadTime.P85,
+		CycleTimeAvg30d: m.CycleTime.Average,
+		CycleTimeP8530d: m.CycleTime.P85,
+
+		ArrivalRate:   m.ArrivalRate,
+		DepartureRate: m.DepartureRate,
+
+		LittlesLawWIP:      m.LittlesLaw.CalculatedWIP,
+		LittlesLawVariance: m.LittlesLaw.Variance,
+
+		FlowEfficiency: m.FlowEfficiency,
+	}
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/kiracore/kanban/internal/config"
+	"github.com/kiracore/kanban/internal/db"
+)
+
+var showStalled bool
+
+func init() {
+	metricsCmd.Flags().BoolVar(&showStalled, "stalled", false, "list open issues unchanged in status longer than settings.stale_threshold_days")
+}
+
+// StalledIssue is an open issue that has sat in one status too long. Unlike
+// aging, comments and other activity don't reset it; only a status change does.
+type StalledIssue struct {
+	Repo         string  `json:"repo"`
+	Number       int     `json:"number"`
+	Title        string  `json:"title"`
+	Status       string  `json:"status"`
+	Assignee     string  `json:"assignee,omitempty"`
+	DaysInStatus float64 `json:"days_in_status"`
+}
+
+// stalledIssues keeps the issues in status longer than thresholdDays. Issues
+// waiting in the intake status (the first of workflow) aren't work in
+// progress and are skipped.
+func stalledIssues(organization string, issues []db.IssueInStatus, thresholdDays float64, workflow []string) []StalledIssue {
+	var stalled []StalledIssue
+	for _, issue := range issues {
+		days := issue.HoursInStatus / 24
+		if days <= thresholdDays || issue.Status == workflow[0] {
+			continue
+		}
+		stalled = append(stalled, StalledIssue{
+			Repo:         displayRepo(organization, issue.Repo),
+			Number:       issue.Number,
+			Title:        issue.Title,
+			Status:       issue.Status,
+			Assignee:     issue.Assignee,
+			DaysInStatus: math.Round(days*10) / 10,
+		})
+	}
+	return stalled
+}
+
+// runStalledReport lists stalled issues from the cache, longest stalled first
+func runStalledReport(orgs []string) error {
+	if liveMode {
+		return fmt.Errorf("--stalled uses cached status transitions; run 'kanban sync' instead of --live")
+	}
+
+	var settings config.Settings
+	if cfg, _ := config.Load(); cfg != nil {
+		settings = cfg.Settings
+	}
+	threshold := settings.StaleThreshold()
+
+	database, err := db.OpenReadOnly(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w (run 'kanban sync' first)", err)
+	}
+	defer database.Close()
+	excludeIssues(database)
+
+	var stalled []StalledIssue
+	for _, organization := range orgs {
+		repos, err := cachedRepos(database, organization)
+		if err != nil {
+			return err
+		}
+		for _, fullName := range repos {
+			issues, err := database.GetTimeInCurrentStatus(fullName)
+			if err != nil {
+				return fmt.Errorf("failed to get issues for %s: %w", fullName, err)
+			}
+			stalled = append(stalled, stalledIssues(organization, issues, threshold, settings.Statuses())...)
+		}
+	}
+
+	w, closeOutput, err := openOutput()
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		if stalled == nil {
+			stalled = []StalledIssue{}
+		}
+		output, _ := json.MarshalIndent(stalled, "", "  ")
+		fmt.Fprintln(w, string(output))
+		return closeOutput()
+	}
+
+	printStalledIssues(w, stalled, threshold)
+	return closeOutput()
+}
+
+func printStalledIssues(w io.Writer, stalled []StalledIssue, threshold float64) {
+	reset := "\033[0m"
+	bold := "\033[1m"
+	yellow := "\033[33m"
+	green := "\033[32m"
+	dim := "\033[90m"
+
+	fmt.Fprintf(w, "\n%s%s  STALLED ISSUES%s %s(no status change in >%.0f days)%s\n\n", bold, yellow, reset, dim, threshold, reset)
+	if len(stalled) == 0 {
+		fmt.Fprintf(w, "%s✓ No stalled issues%s\n\n", green, reset)
+		return
+	}
+
+	fmt.Fprintf(w, "  %-20s %-6s %-12s %-8s %-14s %s\n", "REPO", "#", "STATUS", "DAYS", "ASSIGNEE", "TITLE")
+	for _, s := range stalled {
+		assignee := "-"
+		if s.Assignee != "" {
+			assignee = "@" + s.Assignee
+		}
+		fmt.Fprintf(w, "  %-20s %-6d %-12s %s%-8.1f%s %-14s %s\n",
+			truncate(s.Repo, 20), s.Number, s.Status, yellow, s.DaysInStatus, reset, truncate(assignee, 14), truncate(displayTitle(s.Title), 40))
+	}
+	fmt.Fprintf(w, "\n%d stalled issue(s)\n\n", len(stalled))
+}
@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/kiracore/kanban/internal/config"
+	"github.com/kiracore/kanban/internal/db"
+	"github.com/spf13/cobra"
+)
+
+// newStatusNode labels the source of an issue's first recorded status
+const newStatusNode = "(new)"
+
+var statsTransitions bool
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Report statistics from cached status history",
+	Long: `Report statistics from the status history cached by 'kanban sync'.
+
+--transitions counts status changes per edge (from -> to) over the last
+--days: how often items go ready -> in-progress, or bounce from review back
+to in-progress. --format dot renders the counts as a Graphviz flow diagram.
+
+Examples:
+  # Flow matrix for one repository
+  kanban stats --transitions --org myorg --repo myrepo
+
+  # All cached repositories over the last quarter
+  kanban stats --transitions --org myorg --all --days 90
+
+  # Render a flow diagram
+  kanban stats --transitions --org myorg --repo myrepo --format dot | dot -Tsvg > flow.svg`,
+	RunE: runStats,
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+	statsCmd.Flags().BoolVar(&statsTransitions, "transitions", false, "count status transitions per (from -> to) edge")
+	statsCmd.Flags().StringVarP(&repo, "repo", "r", "", "specific repository")
+	statsCmd.Flags().BoolVar(&allRepos, "all", false, "all cached repositories")
+	statsCmd.Flags().IntVar(&days, "days", 30, "time period in days")
+	statsCmd.Flags().StringVarP(&format, "format", "f", "table", "output format (table|json|dot)")
+	statsCmd.Flags().StringVar(&outputFile, "output", "", "write the report to this file instead of stdout")
+}
+
+// TransitionEdge is how often issues moved from one status to another. From
+// is empty for an issue's first recorded status.
+type TransitionEdge struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Count int    `json:"count"`
+}
+
+// TransitionReport counts status transitions by edge, most frequent first
+type TransitionReport struct {
+	Scope string           `json:"scope"`
+	Days  int              `json:"days"`
+	Total int              `json:"total"`
+	Edges []TransitionEdge `json:"edges"`
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	orgs, err := resolveOrganizations()
+	if err != nil {
+		return err
+	}
+	if !statsTransitions {
+		return fmt.Errorf("specify a report: --transitions")
+	}
+	if repo == "" && !allRepos {
+		return fmt.Errorf("specify --repo or --all")
+	}
+	if days < 1 {
+		return fmt.Errorf("--days must be at least 1")
+	}
+	if format != "table" && format != "json" && format != "dot" {
+		return fmt.Errorf("unsupported format: %s (use table, json or dot)", format)
+	}
+
+	database, err := db.OpenReadOnly(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w (run 'kanban sync' first)", err)
+	}
+	defer database.Close()
+
+	// Without --org the configured organizations' transitions add up
+	var scopes []string
+	matrix := make(map[[2]string]int)
+	for _, organization := range orgs {
+		repos, err := cachedRepos(database, organization)
+		if err != nil {
+			return err
+		}
+		if len(repos) == 0 {
+			continue
+		}
+		scope := organization + " (all repositories)"
+		if repoName, _ := repoForOrg(organization); repoName != "" {
+			scope = organization + "/" + repoName
+		}
+		scopes = append(scopes, scope)
+
+		for _, fullName := range repos {
+			repoID, err := database.GetRepoID(fullName)
+			if err != nil {
+				return fmt.Errorf("failed to look up %s: %w", fullName, err)
+			}
+			edges, err := database.GetTransitionMatrix(repoID, days)
+			if err != nil {
+				return fmt.Errorf("failed to get transitions for %s: %w", fullName, err)
+			}
+			for edge, count := range edges {
+				matrix[edge] += count
+			}
+		}
+	}
+	if len(scopes) == 0 {
+		return fmt.Errorf("no cached data for %s (run 'kanban sync' first)", strings.Join(orgs, ", "))
+	}
+
+	var settings config.Settings
+	if cfg, _ := config.Load(); cfg != nil {
+		settings = cfg.Settings
+	}
+	report := transitionReport(strings.Join(scopes, ", "), days, matrix, settings)
+
+	w, closeOutput, err := openOutput()
+	if err != nil {
+		return err
+	}
+	defer closeOutput()
+
+	switch format {
+	case "json":
+		output, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Fprintln(w, string(output))
+	case "dot":
+		printTransitionsDOT(w, report, settings)
+	default:
+		printTransitionMatrix(w, report, settings)
+	}
+	return nil
+}
+
+// transitionReport turns an edge matrix into a report, most frequent edges
+// first and ties in workflow order
+func transitionReport(scope string, days int, matrix map[[2]string]int, settings config.Settings) TransitionReport {
+	report := TransitionReport{Scope: scope, Days: days, Edges: []TransitionEdge{}}
+	for edge, count := range matrix {
+		report.Edges = append(report.Edges, TransitionEdge{From: edge[0], To: edge[1], Count: count})
+		report.Total += count
+	}
+	sort.Slice(report.Edges, func(i, j int) bool {
+		a, b := report.Edges[i], report.Edges[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.From != b.From {
+			return settings.StatusIndex(a.From) < settings.StatusIndex(b.From)
+		}
+		return settings.StatusIndex(a.To) < settings.StatusIndex(b.To)
+	})
+	return report
+}
+
+// statusNode names a status in the matrix and diagram
+func statusNode(status string) string {
+	if status == "" {
+		return newStatusNode
+	}
+	return status
+}
+
+// printTransitionMatrix writes a from x to table to w; backward moves are
+// highlighted
+func printTransitionMatrix(w io.Writer, r TransitionReport, settings config.Settings) {
+	reset := "\033[0m"
+	bold := "\033[1m"
+	cyan := "\033[36m"
+	yellow := "\033[33m"
+	dim := "\033[90m"
+
+	fmt.Fprintf(w, "\n%s%s  STATUS TRANSITIONS: %s (last %d days)%s\n\n", bold, cyan, r.Scope, r.Days, reset)
+
+	if len(r.Edges) == 0 {
+		fmt.Fprintf(w, "%sNo status transitions.%s\n\n", dim, reset)
+		return
+	}
+
+	counts := make(map[[2]string]int)
+	fromSeen := make(map[string]bool)
+	toSeen := make(map[string]bool)
+	for _, e := range r.Edges {
+		counts[[2]string{e.From, e.To}] = e.Count
+		fromSeen[e.From] = true
+		toSeen[e.To] = true
+	}
+	var froms, tos []string
+	for status := range fromSeen {
+		froms = append(froms, status)
+	}
+	for status := range toSeen {
+		tos = append(tos, status)
+	}
+	// The first-status row reads best on top
+	sortByWorkflow(froms, settings.Statuses())
+	sort.SliceStable(froms, func(i, j int) bool { return froms[i] == "" && froms[j] != "" })
+	sortByWorkflow(tos, settings.Statuses())
+
+	width := len(newStatusNode)
+	for _, status := range append(froms, tos...) {
+		if len(status) > width {
+			width = len(status)
+		}
+	}
+
+	fmt.Fprintf(w, "  %s%-*s%s", dim, width, "from \\ to", reset)
+	for _, to := range tos {
+		fmt.Fprintf(w, "  %s%*s%s", bold, width, statusNode(to), reset)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s\n", strings.Repeat("─", width+(width+2)*len(tos)))
+
+	for _, from := range froms {
+		fmt.Fprintf(w, "  %s%-*s%s", bold, width, statusNode(from), reset)
+		for _, to := range tos {
+			count := counts[[2]string{from, to}]
+			switch {
+			case count == 0:
+				fmt.Fprintf(w, "  %s%*s%s", dim, width, "-", reset)
+			case settings.IsRegression(from, to):
+				fmt.Fprintf(w, "  %s%*d%s", yellow, width, count, reset)
+			default:
+				fmt.Fprintf(w, "  %*d", width, count)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "\n%d transition(s) across %d edge(s)", r.Total, len(r.Edges))
+	fmt.Fprintf(w, " %s(backward moves in yellow)%s\n\n", dim, reset)
+}
+
+// printTransitionsDOT writes the report to w as a Graphviz digraph. Edge width
+// grows with frequency and backward moves are dashed.
+func printTransitionsDOT(w io.Writer, r TransitionReport, settings config.Settings) {
+	maxCount := 0
+	for _, e := range r.Edges {
+		if e.Count > maxCount {
+			maxCount = e.Count
+		}
+	}
+
+	fmt.Fprintln(w, "digraph transitions {")
+	fmt.Fprintf(w, "  label=%q;\n", fmt.Sprintf("%s, last %d days", r.Scope, r.Days))
+	fmt.Fprintln(w, "  rankdir=LR;")
+	fmt.Fprintln(w, "  node [shape=box, style=rounded];")
+	fmt.Fprintf(w, "  %q [shape=plaintext];\n", newStatusNode)
+	for _, e := range r.Edges {
+		attrs := fmt.Sprintf("label=\"%d\", penwidth=%.1f", e.Count, 1+4*float64(e.Count)/float64(maxCount))
+		if settings.IsRegression(e.From, e.To) {
+			attrs += ", style=dashed"
+		}
+		fmt.Fprintf(w, "  %q -> %q [%s];\n", statusNode(e.From), statusNode(e.To), attrs)
+	}
+	fmt.Fprintln(w, "}")
+}
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -11,7 +13,6 @@ import (
 	"github.com/kiracore/kanban/internal/db"
 	"github.com/kiracore/kanban/internal/github"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var syncCmd = &cobra.Command{
@@ -20,40 +21,100 @@ var syncCmd = &cobra.Command{
 	Long: `Sync labels from configuration to GitHub repositories and
 cache issues in the local database for board and metrics.
 
-Creates missing labels, updates existing ones if different,
-and optionally removes labels not in config (with --prune).`,
+Creates missing labels and updates existing ones if different.
+
+--prune-labels then deletes labels that aren't in the config from each
+repository, after listing them and asking for confirmation (skip it with
+--yes). It needs settings.preserve_unknown set to false, keeps labels
+matching settings.preserve_labels, and never deletes a status or priority
+label that an open issue still carries. --dry-run lists what would go.
+
+--prune deletes cached issues that GitHub no longer returns (deleted or
+transferred), with their history. It fetches every issue and skips repos
+whose fetch hit settings.fetch_limit.
+
+Issue sync is incremental by default: only issues updated since the
+repo's last sync are fetched. Use --since to pick the cutoff, or
+--full to re-fetch everything.
+
+Issues opened by settings.ignore_authors or --author-filter (bots such as
+dependabot[bot] and renovate[bot]) aren't cached, so they don't count in
+board or metrics; ones cached earlier are removed when they're fetched
+again. Run with --full once after adding an author to catch them all.
+
+Examples:
+  kanban sync --org myorg --all
+  kanban sync --org myorg --all --since 7d
+  kanban sync --org myorg --repo myrepo --since 2024-06-01
+  kanban sync --org myorg --all --full
+  kanban sync --org myorg --repo myrepo --issues-only --prune
+  kanban sync --org myorg --all --labels-only --prune-labels --dry-run
+  kanban sync --org myorg --repos api,web,docs
+  kanban sync --org myorg --all --full --author-filter '*[bot]'
+
+  # Count the issues a sync would add or update, writing nothing
+  kanban sync --org myorg --all --dry-run`,
 	RunE: runSync,
 }
 
 var (
 	prune        bool
+	pruneLabels  bool
 	labelsOnly   bool
 	issuesOnly   bool
 	fullSync     bool
 	withTimeline bool
 	withPRs      bool
+	syncSince    string
+	labelsFrom   string
+	authorFilter []string
 )
 
 func init() {
 	rootCmd.AddCommand(syncCmd)
 	syncCmd.Flags().StringVarP(&repo, "repo", "r", "", "specific repository")
+	syncCmd.Flags().StringVar(&repoList, "repos", "", "comma-separated repositories (overrides --repo and the config list)")
 	syncCmd.Flags().BoolVar(&allRepos, "all", false, "apply to all repositories")
-	syncCmd.Flags().BoolVar(&prune, "prune", false, "remove labels not in config")
+	syncCmd.Flags().BoolVar(&prune, "prune", false, "remove cached issues gone from GitHub")
+	syncCmd.Flags().BoolVar(&pruneLabels, "prune-labels", false, "delete labels not in config from GitHub (asks first)")
 	syncCmd.Flags().BoolVar(&labelsOnly, "labels-only", false, "only sync labels, skip issues")
 	syncCmd.Flags().BoolVar(&issuesOnly, "issues-only", false, "only sync issues, skip labels")
 	syncCmd.Flags().BoolVar(&fullSync, "full", false, "full sync (ignore last sync time)")
 	syncCmd.Flags().BoolVar(&withTimeline, "with-timeline", false, "fetch timeline for accurate cycle time (slower)")
 	syncCmd.Flags().BoolVar(&withPRs, "with-prs", false, "also sync pull requests and link them to issues")
+	syncCmd.Flags().StringVar(&labelsFrom, "labels-from", "", "sync labels from this file instead of config (labels only, not issues)")
+	syncCmd.Flags().StringVar(&syncSince, "since", "", "only sync issues updated since duration (24h, 7d) or date (2006-01-02)")
+	syncCmd.Flags().StringSliceVar(&authorFilter, "author-filter", nil, "skip issues opened by these logins or globs, e.g. 'renovate*' (added to settings.ignore_authors)")
+	syncCmd.Flags().IntVar(&concurrencyOverride, "concurrency", 0, "repos synced in parallel (overrides settings.concurrency)")
 }
 
+// lastSyncOverlap re-fetches a window before the last sync to cover
+// issues updated while that sync was running
+const lastSyncOverlap = time.Hour
+
 func runSync(cmd *cobra.Command, args []string) error {
-	organization := viper.GetString("organization")
-	if organization == "" && org != "" {
-		organization = org
+	if err := validateConcurrency(cmd); err != nil {
+		return err
+	}
+	orgs, err := resolveOrganizations()
+	if err != nil {
+		return err
 	}
 
-	if organization == "" {
-		return fmt.Errorf("organization required: use --org flag or set in config")
+	if syncSince != "" && fullSync {
+		return fmt.Errorf("--since and --full are mutually exclusive")
+	}
+	if syncSince != "" && prune {
+		return fmt.Errorf("--prune needs the full issue list and can't be used with --since")
+	}
+
+	var sinceCutoff time.Time
+	if syncSince != "" {
+		t, err := parseSince(syncSince, time.Now())
+		if err != nil {
+			return err
+		}
+		sinceCutoff = t
 	}
 
 	// Load config
@@ -74,33 +135,90 @@ func runSync(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to initialize database: %w", err)
 	}
 
+	// Cycle time starts at the configured active status
+	if err := database.SetActiveStartStatus(cfg.Settings.ActiveStart()); err != nil {
+		return fmt.Errorf("invalid settings.active_start_status: %w", err)
+	}
+
+	cfg.Settings.IgnoreAuthors = append(cfg.Settings.IgnoreAuthors, authorFilter...)
+
 	labels := cfg.AllLabels()
+	labelSource := "config"
+	if labelsFrom != "" {
+		if issuesOnly {
+			return fmt.Errorf("--labels-from and --issues-only are mutually exclusive")
+		}
+		labels, err = loadLabelsFile(labelsFrom)
+		if err != nil {
+			return err
+		}
+		labelSource = labelsFrom
+	}
+
 	if len(labels) == 0 && !issuesOnly {
-		return fmt.Errorf("no labels defined in config")
+		return fmt.Errorf("no labels defined in %s", labelSource)
 	}
 
 	if !issuesOnly {
-		fmt.Printf("Loaded %d labels from config\n", len(labels))
+		fmt.Printf("Loaded %d labels from %s\n", len(labels), labelSource)
 	}
 
-	client := github.NewClient()
+	if pruneLabels {
+		if issuesOnly {
+			return fmt.Errorf("--prune-labels and --issues-only are mutually exclusive")
+		}
+		if cfg.Settings.PreserveUnknown {
+			return fmt.Errorf("--prune-labels deletes labels not in config, which settings.preserve_unknown keeps; set it to false first")
+		}
+	}
 
-	// Determine target repos
-	var repos []string
-	if repo != "" {
-		repos = []string{repo}
-	} else if cfg.HasExplicitRepos() {
-		// Use explicit repo list from config
-		repos = cfg.GetRepos()
-	} else if allRepos {
-		// Fetch all and filter by patterns
-		repos, err = client.ListRepos(organization)
-		if err != nil {
+	// --timeout bounds the whole run; every gh call and each repo's start
+	// is checked against ctx
+	ctx := timeoutCtx
+	client := newGitHubClient(ctx)
+	sw := newStopwatch()
+	defer sw.print()
+
+	// Without --org every configured organization is synced; one failing
+	// org doesn't stop the others
+	var failedOrgs []string
+	for _, organization := range orgs {
+		if multipleOrgs {
+			fmt.Printf("\n━━ %s ━━\n", organization)
+		}
+		err := syncOrganization(ctx, organization, cfg, database, client, labels, sinceCutoff, sw)
+		if err == nil {
+			continue
+		}
+		if !multipleOrgs {
 			return err
 		}
-		repos = cfg.FilterRepos(repos)
-	} else {
-		return fmt.Errorf("specify --repo, --all, or define repositories.list in config")
+		fmt.Fprintf(os.Stderr, "%s: %v\n", organization, err)
+		failedOrgs = append(failedOrgs, organization)
+	}
+
+	if len(failedOrgs) > 0 {
+		return fmt.Errorf("sync failed for %s", strings.Join(failedOrgs, ", "))
+	}
+	return nil
+}
+
+// syncOrganization syncs labels and issues for the target repos of one organization
+func syncOrganization(ctx context.Context, organization string, cfg *config.LabelConfig, database *db.DB, client *github.Client,
+	labels []config.Label, sinceCutoff time.Time, sw *stopwatch) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("sync cancelled: --timeout %s expired", ghTimeout)
+	}
+
+	// Determine target repos
+	stop := sw.start("repo listing")
+	repos, ok, err := resolveRepos(cfg, client, organization)
+	stop()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
 	}
 
 	if len(repos) == 0 {
@@ -109,55 +227,114 @@ func runSync(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("Syncing %d repositories...\n", len(repos))
 
+	// Get or create organization in DB; a dry run writes nothing
+	var dbOrg *db.Organization
 	if dryRun {
 		fmt.Println("\n[DRY RUN - no changes will be made]")
+	} else {
+		dbOrg, err = database.GetOrCreateOrg(organization)
+		if err != nil {
+			return fmt.Errorf("failed to create organization in DB: %w", err)
+		}
 	}
 
-	// Get or create organization in DB
-	dbOrg, err := database.GetOrCreateOrg(organization)
-	if err != nil {
-		return fmt.Errorf("failed to create organization in DB: %w", err)
+	// Load project statuses once for all repos when configured as the source
+	var projectSource *github.ProjectStatusSource
+	if cfg.Settings.UsesProjects() && !labelsOnly {
+		p := cfg.Settings.Project
+		projectSource = github.NewProjectStatusSource(client, organization, p.Number, p.StatusField, p.StatusMap)
+		stop := sw.start("project fetch")
+		err := projectSource.Load()
+		stop()
+		if err != nil {
+			return err
+		}
 	}
 
-	// Sync repos (with concurrency limit)
-	concurrency := viper.GetInt("settings.concurrency")
-	if concurrency == 0 {
-		concurrency = 5
-	}
+	fetchLimit := cfg.Settings.FetchLimit
 
-	sem := make(chan struct{}, concurrency)
+	// Sync repos (with concurrency limit)
+	sem := make(chan struct{}, workerConcurrency())
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	var syncErrors []string
 	var totalIssues int
 
-	for _, r := range repos {
+	for i, r := range repos {
+		// --timeout expired: launch none of the remaining repos
+		if ctx.Err() != nil {
+			mu.Lock()
+			for _, repoName := range repos[i:] {
+				syncErrors = append(syncErrors, fmt.Sprintf("%s: not started: --timeout %s expired", repoName, ghTimeout))
+			}
+			mu.Unlock()
+			break
+		}
+
 		wg.Add(1)
 		go func(repoName string) {
 			defer wg.Done()
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
-			fullName := fmt.Sprintf("%s/%s", organization, repoName)
-			fmt.Printf("\nSyncing %s...\n", fullName)
-
-			// Get or create repo in DB
-			dbRepo, err := database.GetOrCreateRepo(dbOrg.ID, repoName, fullName)
-			if err != nil {
+			// --timeout expired while this repo waited for a slot: don't
+			// start it, so it records no sync at all
+			if ctx.Err() != nil {
 				mu.Lock()
-				syncErrors = append(syncErrors, fmt.Sprintf("%s: %v", repoName, err))
+				syncErrors = append(syncErrors, fmt.Sprintf("%s: not started: --timeout %s expired", repoName, ghTimeout))
 				mu.Unlock()
 				return
 			}
 
+			fullName := fmt.Sprintf("%s/%s", organization, repoName)
+			fmt.Printf("\nSyncing %s...\n", fullName)
+
+			// Get or create repo in DB; a dry run only looks it up, and a
+			// repo that was never synced has ID 0 and no cached issues
+			var dbRepo *db.Repository
+			var err error
+			if dryRun {
+				dbRepo = &db.Repository{Name: repoName, FullName: fullName}
+				dbRepo.ID, _ = database.GetRepoID(fullName)
+			} else {
+				dbRepo, err = database.GetOrCreateRepo(dbOrg.ID, repoName, fullName)
+				if err != nil {
+					mu.Lock()
+					syncErrors = append(syncErrors, fmt.Sprintf("%s: %v", repoName, err))
+					mu.Unlock()
+					return
+				}
+			}
+
+			// Determine incremental cutoff: --since, else last sync unless --full
+			// Moving a project card doesn't bump the issue's updatedAt, so
+			// project-sourced statuses need every issue unless --since is given.
+			// --prune needs every issue to tell which ones are gone.
+			since := sinceCutoff
+			if since.IsZero() && !fullSync && !prune && projectSource == nil {
+				if lastSync, err := database.GetRepoLastSync(dbRepo.ID); err == nil && lastSync != nil {
+					since = lastSync.Add(-lastSyncOverlap)
+				}
+			}
+
+			syncType := "full"
+			if !since.IsZero() {
+				syncType = "incremental"
+			}
+
 			// Record sync start
-			syncID, _ := database.RecordSyncStart(&dbRepo.ID, "full")
+			var syncID int64
+			if !dryRun {
+				syncID, _ = database.RecordSyncStart(&dbRepo.ID, syncType)
+			}
 
 			var itemsSynced int
 			var syncErr string
+			var truncated bool
 
 			// Sync labels to GitHub (only if needed)
 			if !issuesOnly && !dryRun {
+				stopLabels := sw.start("label sync")
 				// Check if labels need syncing by comparing with DB cache
 				names := make([]string, len(labels))
 				colors := make([]string, len(labels))
@@ -196,6 +373,7 @@ func runSync(cmd *cobra.Command, args []string) error {
 								Name:        l.Name,
 								Color:       l.Color,
 								Description: l.Description,
+								Category:    config.LabelCategory(l.Name),
 							}
 							database.UpsertLabel(dbLabel)
 						}
@@ -203,145 +381,164 @@ func runSync(cmd *cobra.Command, args []string) error {
 				} else {
 					fmt.Printf("  Labels up-to-date (skipped)\n")
 				}
+				stopLabels()
 			}
 
 			// Sync issues from GitHub to DB
 			if !labelsOnly {
-				issues, err := client.ListAllIssues(organization, repoName, 500)
+				stopFetch := sw.start("issue fetch")
+				var issues []github.IssueDetails
+				issues, truncated, err = fetchIssues(client, organization, repoName, since, fetchLimit)
+				stopFetch()
+				if truncated {
+					warnTruncated(fullName, len(issues))
+				}
+				var ignored []int
+				issues, ignored = splitIgnoredAuthors(issues, cfg.Settings)
+				if len(ignored) > 0 {
+					fmt.Printf("  Skipped %d issues by ignored authors\n", len(ignored))
+				}
 				if err != nil {
 					mu.Lock()
 					syncErrors = append(syncErrors, fmt.Sprintf("%s issues: %v", repoName, err))
 					mu.Unlock()
 					fmt.Fprintf(os.Stderr, "  Issues error: %v\n", err)
 					syncErr = err.Error()
-				} else {
-					for _, issue := range issues {
-						if dryRun {
-							continue
+				} else if dryRun {
+					changes, err := planIssueChanges(database, dbRepo.ID, fullName, issues, projectSource, cfg.Settings.Delimiter())
+					if err != nil {
+						mu.Lock()
+						syncErrors = append(syncErrors, fmt.Sprintf("%s issues: %v", repoName, err))
+						mu.Unlock()
+						fmt.Fprintf(os.Stderr, "  Issues error: %v\n", err)
+					} else {
+						mu.Lock()
+						totalIssues += len(issues)
+						mu.Unlock()
+						fmt.Printf("  Would sync %d issues: %d new, %d updated, %d status transitions\n",
+							len(issues), changes.Inserts, changes.Updates, changes.Transitions)
+						if prune && !pruneSkipped(truncated) {
+							fmt.Printf("  Would prune %d issues no longer on GitHub\n", changes.Prunes)
 						}
+					}
+				} else {
+					removeIgnoredIssues(database, dbRepo.ID, ignored)
+					dbIssues := make([]*db.Issue, len(issues))
+					for i, issue := range issues {
+						dbIssues[i] = buildDBIssue(dbRepo.ID, fullName, issue, projectSource, cfg.Settings.Delimiter())
+						dbIssues[i].SizePoints = sizePoints(dbIssues[i], cfg.Settings)
+					}
 
-						dbIssue := &db.Issue{
-							RepoID:      dbRepo.ID,
-							Number:      issue.Number,
-							Title:       issue.Title,
-							State:       strings.ToLower(issue.State),
-							GHCreatedAt: issue.CreatedAt,
-							GHUpdatedAt: issue.UpdatedAt,
-							Assignee:    issue.Assignee,
+					// One transaction for the repo's issues; a failure leaves none saved
+					stopWrite := sw.start("db writes")
+					err := database.UpsertIssueBatch(dbIssues)
+					stopWrite()
+					if err != nil {
+						mu.Lock()
+						syncErrors = append(syncErrors, fmt.Sprintf("%s issues: %v", repoName, err))
+						mu.Unlock()
+						fmt.Fprintf(os.Stderr, "  Issues error: failed to save issues: %v\n", err)
+						syncErr = err.Error()
+					} else {
+						// Label usage for 'labels list --with-counts'; not worth failing the sync over
+						issueLabels := make(map[int64][]string, len(issues))
+						for i, issue := range issues {
+							issueLabels[dbIssues[i].ID] = issue.Labels
 						}
-
-						if !issue.ClosedAt.IsZero() {
-							dbIssue.GHClosedAt = &issue.ClosedAt
+						stopWrite := sw.start("db writes")
+						if err := database.ReplaceIssueLabels(dbRepo.ID, issueLabels, config.LabelCategory); err != nil {
+							fmt.Fprintf(os.Stderr, "  Warning: failed to save issue labels: %v\n", err)
 						}
-
-						// Parse labels for status, priority, type, size
-						for _, label := range issue.Labels {
-							lower := strings.ToLower(label)
-							if strings.HasPrefix(lower, "status:") {
-								dbIssue.CurrentStatus = strings.TrimPrefix(lower, "status:")
-								dbIssue.CurrentStatus = strings.TrimSpace(dbIssue.CurrentStatus)
-							} else if strings.HasPrefix(lower, "priority:") {
-								dbIssue.CurrentPriority = strings.TrimPrefix(lower, "priority:")
-								dbIssue.CurrentPriority = strings.TrimSpace(dbIssue.CurrentPriority)
-							} else if strings.HasPrefix(lower, "type:") {
-								dbIssue.CurrentType = strings.TrimPrefix(lower, "type:")
-								dbIssue.CurrentType = strings.TrimSpace(dbIssue.CurrentType)
-							} else if strings.HasPrefix(lower, "size:") {
-								dbIssue.CurrentSize = strings.TrimPrefix(lower, "size:")
-								dbIssue.CurrentSize = strings.TrimSpace(dbIssue.CurrentSize)
-							} else if label == "blocked" {
-								dbIssue.IsBlocked = true
+						stopWrite()
+
+						// Second pass: cycle times and timelines need the saved IDs
+						for i, issue := range issues {
+							dbIssue := dbIssues[i]
+							stopWrite := sw.start("db writes")
+
+							// Recalc cycle time for closed issues (uses closed_at as done time)
+							// Inconsistencies are reported after the timeline pass when one follows
+							if dbIssue.GHClosedAt != nil {
+								if err := database.RecalcCycleTime(dbIssue.ID); errors.Is(err, db.ErrCycleExceedsLead) && !withTimeline {
+									fmt.Fprintf(os.Stderr, "  Warning: %v\n", err)
+								}
 							}
-						}
+							stopWrite()
+
+							// Fetch timeline for accurate timestamps if requested
+							if withTimeline && dbIssue.CurrentStatus != "" {
+								stopTimeline := sw.start("timeline fetch")
+								timeline, err := client.GetIssueTimeline(organization, repoName, issue.Number)
+								stopTimeline()
+								if err == nil && timeline != nil {
+									stopWrite := sw.start("db writes")
+									// Update status timestamps
+									database.UpdateIssueTimestamps(dbIssue.ID, workflowEntryTimes(cfg.Settings, timeline.StatusChanges))
+									database.RecordStatusTimestamps(dbIssue.ID, timeline.StatusChanges)
+
+									// Record blocked periods, replacing the last sync's
+									periods := make([]db.BlockedPeriod, 0, len(timeline.BlockedPeriods))
+									for _, bp := range timeline.BlockedPeriods {
+										period := db.BlockedPeriod{BlockedAt: bp.Start, Reason: bp.Reason}
+										if !bp.End.IsZero() {
+											end := bp.End
+											period.UnblockedAt = &end
+										}
+										periods = append(periods, period)
+									}
+									database.ReplaceBlockedPeriods(dbIssue.ID, periods)
 
-						// Calculate lead time for closed issues
-						if dbIssue.GHClosedAt != nil {
-							dbIssue.LeadTimeHours = dbIssue.GHClosedAt.Sub(dbIssue.GHCreatedAt).Hours()
-							// Treat closed as "done" for status if no done label
-							if dbIssue.CurrentStatus == "" {
-								dbIssue.CurrentStatus = "done"
+									// Update blocked time and recalc cycle time
+									if timeline.TotalBlocked > 0 {
+										database.UpdateIssueBlockedTime(dbIssue.ID, timeline.TotalBlocked)
+									}
+									if err := database.RecalcCycleTime(dbIssue.ID); errors.Is(err, db.ErrCycleExceedsLead) {
+										fmt.Fprintf(os.Stderr, "  Warning: %v\n", err)
+									}
+									stopWrite()
+								}
 							}
+							itemsSynced++
 						}
 
-						if err := database.UpsertIssue(dbIssue); err != nil {
-							fmt.Fprintf(os.Stderr, "  Warning: failed to save issue #%d: %v\n", issue.Number, err)
-							continue
-						}
+						mu.Lock()
+						totalIssues += len(issues)
+						mu.Unlock()
 
-						// Recalc cycle time for closed issues (uses closed_at as done time)
-						if dbIssue.GHClosedAt != nil {
-							database.RecalcCycleTime(dbIssue.ID)
+						sinceInfo := ""
+						if !since.IsZero() {
+							sinceInfo = fmt.Sprintf(" (updated since %s)", since.Local().Format("2006-01-02 15:04"))
 						}
-
-						// Fetch timeline for accurate timestamps if requested
-						if withTimeline && dbIssue.CurrentStatus != "" {
-							timeline, err := client.GetIssueTimeline(organization, repoName, issue.Number)
-							if err == nil && timeline != nil {
-								// Update status timestamps
-								var ready, progress, review, testing, done *time.Time
-								if t, ok := timeline.StatusChanges["ready"]; ok {
-									ready = &t
-								}
-								if t, ok := timeline.StatusChanges["in-progress"]; ok {
-									progress = &t
-								}
-								if t, ok := timeline.StatusChanges["review"]; ok {
-									review = &t
-								}
-								if t, ok := timeline.StatusChanges["testing"]; ok {
-									testing = &t
-								}
-								if t, ok := timeline.StatusChanges["done"]; ok {
-									done = &t
-								}
-								database.UpdateIssueTimestamps(dbIssue.ID, ready, progress, review, testing, done)
-
-								// Record blocked periods
-								for _, bp := range timeline.BlockedPeriods {
-									start := bp.Start
-									var end *time.Time
-									if !bp.End.IsZero() {
-										end = &bp.End
-									}
-									database.RecordBlockedPeriod(dbIssue.ID, &start, end, "")
-								}
-
-								// Update blocked time and recalc cycle time
-								if timeline.TotalBlocked > 0 {
-									database.UpdateIssueBlockedTime(dbIssue.ID, timeline.TotalBlocked)
-								}
-								database.RecalcCycleTime(dbIssue.ID)
-							}
+						if withTimeline {
+							fmt.Printf("  %d issues synced%s (with timeline)\n", len(issues), sinceInfo)
+						} else {
+							fmt.Printf("  %d issues synced%s\n", len(issues), sinceInfo)
 						}
-						itemsSynced++
-					}
 
-					mu.Lock()
-					totalIssues += len(issues)
-					mu.Unlock()
-
-					if withTimeline {
-						fmt.Printf("  %d issues synced (with timeline)\n", len(issues))
-					} else {
-						fmt.Printf("  %d issues synced\n", len(issues))
+						if prune && !pruneSkipped(truncated) {
+							pruneIssues(database, dbRepo.ID, issues)
+						}
 					}
 				}
 			}
 
 			// Sync PRs if requested
 			if withPRs && !labelsOnly {
+				stopPRs := sw.start("pr fetch")
 				prs, err := client.ListPRs(organization, repoName, 200)
+				stopPRs()
 				if err != nil {
 					mu.Lock()
 					syncErrors = append(syncErrors, fmt.Sprintf("%s PRs: %v", repoName, err))
 					mu.Unlock()
 					fmt.Fprintf(os.Stderr, "  PRs error: %v\n", err)
 				} else {
+					if dryRun {
+						fmt.Printf("  Would sync %d PRs\n", len(prs))
+						prs = nil
+					}
 					prCount := 0
 					for _, pr := range prs {
-						if dryRun {
-							continue
-						}
 
 						dbPR := &db.PullRequest{
 							RepoID:       dbRepo.ID,
@@ -364,6 +561,13 @@ func runSync(cmd *cobra.Command, args []string) error {
 							dbPR.GHClosedAt = &pr.ClosedAt
 						}
 
+						// Time to first review by someone other than the author
+						if reviews, err := client.GetPRReviewTimeline(organization, repoName, pr.Number); err == nil {
+							dbPR.ReviewTimeHours = reviews.ReviewTimeHours(pr.Author, pr.CreatedAt)
+						} else if verbose {
+							fmt.Fprintf(os.Stderr, "  Warning: reviews for PR #%d: %v\n", pr.Number, err)
+						}
+
 						if err := database.UpsertPR(dbPR); err != nil {
 							fmt.Fprintf(os.Stderr, "  Warning: failed to save PR #%d: %v\n", pr.Number, err)
 							continue
@@ -382,14 +586,25 @@ func runSync(cmd *cobra.Command, args []string) error {
 
 						prCount++
 					}
-					fmt.Printf("  %d PRs synced\n", prCount)
+					if !dryRun {
+						fmt.Printf("  %d PRs synced\n", prCount)
+					}
 				}
 			}
 
-			// Record sync completion
-			if !dryRun {
+			// Record sync completion; a timed-out repo keeps its previous
+			// last sync time so the next run fetches what was missed
+			if !dryRun && ctx.Err() != nil {
+				msg := fmt.Sprintf("cancelled: --timeout %s expired", ghTimeout)
+				if syncErr != "" {
+					msg += ": " + syncErr
+				}
+				database.RecordSyncCancelled(syncID, itemsSynced, msg)
+			} else if !dryRun {
 				database.RecordSyncComplete(syncID, itemsSynced, syncErr)
-				database.UpdateRepoSyncTime(dbRepo.ID)
+				if advanceLastSync(labelsOnly, syncErr, truncated) {
+					database.UpdateRepoSyncTime(dbRepo.ID)
+				}
 
 				// Auto CFD snapshot if >24h since last
 				today := time.Now().Truncate(24 * time.Hour)
@@ -411,13 +626,291 @@ func runSync(cmd *cobra.Command, args []string) error {
 		for _, e := range syncErrors {
 			fmt.Fprintf(os.Stderr, "  - %s\n", e)
 		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("sync cancelled: --timeout %s expired", ghTimeout)
+		}
 		return fmt.Errorf("sync completed with errors")
 	}
 
-	fmt.Printf("\nSync completed! %d issues cached.\n", totalIssues)
+	if dryRun {
+		fmt.Printf("\nDry run completed: %d issues would be cached.\n", totalIssues)
+	} else {
+		fmt.Printf("\nSync completed! %d issues cached.\n", totalIssues)
+	}
+
+	if pruneLabels {
+		return pruneRepoLabels(organization, repos, labels, cfg.Settings, database, client)
+	}
 	return nil
 }
 
+// issueChanges is what syncing a batch of issues would write
+type issueChanges struct {
+	Inserts     int
+	Updates     int
+	Transitions int // status_transitions rows, as recorded by UpsertIssue
+	Prunes      int // cached issues missing from the fetch (--prune)
+}
+
+// planIssueChanges works out what syncing issues would write to the database
+// without writing anything
+func planIssueChanges(database *db.DB, repoID int64, fullName string, issues []github.IssueDetails,
+	projectSource *github.ProjectStatusSource, delimiter string) (issueChanges, error) {
+	var changes issueChanges
+
+	existing, err := database.GetIssueStatuses(repoID)
+	if err != nil {
+		return changes, fmt.Errorf("failed to read cached issues: %w", err)
+	}
+
+	fetched := make(map[int]bool, len(issues))
+	for _, issue := range issues {
+		fetched[issue.Number] = true
+		dbIssue := buildDBIssue(repoID, fullName, issue, projectSource, delimiter)
+		oldStatus, ok := existing[issue.Number]
+		switch {
+		case !ok:
+			changes.Inserts++
+			if dbIssue.CurrentStatus != "" {
+				changes.Transitions++
+			}
+		default:
+			changes.Updates++
+			if oldStatus != dbIssue.CurrentStatus {
+				changes.Transitions++
+			}
+		}
+	}
+	for number := range existing {
+		if !fetched[number] {
+			changes.Prunes++
+		}
+	}
+	return changes, nil
+}
+
+// fetchIssues fetches a repo's issues: all of them, or with since those
+// updated since then. GitHub search stops at SearchResultCap results, so an
+// incremental fetch that hits the cap falls back to listing every issue.
+// truncated reports a fetch that may still be missing issues.
+func fetchIssues(client *github.Client, organization, repoName string, since time.Time, limit int) (issues []github.IssueDetails, truncated bool, err error) {
+	if !since.IsZero() {
+		issues, err = client.ListIssuesUpdatedSince(organization, repoName, since, limit)
+		truncated = err == nil && fetchTruncated(len(issues), limit, true)
+		if !truncated || (limit > 0 && limit <= github.SearchResultCap) {
+			return issues, truncated, err
+		}
+		fmt.Printf("  Search stopped at %d updated issues, listing all issues instead\n", len(issues))
+	}
+	issues, err = client.ListAllIssues(organization, repoName, limit)
+	return issues, err == nil && fetchTruncated(len(issues), limit, false), err
+}
+
+// advanceLastSync reports whether a repo's last sync time may move forward:
+// only after an issue fetch that succeeded and wasn't truncated, so the next
+// incremental sync doesn't skip over a failed, labels-only or cut-off run
+func advanceLastSync(labelsOnly bool, syncErr string, truncated bool) bool {
+	return !labelsOnly && syncErr == "" && !truncated
+}
+
+// fetchTruncated reports whether a fetch of n issues hit limit (0 for no
+// limit) or, for search queries, GitHub's search cap, so issues may be missing
+func fetchTruncated(n, limit int, search bool) bool {
+	if search && (limit <= 0 || limit > github.SearchResultCap) {
+		limit = github.SearchResultCap
+	}
+	return limit > 0 && n >= limit
+}
+
+// warnTruncated warns that a fetch for fullName may be missing issues
+func warnTruncated(fullName string, n int) {
+	fmt.Fprintf(os.Stderr, "  Warning: %s: fetch stopped at the limit of %d issues, data may be truncated (settings.fetch_limit, 0 = no limit)\n",
+		fullName, n)
+}
+
+// pruneSkipped reports, and says so, when a truncated fetch makes pruning
+// unsafe: issues cut off by the limit would be deleted
+func pruneSkipped(truncated bool) bool {
+	if truncated {
+		fmt.Printf("  Prune skipped: the fetch may be missing issues\n")
+	}
+	return truncated
+}
+
+// workflowEntryTimes keeps the timeline's status entry times for the
+// statuses in the configured workflow
+func workflowEntryTimes(settings config.Settings, changes map[string]time.Time) map[string]time.Time {
+	entered := make(map[string]time.Time)
+	for _, status := range settings.Statuses() {
+		if t, ok := changes[status]; ok {
+			entered[status] = t
+		}
+	}
+	return entered
+}
+
+// splitIgnoredAuthors separates the issues opened by settings.ignore_authors
+// from the ones to cache, returning the numbers of the ignored ones
+func splitIgnoredAuthors(issues []github.IssueDetails, settings config.Settings) ([]github.IssueDetails, []int) {
+	var kept []github.IssueDetails
+	var ignored []int
+	for _, issue := range issues {
+		if settings.IsIgnoredAuthor(issue.Author) {
+			ignored = append(ignored, issue.Number)
+			continue
+		}
+		kept = append(kept, issue)
+	}
+	return kept, ignored
+}
+
+// removeIgnoredIssues deletes issues by ignored authors cached before the
+// author was ignored
+func removeIgnoredIssues(database *db.DB, repoID int64, numbers []int) {
+	if len(numbers) == 0 {
+		return
+	}
+	removed, err := database.DeleteIssues(repoID, numbers)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "  Warning: failed to remove issues by ignored authors: %v\n", err)
+		return
+	}
+	if len(removed) > 0 {
+		fmt.Printf("  Removed %d cached issues by ignored authors: %s\n", len(removed), formatIssueNumbers(removed))
+	}
+}
+
+// pruneIssues deletes cached issues that weren't in the full fetch
+func pruneIssues(database *db.DB, repoID int64, issues []github.IssueDetails) {
+	numbers := make([]int, len(issues))
+	for i, issue := range issues {
+		numbers[i] = issue.Number
+	}
+	pruned, err := database.PruneIssues(repoID, numbers)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "  Warning: failed to prune issues: %v\n", err)
+		return
+	}
+	if len(pruned) > 0 {
+		fmt.Printf("  Pruned %d issues no longer on GitHub: %s\n", len(pruned), formatIssueNumbers(pruned))
+	}
+}
+
+func formatIssueNumbers(numbers []int) string {
+	parts := make([]string, len(numbers))
+	for i, n := range numbers {
+		parts[i] = fmt.Sprintf("#%d", n)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// buildDBIssue converts a fetched issue to its cached form: status, priority,
+// type and size from labels written with delimiter (or the project board),
+// lead time when closed. fullName (org/repo) finds the issue on the board.
+func buildDBIssue(repoID int64, fullName string, issue github.IssueDetails, projectSource *github.ProjectStatusSource,
+	delimiter string) *db.Issue {
+	dbIssue := &db.Issue{
+		RepoID:      repoID,
+		Number:      issue.Number,
+		Title:       issue.Title,
+		State:       strings.ToLower(issue.State),
+		GHCreatedAt: issue.CreatedAt,
+		GHUpdatedAt: issue.UpdatedAt,
+		Assignee:    issue.Assignee,
+		Milestone:   issue.Milestone,
+		Author:      issue.Author,
+		StateReason: issue.StateReason,
+	}
+
+	if !issue.ClosedAt.IsZero() {
+		dbIssue.GHClosedAt = &issue.ClosedAt
+	}
+
+	// Parse labels for status, priority, type, size
+	for _, label := range issue.Labels {
+		if value, ok := config.LabelValue(label, "status", delimiter); ok {
+			dbIssue.CurrentStatus = value
+		} else if value, ok := config.LabelValue(label, "priority", delimiter); ok {
+			dbIssue.CurrentPriority = value
+		} else if value, ok := config.LabelValue(label, "type", delimiter); ok {
+			dbIssue.CurrentType = value
+		} else if value, ok := config.LabelValue(label, "size", delimiter); ok {
+			dbIssue.CurrentSize = value
+		} else if label == "blocked" {
+			dbIssue.IsBlocked = true
+		}
+	}
+
+	// Projects v2 status field replaces status: labels
+	if projectSource != nil {
+		dbIssue.CurrentStatus = projectSource.Status(fullName, issue.Number)
+	}
+
+	// Calculate lead time for closed issues
+	if dbIssue.GHClosedAt != nil {
+		dbIssue.LeadTimeHours = dbIssue.GHClosedAt.Sub(dbIssue.GHCreatedAt).Hours()
+		// Treat closed as "done" for status if no done label
+		if dbIssue.CurrentStatus == "" {
+			dbIssue.CurrentStatus = "done"
+		}
+	}
+	return dbIssue
+}
+
+// sizePoints returns the story points of a closed issue's size label from
+// settings.size_points, or nil when it's open, unsized or the size isn't mapped
+func sizePoints(issue *db.Issue, settings config.Settings) *float64 {
+	if issue.GHClosedAt == nil {
+		return nil
+	}
+	points, ok := settings.Points(issue.CurrentSize)
+	if !ok {
+		return nil
+	}
+	return &points
+}
+
+// loadLabelsFile loads and validates labels from a standalone yaml/json file
+func loadLabelsFile(path string) ([]config.Label, error) {
+	fileCfg, err := config.LoadLabelsFromFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load labels from %s: %w", path, err)
+	}
+
+	result := fileCfg.ValidateLabels()
+	for _, w := range result.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", path, w)
+	}
+	if !result.IsValid() {
+		for _, e := range result.Errors {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", path, e)
+		}
+		return nil, fmt.Errorf("%s has %d invalid label(s)", path, len(result.Errors))
+	}
+
+	return fileCfg.AllLabels(), nil
+}
+
+// parseSince parses a --since value: a duration like 24h or 7d, or a date (2006-01-02 or RFC3339)
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if strings.HasSuffix(value, "d") {
+		var n int
+		if _, err := fmt.Sscanf(value, "%dd", &n); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since value %q: use a duration (24h, 7d) or date (2006-01-02)", value)
+}
+
 // extractLabelValue extracts the value from a prefixed label
 func extractLabelValue(labels []string, prefix string) string {
 	for _, label := range labels {
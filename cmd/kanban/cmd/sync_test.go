@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kiracore/kanban/internal/config"
+	"github.com/kiracore/kanban/internal/github"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		value string
+		want  time.Time
+	}{
+		{"hours", "24h", now.Add(-24 * time.Hour)},
+		{"minutes", "90m", now.Add(-90 * time.Minute)},
+		{"days", "7d", now.AddDate(0, 0, -7)},
+		{"zero days", "0d", now},
+		{"date", "2026-03-01", time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)},
+		{"RFC3339", "2026-03-01T08:30:00Z", time.Date(2026, 3, 1, 8, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSince(tt.value, now)
+			if err != nil {
+				t.Fatalf("parseSince(%q) error: %v", tt.value, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseSince(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSince_Invalid(t *testing.T) {
+	now := time.Now()
+	for _, value := range []string{"", "yesterday", "-3d", "7w", "2026-13-01", "03/01/2026"} {
+		if got, err := parseSince(value, now); err == nil {
+			t.Errorf("parseSince(%q) = %v, want an error", value, got)
+		}
+	}
+}
+
+// withFakeIssueLists puts a gh on PATH answering issue list with searched
+// issues for --search queries and listed issues otherwise. Each call's
+// arguments are appended to the returned file, one call per line.
+func withFakeIssueLists(t *testing.T, searched, listed int) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, n := range map[string]int{"search.json": searched, "list.json": listed} {
+		issues := make([]map[string]interface{}, n)
+		for i := range issues {
+			issues[i] = map[string]interface{}{
+				"number": i + 1, "title": fmt.Sprintf("Issue %d", i+1), "state": "OPEN",
+				"createdAt": "2026-03-01T00:00:00Z", "updatedAt": "2026-03-02T00:00:00Z",
+			}
+		}
+		data, _ := json.Marshal(issues)
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	calls := filepath.Join(dir, "calls")
+	script := "#!/bin/sh\necho \"$*\" >> " + calls + "\ncase \"$*\" in\n*--search*) cat " +
+		filepath.Join(dir, "search.json") + " ;;\n*) cat " + filepath.Join(dir, "list.json") + " ;;\nesac\n"
+	if err := os.WriteFile(filepath.Join(dir, "gh"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("GH_TOKEN", "")
+	return calls
+}
+
+func TestFetchIssues_SearchCapFallsBackToList(t *testing.T) {
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		limit         int
+		searched      int
+		listed        int
+		wantIssues    int
+		wantTruncated bool
+		wantCalls     int
+	}{
+		{"under the search cap", 0, 20, 1500, 20, false, 1},
+		{"search cap hit, full list", 0, github.SearchResultCap, 1500, 1500, false, 2},
+		{"search cap hit, list at the fetch limit", 1200, github.SearchResultCap, 1200, 1200, true, 2},
+		{"fetch limit under the cap", 50, 50, 1500, 50, true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := withFakeIssueLists(t, tt.searched, tt.listed)
+			client := github.NewClient(context.Background(), github.Options{})
+
+			issues, truncated, err := fetchIssues(client, "acme", "app", since, tt.limit)
+			if err != nil {
+				t.Fatalf("fetchIssues() error: %v", err)
+			}
+			if len(issues) != tt.wantIssues || truncated != tt.wantTruncated {
+				t.Errorf("fetchIssues() = %d issues, truncated %v; want %d, %v", len(issues), truncated, tt.wantIssues, tt.wantTruncated)
+			}
+			data, _ := os.ReadFile(calls)
+			if n := strings.Count(string(data), "\n"); n != tt.wantCalls {
+				t.Errorf("gh called %d times, want %d:\n%s", n, tt.wantCalls, data)
+			}
+		})
+	}
+}
+
+func TestAdvanceLastSync(t *testing.T) {
+	tests := []struct {
+		name       string
+		labelsOnly bool
+		syncErr    string
+		truncated  bool
+		want       bool
+	}{
+		{"complete fetch", false, "", false, true},
+		{"labels only", true, "", false, false},
+		{"fetch failed", false, "failed to list issues", false, false},
+		{"truncated fetch", false, "", true, false},
+	}
+	for _, tt := range tests {
+		if got := advanceLastSync(tt.labelsOnly, tt.syncErr, tt.truncated); got != tt.want {
+			t.Errorf("%s: advanceLastSync() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWorkflowEntryTimes(t *testing.T) {
+	at := func(day int) time.Time { return time.Date(2026, 3, day, 9, 0, 0, 0, time.UTC) }
+	changes := map[string]time.Time{"ready": at(1), "in-progress": at(2), "testing": at(3), "qa": at(4), "done": at(5)}
+
+	tests := []struct {
+		name     string
+		workflow []string
+		want     map[string]time.Time
+	}{
+		{"default workflow", nil,
+			map[string]time.Time{"ready": at(1), "in-progress": at(2), "testing": at(3), "done": at(5)}},
+		{"custom workflow", []string{"backlog", "in-progress", "qa", "done"},
+			map[string]time.Time{"in-progress": at(2), "qa": at(4), "done": at(5)}},
+	}
+	for _, tt := range tests {
+		got := workflowEntryTimes(config.Settings{Workflow: tt.workflow}, changes)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: workflowEntryTimes() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
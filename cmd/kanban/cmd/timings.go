@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// stopwatch accumulates elapsed time per named phase.
+// Safe for concurrent use; phases timed from several goroutines add up,
+// so per-phase totals can exceed wall-clock time.
+type stopwatch struct {
+	mu     sync.Mutex
+	began  time.Time
+	order  []string
+	phases map[string]time.Duration
+}
+
+func newStopwatch() *stopwatch {
+	return &stopwatch{
+		began:  time.Now(),
+		phases: make(map[string]time.Duration),
+	}
+}
+
+// start begins timing a phase and returns a func that records the elapsed time
+func (s *stopwatch) start(phase string) func() {
+	t := time.Now()
+	return func() {
+		s.add(phase, time.Since(t))
+	}
+}
+
+func (s *stopwatch) add(phase string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.phases[phase]; !ok {
+		s.order = append(s.order, phase)
+	}
+	s.phases[phase] += d
+}
+
+// print writes the phase summary to stderr when --timings is set
+func (s *stopwatch) print() {
+	if !showTimings {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fmt.Fprintln(os.Stderr, "\nTimings:")
+	for _, phase := range s.order {
+		fmt.Fprintf(os.Stderr, "  %-16s %s\n", phase, s.phases[phase].Round(time.Millisecond))
+	}
+	fmt.Fprintf(os.Stderr, "  %-16s %s\n", "total", time.Since(s.began).Round(time.Millisecond))
+}
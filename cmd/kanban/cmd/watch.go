@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchInterval time.Duration
+	watchSync     bool
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Show the board and refresh it on an interval",
+	Long: `Show the cached board and redraw it every --interval, e.g. on a team
+dashboard. With --sync an incremental issue sync runs before each refresh.
+
+When stdout isn't a terminal the board is printed again below a separator
+instead of clearing the screen. Press Ctrl+C to exit.
+
+Examples:
+  kanban watch --org myorg --repo myrepo
+  kanban watch --org myorg --all --interval 5m --sync`,
+	RunE: runWatch,
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	watchCmd.Flags().StringVarP(&repo, "repo", "r", "", "specific repository")
+	watchCmd.Flags().BoolVar(&allRepos, "all", false, "show board for all repositories")
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", 30*time.Second, "time between refreshes")
+	watchCmd.Flags().BoolVar(&watchSync, "sync", false, "run an incremental issue sync before each refresh")
+	watchCmd.Flags().IntVarP(&maxIssues, "limit", "n", 10, "max issues per column (0 = no limit)")
+	watchCmd.Flags().StringVarP(&sortBy, "sort", "s", "priority", "sort by: priority, updated, age, assignee, created")
+	watchCmd.Flags().StringVarP(&filterAssignee, "assignee", "a", "", "filter by assignee username")
+	watchCmd.Flags().StringVar(&filterTypes, "type", "", "filter by issue type, comma-separated (e.g. bug,feature)")
+	watchCmd.Flags().StringVar(&filterPriority, "priority", "", "filter by priority, comma-separated (e.g. critical,high)")
+	watchCmd.Flags().BoolVar(&blockedOnly, "blocked-only", false, "only show blocked issues")
+	watchCmd.Flags().StringVar(&groupBy, "group-by", "status", "columns by: status, type, priority")
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	if watchInterval < time.Second {
+		return fmt.Errorf("--interval must be at least 1s")
+	}
+	if err := validateGroupBy(); err != nil {
+		return err
+	}
+
+	orgs, err := resolveOrganizations()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// The sync before each refresh only fetches issues
+	issuesOnly = true
+
+	fi, err := os.Stdout.Stat()
+	tty := err == nil && fi.Mode()&os.ModeCharDevice != 0
+
+	w, _, err := openOutput()
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	for {
+		refreshBoard(cmd, w, orgs, tty)
+
+		select {
+		case <-ctx.Done():
+			fmt.Println()
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshBoard syncs if asked and draws the board once. Errors are shown in
+// place of the board so a failed refresh doesn't end the watch.
+func refreshBoard(cmd *cobra.Command, w io.Writer, orgs []string, tty bool) {
+	if watchSync {
+		if err := runSync(cmd, nil); err != nil {
+			fmt.Fprintf(os.Stderr, "Sync failed: %v\n", err)
+		}
+	}
+
+	columns, repos, _, err := loadBoard(orgs, newStopwatch())
+
+	if tty {
+		fmt.Print("\033[H\033[2J")
+	} else {
+		fmt.Println(strings.Repeat("═", 80))
+	}
+
+	dim := "\033[90m"
+	reset := "\033[0m"
+	fmt.Fprintf(w, "%sUpdated %s, refreshing every %s (Ctrl+C to exit)%s\n",
+		dim, time.Now().Format("15:04:05"), watchInterval, reset)
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\nError: %v\n", err)
+		return
+	}
+	renderBoard(w, columns, repos, orgs)
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var whoamiCmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Show the GitHub identity and auth backend in use",
+	Long: `Show which GitHub account kanban operates as.
+
+Prints the authenticated login, the auth backend gh resolves to
+(GH_TOKEN or GITHUB_TOKEN environment variable, or gh keyring/config) and
+the host. Run this before label operations when several gh accounts or
+tokens are configured.
+
+Note: GH_TOKEN is passed to gh in CI and when gh has no login of its own;
+otherwise kanban removes it so the 'gh auth login' account is used.
+settings.use_gh_token: true/false overrides this.`,
+	RunE: runWhoami,
+}
+
+func init() {
+	rootCmd.AddCommand(whoamiCmd)
+	whoamiCmd.Flags().StringVarP(&format, "format", "f", "table", "output format (table|json)")
+}
+
+func runWhoami(cmd *cobra.Command, args []string) error {
+	client := newGitHubClient(timeoutCtx)
+
+	info, err := client.AuthInfo()
+	if err != nil {
+		return fmt.Errorf("%w (run 'gh auth login')", err)
+	}
+
+	if format == "json" {
+		output, _ := json.MarshalIndent(info, "", "  ")
+		fmt.Println(string(output))
+		return nil
+	}
+
+	fmt.Printf("Login:   %s\n", info.Login)
+	fmt.Printf("Host:    %s\n", info.Host)
+	fmt.Printf("Backend: %s\n", info.Backend)
+	if info.GHTokenIgnored {
+		fmt.Println("Note:    GH_TOKEN is set but ignored by kanban (set settings.use_gh_token: true to use it)")
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kiracore/kanban/internal/config"
+	"github.com/kiracore/kanban/internal/db"
+)
+
+// chronicBreachDays is how many consecutive days over limit make a column a
+// chronic bottleneck
+const chronicBreachDays = 5
+
+var showWIPHistory bool
+
+func init() {
+	metricsCmd.Flags().BoolVar(&showWIPHistory, "wip-history", false, "report how many days each column was over its WIP limit (from daily CFD snapshots)")
+}
+
+// WIPBreachHistory summarizes a repo's WIP-limit breaches over daily snapshots
+type WIPBreachHistory struct {
+	Repo      string         `json:"repo"`
+	Snapshots int            `json:"snapshots"`
+	From      string         `json:"from,omitempty"`
+	To        string         `json:"to,omitempty"`
+	Columns   []ColumnBreach `json:"columns"`
+}
+
+// ColumnBreach is how often one column was over its WIP limit
+type ColumnBreach struct {
+	Status        string `json:"status"`
+	Limit         int    `json:"limit"`
+	DaysOver      int    `json:"days_over"`
+	MaxWIP        int    `json:"max_wip"`
+	LongestStreak int    `json:"longest_streak_days"`
+	Chronic       bool   `json:"chronic"`
+}
+
+// wipSnapshot is one day's count of open issues per status
+type wipSnapshot struct {
+	Date time.Time
+	WIP  map[string]int
+}
+
+// wipSnapshots turns CFD snapshot rows into daily WIP counts in date order.
+// Sync saves a CFD snapshot once a day (as does 'kanban cfd snapshot'), with
+// any status of the workflow, custom ones included.
+func wipSnapshots(data []struct {
+	Date   string
+	Status string
+	Count  int
+}, workflow []string) []wipSnapshot {
+	dates, byDate, _ := groupCFDData(data, workflow)
+	snapshots := make([]wipSnapshot, 0, len(dates))
+	for _, date := range dates {
+		day, err := time.Parse("2006-01-02", cfdDateLabel(date))
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, wipSnapshot{Date: day, WIP: byDate[date]})
+	}
+	return snapshots
+}
+
+// runWIPHistory walks the daily CFD snapshots of the last --days and counts
+// the days each limited column was over its WIP limit
+func runWIPHistory(orgs []string) error {
+	var settings config.Settings
+	if cfg, _ := config.Load(); cfg != nil {
+		settings = cfg.Settings
+	}
+	wipLimits := settings.WIPLimits
+	if len(wipLimits) == 0 {
+		return fmt.Errorf("no WIP limits configured (set settings.wip_limits in the config)")
+	}
+
+	database, err := db.OpenReadOnly(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w (run 'kanban sync' first)", err)
+	}
+	defer database.Close()
+
+	var histories []WIPBreachHistory
+	for _, organization := range orgs {
+		repos, err := cachedRepos(database, organization)
+		if err != nil {
+			return err
+		}
+
+		for _, fullName := range repos {
+			repoID, err := database.GetRepoID(fullName)
+			if err != nil {
+				return fmt.Errorf("failed to look up %s: %w", fullName, err)
+			}
+			data, err := database.GetCFDData(repoID, days)
+			if err != nil {
+				return fmt.Errorf("failed to get CFD snapshots for %s: %w", fullName, err)
+			}
+			h := wipBreaches(wipSnapshots(data, settings.Statuses()), wipLimits, settings.Statuses())
+			h.Repo = displayRepo(organization, fullName)
+			histories = append(histories, h)
+		}
+	}
+
+	if len(histories) == 0 {
+		return fmt.Errorf("no data found. Run 'kanban sync' first to populate the database")
+	}
+
+	w, closeOutput, err := openOutput()
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		output, _ := json.MarshalIndent(histories, "", "  ")
+		fmt.Fprintln(w, string(output))
+		return closeOutput()
+	}
+
+	for _, h := range histories {
+		printWIPHistory(w, h)
+	}
+	return closeOutput()
+}
+
+// wipBreaches counts breaches per limited column of workflow. Snapshots are
+// in date order; a missing day ends a streak.
+func wipBreaches(snapshots []wipSnapshot, wipLimits map[string]int, workflow []string) WIPBreachHistory {
+	h := WIPBreachHistory{Snapshots: len(snapshots)}
+	if len(snapshots) > 0 {
+		h.From = snapshots[0].Date.Format("2006-01-02")
+		h.To = snapshots[len(snapshots)-1].Date.Format("2006-01-02")
+	}
+
+	for _, status := range workflow {
+		limit, ok := config.WIPLimit(wipLimits, status)
+		if !ok {
+			continue
+		}
+
+		c := ColumnBreach{Status: status, Limit: limit}
+		streak := 0
+		var lastOver time.Time
+		for _, s := range snapshots {
+			count := s.WIP[status]
+			if count > c.MaxWIP {
+				c.MaxWIP = count
+			}
+			if count <= limit {
+				streak = 0
+				continue
+			}
+
+			c.DaysOver++
+			if streak > 0 && s.Date.Sub(lastOver) > 24*time.Hour {
+				streak = 0
+			}
+			streak++
+			lastOver = s.Date
+			if streak > c.LongestStreak {
+				c.LongestStreak = streak
+			}
+		}
+		c.Chronic = c.LongestStreak >= chronicBreachDays
+		h.Columns = append(h.Columns, c)
+	}
+	return h
+}
+
+func printWIPHistory(w io.Writer, h WIPBreachHistory) {
+	reset := "\033[0m"
+	bold := "\033[1m"
+	cyan := "\033[36m"
+	red := "\033[31m"
+	green := "\033[32m"
+	dim := "\033[90m"
+
+	fmt.Fprintf(w, "\n%s%s  WIP LIMIT HISTORY: %s%s\n", bold, cyan, h.Repo, reset)
+	if h.Snapshots == 0 {
+		fmt.Fprintf(w, "%sNo daily snapshots in the last %d days (sync takes one a day)%s\n\n", dim, days, reset)
+		return
+	}
+	fmt.Fprintf(w, "%s%d snapshots, %s → %s%s\n\n", dim, h.Snapshots, h.From, h.To, reset)
+	fmt.Fprintf(w, "  %-14s %6s %9s %8s %8s\n", "STATUS", "LIMIT", "DAYS OVER", "MAX WIP", "STREAK")
+
+	for _, c := range h.Columns {
+		color := green
+		if c.DaysOver > 0 {
+			color = red
+		}
+		note := ""
+		if c.Chronic {
+			note = fmt.Sprintf(" %s⚠ chronic bottleneck%s", red, reset)
+		}
+		fmt.Fprintf(w, "  %-14s %6d %s%9d%s %8d %8d%s\n",
+			c.Status, c.Limit, color, c.DaysOver, reset, c.MaxWIP, c.LongestStreak, note)
+	}
+	fmt.Fprintln(w)
+}
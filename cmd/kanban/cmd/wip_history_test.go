@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+// wipDays builds one snapshot per day from 2026-03-01, skipping nil entries
+func wipDays(status string, counts ...any) []wipSnapshot {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	var snapshots []wipSnapshot
+	for i, c := range counts {
+		if c == nil {
+			continue
+		}
+		snapshots = append(snapshots, wipSnapshot{Date: start.AddDate(0, 0, i), WIP: map[string]int{status: c.(int)}})
+	}
+	return snapshots
+}
+
+func TestWIPBreaches(t *testing.T) {
+	workflow := []string{"backlog", "ready", "in-progress", "review", "testing", "done"}
+	limits := map[string]int{"status: in-progress": 3, "review": 2}
+
+	// Over the limit for 6 days in a row, then 2 more after a missing day
+	snapshots := wipDays("in-progress", 4, 5, 4, 4, 6, 4, 2, nil, 4, 4)
+	h := wipBreaches(snapshots, limits, workflow)
+
+	if h.Snapshots != 9 || h.From != "2026-03-01" || h.To != "2026-03-10" {
+		t.Errorf("range = %d snapshots %s..%s, want 9 2026-03-01..2026-03-10", h.Snapshots, h.From, h.To)
+	}
+	if len(h.Columns) != 2 || h.Columns[0].Status != "in-progress" || h.Columns[1].Status != "review" {
+		t.Fatalf("columns = %+v, want in-progress then review", h.Columns)
+	}
+
+	c := h.Columns[0]
+	if c.Limit != 3 || c.DaysOver != 8 || c.MaxWIP != 6 || c.LongestStreak != 6 || !c.Chronic {
+		t.Errorf("in-progress = %+v, want limit 3, 8 days over, max 6, streak 6, chronic", c)
+	}
+	if r := h.Columns[1]; r.DaysOver != 0 || r.Chronic {
+		t.Errorf("review = %+v, want never over", r)
+	}
+}
+
+func TestWIPBreaches_GapEndsStreak(t *testing.T) {
+	workflow := []string{"backlog", "in-progress", "done"}
+	limits := map[string]int{"in-progress": 1}
+
+	h := wipBreaches(wipDays("in-progress", 2, 2, 2, nil, 2, 2, 2), limits, workflow)
+	if c := h.Columns[0]; c.DaysOver != 6 || c.LongestStreak != 3 || c.Chronic {
+		t.Errorf("in-progress = %+v, want 6 days over, streak 3, not chronic", c)
+	}
+}
+
+func TestWIPBreaches_CustomWorkflow(t *testing.T) {
+	workflow := []string{"todo", "doing", "qa", "done"}
+	limits := map[string]int{"qa": 1, "in-progress": 1}
+
+	h := wipBreaches(wipDays("qa", 3, 1), limits, workflow)
+	if len(h.Columns) != 1 || h.Columns[0].Status != "qa" {
+		t.Fatalf("columns = %+v, want only qa (in-progress isn't in the workflow)", h.Columns)
+	}
+	if c := h.Columns[0]; c.DaysOver != 1 || c.MaxWIP != 3 {
+		t.Errorf("qa = %+v, want 1 day over, max 3", c)
+	}
+}
+
+func TestWIPBreaches_NoSnapshots(t *testing.T) {
+	h := wipBreaches(nil, map[string]int{"review": 2}, []string{"review", "done"})
+	if h.Snapshots != 0 || h.From != "" || len(h.Columns) != 1 || h.Columns[0].DaysOver != 0 {
+		t.Errorf("wipBreaches(nil) = %+v, want an empty review column", h)
+	}
+}
+
+func TestWIPSnapshots(t *testing.T) {
+	data := []struct {
+		Date   string
+		Status string
+		Count  int
+	}{
+		{"2026-03-02T00:00:00Z", "qa", 1},
+		{"2026-03-01T00:00:00Z", "qa", 3},
+		{"2026-03-01T00:00:00Z", "doing", 2},
+	}
+
+	snapshots := wipSnapshots(data, []string{"todo", "doing", "qa", "done"})
+	if len(snapshots) != 2 {
+		t.Fatalf("got %d snapshots, want 2", len(snapshots))
+	}
+	if d := snapshots[0].Date.Format("2006-01-02"); d != "2026-03-01" {
+		t.Errorf("first snapshot = %s, want 2026-03-01", d)
+	}
+	if snapshots[0].WIP["qa"] != 3 || snapshots[0].WIP["doing"] != 2 || snapshots[1].WIP["qa"] != 1 {
+		t.Errorf("snapshots = %+v, want qa 3 and doing 2, then qa 1", snapshots)
+	}
+}
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Default working week for business-time durations
+var (
+	DefaultBusinessDays  = []string{"mon", "tue", "wed", "thu", "fri"}
+	DefaultBusinessStart = "09:00"
+	DefaultBusinessEnd   = "17:00"
+)
+
+// BusinessHours is the working week business-time durations count: the
+// working days, the hours worked on each, and the timezone they're in.
+// Empty fields take the defaults, Mon–Fri 09:00–17:00 UTC.
+type BusinessHours struct {
+	Days     []string `yaml:"days" json:"days,omitempty" mapstructure:"days"`             // mon, tue, ...
+	Start    string   `yaml:"start" json:"start,omitempty" mapstructure:"start"`          // HH:MM
+	End      string   `yaml:"end" json:"end,omitempty" mapstructure:"end"`                // HH:MM, after start
+	Timezone string   `yaml:"timezone" json:"timezone,omitempty" mapstructure:"timezone"` // IANA name, e.g. Europe/Berlin
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// workWeek is a parsed BusinessHours
+type workWeek struct {
+	days       [7]bool
+	start, end time.Duration // since midnight
+	loc        *time.Location
+}
+
+// parse resolves defaults and checks every field
+func (b BusinessHours) parse() (*workWeek, error) {
+	w := &workWeek{loc: time.UTC}
+
+	days := b.Days
+	if len(days) == 0 {
+		days = DefaultBusinessDays
+	}
+	for _, d := range days {
+		key := strings.ToLower(strings.TrimSpace(d))
+		if len(key) > 3 {
+			key = key[:3]
+		}
+		day, ok := weekdays[key]
+		if !ok {
+			return nil, fmt.Errorf("invalid day %q (use mon, tue, ... sun)", d)
+		}
+		w.days[day] = true
+	}
+
+	var err error
+	if w.start, err = clockTime(b.Start, DefaultBusinessStart); err != nil {
+		return nil, fmt.Errorf("invalid start: %w", err)
+	}
+	if w.end, err = clockTime(b.End, DefaultBusinessEnd); err != nil {
+		return nil, fmt.Errorf("invalid end: %w", err)
+	}
+	if w.end <= w.start {
+		return nil, fmt.Errorf("end must be after start")
+	}
+
+	if b.Timezone != "" {
+		if w.loc, err = time.LoadLocation(b.Timezone); err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", b.Timezone, err)
+		}
+	}
+	return w, nil
+}
+
+// at returns the wall-clock time since midnight on day. It's built from the
+// clock rather than added to midnight so days with a DST change keep their hours.
+func (w *workWeek) at(day time.Time, clock time.Duration) time.Time {
+	y, m, d := day.Date()
+	return time.Date(y, m, d, int(clock/time.Hour), int(clock%time.Hour/time.Minute), 0, 0, w.loc)
+}
+
+// clockTime parses HH:MM as time since midnight, using def when s is empty
+func clockTime(s, def string) (time.Duration, error) {
+	if s == "" {
+		s = def
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not HH:MM", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Validate reports the first invalid field, if any
+func (b BusinessHours) Validate() error {
+	_, err := b.parse()
+	return err
+}
+
+// DayHours returns the length of a working day in hours
+func (b BusinessHours) DayHours() float64 {
+	w, err := b.parse()
+	if err != nil {
+		return 0
+	}
+	return (w.end - w.start).Hours()
+}
+
+// String describes the working week, e.g. "mon-fri 09:00-17:00 UTC"
+func (b BusinessHours) String() string {
+	w, err := b.parse()
+	if err != nil {
+		return "invalid business hours"
+	}
+	var days []string
+	for _, d := range []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday} {
+		if w.days[d] {
+			days = append(days, strings.ToLower(d.String()[:3]))
+		}
+	}
+	dayList := strings.Join(days, ",")
+	if dayList == "mon,tue,wed,thu,fri" {
+		dayList = "mon-fri"
+	}
+	clock := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%s %s-%s %s", dayList, clock(w.start), clock(w.end), w.loc)
+}
+
+// BusinessHoursBetween returns the working hours from start to end: the
+// time that falls within cfg's working days and hours, in its timezone.
+// It returns 0 when end isn't after start or cfg is invalid.
+func BusinessHoursBetween(start, end time.Time, cfg BusinessHours) float64 {
+	w, err := cfg.parse()
+	if err != nil || !end.After(start) {
+		return 0
+	}
+
+	var total time.Duration
+	start, end = start.In(w.loc), end.In(w.loc)
+	y, m, d := start.Date()
+	for day := time.Date(y, m, d, 0, 0, 0, 0, w.loc); day.Before(end); day = day.AddDate(0, 0, 1) {
+		if !w.days[day.Weekday()] {
+			continue
+		}
+		opens, closes := w.at(day, w.start), w.at(day, w.end)
+		if opens.Before(start) {
+			opens = start
+		}
+		if closes.After(end) {
+			closes = end
+		}
+		if closes.After(opens) {
+			total += closes.Sub(opens)
+		}
+	}
+	return total.Hours()
+}
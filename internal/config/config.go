@@ -5,7 +5,10 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
@@ -55,8 +58,19 @@ func (c *LabelConfig) Validate() *ValidationResult {
 	}
 
 	// Organization required
-	if c.Organization == "" {
-		result.AddError("organization", "organization is required")
+	if c.Organization == "" && len(c.Organizations) == 0 {
+		result.AddError("organization", "organization or organizations is required")
+	}
+	seenOrgs := make(map[string]bool)
+	for i, o := range c.Organizations {
+		if o == "" {
+			result.AddError(fmt.Sprintf("organizations[%d]", i), "empty organization name")
+			continue
+		}
+		if seenOrgs[strings.ToLower(o)] {
+			result.AddWarning(fmt.Sprintf("organizations[%d]", i), fmt.Sprintf("duplicate organization %q", o))
+		}
+		seenOrgs[strings.ToLower(o)] = true
 	}
 
 	// Validate labels
@@ -74,9 +88,16 @@ func (c *LabelConfig) Validate() *ValidationResult {
 	return result
 }
 
+// ValidateLabels validates only the labels section, for standalone label files
+func (c *LabelConfig) ValidateLabels() *ValidationResult {
+	result := &ValidationResult{}
+	c.validateLabels(result)
+	return result
+}
+
 var (
 	hexColorRegex = regexp.MustCompile(`^[0-9a-fA-F]{6}$`)
-	labelNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9 :\-_\.]*$`)
+	labelNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9 :/\-_\.]*$`)
 )
 
 func (c *LabelConfig) validateLabels(result *ValidationResult) {
@@ -104,7 +125,7 @@ func (c *LabelConfig) validateLabels(result *ValidationResult) {
 
 			// Name format
 			if !labelNameRegex.MatchString(label.Name) {
-				result.AddError(field+".name", fmt.Sprintf("invalid label name %q (must start with alphanumeric, contain only alphanumeric, spaces, colons, hyphens, underscores, dots)", label.Name))
+				result.AddError(field+".name", fmt.Sprintf("invalid label name %q (must start with alphanumeric, contain only alphanumeric, spaces, colons, slashes, hyphens, underscores, dots)", label.Name))
 			}
 
 			// Duplicate check
@@ -120,6 +141,15 @@ func (c *LabelConfig) validateLabels(result *ValidationResult) {
 				result.AddError(field+".color", fmt.Sprintf("invalid color %q (must be 6-digit hex without #)", label.Color))
 			}
 
+			// Category check, for labels pasted under the wrong heading
+			if inferred := LabelCategory(label.Name); inferred != category && (inferred != "special" || isPrefixedCategory(category)) {
+				if inferred == "special" {
+					result.AddWarning(field, fmt.Sprintf("label %q has no %q prefix but is listed under %s", label.Name, category+":", category))
+				} else {
+					result.AddWarning(field, fmt.Sprintf("label %q looks like a %s label but is listed under %s", label.Name, inferred, category))
+				}
+			}
+
 			// Description check
 			if label.Description == "" {
 				result.AddWarning(field+".description", "description not specified")
@@ -130,6 +160,16 @@ func (c *LabelConfig) validateLabels(result *ValidationResult) {
 	}
 }
 
+// isPrefixedCategory reports whether labels in category need a name prefix
+func isPrefixedCategory(category string) bool {
+	for _, c := range prefixedCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *LabelConfig) validateRepositories(result *ValidationResult) {
 	hasExplicit := len(c.Repositories.List) > 0
 	hasPatterns := len(c.Repositories.Include) > 0 || len(c.Repositories.Exclude) > 0
@@ -198,6 +238,186 @@ func (c *LabelConfig) validateSettings(result *ValidationResult) {
 			result.AddWarning(fmt.Sprintf("settings.wip_limits.%s", status), "WIP limit < 1 is not useful")
 		}
 	}
+
+	for size, points := range c.Settings.SizePoints {
+		if points < 0 {
+			result.AddError(fmt.Sprintf("settings.size_points.%s", size), "story points cannot be negative")
+		}
+	}
+
+	for i, label := range c.Settings.ExcludeLabels {
+		if strings.TrimSpace(label) == "" {
+			result.AddError(fmt.Sprintf("settings.exclude_labels[%d]", i), "label cannot be empty")
+		}
+	}
+
+	for repo, numbers := range c.Settings.ExcludeIssues {
+		for _, n := range numbers {
+			if n < 1 {
+				result.AddError(fmt.Sprintf("settings.exclude_issues.%s", repo), fmt.Sprintf("invalid issue number %d", n))
+			}
+		}
+	}
+
+	if c.Settings.BusinessHours != nil {
+		if err := c.Settings.BusinessHours.Validate(); err != nil {
+			result.AddError("settings.business_hours", err.Error())
+		}
+	}
+
+	c.validateWorkflow(result)
+	statuses := c.Settings.Statuses()
+
+	// Cycle time can start anywhere between intake and done
+	if s := c.Settings.ActiveStartStatus; s != "" && (len(statuses) < 3 || !contains(statuses[1:len(statuses)-1], s)) {
+		result.AddError("settings.active_start_status",
+			fmt.Sprintf("invalid status %q (must be a workflow status between %s and %s)", s, statuses[0], DoneStatus))
+	}
+
+	if c.Settings.MaxRetries < 0 {
+		result.AddError("settings.max_retries", "max retries cannot be negative")
+	} else if c.Settings.MaxRetries > 10 {
+		result.AddWarning("settings.max_retries", "max_retries > 10 can stall a sync for a long time when rate limited")
+	}
+
+	if host := c.Settings.GitHubHost; strings.Contains(host, "/") || strings.ContainsAny(host, " \t") {
+		result.AddError("settings.github_host", fmt.Sprintf("invalid host %q (use a bare hostname like github.example.com)", host))
+	}
+
+	if d := c.Settings.LabelDelimiter; strings.ContainsFunc(d, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' }) {
+		result.AddError("settings.label_delimiter", fmt.Sprintf("invalid delimiter %q (use punctuation or a space, e.g. \": \" or \"/\")", d))
+	}
+
+	if c.Settings.BlockedThresholdHours < 0 {
+		result.AddError("settings.blocked_threshold_hours", "blocked threshold cannot be negative")
+	}
+
+	if c.Settings.StaleThresholdDays < 0 {
+		result.AddError("settings.stale_threshold_days", "stale threshold cannot be negative")
+	}
+
+	if c.Settings.FetchLimit < 0 {
+		result.AddError("settings.fetch_limit", "fetch limit cannot be negative (use 0 for no limit)")
+	}
+
+	if c.Settings.SlackWebhook != "" && !strings.HasPrefix(c.Settings.SlackWebhook, "https://") {
+		result.AddError("settings.slack_webhook", "slack webhook must be an https:// URL")
+	}
+
+	switch c.Settings.StatusSource {
+	case "", StatusSourceLabels:
+	case StatusSourceProjects:
+		if c.Settings.Project.Number < 1 {
+			result.AddError("settings.project.number", "project number is required when status_source is projects")
+		}
+	default:
+		result.AddError("settings.status_source",
+			fmt.Sprintf("invalid status source %q (must be %s or %s)", c.Settings.StatusSource, StatusSourceLabels, StatusSourceProjects))
+	}
+
+	for status, aliases := range c.Settings.StatusLabelAliases {
+		field := fmt.Sprintf("settings.status_label_aliases.%s", status)
+		if !contains(statuses, status) {
+			result.AddError(field, fmt.Sprintf("unknown status %q (must be one of: %s)", status, strings.Join(statuses, ", ")))
+		}
+		for i, alias := range aliases {
+			if alias == "" {
+				result.AddError(fmt.Sprintf("%s[%d]", field, i), "empty label alias")
+			}
+		}
+	}
+
+	for column, status := range c.Settings.Project.StatusMap {
+		if !contains(statuses, status) {
+			result.AddError(fmt.Sprintf("settings.project.status_map.%s", column),
+				fmt.Sprintf("invalid status %q (must be one of: %s)", status, strings.Join(statuses, ", ")))
+		}
+	}
+}
+
+func (c *LabelConfig) validateWorkflow(result *ValidationResult) {
+	workflow := c.Settings.Workflow
+	if len(workflow) == 0 {
+		return
+	}
+
+	seen := make(map[string]bool)
+	for i, status := range workflow {
+		field := fmt.Sprintf("settings.workflow[%d]", i)
+		if status == "" {
+			result.AddError(field, "status is empty")
+			continue
+		}
+		if status != strings.ToLower(status) || strings.ContainsAny(status, " :") {
+			result.AddError(field, fmt.Sprintf("invalid status %q (use lowercase names like in-progress)", status))
+		}
+		if seen[status] {
+			result.AddError(field, fmt.Sprintf("duplicate status %q", status))
+		}
+		seen[status] = true
+	}
+
+	if workflow[len(workflow)-1] != DoneStatus {
+		result.AddError("settings.workflow", fmt.Sprintf("workflow must end with %q", DoneStatus))
+	}
+	if len(workflow) < 3 {
+		result.AddWarning("settings.workflow", "workflow has no statuses between intake and done, so cycle time can't be measured")
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Status sources for settings.status_source
+const (
+	StatusSourceLabels   = "labels"
+	StatusSourceProjects = "projects"
+)
+
+// DefaultWorkflow is the status workflow used when settings.workflow is unset
+var DefaultWorkflow = []string{"backlog", "ready", "in-progress", "review", "testing", "done"}
+
+// DoneStatus ends every workflow; closed issues are shown in it
+const DoneStatus = "done"
+
+// DefaultActiveStartStatus is where active work begins when not configured
+const DefaultActiveStartStatus = "in-progress"
+
+// DefaultMaxRetries is how many times a rate-limited GitHub call is retried
+const DefaultMaxRetries = 3
+
+// DefaultBlockedThresholdHours is how long an issue may stay blocked before
+// the blocked command warns about it
+const DefaultBlockedThresholdHours = 72
+
+// DefaultLabelDelimiter separates a label's category from its value, as
+// in "status: in-progress"
+const DefaultLabelDelimiter = ": "
+
+// DefaultStaleThresholdDays is how long an issue may sit in one status
+// before metrics --stalled reports it
+const DefaultStaleThresholdDays = 14
+
+// prefixedCategories are the label categories recognized by name prefix,
+// e.g. "priority: high"
+var prefixedCategories = []string{"status", "priority", "type", "size"}
+
+// LabelCategory infers a label's category from its name prefix. Labels
+// without a known prefix are "special".
+func LabelCategory(name string) string {
+	lower := strings.ToLower(name)
+	for _, category := range prefixedCategories {
+		if strings.HasPrefix(lower, category+":") || strings.HasPrefix(lower, category+" ") {
+			return category
+		}
+	}
+	return "special"
 }
 
 // Label represents a GitHub label
@@ -209,25 +429,59 @@ type Label struct {
 
 // LabelConfig represents the label configuration file
 type LabelConfig struct {
-	Version      string              `yaml:"version" json:"version"`
-	Organization string              `yaml:"organization" json:"organization"`
-	Repositories RepoConfig          `yaml:"repositories" json:"repositories"`
-	Maintainers  []string            `yaml:"maintainers" json:"maintainers"`
-	Labels       map[string][]Label  `yaml:"labels" json:"labels"`
-	Migrations   []Migration         `yaml:"migrations" json:"migrations"`
-	Settings     Settings            `yaml:"settings" json:"settings"`
+	Version       string             `yaml:"version" json:"version"`
+	Organization  string             `yaml:"organization" json:"organization"`
+	Organizations []string           `yaml:"organizations" json:"organizations"` // Extra orgs covered when --org isn't given
+	Repositories  RepoConfig         `yaml:"repositories" json:"repositories"`
+	Maintainers   []string           `yaml:"maintainers" json:"maintainers"`
+	Labels        map[string][]Label `yaml:"labels" json:"labels"`
+	Migrations    []Migration        `yaml:"migrations" json:"migrations"`
+	Settings      Settings           `yaml:"settings" json:"settings"`
 }
 
-// RepoConfig defines which repos to include/exclude
+// RepoConfig defines which repos to include/exclude. Entries may be
+// qualified as "org/repo" to apply to one organization only.
 type RepoConfig struct {
 	List    []string `yaml:"list" json:"list"`       // Explicit list of repos
 	Include []string `yaml:"include" json:"include"` // Pattern-based include
 	Exclude []string `yaml:"exclude" json:"exclude"` // Pattern-based exclude
 }
 
-// GetRepos returns the explicit repo list or nil if using patterns
-func (c *LabelConfig) GetRepos() []string {
-	return c.Repositories.List
+// OrgList returns every configured organization, organization first
+func (c *LabelConfig) OrgList() []string {
+	var orgs []string
+	seen := make(map[string]bool)
+	for _, o := range append([]string{c.Organization}, c.Organizations...) {
+		if o == "" || seen[strings.ToLower(o)] {
+			continue
+		}
+		seen[strings.ToLower(o)] = true
+		orgs = append(orgs, o)
+	}
+	return orgs
+}
+
+// GetRepos returns the explicit repo list for org, or nil if using patterns.
+// Unqualified entries belong to the first organization when several are
+// configured, and to any org otherwise.
+func (c *LabelConfig) GetRepos(org string) []string {
+	var repos []string
+	for _, entry := range c.Repositories.List {
+		if name, ok := c.entryForOrg(org, entry); ok {
+			repos = append(repos, name)
+		}
+	}
+	return repos
+}
+
+// entryForOrg strips an "org/" qualifier from a repo list entry and reports
+// whether the entry applies to org
+func (c *LabelConfig) entryForOrg(org, entry string) (string, bool) {
+	if owner, name, ok := strings.Cut(entry, "/"); ok {
+		return name, strings.EqualFold(owner, org)
+	}
+	orgs := c.OrgList()
+	return entry, len(orgs) <= 1 || strings.EqualFold(orgs[0], org)
 }
 
 // HasExplicitRepos returns true if explicit repo list is defined
@@ -243,27 +497,310 @@ type Migration struct {
 
 // Settings holds configuration settings
 type Settings struct {
-	PreserveUnknown bool           `yaml:"preserve_unknown" json:"preserve_unknown"`
-	Concurrency     int            `yaml:"concurrency" json:"concurrency"`
-	WIPLimits       map[string]int `yaml:"wip_limits" json:"wip_limits"`
+	PreserveUnknown       bool                `yaml:"preserve_unknown" json:"preserve_unknown" mapstructure:"preserve_unknown"`
+	Concurrency           int                 `yaml:"concurrency" json:"concurrency"`
+	WIPLimits             map[string]int      `yaml:"wip_limits" json:"wip_limits" mapstructure:"wip_limits"`
+	ActiveStartStatus     string              `yaml:"active_start_status" json:"active_start_status" mapstructure:"active_start_status"`
+	StatusSource          string              `yaml:"status_source" json:"status_source" mapstructure:"status_source"` // labels (default) or projects
+	Project               ProjectConfig       `yaml:"project" json:"project" mapstructure:"project"`
+	IgnoreAuthors         []string            `yaml:"ignore_authors" json:"ignore_authors" mapstructure:"ignore_authors"`                            // Logins/globs (bots) whose issues sync skips, also left out of per-person metrics
+	PreserveLabels        []string            `yaml:"preserve_labels" json:"preserve_labels,omitempty" mapstructure:"preserve_labels"`               // Labels/globs outside the config that sync --prune-labels keeps
+	BlockedThresholdHours float64             `yaml:"blocked_threshold_hours" json:"blocked_threshold_hours" mapstructure:"blocked_threshold_hours"` // Warn when blocked longer than this
+	MaxRetries            int                 `yaml:"max_retries" json:"max_retries" mapstructure:"max_retries"`                                     // Retries for rate-limited GitHub calls
+	Workflow              []string            `yaml:"workflow" json:"workflow" mapstructure:"workflow"`                                              // Ordered statuses, ending with done
+	StatusLabelAliases    map[string][]string `yaml:"status_label_aliases" json:"status_label_aliases" mapstructure:"status_label_aliases"`          // Extra GitHub label spellings per status
+	StaleThresholdDays    float64             `yaml:"stale_threshold_days" json:"stale_threshold_days" mapstructure:"stale_threshold_days"`          // Flag issues unchanged in status longer than this
+	SlackWebhook          string              `yaml:"slack_webhook" json:"slack_webhook" mapstructure:"slack_webhook"`                               // Incoming webhook for kanban notify
+	FetchLimit            int                 `yaml:"fetch_limit" json:"fetch_limit" mapstructure:"fetch_limit"`                                     // Max issues fetched per repo and query, 0 = no limit
+	GitHubHost            string              `yaml:"github_host" json:"github_host,omitempty" mapstructure:"github_host"`                           // GitHub Enterprise Server host, empty = GH_HOST or github.com
+	UseGHToken            *bool               `yaml:"use_gh_token" json:"use_gh_token,omitempty" mapstructure:"use_gh_token"`                        // Pass GH_TOKEN to gh; unset = in CI or without a gh login
+	SizePoints            map[string]float64  `yaml:"size_points" json:"size_points,omitempty" mapstructure:"size_points"`                           // Story points per size: label value, for velocity
+	BusinessHours         *BusinessHours      `yaml:"business_hours" json:"business_hours,omitempty" mapstructure:"business_hours"`                  // Working week for metrics --business-time
+	LabelDelimiter        string              `yaml:"label_delimiter" json:"label_delimiter,omitempty" mapstructure:"label_delimiter"`               // Between a label's category and value, e.g. ": " or "/"
+	ExcludeLabels         []string            `yaml:"exclude_labels" json:"exclude_labels,omitempty" mapstructure:"exclude_labels"`                  // Issues with these labels (epics, trackers) are left out of the board, WIP and metrics
+	ExcludeIssues         map[string][]int    `yaml:"exclude_issues" json:"exclude_issues,omitempty" mapstructure:"exclude_issues"`                  // Issue numbers left out the same way, per repo ("repo" or "org/repo")
+}
+
+// IsExcludedIssue reports whether an issue is left out of the board, WIP and
+// metrics by exclude_labels or exclude_issues. fullName is "org/repo"; labels
+// match ignoring case and spaces.
+func (s Settings) IsExcludedIssue(fullName string, number int, labels []string) bool {
+	for _, label := range labels {
+		for _, excluded := range s.ExcludeLabels {
+			if compactLabel(label) == compactLabel(excluded) {
+				return true
+			}
+		}
+	}
+	_, name, _ := strings.Cut(fullName, "/")
+	for repo, numbers := range s.ExcludeIssues {
+		if !strings.EqualFold(repo, fullName) && !strings.EqualFold(repo, name) {
+			continue
+		}
+		if slices.Contains(numbers, number) {
+			return true
+		}
+	}
+	return false
+}
+
+// compactLabel lowercases a label and drops its spaces, so "Type: Epic"
+// and "type:epic" compare equal
+func compactLabel(label string) string {
+	return strings.ReplaceAll(strings.ToLower(label), " ", "")
+}
+
+// IsIgnoredAuthor returns true if login matches an ignore_authors entry (glob, case-insensitive)
+func (s Settings) IsIgnoredAuthor(login string) bool {
+	for _, pattern := range s.IgnoreAuthors {
+		if matchPattern(strings.ToLower(pattern), strings.ToLower(login)) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPreservedLabel returns true if name matches a preserve_labels entry (glob, case-insensitive)
+func (s Settings) IsPreservedLabel(name string) bool {
+	for _, pattern := range s.PreserveLabels {
+		if matchPattern(strings.ToLower(pattern), strings.ToLower(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Points returns the story points for a size label value ("M" for "size: M").
+// Sizes match case-insensitively, and keys may be the full label name.
+func (s Settings) Points(size string) (float64, bool) {
+	size = strings.ToLower(strings.TrimSpace(size))
+	if size == "" {
+		return 0, false
+	}
+	for key, points := range s.SizePoints {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if value, ok := LabelValue(key, "size", s.Delimiter()); key == size || (ok && value == size) {
+			return points, true
+		}
+	}
+	return 0, false
+}
+
+// WorkWeek returns settings.business_hours, or the default Mon–Fri
+// 09:00–17:00 UTC week when it isn't set
+func (s Settings) WorkWeek() BusinessHours {
+	if s.BusinessHours == nil {
+		return BusinessHours{}
+	}
+	return *s.BusinessHours
+}
+
+// ProjectConfig configures GitHub Projects v2 as the status source
+type ProjectConfig struct {
+	Number      int               `yaml:"number" json:"number" mapstructure:"number"`                   // Org project number
+	StatusField string            `yaml:"status_field" json:"status_field" mapstructure:"status_field"` // Single-select field name (default "Status")
+	StatusMap   map[string]string `yaml:"status_map" json:"status_map" mapstructure:"status_map"`       // Project column name -> internal status
+}
+
+// UsesProjects returns true if statuses come from a GitHub Projects v2 field
+func (s Settings) UsesProjects() bool {
+	return s.StatusSource == StatusSourceProjects
+}
+
+// WIPLimit returns the WIP limit for a status column. Limits are keyed by
+// label name ("status: in-progress"); a bare status name is accepted too.
+func WIPLimit(limits map[string]int, status string) (int, bool) {
+	if limit, ok := limits["status: "+status]; ok {
+		return limit, true
+	}
+	limit, ok := limits[status]
+	return limit, ok
+}
+
+// Statuses returns the configured workflow, or DefaultWorkflow when unset
+func (s Settings) Statuses() []string {
+	if len(s.Workflow) == 0 {
+		return DefaultWorkflow
+	}
+	return s.Workflow
+}
+
+// StatusIndex returns the position of status in the workflow, or -1 if unknown
+func (s Settings) StatusIndex(status string) int {
+	for i, st := range s.Statuses() {
+		if st == status {
+			return i
+		}
+	}
+	return -1
+}
+
+// IsRegression returns true if moving from one status to another goes backwards
+// in the workflow. Transitions involving unknown statuses are never regressions.
+func (s Settings) IsRegression(from, to string) bool {
+	fi, ti := s.StatusIndex(from), s.StatusIndex(to)
+	return fi >= 0 && ti >= 0 && ti < fi
+}
+
+// ActiveStatuses returns the statuses between the first (intake) and the
+// last (done) of the workflow, where work counts as in flight
+func (s Settings) ActiveStatuses() []string {
+	statuses := s.Statuses()
+	if len(statuses) < 3 {
+		return nil
+	}
+	return statuses[1 : len(statuses)-1]
+}
+
+// StatusLabels returns the GitHub labels that mark an issue as being in
+// status: "status: <name>" followed by any status_label_aliases
+func (s Settings) StatusLabels(status string) []string {
+	return append([]string{s.Label("status", status)}, s.StatusLabelAliases[status]...)
 }
 
-// Load loads configuration from viper
+// Delimiter returns settings.label_delimiter, defaulting to ": "
+func (s Settings) Delimiter() string {
+	if s.LabelDelimiter == "" {
+		return DefaultLabelDelimiter
+	}
+	return s.LabelDelimiter
+}
+
+// Label returns the label for a category's value, e.g. "status: review",
+// or "status/review" with label_delimiter "/"
+func (s Settings) Label(category, value string) string {
+	return category + s.Delimiter() + value
+}
+
+// LabelValue returns the value of a category label written with delimiter,
+// lowercased: "Status/Review" is "review" for category "status" and
+// delimiter "/". Spaces around the delimiter are ignored, so ":" and ": "
+// read the same labels.
+func LabelValue(label, category, delimiter string) (string, bool) {
+	rest, ok := strings.CutPrefix(strings.ToLower(strings.TrimSpace(label)), category)
+	if !ok {
+		return "", false
+	}
+	if sep := strings.TrimSpace(delimiter); sep != "" {
+		if rest, ok = strings.CutPrefix(strings.TrimLeft(rest, " "), sep); !ok {
+			return "", false
+		}
+	} else if !strings.HasPrefix(rest, " ") {
+		// A space delimiter: "status in-progress"
+		return "", false
+	}
+	value := strings.TrimSpace(rest)
+	return value, value != ""
+}
+
+// ActiveStart returns the status where cycle time starts, defaulting to in-progress
+func (s Settings) ActiveStart() string {
+	if s.ActiveStartStatus == "" {
+		return DefaultActiveStartStatus
+	}
+	return s.ActiveStartStatus
+}
+
+// StaleThreshold returns how many days an issue may stay in one status
+// before it counts as stalled
+func (s Settings) StaleThreshold() float64 {
+	if s.StaleThresholdDays <= 0 {
+		return DefaultStaleThresholdDays
+	}
+	return s.StaleThresholdDays
+}
+
+// BlockedThreshold returns how long an issue may stay blocked before it is flagged
+func (s Settings) BlockedThreshold() float64 {
+	if s.BlockedThresholdHours <= 0 {
+		return DefaultBlockedThresholdHours
+	}
+	return s.BlockedThresholdHours
+}
+
+// envSettings are the settings that can be overridden with
+// KANBAN_SETTINGS_<KEY> even when the config file doesn't set them
+var envSettings = []string{
+	"concurrency", "max_retries", "fetch_limit", "active_start_status",
+	"blocked_threshold_hours", "stale_threshold_days", "slack_webhook", "github_host",
+	"use_gh_token",
+}
+
+// wipLimitEnvPrefix starts a per-status WIP limit override, e.g.
+// KANBAN_WIP_LIMIT_IN_PROGRESS=2 for in-progress
+const wipLimitEnvPrefix = "KANBAN_WIP_LIMIT_"
+
+// Load loads configuration from viper, with KANBAN_SETTINGS_* and
+// KANBAN_WIP_LIMIT_* environment variables applied on top
 func Load() (*LabelConfig, error) {
 	cfg := &LabelConfig{
 		Settings: Settings{
 			PreserveUnknown: true,
 			Concurrency:     5,
+			MaxRetries:      DefaultMaxRetries,
 		},
 	}
 
+	// Unmarshal only sees keys viper knows about, so bind the env names
+	for _, key := range envSettings {
+		if err := viper.BindEnv("settings."+key, "KANBAN_SETTINGS_"+strings.ToUpper(key)); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := viper.Unmarshal(cfg); err != nil {
 		return nil, err
 	}
 
+	if err := applyWIPLimitEnv(&cfg.Settings, os.Environ()); err != nil {
+		return nil, err
+	}
+	normalizeWIPLimits(&cfg.Settings)
+
 	return cfg, nil
 }
 
+// applyWIPLimitEnv sets WIP limits from KANBAN_WIP_LIMIT_<STATUS> variables,
+// where underscores in STATUS stand for hyphens. They replace the config's
+// limit for that status, whether keyed by label or bare status name.
+func applyWIPLimitEnv(s *Settings, environ []string) error {
+	for _, kv := range environ {
+		name, value, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(name, wipLimitEnvPrefix) || name == wipLimitEnvPrefix {
+			continue
+		}
+		status := strings.ToLower(strings.ReplaceAll(strings.TrimPrefix(name, wipLimitEnvPrefix), "_", "-"))
+
+		limit, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || limit < 0 {
+			return fmt.Errorf("invalid %s=%q: must be a non-negative integer", name, value)
+		}
+
+		if s.WIPLimits == nil {
+			s.WIPLimits = make(map[string]int)
+		}
+		delete(s.WIPLimits, status)
+		s.WIPLimits["status: "+status] = limit
+	}
+	return nil
+}
+
+// normalizeWIPLimits rekeys WIP limits written with label_delimiter, such as
+// "status/review", to the "status: review" form WIPLimit looks up
+func normalizeWIPLimits(s *Settings) {
+	if s.Delimiter() == DefaultLabelDelimiter {
+		return
+	}
+	for key, limit := range s.WIPLimits {
+		status, ok := LabelValue(key, "status", s.Delimiter())
+		if !ok {
+			continue
+		}
+		delete(s.WIPLimits, key)
+		s.WIPLimits["status: "+status] = limit
+	}
+}
+
 // LoadLabelsFromFile loads labels from a yaml/json file
 func LoadLabelsFromFile(path string) (*LabelConfig, error) {
 	data, err := os.ReadFile(path)
@@ -288,35 +825,53 @@ func (c *LabelConfig) AllLabels() []Label {
 	return labels
 }
 
-// FilterRepos filters repos based on include/exclude patterns
-func (c *LabelConfig) FilterRepos(repos []string) []string {
-	if len(c.Repositories.Include) == 0 && len(c.Repositories.Exclude) == 0 {
+// FilterRepos filters org's repos based on include/exclude patterns.
+// Patterns qualified as "org/pattern" only apply to that organization.
+func (c *LabelConfig) FilterRepos(org string, repos []string) []string {
+	include := patternsForOrg(org, c.Repositories.Include)
+	exclude := patternsForOrg(org, c.Repositories.Exclude)
+	if len(include) == 0 && len(exclude) == 0 {
 		return repos
 	}
 
 	var filtered []string
 	for _, repo := range repos {
-		if c.shouldIncludeRepo(repo) {
+		if shouldIncludeRepo(repo, include, exclude) {
 			filtered = append(filtered, repo)
 		}
 	}
 	return filtered
 }
 
-func (c *LabelConfig) shouldIncludeRepo(repo string) bool {
+// patternsForOrg returns the patterns that apply to org, without their qualifier
+func patternsForOrg(org string, patterns []string) []string {
+	var result []string
+	for _, pattern := range patterns {
+		if owner, rest, ok := strings.Cut(pattern, "/"); ok {
+			if strings.EqualFold(owner, org) {
+				result = append(result, rest)
+			}
+			continue
+		}
+		result = append(result, pattern)
+	}
+	return result
+}
+
+func shouldIncludeRepo(repo string, include, exclude []string) bool {
 	// Check excludes first
-	for _, pattern := range c.Repositories.Exclude {
+	for _, pattern := range exclude {
 		if matchPattern(pattern, repo) {
 			return false
 		}
 	}
 
 	// Check includes
-	if len(c.Repositories.Include) == 0 {
+	if len(include) == 0 {
 		return true
 	}
 
-	for _, pattern := range c.Repositories.Include {
+	for _, pattern := range include {
 		if matchPattern(pattern, repo) {
 			return true
 		}
@@ -1,9 +1,17 @@
 package config
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 )
 
 func TestMatchPattern(t *testing.T) {
@@ -100,7 +108,7 @@ func TestFilterRepos(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			result := tc.config.FilterRepos(tc.input)
+			result := tc.config.FilterRepos("myorg", tc.input)
 			if len(result) != len(tc.expected) {
 				t.Errorf("FilterRepos() returned %d items, want %d", len(result), len(tc.expected))
 				t.Errorf("Got: %v, Want: %v", result, tc.expected)
@@ -115,6 +123,27 @@ func TestFilterRepos(t *testing.T) {
 	}
 }
 
+func TestFilterRepos_QualifiedPatterns(t *testing.T) {
+	config := LabelConfig{
+		Organization:  "kiracore",
+		Organizations: []string{"kira-infra"},
+		Repositories: RepoConfig{
+			Include: []string{"kira-infra/deploy-*"},
+			Exclude: []string{"*.github.io", "kiracore/legacy"},
+		},
+	}
+
+	core := config.FilterRepos("kiracore", []string{"sekai", "legacy", "kiracore.github.io"})
+	if len(core) != 1 || core[0] != "sekai" {
+		t.Errorf("FilterRepos(kiracore) = %v, want [sekai]", core)
+	}
+
+	infra := config.FilterRepos("kira-infra", []string{"deploy-prod", "legacy", "tools"})
+	if len(infra) != 1 || infra[0] != "deploy-prod" {
+		t.Errorf("FilterRepos(kira-infra) = %v, want [deploy-prod]", infra)
+	}
+}
+
 func TestAllLabels(t *testing.T) {
 	config := LabelConfig{
 		Labels: map[string][]Label{
@@ -183,7 +212,7 @@ func TestGetRepos(t *testing.T) {
 		},
 	}
 
-	repos := config.GetRepos()
+	repos := config.GetRepos("myorg")
 	if len(repos) != 2 {
 		t.Errorf("GetRepos() returned %d repos, want 2", len(repos))
 	}
@@ -192,6 +221,69 @@ func TestGetRepos(t *testing.T) {
 	}
 }
 
+func TestGetRepos_MultipleOrgs(t *testing.T) {
+	config := LabelConfig{
+		Organization:  "kiracore",
+		Organizations: []string{"kira-infra"},
+		Repositories: RepoConfig{
+			List: []string{"sekai", "kira-infra/deploy", "KiraCore/interx"},
+		},
+	}
+
+	tests := []struct {
+		org  string
+		want []string
+	}{
+		{"kiracore", []string{"sekai", "interx"}},
+		{"kira-infra", []string{"deploy"}},
+		{"other", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.org, func(t *testing.T) {
+			got := config.GetRepos(tt.org)
+			if len(got) != len(tt.want) {
+				t.Fatalf("GetRepos(%q) = %v, want %v", tt.org, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("GetRepos(%q) = %v, want %v", tt.org, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestOrgList(t *testing.T) {
+	tests := []struct {
+		name   string
+		config LabelConfig
+		want   []string
+	}{
+		{"none", LabelConfig{}, nil},
+		{"single", LabelConfig{Organization: "kiracore"}, []string{"kiracore"}},
+		{"list only", LabelConfig{Organizations: []string{"a", "b"}}, []string{"a", "b"}},
+		{"primary first, deduplicated", LabelConfig{
+			Organization:  "kiracore",
+			Organizations: []string{"kira-infra", "KiraCore"},
+		}, []string{"kiracore", "kira-infra"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.config.OrgList()
+			if len(got) != len(tt.want) {
+				t.Fatalf("OrgList() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("OrgList() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
 func TestLoadLabelsFromFile(t *testing.T) {
 	// Create a temporary config file
 	tmpDir := t.TempDir()
@@ -415,6 +507,56 @@ func TestValidate_DuplicateLabelNames(t *testing.T) {
 	}
 }
 
+func TestValidate_LabelCategory(t *testing.T) {
+	cfg := &LabelConfig{
+		Version:      "1",
+		Organization: "testorg",
+		Labels: map[string][]Label{
+			"status":   {{Name: "status: backlog", Color: "d4d4d4", Description: "d"}, {Name: "blocked", Color: "d4d4d4", Description: "d"}},
+			"priority": {{Name: "priority: high", Color: "d4d4d4", Description: "d"}, {Name: "type: bug", Color: "d4d4d4", Description: "d"}},
+			"special":  {{Name: "wontfix", Color: "d4d4d4", Description: "d"}, {Name: "Size: XL", Color: "d4d4d4", Description: "d"}},
+			"area":     {{Name: "frontend", Color: "d4d4d4", Description: "d"}},
+		},
+	}
+
+	result := cfg.Validate()
+
+	got := make(map[string]bool)
+	for _, w := range result.Warnings {
+		if strings.Contains(w.Message, "listed under") {
+			got[w.Field] = true
+		}
+	}
+	want := map[string]bool{"labels.status[1]": true, "labels.priority[1]": true, "labels.special[1]": true}
+	if len(got) != len(want) {
+		t.Errorf("category warnings on %v, want %v", got, want)
+	}
+	for field := range want {
+		if !got[field] {
+			t.Errorf("missing category warning for %s", field)
+		}
+	}
+	if !result.IsValid() {
+		t.Errorf("category mismatches should only warn, got errors: %v", result.Errors)
+	}
+}
+
+func TestLabelCategory(t *testing.T) {
+	tests := map[string]string{
+		"status: done":   "status",
+		"Priority: high": "priority",
+		"type bug":       "type",
+		"size: XS":       "size",
+		"blocked":        "special",
+		"typescript":     "special",
+	}
+	for name, want := range tests {
+		if got := LabelCategory(name); got != want {
+			t.Errorf("LabelCategory(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
 func TestValidate_Migrations(t *testing.T) {
 	cfg := &LabelConfig{
 		Version:      "1",
@@ -506,6 +648,659 @@ func TestValidate_Settings(t *testing.T) {
 	}
 }
 
+func TestValidate_ActiveStartStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    string
+		wantError bool
+	}{
+		{"unset uses default", "", false},
+		{"ready", "ready", false},
+		{"in-progress", "in-progress", false},
+		{"done is not active", "done", true},
+		{"unknown", "doing", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &LabelConfig{
+				Version:      "1",
+				Organization: "testorg",
+				Labels: map[string][]Label{
+					"status": {{Name: "status: backlog", Color: "d4d4d4"}},
+				},
+				Settings: Settings{
+					Concurrency:       5,
+					ActiveStartStatus: tc.status,
+				},
+			}
+
+			result := cfg.Validate()
+
+			hasError := false
+			for _, e := range result.Errors {
+				if e.Field == "settings.active_start_status" {
+					hasError = true
+					break
+				}
+			}
+
+			if tc.wantError != hasError {
+				t.Errorf("active_start_status=%q: error = %v, want %v", tc.status, hasError, tc.wantError)
+			}
+		})
+	}
+
+	if got := (Settings{}).ActiveStart(); got != DefaultActiveStartStatus {
+		t.Errorf("ActiveStart() = %q, want %q", got, DefaultActiveStartStatus)
+	}
+}
+
+func TestValidate_Exclusions(t *testing.T) {
+	cfg := &LabelConfig{
+		Version:      "1",
+		Organization: "testorg",
+		Labels: map[string][]Label{
+			"status": {{Name: "status: backlog", Color: "d4d4d4"}},
+		},
+		Settings: Settings{
+			Concurrency:   5,
+			ExcludeLabels: []string{"epic", " "},
+			ExcludeIssues: map[string][]int{"myrepo": {12, 0}},
+		},
+	}
+
+	result := cfg.Validate()
+
+	fields := make(map[string]bool)
+	for _, e := range result.Errors {
+		fields[e.Field] = true
+	}
+	for _, want := range []string{"settings.exclude_labels[1]", "settings.exclude_issues.myrepo"} {
+		if !fields[want] {
+			t.Errorf("expected error for %s, got %v", want, result.Errors)
+		}
+	}
+	if fields["settings.exclude_labels[0]"] {
+		t.Errorf("unexpected error for settings.exclude_labels[0]")
+	}
+}
+
+func TestValidate_Workflow(t *testing.T) {
+	tests := []struct {
+		name        string
+		workflow    []string
+		activeStart string
+		wantFields  []string
+	}{
+		{"unset uses default", nil, "", nil},
+		{"custom", []string{"backlog", "analysis", "build", "done"}, "analysis", nil},
+		{"must end with done", []string{"backlog", "build", "shipped"}, "", []string{"settings.workflow"}},
+		{"duplicate", []string{"backlog", "build", "build", "done"}, "", []string{"settings.workflow[2]"}},
+		{"empty status", []string{"backlog", "", "done"}, "", []string{"settings.workflow[1]"}},
+		{"uppercase", []string{"backlog", "Build", "done"}, "", []string{"settings.workflow[1]"}},
+		{"active start outside workflow", []string{"backlog", "build", "done"}, "in-progress", []string{"settings.active_start_status"}},
+		{"active start at intake", []string{"backlog", "build", "done"}, "backlog", []string{"settings.active_start_status"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &LabelConfig{
+				Version:      "1",
+				Organization: "testorg",
+				Labels: map[string][]Label{
+					"status": {{Name: "status: backlog", Color: "d4d4d4"}},
+				},
+				Settings: Settings{
+					Concurrency:       5,
+					Workflow:          tc.workflow,
+					ActiveStartStatus: tc.activeStart,
+				},
+			}
+
+			result := cfg.Validate()
+
+			var fields []string
+			for _, e := range result.Errors {
+				fields = append(fields, e.Field)
+			}
+			if !reflect.DeepEqual(fields, tc.wantFields) {
+				t.Errorf("error fields = %v, want %v", fields, tc.wantFields)
+			}
+		})
+	}
+
+	if got := (Settings{}).Statuses(); !reflect.DeepEqual(got, DefaultWorkflow) {
+		t.Errorf("Statuses() = %v, want %v", got, DefaultWorkflow)
+	}
+}
+
+func TestSettings_CustomWorkflow(t *testing.T) {
+	s := Settings{Workflow: []string{"todo", "doing", "done"}}
+	if got := s.StatusIndex("doing"); got != 1 {
+		t.Errorf("StatusIndex(doing) = %d, want 1", got)
+	}
+	if !s.IsRegression("doing", "todo") {
+		t.Error("IsRegression(doing, todo) = false, want true")
+	}
+	if got := s.ActiveStatuses(); !reflect.DeepEqual(got, []string{"doing"}) {
+		t.Errorf("ActiveStatuses() = %v, want [doing]", got)
+	}
+
+	// The default workflow is unaffected by another Settings' workflow
+	if got := (Settings{}).StatusIndex("review"); got != 3 {
+		t.Errorf("StatusIndex(review) with default workflow = %d, want 3", got)
+	}
+	if got := (Settings{}).StatusIndex("doing"); got != -1 {
+		t.Errorf("StatusIndex(doing) with default workflow = %d, want -1", got)
+	}
+}
+
+func TestStatusLabelAliases(t *testing.T) {
+	s := Settings{StatusLabelAliases: map[string][]string{
+		"in-progress": {"Status/InProgress", "wip"},
+	}}
+
+	if got, want := s.StatusLabels("in-progress"), []string{"status: in-progress", "Status/InProgress", "wip"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("StatusLabels(in-progress) = %v, want %v", got, want)
+	}
+	if got, want := s.StatusLabels("review"), []string{"status: review"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("StatusLabels(review) = %v, want %v", got, want)
+	}
+
+	cfg := &LabelConfig{
+		Version:      "1",
+		Organization: "testorg",
+		Labels: map[string][]Label{
+			"status": {{Name: "status: backlog", Color: "d4d4d4"}},
+		},
+		Settings: Settings{
+			Concurrency: 5,
+			StatusLabelAliases: map[string][]string{
+				"in-progress": {"Status/InProgress"},
+				"doing":       {"Status/Doing"},
+				"review":      {""},
+			},
+		},
+	}
+
+	var fields []string
+	for _, e := range cfg.Validate().Errors {
+		fields = append(fields, e.Field)
+	}
+	sort.Strings(fields)
+	want := []string{"settings.status_label_aliases.doing", "settings.status_label_aliases.review[0]"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("error fields = %v, want %v", fields, want)
+	}
+}
+
+func TestValidate_StatusSource(t *testing.T) {
+	tests := []struct {
+		name       string
+		settings   Settings
+		wantFields []string
+	}{
+		{"default labels", Settings{Concurrency: 5}, nil},
+		{"explicit labels", Settings{Concurrency: 5, StatusSource: "labels"}, nil},
+		{"projects with number", Settings{Concurrency: 5, StatusSource: "projects", Project: ProjectConfig{Number: 3}}, nil},
+		{"projects without number", Settings{Concurrency: 5, StatusSource: "projects"}, []string{"settings.project.number"}},
+		{"unknown source", Settings{Concurrency: 5, StatusSource: "jira"}, []string{"settings.status_source"}},
+		{
+			"bad mapping",
+			Settings{Concurrency: 5, StatusSource: "projects", Project: ProjectConfig{Number: 3, StatusMap: map[string]string{"Shipped": "released"}}},
+			[]string{"settings.project.status_map.Shipped"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &LabelConfig{
+				Version:      "1",
+				Organization: "testorg",
+				Labels: map[string][]Label{
+					"status": {{Name: "status: backlog", Color: "d4d4d4"}},
+				},
+				Settings: tc.settings,
+			}
+
+			result := cfg.Validate()
+
+			if len(result.Errors) != len(tc.wantFields) {
+				t.Fatalf("Validate() errors = %v, want fields %v", result.Errors, tc.wantFields)
+			}
+			for i, field := range tc.wantFields {
+				if result.Errors[i].Field != field {
+					t.Errorf("Error field = %q, want %q", result.Errors[i].Field, field)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateLabels_IgnoresOtherSections(t *testing.T) {
+	// Standalone label files have no organization or settings
+	cfg := &LabelConfig{
+		Labels: map[string][]Label{
+			"status": {{Name: "status: backlog", Color: "d4d4d4", Description: "Backlog"}},
+		},
+	}
+	if result := cfg.ValidateLabels(); !result.IsValid() {
+		t.Errorf("ValidateLabels() errors = %v, want none", result.Errors)
+	}
+
+	cfg.Labels["status"] = append(cfg.Labels["status"], Label{Name: "status: ready", Color: "#0075ca"})
+	if result := cfg.ValidateLabels(); result.IsValid() {
+		t.Error("ValidateLabels() should reject invalid color")
+	}
+}
+
+func TestSettings_IsIgnoredAuthor(t *testing.T) {
+	s := Settings{IgnoreAuthors: []string{"*[bot]", "renovate"}}
+
+	tests := []struct {
+		login string
+		want  bool
+	}{
+		{"dependabot[bot]", true},
+		{"Renovate", true},
+		{"alice", false},
+		{"@unassigned", false},
+	}
+	for _, tt := range tests {
+		if got := s.IsIgnoredAuthor(tt.login); got != tt.want {
+			t.Errorf("IsIgnoredAuthor(%q) = %v, want %v", tt.login, got, tt.want)
+		}
+	}
+}
+
+func TestSettings_IsPreservedLabel(t *testing.T) {
+	s := Settings{PreserveLabels: []string{"dependencies", "release-*"}}
+
+	for name, want := range map[string]bool{
+		"Dependencies":  true,
+		"release-1.2":   true,
+		"wontfix":       false,
+		"status: ready": false,
+	} {
+		if got := s.IsPreservedLabel(name); got != want {
+			t.Errorf("IsPreservedLabel(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestSettings_IsExcludedIssue(t *testing.T) {
+	s := Settings{
+		ExcludeLabels: []string{"epic", "type: tracking"},
+		ExcludeIssues: map[string][]int{"myrepo": {7}, "otherorg/app": {3}},
+	}
+
+	tests := []struct {
+		fullName string
+		number   int
+		labels   []string
+		want     bool
+	}{
+		{"myorg/myrepo", 1, []string{"Epic"}, true},
+		{"myorg/myrepo", 1, []string{"type:tracking"}, true},
+		{"myorg/myrepo", 1, []string{"type: bug"}, false},
+		{"myorg/myrepo", 7, nil, true},
+		{"otherorg/myrepo", 7, nil, true},
+		{"otherorg/app", 3, nil, true},
+		{"myorg/app", 3, nil, false},
+	}
+	for _, tt := range tests {
+		if got := s.IsExcludedIssue(tt.fullName, tt.number, tt.labels); got != tt.want {
+			t.Errorf("IsExcludedIssue(%q, %d, %v) = %v, want %v", tt.fullName, tt.number, tt.labels, got, tt.want)
+		}
+	}
+}
+
+func TestSettings_Points(t *testing.T) {
+	// Viper lowercases map keys, so "M" may arrive as "m"
+	s := Settings{SizePoints: map[string]float64{"xs": 1, "M": 3, "size: XL": 8}}
+
+	tests := []struct {
+		size   string
+		want   float64
+		wantOK bool
+	}{
+		{"xs", 1, true},
+		{"XS", 1, true},
+		{"m", 3, true},
+		{" xl ", 8, true},
+		{"l", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := s.Points(tt.size)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("Points(%q) = %v, %v, want %v, %v", tt.size, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	cfg := &LabelConfig{
+		Version:      "1",
+		Organization: "testorg",
+		Labels: map[string][]Label{
+			"status": {{Name: "status: backlog", Color: "d4d4d4"}},
+		},
+		Settings: Settings{Concurrency: 5, SizePoints: map[string]float64{"S": -2}},
+	}
+	if cfg.Validate().IsValid() {
+		t.Error("negative size_points should be invalid")
+	}
+}
+
+func TestBusinessHoursBetween(t *testing.T) {
+	// 2026-10-16 is a Friday
+	at := func(day, hour, min int) time.Time { return time.Date(2026, 10, day, hour, min, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		cfg        BusinessHours
+		want       float64
+	}{
+		{"within one day", at(16, 10, 0), at(16, 12, 30), BusinessHours{}, 2.5},
+		{"before opening to after closing", at(16, 6, 0), at(16, 20, 0), BusinessHours{}, 8},
+		{"over the weekend", at(16, 16, 0), at(19, 10, 0), BusinessHours{}, 2},
+		{"weekend only", at(17, 9, 0), at(18, 17, 0), BusinessHours{}, 0},
+		{"full week", at(12, 0, 0), at(19, 0, 0), BusinessHours{}, 40},
+		{"custom days and hours", at(16, 0, 0), at(18, 0, 0),
+			BusinessHours{Days: []string{"Saturday"}, Start: "10:00", End: "14:00"}, 4},
+		// 07:00-09:00 UTC is 09:00-11:00 in Berlin (CEST)
+		{"timezone", at(16, 7, 0), at(16, 9, 0), BusinessHours{Timezone: "Europe/Berlin"}, 2},
+		{"end before start", at(16, 12, 0), at(16, 10, 0), BusinessHours{}, 0},
+		{"invalid config", at(16, 10, 0), at(16, 12, 0), BusinessHours{Start: "9am"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BusinessHoursBetween(tt.start, tt.end, tt.cfg); got != tt.want {
+				t.Errorf("BusinessHoursBetween() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Berlin leaves DST on Sunday 2026-10-25; that day still has its 8 hours
+	berlin := BusinessHours{Days: []string{"sun"}, Timezone: "Europe/Berlin"}
+	if got := BusinessHoursBetween(at(24, 0, 0), at(26, 0, 0), berlin); got != 8 {
+		t.Errorf("BusinessHoursBetween() over DST change = %v, want 8", got)
+	}
+}
+
+func TestBusinessHours_Validate(t *testing.T) {
+	valid := []BusinessHours{
+		{},
+		{Days: []string{"mon", "Tuesday"}, Start: "08:30", End: "16:30", Timezone: "America/New_York"},
+	}
+	for _, b := range valid {
+		if err := b.Validate(); err != nil {
+			t.Errorf("Validate(%+v) = %v, want nil", b, err)
+		}
+	}
+
+	invalid := []BusinessHours{
+		{Days: []string{"funday"}},
+		{Start: "25:00"},
+		{Start: "17:00", End: "09:00"},
+		{Timezone: "Mars/Olympus"},
+	}
+	for _, b := range invalid {
+		if err := b.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want an error", b)
+		}
+	}
+
+	if got := (BusinessHours{}).String(); got != "mon-fri 09:00-17:00 UTC" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := (BusinessHours{Start: "10:00", End: "16:00"}).DayHours(); got != 6 {
+		t.Errorf("DayHours() = %v, want 6", got)
+	}
+
+	cfg := &LabelConfig{
+		Version:      "1",
+		Organization: "testorg",
+		Labels: map[string][]Label{
+			"status": {{Name: "status: backlog", Color: "d4d4d4"}},
+		},
+		Settings: Settings{Concurrency: 5, BusinessHours: &BusinessHours{Timezone: "Nowhere/Land"}},
+	}
+	if cfg.Validate().IsValid() {
+		t.Error("an unknown business_hours timezone should be invalid")
+	}
+}
+
+func TestValidate_MaxRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxRetries int
+		wantValid  bool
+		wantWarn   bool
+	}{
+		{"default", DefaultMaxRetries, true, false},
+		{"disabled", 0, true, false},
+		{"negative", -1, false, false},
+		{"excessive", 20, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &LabelConfig{
+				Organization: "test-org",
+				Settings:     Settings{Concurrency: 5, MaxRetries: tt.maxRetries},
+			}
+			result := cfg.Validate()
+			if result.IsValid() != tt.wantValid {
+				t.Errorf("IsValid() = %v, want %v", result.IsValid(), tt.wantValid)
+			}
+			warned := false
+			for _, w := range result.Warnings {
+				if w.Field == "settings.max_retries" {
+					warned = true
+				}
+			}
+			if warned != tt.wantWarn {
+				t.Errorf("max_retries warning = %v, want %v", warned, tt.wantWarn)
+			}
+		})
+	}
+}
+
+func TestWIPLimit(t *testing.T) {
+	limits := map[string]int{
+		"status: in-progress": 2,
+		"review":              3,
+	}
+
+	tests := []struct {
+		status string
+		want   int
+		wantOK bool
+	}{
+		{"in-progress", 2, true},
+		{"review", 3, true},
+		{"testing", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := WIPLimit(limits, tt.status)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("WIPLimit(%q) = %d, %v; want %d, %v", tt.status, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestLabelValue(t *testing.T) {
+	tests := []struct {
+		label     string
+		delimiter string
+		want      string
+		wantOK    bool
+	}{
+		{"status: in-progress", ": ", "in-progress", true},
+		{"status:in-progress", ": ", "in-progress", true},
+		{"Status: Review", ":", "review", true},
+		{"status:review", ":", "review", true},
+		{"status/in-progress", "/", "in-progress", true},
+		{"status / review", "/", "review", true},
+		{"status: review", "/", "", false},
+		{"status in-progress", " ", "in-progress", true},
+		{"status:review", " ", "", false},
+		{"statusreview", " ", "", false},
+		{"priority: high", ": ", "", false},
+		{"status:", ": ", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := LabelValue(tt.label, "status", tt.delimiter)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("LabelValue(%q, status, %q) = %q, %v; want %q, %v", tt.label, tt.delimiter, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestSettings_LabelDelimiter(t *testing.T) {
+	s := Settings{}
+	if got := s.StatusLabels("review"); got[0] != "status: review" {
+		t.Errorf("StatusLabels(review) = %v, want status: review first", got)
+	}
+
+	s = Settings{
+		LabelDelimiter: "/",
+		WIPLimits:      map[string]int{"status/in-progress": 2, "review": 3},
+		SizePoints:     map[string]float64{"size/m": 3},
+	}
+	if got := s.StatusLabels("review"); got[0] != "status/review" {
+		t.Errorf("StatusLabels(review) with / = %v, want status/review first", got)
+	}
+	if got, ok := s.Points("M"); !ok || got != 3 {
+		t.Errorf("Points(M) with size/m key = %v, %v; want 3, true", got, ok)
+	}
+
+	normalizeWIPLimits(&s)
+	if limit, ok := WIPLimit(s.WIPLimits, "in-progress"); !ok || limit != 2 {
+		t.Errorf("WIPLimit(in-progress) after normalizing status/in-progress = %d, %v; want 2, true", limit, ok)
+	}
+	if limit, ok := WIPLimit(s.WIPLimits, "review"); !ok || limit != 3 {
+		t.Errorf("WIPLimit(review) = %d, %v; want 3, true", limit, ok)
+	}
+
+	for delimiter, valid := range map[string]bool{"": true, ": ": true, ":": true, "/": true, " ": true, "::": true, "-": false, "x": false} {
+		cfg := &LabelConfig{
+			Organization: "test-org",
+			Settings:     Settings{Concurrency: 5, LabelDelimiter: delimiter},
+		}
+		if got := cfg.Validate().IsValid(); got != valid {
+			t.Errorf("Validate() with label_delimiter %q valid = %v, want %v", delimiter, got, valid)
+		}
+	}
+}
+
+func TestSettings_BlockedThreshold(t *testing.T) {
+	if got := (Settings{}).BlockedThreshold(); got != DefaultBlockedThresholdHours {
+		t.Errorf("BlockedThreshold() = %v, want default %v", got, DefaultBlockedThresholdHours)
+	}
+	if got := (Settings{BlockedThresholdHours: 24}).BlockedThreshold(); got != 24 {
+		t.Errorf("BlockedThreshold() = %v, want 24", got)
+	}
+
+	cfg := &LabelConfig{
+		Organization: "test-org",
+		Settings:     Settings{Concurrency: 5, BlockedThresholdHours: -1},
+	}
+	result := cfg.Validate()
+	if result.IsValid() {
+		t.Error("Validate() should reject a negative blocked_threshold_hours")
+	}
+}
+
+func TestSettings_StaleThreshold(t *testing.T) {
+	if got := (Settings{}).StaleThreshold(); got != DefaultStaleThresholdDays {
+		t.Errorf("StaleThreshold() = %v, want default %v", got, DefaultStaleThresholdDays)
+	}
+	if got := (Settings{StaleThresholdDays: 7}).StaleThreshold(); got != 7 {
+		t.Errorf("StaleThreshold() = %v, want 7", got)
+	}
+
+	cfg := &LabelConfig{
+		Organization: "test-org",
+		Settings:     Settings{Concurrency: 5, StaleThresholdDays: -1},
+	}
+	result := cfg.Validate()
+	if result.IsValid() {
+		t.Error("Validate() should reject a negative stale_threshold_days")
+	}
+}
+
+func TestValidate_FetchLimit(t *testing.T) {
+	for limit, valid := range map[int]bool{0: true, 2000: true, -1: false} {
+		cfg := &LabelConfig{
+			Organization: "test-org",
+			Settings:     Settings{Concurrency: 5, FetchLimit: limit},
+		}
+		if got := cfg.Validate().IsValid(); got != valid {
+			t.Errorf("Validate() with fetch_limit %d valid = %v, want %v", limit, got, valid)
+		}
+	}
+}
+
+func TestValidate_SlackWebhook(t *testing.T) {
+	for webhook, valid := range map[string]bool{
+		"":                                   true,
+		"https://hooks.slack.com/services/x": true,
+		"http://hooks.slack.com/services/x":  false,
+		"hooks.slack.com/services/x":         false,
+	} {
+		cfg := &LabelConfig{
+			Organization: "test-org",
+			Settings:     Settings{Concurrency: 5, SlackWebhook: webhook},
+		}
+		if got := cfg.Validate().IsValid(); got != valid {
+			t.Errorf("Validate() with slack_webhook %q valid = %v, want %v", webhook, got, valid)
+		}
+	}
+}
+
+func TestValidate_GitHubHost(t *testing.T) {
+	for host, valid := range map[string]bool{
+		"":                           true,
+		"github.example.com":         true,
+		"ghe.internal:8443":          true,
+		"https://github.example.com": false,
+		"github.example.com/api/v3":  false,
+		"github example.com":         false,
+	} {
+		cfg := &LabelConfig{
+			Organization: "test-org",
+			Settings:     Settings{Concurrency: 5, GitHubHost: host},
+		}
+		if got := cfg.Validate().IsValid(); got != valid {
+			t.Errorf("Validate() with github_host %q valid = %v, want %v", host, got, valid)
+		}
+	}
+}
+
+func TestIsRegression(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{"review", "in-progress", true},
+		{"done", "ready", true},
+		{"in-progress", "review", false},
+		{"ready", "ready", false},
+		{"", "backlog", false},
+		{"review", "unknown", false},
+	}
+	for _, tt := range tests {
+		if got := (Settings{}).IsRegression(tt.from, tt.to); got != tt.want {
+			t.Errorf("IsRegression(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
 func TestValidate_Repositories(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -594,3 +1389,111 @@ func TestValidationError_Error(t *testing.T) {
 		t.Errorf("Error() = %q, want %q", e.Error(), expected)
 	}
 }
+
+func TestMigrateSchema(t *testing.T) {
+	old := []byte(`# Team config
+organization: acme # main org
+labels:
+  status:
+    - name: "status: ready"
+      color: "0e8a16"
+settings:
+  # Only 3 at once
+  concurrency: 3
+`)
+
+	migrated, changes, err := MigrateSchema(old)
+	if err != nil {
+		t.Fatalf("MigrateSchema() error: %v", err)
+	}
+	if len(changes) == 0 {
+		t.Fatal("MigrateSchema() made no changes to an unversioned config")
+	}
+	for _, comment := range []string{"# Team config", "# main org", "# Only 3 at once"} {
+		if !strings.Contains(string(migrated), comment) {
+			t.Errorf("migrated config lost comment %q:\n%s", comment, migrated)
+		}
+	}
+
+	cfg := &LabelConfig{}
+	if err := yaml.Unmarshal(migrated, cfg); err != nil {
+		t.Fatalf("migrated config doesn't parse: %v", err)
+	}
+	if cfg.Version != CurrentVersion {
+		t.Errorf("Version = %q, want %q", cfg.Version, CurrentVersion)
+	}
+	if cfg.Settings.Concurrency != 3 {
+		t.Errorf("Concurrency = %d, want existing 3 kept", cfg.Settings.Concurrency)
+	}
+	if cfg.Settings.MaxRetries != DefaultMaxRetries || cfg.Settings.ActiveStartStatus != DefaultActiveStartStatus || !cfg.Settings.PreserveUnknown {
+		t.Errorf("defaults not filled in: %+v", cfg.Settings)
+	}
+
+	// A second run is a no-op
+	again, changes, err := MigrateSchema(migrated)
+	if err != nil {
+		t.Fatalf("second MigrateSchema() error: %v", err)
+	}
+	if len(changes) != 0 || !bytes.Equal(again, migrated) {
+		t.Errorf("second MigrateSchema() changed the config: %v", changes)
+	}
+
+	if _, _, err := MigrateSchema([]byte("version: \"9\"\n")); err == nil {
+		t.Error("MigrateSchema() should refuse a newer version")
+	}
+}
+
+func TestLoad_EnvOverrides(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	path := filepath.Join(t.TempDir(), "kanban.yaml")
+	data := []byte(`organization: acme
+settings:
+  preserve_unknown: false
+  wip_limits:
+    "status: in-progress": 5
+    review: 4
+    "status: testing": 3
+`)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	viper.SetConfigFile(path)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("KANBAN_SETTINGS_CONCURRENCY", "12")
+	t.Setenv("KANBAN_SETTINGS_STALE_THRESHOLD_DAYS", "7")
+	t.Setenv("KANBAN_SETTINGS_USE_GH_TOKEN", "false")
+	t.Setenv("KANBAN_WIP_LIMIT_IN_PROGRESS", "2")
+	t.Setenv("KANBAN_WIP_LIMIT_REVIEW", "1")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.Settings.Concurrency != 12 {
+		t.Errorf("Concurrency = %d, want 12", cfg.Settings.Concurrency)
+	}
+	if cfg.Settings.StaleThresholdDays != 7 {
+		t.Errorf("StaleThresholdDays = %v, want 7", cfg.Settings.StaleThresholdDays)
+	}
+	if cfg.Settings.PreserveUnknown {
+		t.Error("PreserveUnknown = true, want false from the config file")
+	}
+	if cfg.Settings.UseGHToken == nil || *cfg.Settings.UseGHToken {
+		t.Errorf("UseGHToken = %v, want false", cfg.Settings.UseGHToken)
+	}
+	for status, want := range map[string]int{"in-progress": 2, "review": 1, "testing": 3} {
+		if got, ok := WIPLimit(cfg.Settings.WIPLimits, status); !ok || got != want {
+			t.Errorf("WIPLimit(%s) = %d, %v, want %d", status, got, ok, want)
+		}
+	}
+
+	t.Setenv("KANBAN_WIP_LIMIT_REVIEW", "lots")
+	if _, err := Load(); err == nil {
+		t.Error("Load() should reject a non-numeric WIP limit")
+	}
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the config schema version written by MigrateSchema
+const CurrentVersion = "1"
+
+// schemaDefaults are the settings MigrateSchema adds when missing, in the
+// order of the default config, each with the comment written above it
+var schemaDefaults = []struct {
+	key, value, tag, comment string
+}{
+	{"preserve_unknown", "true", "!!bool", "Keep labels not defined in this config"},
+	{"concurrency", "5", "!!int", "Parallel operations (repos processed concurrently)"},
+	{"max_retries", strconv.Itoa(DefaultMaxRetries), "!!int", "Retries (with exponential backoff) for GitHub calls that hit a rate limit"},
+	{"fetch_limit", "0", "!!int", "Most issues fetched per repo and query; 0 = no limit"},
+	{"active_start_status", DefaultActiveStartStatus, "!!str", "Status where active work begins; cycle time is measured from here"},
+	{"blocked_threshold_hours", strconv.Itoa(DefaultBlockedThresholdHours), "!!int", "Hours an issue may stay blocked before \"kanban blocked\" flags it"},
+	{"stale_threshold_days", strconv.Itoa(DefaultStaleThresholdDays), "!!int", "Days an issue may stay in one status before metrics --stalled lists it"},
+	{"status_source", StatusSourceLabels, "!!str", "Where issue statuses come from: \"labels\" or \"projects\""},
+}
+
+// MigrateSchema upgrades config YAML to CurrentVersion: it sets a missing or
+// older version and adds missing settings with their defaults. Existing
+// values and comments are kept, though blank lines may not be. It returns
+// the changes made; without any, data is returned as is, so migrating twice
+// is a no-op.
+func MigrateSchema(data []byte) ([]byte, []string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("config is not a YAML mapping")
+	}
+	root := doc.Content[0]
+
+	var changes []string
+
+	// Version
+	version := mappingValue(root, "version")
+	switch {
+	case version == nil:
+		// The first key's head comment is usually the file's header, keep it on top
+		key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "version"}
+		if len(root.Content) > 0 {
+			key.HeadComment, root.Content[0].HeadComment = root.Content[0].HeadComment, ""
+		}
+		root.Content = append([]*yaml.Node{
+			key,
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: CurrentVersion, Style: yaml.DoubleQuotedStyle},
+		}, root.Content...)
+		changes = append(changes, fmt.Sprintf("set version to %q", CurrentVersion))
+	case version.Value != CurrentVersion:
+		v, err := strconv.Atoi(version.Value)
+		current, _ := strconv.Atoi(CurrentVersion)
+		if version.Value != "" && (err != nil || v > current) {
+			return nil, nil, fmt.Errorf("unknown config version %q (this kanban supports up to %q)", version.Value, CurrentVersion)
+		}
+		changes = append(changes, fmt.Sprintf("set version from %q to %q", version.Value, CurrentVersion))
+		version.Kind, version.Tag, version.Value, version.Style = yaml.ScalarNode, "!!str", CurrentVersion, yaml.DoubleQuotedStyle
+	}
+
+	// Settings
+	settings := mappingValue(root, "settings")
+	if settings == nil {
+		settings = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		root.Content = append(root.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "settings", HeadComment: "# Settings"},
+			settings)
+	} else if settings.Kind == yaml.ScalarNode && settings.Tag == "!!null" {
+		// "settings:" with nothing under it
+		settings.Kind, settings.Tag, settings.Value = yaml.MappingNode, "!!map", ""
+	} else if settings.Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("settings is not a YAML mapping")
+	}
+
+	for _, d := range schemaDefaults {
+		if mappingValue(settings, d.key) != nil {
+			continue
+		}
+		settings.Content = append(settings.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: d.key, HeadComment: "# " + d.comment},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: d.tag, Value: d.value})
+		changes = append(changes, fmt.Sprintf("added settings.%s: %s", d.key, d.value))
+	}
+
+	if len(changes) == 0 {
+		return data, nil, nil
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, nil, err
+	}
+	return buf.Bytes(), changes, nil
+}
+
+// mappingValue returns the value node of key in a mapping node, or nil
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -1,12 +1,17 @@
 package db
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
 	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/kiracore/kanban/internal/paths"
@@ -17,6 +22,32 @@ import (
 type DB struct {
 	*sql.DB
 	path string
+
+	// activeStart is the SQL expression for when cycle time starts
+	activeStart string
+
+	// includeNotPlanned keeps issues closed as not planned in completion metrics
+	includeNotPlanned bool
+
+	// exclusions are left out of the board, WIP and flow metrics
+	exclusions Exclusions
+}
+
+// Exclusions are issues kept off the board and out of WIP and flow metrics,
+// such as epics and tracking issues
+type Exclusions struct {
+	Labels []string         // label names, matched ignoring case and spaces
+	Issues map[string][]int // issue numbers by repo, "repo" or "org/repo"
+}
+
+// statusColumns maps the default workflow statuses to their entry timestamp
+// column on issues. Other statuses are only kept in status_timestamps.
+var statusColumns = map[string]string{
+	"ready":       "entered_ready_at",
+	"in-progress": "entered_progress_at",
+	"review":      "entered_review_at",
+	"testing":     "entered_testing_at",
+	"done":        "entered_done_at",
 }
 
 // DefaultDBPath returns the default database path.
@@ -49,7 +80,128 @@ func Open(path string) (*DB, error) {
 	db.SetMaxIdleConns(1)
 	db.SetConnMaxLifetime(0) // Keep connection open indefinitely
 
-	return &DB{DB: db, path: path}, nil
+	return &DB{DB: db, path: path, activeStart: "entered_progress_at"}, nil
+}
+
+// readOnlyConns is how many connections an OpenReadOnly handle may use
+const readOnlyConns = 4
+
+// OpenReadOnly opens an existing database for reading. Unlike Open, several
+// connections are allowed, so queries can run side by side (WAL mode lets
+// readers share the file with a writer). Writers still use Open, whose single
+// connection keeps writes serialized.
+func OpenReadOnly(path string) (*DB, error) {
+	if path == "" {
+		path = DefaultDBPath()
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	connStr := "file:" + path + "?mode=ro&_pragma=cache_size(-64000)&_pragma=busy_timeout(5000)"
+	db, err := sql.Open("sqlite", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	db.SetMaxOpenConns(readOnlyConns)
+	db.SetMaxIdleConns(readOnlyConns)
+	db.SetConnMaxLifetime(0)
+
+	return &DB{DB: db, path: path, activeStart: "entered_progress_at"}, nil
+}
+
+// SetActiveStartStatus sets the status whose entry starts cycle time (default
+// in-progress). Statuses outside the default workflow are looked up in
+// status_timestamps.
+func (db *DB) SetActiveStartStatus(status string) error {
+	if status == "" || status == "done" {
+		return fmt.Errorf("unknown active start status: %q", status)
+	}
+	if column, ok := statusColumns[status]; ok {
+		db.activeStart = column
+		return nil
+	}
+	db.activeStart = "(SELECT entered_at FROM status_timestamps WHERE issue_id = issues.id AND status = '" +
+		strings.ReplaceAll(status, "'", "''") + "')"
+	return nil
+}
+
+// SetIncludeNotPlanned sets whether issues closed as not planned or as
+// duplicates count as completed. By default they're left out of throughput,
+// velocity and lead time.
+func (db *DB) SetIncludeNotPlanned(include bool) {
+	db.includeNotPlanned = include
+}
+
+// completedFilter is the condition on issues aliased as i that leaves out
+// closures that weren't completed work, unless SetIncludeNotPlanned is on.
+// Issues with no recorded reason count as completed.
+func (db *DB) completedFilter() string {
+	if db.includeNotPlanned {
+		return ""
+	}
+	return " AND COALESCE(i.state_reason, '') NOT IN ('not_planned', 'duplicate')"
+}
+
+// SetExclusions sets the issues left out of GetBoardIssues, GetWIPSummary,
+// closed-issue flow metrics and arrivals. Label exclusions need the issue
+// labels recorded by sync.
+func (db *DB) SetExclusions(e Exclusions) {
+	db.exclusions = e
+}
+
+// excludedCondition is the condition matching excluded issues, on issues
+// aliased as i joined to repositories as r, with its arguments. It's empty
+// when nothing is excluded.
+func (db *DB) excludedCondition() (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	if labels := db.exclusions.Labels; len(labels) > 0 {
+		conds = append(conds, `i.id IN (SELECT il.issue_id FROM issue_labels il
+			JOIN labels l ON l.id = il.label_id
+			WHERE l.repo_id = i.repo_id AND REPLACE(LOWER(l.name), ' ', '') IN (?`+strings.Repeat(", ?", len(labels)-1)+`))`)
+		for _, label := range labels {
+			args = append(args, strings.ReplaceAll(strings.ToLower(label), " ", ""))
+		}
+	}
+
+	repos := make([]string, 0, len(db.exclusions.Issues))
+	for repo := range db.exclusions.Issues {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	for _, repo := range repos {
+		numbers := db.exclusions.Issues[repo]
+		if len(numbers) == 0 {
+			continue
+		}
+		// A bare repo name matches it in any organization
+		column := "LOWER(r.full_name)"
+		if !strings.Contains(repo, "/") {
+			column = "LOWER(SUBSTR(r.full_name, INSTR(r.full_name, '/') + 1))"
+		}
+		conds = append(conds, "("+column+" = ? AND i.number IN (?"+strings.Repeat(", ?", len(numbers)-1)+"))")
+		args = append(args, strings.ToLower(repo))
+		for _, n := range numbers {
+			args = append(args, n)
+		}
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return "(" + strings.Join(conds, " OR ") + ")", args
+}
+
+// withoutExcluded adds the condition leaving out excluded issues to a query
+// on issues i and repositories r
+func (db *DB) withoutExcluded(query string, args []interface{}) (string, []interface{}) {
+	cond, condArgs := db.excludedCondition()
+	if cond == "" {
+		return query, args
+	}
+	return query + " AND NOT " + cond, append(args, condArgs...)
 }
 
 // Path returns the database file path
@@ -57,7 +209,8 @@ func (db *DB) Path() string {
 	return db.path
 }
 
-// Init initializes the database schema
+// Init initializes the database schema. A database created by an older
+// release is brought up to date by running its pending migrations first.
 func (db *DB) Init() error {
 	// Check if already initialized
 	var version int
@@ -66,7 +219,14 @@ func (db *DB) Init() error {
 		return nil // Already up to date
 	}
 
-	// Create schema
+	// Upgrade an existing database one version at a time
+	if err == nil && version > 0 {
+		if err := db.migrate(version); err != nil {
+			return err
+		}
+	}
+
+	// Create schema (CREATE ... IF NOT EXISTS leaves migrated tables alone)
 	if _, err := db.Exec(Schema); err != nil {
 		return fmt.Errorf("failed to create schema: %w", err)
 	}
@@ -85,63 +245,190 @@ func (db *DB) Init() error {
 	return nil
 }
 
-// Backup copies the database to the specified path
+// Backup writes a consistent snapshot of the database to the specified path
+// with VACUUM INTO, so writes from other connections or processes can't tear
+// it. The snapshot goes to a temporary file first; an existing backup at
+// destPath is only replaced once it is complete.
 func (db *DB) Backup(destPath string) error {
-	// Close WAL checkpoint first
-	if _, err := db.Exec("PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
-		return fmt.Errorf("failed to checkpoint: %w", err)
-	}
-
-	src, err := os.Open(db.path)
-	if err != nil {
-		return fmt.Errorf("failed to open source: %w", err)
-	}
-	defer src.Close()
-
 	// Ensure destination directory exists
 	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
 		return fmt.Errorf("failed to create destination directory: %w", err)
 	}
 
-	dst, err := os.Create(destPath)
-	if err != nil {
-		return fmt.Errorf("failed to create destination: %w", err)
+	// VACUUM INTO refuses to overwrite, so clear a leftover from a failed run
+	tmpPath := destPath + ".tmp"
+	if err := os.Remove(tmpPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove stale temporary file: %w", err)
 	}
-	defer dst.Close()
 
-	if _, err := io.Copy(dst, src); err != nil {
-		return fmt.Errorf("failed to copy: %w", err)
+	if _, err := db.Exec("VACUUM INTO ?", tmpPath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to back up: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, destPath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to move backup into place: %w", err)
 	}
 
 	return nil
 }
 
-// Restore restores the database from a backup
+// Restore restores the database from a backup.
+// The backup is copied next to the database and must pass an integrity check
+// before it is renamed over the original, so a bad or truncated backup leaves
+// the database and this handle untouched. On success the handle is closed and
+// stale -wal/-shm files are removed so that a subsequent Open sees only the
+// restored file.
 func (db *DB) Restore(srcPath string) error {
+	tmpPath, err := copyToTemp(srcPath, db.path)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+
+	if err := checkDatabaseFile(tmpPath); err != nil {
+		return fmt.Errorf("invalid backup: %w", err)
+	}
+
+	// Flush pending WAL pages before closing; the files are removed below anyway
+	db.Exec("PRAGMA wal_checkpoint(TRUNCATE)")
+
 	// Close the current database
 	if err := db.Close(); err != nil {
 		return fmt.Errorf("failed to close database: %w", err)
 	}
 
+	// Leftover WAL/SHM from the old database would be replayed over the restored file
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if err := os.Remove(db.path + suffix); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove stale %s file: %w", suffix, err)
+		}
+	}
+
+	if err := os.Rename(tmpPath, db.path); err != nil {
+		return fmt.Errorf("failed to replace database: %w", err)
+	}
+
+	return nil
+}
+
+// copyToTemp copies srcPath to a new temporary file in the directory of
+// destPath, so it can later be renamed over destPath
+func copyToTemp(srcPath, destPath string) (string, error) {
 	src, err := os.Open(srcPath)
 	if err != nil {
-		return fmt.Errorf("failed to open backup: %w", err)
+		return "", fmt.Errorf("failed to open backup: %w", err)
 	}
 	defer src.Close()
 
-	dst, err := os.Create(db.path)
+	dst, err := os.CreateTemp(filepath.Dir(destPath), filepath.Base(destPath)+".restore-*")
 	if err != nil {
-		return fmt.Errorf("failed to create destination: %w", err)
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
 	}
-	defer dst.Close()
 
 	if _, err := io.Copy(dst, src); err != nil {
-		return fmt.Errorf("failed to copy: %w", err)
+		dst.Close()
+		os.Remove(dst.Name())
+		return "", fmt.Errorf("failed to copy: %w", err)
+	}
+	if err := dst.Sync(); err != nil {
+		dst.Close()
+		os.Remove(dst.Name())
+		return "", fmt.Errorf("failed to sync: %w", err)
 	}
+	if err := dst.Close(); err != nil {
+		os.Remove(dst.Name())
+		return "", fmt.Errorf("failed to close temporary file: %w", err)
+	}
+	return dst.Name(), nil
+}
 
+// checkDatabaseFile opens the SQLite file at path, runs an integrity check
+// and makes sure it holds a kanban schema
+func checkDatabaseFile(path string) error {
+	candidate, err := Open(path)
+	if err != nil {
+		return err
+	}
+	err = candidate.IntegrityCheck()
+	if err == nil {
+		var tables int
+		candidate.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'issues'").Scan(&tables)
+		if tables == 0 {
+			err = fmt.Errorf("not a kanban database")
+		}
+	}
+	candidate.Close()
+	// Opening switches the file to WAL mode; drop what that leaves behind
+	for _, suffix := range []string{"-wal", "-shm"} {
+		os.Remove(path + suffix)
+	}
+	return err
+}
+
+// IntegrityCheck runs PRAGMA integrity_check and returns an error unless it reports ok
+func (db *DB) IntegrityCheck() error {
+	problems, err := db.integrityProblems()
+	if err != nil {
+		return fmt.Errorf("integrity check failed: %w", err)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("integrity check failed: %s", strings.Join(problems, "; "))
+	}
 	return nil
 }
 
+// Verify runs PRAGMA integrity_check and PRAGMA foreign_key_check and returns
+// the problems they report; none means the database is healthy
+func (db *DB) Verify() ([]string, error) {
+	problems, err := db.integrityProblems()
+	if err != nil {
+		return nil, fmt.Errorf("integrity check failed: %w", err)
+	}
+
+	rows, err := db.Query("PRAGMA foreign_key_check")
+	if err != nil {
+		return nil, fmt.Errorf("foreign key check failed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var table, parent string
+		var rowID sql.NullInt64
+		var fkID int
+		if err := rows.Scan(&table, &rowID, &parent, &fkID); err != nil {
+			return nil, fmt.Errorf("foreign key check failed: %w", err)
+		}
+		problems = append(problems, fmt.Sprintf("%s row %d references a missing %s row", table, rowID.Int64, parent))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("foreign key check failed: %w", err)
+	}
+	return problems, nil
+}
+
+// integrityProblems returns the messages of PRAGMA integrity_check other than ok
+func (db *DB) integrityProblems() ([]string, error) {
+	rows, err := db.Query("PRAGMA integrity_check")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var msg string
+		if err := rows.Scan(&msg); err != nil {
+			return nil, err
+		}
+		if msg != "ok" {
+			problems = append(problems, msg)
+		}
+	}
+	return problems, rows.Err()
+}
+
 // Stats returns database statistics
 type Stats struct {
 	Path          string    `json:"path"`
@@ -198,10 +485,31 @@ type ExportData struct {
 	Repositories  []Repository   `json:"repositories"`
 	Labels        []Label        `json:"labels"`
 	Issues        []Issue        `json:"issues"`
+
+	// History; absent from exports made before these tables were included
+	PullRequests      []PullRequest      `json:"pull_requests,omitempty"`
+	PRIssueLinks      []PRIssueLink      `json:"pr_issue_links,omitempty"`
+	StatusTransitions []StatusTransition `json:"status_transitions,omitempty"`
+	StatusTimestamps  []StatusTimestamp  `json:"status_timestamps,omitempty"`
+	BlockedPeriods    []BlockedPeriod    `json:"blocked_periods,omitempty"`
+	MetricsDaily      []MetricsDaily     `json:"metrics_daily,omitempty"`
+	CFDData           []CFDEntry         `json:"cfd_data,omitempty"`
+	Baselines         []MetricBaseline   `json:"metric_baselines,omitempty"`
 }
 
-// Export exports the database to JSON
+// ExportOptions controls the export encoding
+type ExportOptions struct {
+	Compact bool // no indentation
+	Gzip    bool // gzip-compress the JSON
+}
+
+// Export exports the database to pretty-printed JSON
 func (db *DB) Export(w io.Writer) error {
+	return db.ExportWithOptions(w, ExportOptions{})
+}
+
+// ExportWithOptions exports the database to JSON, optionally compact and gzipped
+func (db *DB) ExportWithOptions(w io.Writer, opts ExportOptions) error {
 	data := ExportData{
 		ExportedAt:    time.Now().UTC(),
 		SchemaVersion: SchemaVersion,
@@ -248,64 +556,422 @@ func (db *DB) Export(w io.Writer) error {
 	}
 
 	// Export issues
-	rows, err = db.Query(`SELECT id, repo_id, number, title, state,
-		gh_created_at, gh_updated_at, gh_closed_at,
-		current_status, current_priority, current_type, current_size, is_blocked, assignee,
-		lead_time_hours, cycle_time_hours, blocked_time_hours FROM issues`)
+	rows, err = db.Query(`SELECT ` + exportIssueColumns + ` FROM issues i`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		i, err := scanExportIssue(rows)
+		if err != nil {
+			return err
+		}
+		data.Issues = append(data.Issues, i)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// Export pull requests and their issue links
+	rows, err = db.Query(`SELECT id, repo_id, number, title, state, is_draft,
+		gh_created_at, gh_updated_at, gh_merged_at, gh_closed_at,
+		author, additions, deletions, changed_files,
+		review_time_hours, merge_time_hours
+		FROM pull_requests ORDER BY id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	data.PullRequests = scanPRs(rows)
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = db.Query("SELECT pr_id, issue_id, created_at FROM pr_issue_links")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var l PRIssueLink
+		if err := rows.Scan(&l.PRID, &l.IssueID, &l.CreatedAt); err != nil {
+			return err
+		}
+		data.PRIssueLinks = append(data.PRIssueLinks, l)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// Export status transitions
+	rows, err = db.Query(`SELECT id, issue_id, from_status, to_status, transitioned_at, created_at
+		FROM status_transitions ORDER BY id`)
 	if err != nil {
 		return err
 	}
 	defer rows.Close()
 	for rows.Next() {
-		var i Issue
-		var closedAt sql.NullTime
-		var status, priority, itype, size, assignee sql.NullString
-		var leadTime, cycleTime, blockedTime sql.NullFloat64
-		rows.Scan(&i.ID, &i.RepoID, &i.Number, &i.Title, &i.State,
-			&i.GHCreatedAt, &i.GHUpdatedAt, &closedAt,
-			&status, &priority, &itype, &size, &i.IsBlocked, &assignee,
-			&leadTime, &cycleTime, &blockedTime)
-		if closedAt.Valid {
-			i.GHClosedAt = &closedAt.Time
+		var st StatusTransition
+		var from sql.NullString
+		if err := rows.Scan(&st.ID, &st.IssueID, &from, &st.ToStatus, &st.TransitionedAt, &st.CreatedAt); err != nil {
+			return err
 		}
-		if status.Valid {
-			i.CurrentStatus = status.String
+		st.FromStatus = from.String
+		data.StatusTransitions = append(data.StatusTransitions, st)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// Export status entry times
+	rows, err = db.Query(`SELECT issue_id, status, entered_at FROM status_timestamps ORDER BY issue_id, status`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var ts StatusTimestamp
+		if err := rows.Scan(&ts.IssueID, &ts.Status, &ts.EnteredAt); err != nil {
+			return err
 		}
-		if priority.Valid {
-			i.CurrentPriority = priority.String
+		data.StatusTimestamps = append(data.StatusTimestamps, ts)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// Export blocked periods
+	rows, err = db.Query(`SELECT id, issue_id, blocked_at, unblocked_at, duration_hours, reason, manual, created_at
+		FROM blocked_periods ORDER BY id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var bp BlockedPeriod
+		var unblockedAt sql.NullTime
+		var duration sql.NullFloat64
+		var reason sql.NullString
+		var manual sql.NullBool
+		if err := rows.Scan(&bp.ID, &bp.IssueID, &bp.BlockedAt, &unblockedAt, &duration, &reason, &manual, &bp.CreatedAt); err != nil {
+			return err
 		}
-		if itype.Valid {
-			i.CurrentType = itype.String
+		bp.Manual = manual.Bool
+		if unblockedAt.Valid {
+			bp.UnblockedAt = &unblockedAt.Time
 		}
-		if size.Valid {
-			i.CurrentSize = size.String
+		bp.DurationHours = duration.Float64
+		bp.Reason = reason.String
+		data.BlockedPeriods = append(data.BlockedPeriods, bp)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// Export metrics snapshots
+	rows, err = db.Query(`SELECT id, repo_id, snapshot_date,
+		wip_backlog, wip_ready, wip_in_progress, wip_review, wip_testing, wip_done, wip_total,
+		COALESCE(throughput_30d, 0), COALESCE(lead_time_avg_30d, 0), COALESCE(lead_time_p85_30d, 0),
+		COALESCE(cycle_time_avg_30d, 0), COALESCE(cycle_time_p85_30d, 0),
+		COALESCE(arrival_rate, 0), COALESCE(departure_rate, 0),
+		COALESCE(littles_law_wip, 0), COALESCE(littles_law_variance, 0), COALESCE(flow_efficiency, 0),
+		created_at
+		FROM metrics_daily ORDER BY id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var m MetricsDaily
+		if err := rows.Scan(&m.ID, &m.RepoID, &m.SnapshotDate,
+			&m.WIPBacklog, &m.WIPReady, &m.WIPInProgress, &m.WIPReview, &m.WIPTesting, &m.WIPDone, &m.WIPTotal,
+			&m.Throughput30d, &m.LeadTimeAvg30d, &m.LeadTimeP8530d, &m.CycleTimeAvg30d, &m.CycleTimeP8530d,
+			&m.ArrivalRate, &m.DepartureRate, &m.LittlesLawWIP, &m.LittlesLawVariance, &m.FlowEfficiency,
+			&m.CreatedAt); err != nil {
+			return err
 		}
-		if assignee.Valid {
-			i.Assignee = assignee.String
+		data.MetricsDaily = append(data.MetricsDaily, m)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// Export CFD snapshots
+	rows, err = db.Query(`SELECT repo_id, snapshot_date, status, cumulative_count
+		FROM cfd_data ORDER BY repo_id, snapshot_date, status`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var c CFDEntry
+		if err := rows.Scan(&c.RepoID, &c.SnapshotDate, &c.Status, &c.CumulativeCount); err != nil {
+			return err
 		}
-		if leadTime.Valid {
-			i.LeadTimeHours = leadTime.Float64
+		if len(c.SnapshotDate) > len("2006-01-02") {
+			c.SnapshotDate = c.SnapshotDate[:len("2006-01-02")]
 		}
-		if cycleTime.Valid {
-			i.CycleTimeHours = cycleTime.Float64
+		data.CFDData = append(data.CFDData, c)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// Export metric baselines
+	rows, err = db.Query("SELECT name, repo, metrics_json, created_at FROM metric_baselines ORDER BY name, repo")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var b MetricBaseline
+		var metrics string
+		if err := rows.Scan(&b.Name, &b.Repo, &metrics, &b.CreatedAt); err != nil {
+			return err
 		}
-		if blockedTime.Valid {
-			i.BlockedTimeHours = blockedTime.Float64
+		b.Metrics = json.RawMessage(metrics)
+		data.Baselines = append(data.Baselines, b)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if opts.Gzip {
+		gz := gzip.NewWriter(w)
+		if err := encodeExport(gz, data, opts.Compact); err != nil {
+			gz.Close()
+			return err
 		}
-		data.Issues = append(data.Issues, i)
+		return gz.Close()
 	}
+	return encodeExport(w, data, opts.Compact)
+}
 
+// exportIssueColumns are the issue columns written by exports, read from
+// issues aliased as i
+const exportIssueColumns = `i.id, i.repo_id, i.number, i.title, i.state,
+		i.gh_created_at, i.gh_updated_at, i.gh_closed_at,
+		i.current_status, i.current_priority, i.current_type, i.current_size, i.is_blocked, i.assignee, i.milestone, i.author, i.state_reason,
+		i.lead_time_hours, i.cycle_time_hours, i.blocked_time_hours, i.size_points`
+
+// scanExportIssue scans an issue selected with exportIssueColumns, plus any
+// trailing destinations
+func scanExportIssue(rows *sql.Rows, extra ...any) (Issue, error) {
+	var i Issue
+	var closedAt sql.NullTime
+	var status, priority, itype, size, assignee, milestone, author, stateReason sql.NullString
+	var leadTime, cycleTime, blockedTime, sizePoints sql.NullFloat64
+	dest := []any{&i.ID, &i.RepoID, &i.Number, &i.Title, &i.State,
+		&i.GHCreatedAt, &i.GHUpdatedAt, &closedAt,
+		&status, &priority, &itype, &size, &i.IsBlocked, &assignee, &milestone, &author, &stateReason,
+		&leadTime, &cycleTime, &blockedTime, &sizePoints}
+	if err := rows.Scan(append(dest, extra...)...); err != nil {
+		return i, err
+	}
+
+	if closedAt.Valid {
+		i.GHClosedAt = &closedAt.Time
+	}
+	i.CurrentStatus = status.String
+	i.CurrentPriority = priority.String
+	i.CurrentType = itype.String
+	i.CurrentSize = size.String
+	i.Assignee = assignee.String
+	i.Milestone = milestone.String
+	i.Author = author.String
+	i.StateReason = stateReason.String
+	i.LeadTimeHours = leadTime.Float64
+	i.CycleTimeHours = cycleTime.Float64
+	i.BlockedTimeHours = blockedTime.Float64
+	if sizePoints.Valid {
+		i.SizePoints = &sizePoints.Float64
+	}
+	return i, nil
+}
+
+// StreamIssue is one issue record of a streaming export
+type StreamIssue struct {
+	Issue
+	RepoFullName string `json:"repo_full_name"`
+}
+
+// ExportStream writes issues as they are read, without holding the whole
+// export in memory. Format "ndjson" writes one JSON object per line; "json"
+// writes a single JSON array.
+func (db *DB) ExportStream(w io.Writer, format string) error {
+	if format != "ndjson" && format != "json" {
+		return fmt.Errorf("unknown stream format %q (use ndjson or json)", format)
+	}
+
+	rows, err := db.Query(`SELECT ` + exportIssueColumns + `, r.full_name
+		FROM issues i JOIN repositories r ON r.id = i.repo_id
+		ORDER BY r.full_name, i.number`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	bw := bufio.NewWriter(w)
+	sep, end := "", ""
+	if format == "json" {
+		sep, end = "[\n", "\n]\n"
+	}
+	n := 0
+	for rows.Next() {
+		var rec StreamIssue
+		rec.Issue, err = scanExportIssue(rows, &rec.RepoFullName)
+		if err != nil {
+			return err
+		}
+		line, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+
+		bw.WriteString(sep)
+		bw.Write(line)
+		if format == "ndjson" {
+			bw.WriteByte('\n')
+		} else {
+			sep = ",\n"
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if format == "json" {
+		if n == 0 {
+			end = "[]\n"
+		}
+		bw.WriteString(end)
+	}
+	return bw.Flush()
+}
+
+// IssueFilter selects the issues StreamIssues reads. Empty fields match
+// every issue.
+type IssueFilter struct {
+	Org   string    // organization name
+	Repo  string    // repository full name
+	State string    // open or closed
+	Since time.Time // updated at or after
+}
+
+// StreamIssues calls fn with each cached issue matching filter, with its
+// repo and status entry times, as rows are read, by repo and number. An
+// error from fn stops the scan and is returned.
+func (db *DB) StreamIssues(filter IssueFilter, fn func(StreamIssue) error) error {
+	query := `SELECT ` + exportIssueColumns + `, r.full_name,
+		i.entered_ready_at, i.entered_progress_at, i.entered_review_at, i.entered_testing_at, i.entered_done_at
+		FROM issues i
+		JOIN repositories r ON r.id = i.repo_id
+		JOIN organizations o ON o.id = r.org_id
+		WHERE 1=1`
+	args := []any{}
+
+	if filter.Org != "" {
+		query += " AND o.name = ?"
+		args = append(args, filter.Org)
+	}
+	if filter.Repo != "" {
+		query += " AND r.full_name = ?"
+		args = append(args, filter.Repo)
+	}
+	if filter.State != "" {
+		query += " AND i.state = ?"
+		args = append(args, filter.State)
+	}
+	if !filter.Since.IsZero() {
+		query += " AND i.gh_updated_at >= ?"
+		args = append(args, sqlTime(filter.Since))
+	}
+	query += " ORDER BY r.full_name, i.number"
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rec StreamIssue
+		var ready, progress, review, testing, done sql.NullTime
+		rec.Issue, err = scanExportIssue(rows, &rec.RepoFullName, &ready, &progress, &review, &testing, &done)
+		if err != nil {
+			return err
+		}
+		for _, t := range []struct {
+			src *sql.NullTime
+			dst **time.Time
+		}{
+			{&ready, &rec.EnteredReadyAt},
+			{&progress, &rec.EnteredProgressAt},
+			{&review, &rec.EnteredReviewAt},
+			{&testing, &rec.EnteredTestingAt},
+			{&done, &rec.EnteredDoneAt},
+		} {
+			if t.src.Valid {
+				at := t.src.Time
+				*t.dst = &at
+			}
+		}
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func encodeExport(w io.Writer, data ExportData, compact bool) error {
 	encoder := json.NewEncoder(w)
-	encoder.SetIndent("", "  ")
+	if !compact {
+		encoder.SetIndent("", "  ")
+	}
 	return encoder.Encode(data)
 }
 
-// Import imports data from JSON
+// gzipMagic is the two-byte header of gzip streams
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// importData is ExportData as Import decodes it. Issue titles go through a
+// pointer so a null or missing title can be told from an empty one.
+type importData struct {
+	ExportData
+	Issues []importedIssue `json:"issues"`
+}
+
+type importedIssue struct {
+	Issue
+	Title *string `json:"title"`
+}
+
+// Import imports data from JSON; gzipped input is detected automatically
 func (db *DB) Import(r io.Reader) error {
-	var data ExportData
-	if err := json.NewDecoder(r).Decode(&data); err != nil {
+	br := bufio.NewReader(r)
+	var src io.Reader = br
+	if magic, err := br.Peek(len(gzipMagic)); err == nil && bytes.Equal(magic, gzipMagic) {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return fmt.Errorf("failed to open gzip data: %w", err)
+		}
+		defer gz.Close()
+		src = gz
+	}
+
+	var in importData
+	if err := json.NewDecoder(src).Decode(&in); err != nil {
 		return fmt.Errorf("failed to decode JSON: %w", err)
 	}
+	data := in.ExportData
+	data.Issues = make([]Issue, len(in.Issues))
+	for i, issue := range in.Issues {
+		if issue.Title == nil {
+			return fmt.Errorf("invalid import: issue #%d (repo_id %d) has no title", issue.Number, issue.RepoID)
+		}
+		issue.Issue.Title = *issue.Title
+		data.Issues[i] = issue.Issue
+	}
 
 	tx, err := db.Begin()
 	if err != nil {
@@ -316,7 +982,7 @@ func (db *DB) Import(r io.Reader) error {
 	// Import organizations
 	for _, o := range data.Organizations {
 		_, err := tx.Exec(`INSERT OR REPLACE INTO organizations (id, name, created_at) VALUES (?, ?, ?)`,
-			o.ID, o.Name, o.CreatedAt)
+			o.ID, o.Name, sqlTime(o.CreatedAt))
 		if err != nil {
 			return fmt.Errorf("failed to import organization: %w", err)
 		}
@@ -326,7 +992,7 @@ func (db *DB) Import(r io.Reader) error {
 	for _, r := range data.Repositories {
 		_, err := tx.Exec(`INSERT OR REPLACE INTO repositories
 			(id, org_id, name, full_name, is_active, last_sync_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
-			r.ID, r.OrgID, r.Name, r.FullName, r.IsActive, r.LastSyncAt, r.CreatedAt)
+			r.ID, r.OrgID, r.Name, r.FullName, r.IsActive, nullTime(r.LastSyncAt), sqlTime(r.CreatedAt))
 		if err != nil {
 			return fmt.Errorf("failed to import repository: %w", err)
 		}
@@ -346,17 +1012,102 @@ func (db *DB) Import(r io.Reader) error {
 	for _, i := range data.Issues {
 		_, err := tx.Exec(`INSERT OR REPLACE INTO issues
 			(id, repo_id, number, title, state, gh_created_at, gh_updated_at, gh_closed_at,
-			current_status, current_priority, current_type, current_size, is_blocked, assignee,
-			lead_time_hours, cycle_time_hours, blocked_time_hours)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			current_status, current_priority, current_type, current_size, is_blocked, assignee, milestone, author, state_reason,
+			lead_time_hours, cycle_time_hours, blocked_time_hours, size_points)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 			i.ID, i.RepoID, i.Number, i.Title, i.State,
-			i.GHCreatedAt, i.GHUpdatedAt, i.GHClosedAt,
-			i.CurrentStatus, i.CurrentPriority, i.CurrentType, i.CurrentSize, i.IsBlocked, i.Assignee,
-			i.LeadTimeHours, i.CycleTimeHours, i.BlockedTimeHours)
+			sqlTime(i.GHCreatedAt), sqlTime(i.GHUpdatedAt), nullTime(i.GHClosedAt),
+			i.CurrentStatus, i.CurrentPriority, i.CurrentType, i.CurrentSize, i.IsBlocked, i.Assignee, nullString(i.Milestone), nullString(i.Author), nullString(i.StateReason),
+			i.LeadTimeHours, i.CycleTimeHours, i.BlockedTimeHours, i.SizePoints)
 		if err != nil {
 			return fmt.Errorf("failed to import issue: %w", err)
 		}
 	}
 
+	// Import pull requests, then the links that reference them and issues
+	for _, pr := range data.PullRequests {
+		_, err := tx.Exec(`INSERT OR REPLACE INTO pull_requests
+			(id, repo_id, number, title, state, is_draft, gh_created_at, gh_updated_at, gh_merged_at, gh_closed_at,
+			author, additions, deletions, changed_files, review_time_hours, merge_time_hours)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			pr.ID, pr.RepoID, pr.Number, pr.Title, pr.State, pr.IsDraft,
+			sqlTime(pr.GHCreatedAt), sqlTime(pr.GHUpdatedAt), nullTime(pr.GHMergedAt), nullTime(pr.GHClosedAt),
+			nullString(pr.Author), pr.Additions, pr.Deletions, pr.ChangedFiles,
+			pr.ReviewTimeHours, pr.MergeTimeHours)
+		if err != nil {
+			return fmt.Errorf("failed to import pull request: %w", err)
+		}
+	}
+	for _, l := range data.PRIssueLinks {
+		_, err := tx.Exec(`INSERT OR REPLACE INTO pr_issue_links (pr_id, issue_id, created_at) VALUES (?, ?, ?)`,
+			l.PRID, l.IssueID, sqlTime(l.CreatedAt))
+		if err != nil {
+			return fmt.Errorf("failed to import PR link: %w", err)
+		}
+	}
+
+	// Import status transitions
+	for _, st := range data.StatusTransitions {
+		_, err := tx.Exec(`INSERT OR REPLACE INTO status_transitions
+			(id, issue_id, from_status, to_status, transitioned_at, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+			st.ID, st.IssueID, nullString(st.FromStatus), st.ToStatus, sqlTime(st.TransitionedAt), sqlTime(st.CreatedAt))
+		if err != nil {
+			return fmt.Errorf("failed to import status transition: %w", err)
+		}
+	}
+
+	// Import status entry times
+	for _, ts := range data.StatusTimestamps {
+		_, err := tx.Exec(`INSERT OR REPLACE INTO status_timestamps (issue_id, status, entered_at) VALUES (?, ?, ?)`,
+			ts.IssueID, ts.Status, sqlTime(ts.EnteredAt))
+		if err != nil {
+			return fmt.Errorf("failed to import status timestamp: %w", err)
+		}
+	}
+
+	// Import blocked periods
+	for _, bp := range data.BlockedPeriods {
+		_, err := tx.Exec(`INSERT OR REPLACE INTO blocked_periods
+			(id, issue_id, blocked_at, unblocked_at, duration_hours, reason, manual, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			bp.ID, bp.IssueID, sqlTime(bp.BlockedAt), nullTime(bp.UnblockedAt), bp.DurationHours, nullString(bp.Reason), bp.Manual, sqlTime(bp.CreatedAt))
+		if err != nil {
+			return fmt.Errorf("failed to import blocked period: %w", err)
+		}
+	}
+
+	// Import metrics snapshots
+	for _, m := range data.MetricsDaily {
+		_, err := tx.Exec(`INSERT OR REPLACE INTO metrics_daily
+			(id, repo_id, snapshot_date, wip_backlog, wip_ready, wip_in_progress, wip_review, wip_testing, wip_done, wip_total,
+			throughput_30d, lead_time_avg_30d, lead_time_p85_30d, cycle_time_avg_30d, cycle_time_p85_30d,
+			arrival_rate, departure_rate, littles_law_wip, littles_law_variance, flow_efficiency, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			m.ID, m.RepoID, m.SnapshotDate.Format("2006-01-02"),
+			m.WIPBacklog, m.WIPReady, m.WIPInProgress, m.WIPReview, m.WIPTesting, m.WIPDone, m.WIPTotal,
+			m.Throughput30d, m.LeadTimeAvg30d, m.LeadTimeP8530d, m.CycleTimeAvg30d, m.CycleTimeP8530d,
+			m.ArrivalRate, m.DepartureRate, m.LittlesLawWIP, m.LittlesLawVariance, m.FlowEfficiency, sqlTime(m.CreatedAt))
+		if err != nil {
+			return fmt.Errorf("failed to import metrics snapshot: %w", err)
+		}
+	}
+
+	// Import CFD snapshots
+	for _, c := range data.CFDData {
+		_, err := tx.Exec(`INSERT OR REPLACE INTO cfd_data (repo_id, snapshot_date, status, cumulative_count)
+			VALUES (?, ?, ?, ?)`, c.RepoID, c.SnapshotDate, c.Status, c.CumulativeCount)
+		if err != nil {
+			return fmt.Errorf("failed to import CFD snapshot: %w", err)
+		}
+	}
+
+	// Import metric baselines
+	for _, b := range data.Baselines {
+		_, err := tx.Exec(`INSERT OR REPLACE INTO metric_baselines (name, repo, metrics_json, created_at)
+			VALUES (?, ?, ?, ?)`, b.Name, b.Repo, string(b.Metrics), sqlTime(b.CreatedAt))
+		if err != nil {
+			return fmt.Errorf("failed to import baseline: %w", err)
+		}
+	}
+
 	return tx.Commit()
 }
@@ -1,9 +1,16 @@
 package db
 
 import (
+	"bytes"
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"math"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 )
@@ -56,6 +63,48 @@ func TestOpen_DefaultPath(t *testing.T) {
 	}
 }
 
+func TestOpenReadOnly(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+	now := time.Now()
+	db.UpsertIssue(&Issue{RepoID: repo.ID, Number: 1, Title: "Issue", State: "open", CurrentStatus: "ready", GHCreatedAt: now, GHUpdatedAt: now})
+
+	ro, err := OpenReadOnly(db.Path())
+	if err != nil {
+		t.Fatalf("OpenReadOnly() error: %v", err)
+	}
+	defer ro.Close()
+
+	// Reads see the writer's data, from several connections at once
+	counts := make(chan int, 8)
+	for i := 0; i < cap(counts); i++ {
+		go func() {
+			statuses, err := ro.GetStatusCounts(repo.ID)
+			if err != nil {
+				counts <- -1
+				return
+			}
+			counts <- statuses["ready"]
+		}()
+	}
+	for i := 0; i < cap(counts); i++ {
+		if got := <-counts; got != 1 {
+			t.Errorf("read-only GetStatusCounts()[ready] = %d, want 1 (-1 = error)", got)
+		}
+	}
+
+	if _, err := ro.Exec("DELETE FROM issues"); err == nil {
+		t.Error("write through a read-only handle should fail")
+	}
+
+	if _, err := OpenReadOnly(filepath.Join(t.TempDir(), "missing.db")); err == nil {
+		t.Error("OpenReadOnly() of a missing database should fail")
+	}
+}
+
 func TestInit(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -176,6 +225,343 @@ func TestUpsertIssue(t *testing.T) {
 	}
 }
 
+func TestUpsertIssueBatch_Transitions(t *testing.T) {
+	updatedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	rounds := [][]string{
+		{"backlog", "", "ready"},          // statuses of #1, #2, #3 on first sync
+		{"in-progress", "ready", "ready"}, // #1 and #2 move, #3 stays
+		{"review", "ready", ""},           // #1 moves on, #3 loses its status
+	}
+
+	// history syncs the rounds with upsert and returns each issue's
+	// transitions, entered statuses and entered_progress_at
+	history := func(t *testing.T, upsert func(*DB, []*Issue) error) (map[int][]string, map[int][]string, map[int]bool) {
+		db, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		org, _ := db.GetOrCreateOrg("testorg")
+		repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+		for _, statuses := range rounds {
+			var issues []*Issue
+			for i, status := range statuses {
+				issues = append(issues, &Issue{RepoID: repo.ID, Number: i + 1, Title: "Issue", State: "open",
+					CurrentStatus: status, GHCreatedAt: updatedAt, GHUpdatedAt: updatedAt})
+			}
+			if err := upsert(db, issues); err != nil {
+				t.Fatalf("upsert error: %v", err)
+			}
+		}
+
+		transitions := make(map[int][]string)
+		entered := make(map[int][]string)
+		inProgress := make(map[int]bool)
+		for number := 1; number <= len(rounds[0]); number++ {
+			id, _ := db.GetIssueIDByNumber(repo.ID, number)
+			rows, _ := db.GetStatusTransitions(id)
+			for _, tr := range rows {
+				transitions[number] = append(transitions[number], tr.FromStatus+"->"+tr.ToStatus)
+			}
+			stamps, _ := db.GetStatusTimestamps(id)
+			for status := range stamps {
+				entered[number] = append(entered[number], status)
+			}
+			sort.Strings(entered[number])
+			var progressAt sql.NullString
+			db.QueryRow("SELECT entered_progress_at FROM issues WHERE id = ?", id).Scan(&progressAt)
+			inProgress[number] = progressAt.Valid
+		}
+		return transitions, entered, inProgress
+	}
+
+	seqTransitions, seqEntered, seqProgress := history(t, func(db *DB, issues []*Issue) error {
+		for _, issue := range issues {
+			if err := db.UpsertIssue(issue); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	batchTransitions, batchEntered, batchProgress := history(t, func(db *DB, issues []*Issue) error {
+		return db.UpsertIssueBatch(issues)
+	})
+
+	if !reflect.DeepEqual(batchTransitions, seqTransitions) {
+		t.Errorf("batch transitions = %v, sequential = %v", batchTransitions, seqTransitions)
+	}
+	if !reflect.DeepEqual(batchEntered, seqEntered) {
+		t.Errorf("batch status timestamps = %v, sequential = %v", batchEntered, seqEntered)
+	}
+	if !reflect.DeepEqual(batchProgress, seqProgress) {
+		t.Errorf("batch entered_progress_at set = %v, sequential = %v", batchProgress, seqProgress)
+	}
+	if want := []string{"->backlog", "backlog->in-progress", "in-progress->review"}; !reflect.DeepEqual(batchTransitions[1], want) {
+		t.Errorf("#1 transitions = %v, want %v", batchTransitions[1], want)
+	}
+}
+
+func TestUpsertIssueBatch_Fields(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	now := time.Now()
+	points := 3.0
+	issue := &Issue{RepoID: repo.ID, Number: 1, Title: "Issue", State: "open", Milestone: "v1",
+		Author: "alice", SizePoints: &points, GHCreatedAt: now, GHUpdatedAt: now}
+
+	// Insert, then update through the same batch path
+	for _, milestone := range []string{"v1", "v2"} {
+		issue.Milestone = milestone
+		if err := db.UpsertIssueBatch([]*Issue{issue}); err != nil {
+			t.Fatalf("UpsertIssueBatch() error: %v", err)
+		}
+
+		var gotMilestone, gotAuthor string
+		var gotPoints float64
+		db.QueryRow("SELECT milestone, author, size_points FROM issues WHERE id = ?", issue.ID).
+			Scan(&gotMilestone, &gotAuthor, &gotPoints)
+		if gotMilestone != milestone || gotAuthor != "alice" || gotPoints != 3 {
+			t.Errorf("stored milestone, author, size_points = %q, %q, %v, want %q, alice, 3",
+				gotMilestone, gotAuthor, gotPoints, milestone)
+		}
+	}
+}
+
+// prefixCategory stands in for config.LabelCategory: the part of a label
+// name before a colon, or "special"
+func prefixCategory(name string) string {
+	if category, _, ok := strings.Cut(strings.ToLower(name), ":"); ok {
+		return category
+	}
+	return "special"
+}
+
+func TestReplaceIssueLabels(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	now := time.Now()
+	one := &Issue{RepoID: repo.ID, Number: 1, Title: "One", State: "open", GHCreatedAt: now, GHUpdatedAt: now}
+	two := &Issue{RepoID: repo.ID, Number: 2, Title: "Two", State: "closed", GHCreatedAt: now, GHUpdatedAt: now}
+	if err := db.UpsertIssueBatch([]*Issue{one, two}); err != nil {
+		t.Fatalf("UpsertIssueBatch() error: %v", err)
+	}
+	db.UpsertLabel(&Label{RepoID: repo.ID, Name: "bug", Color: "d73a4a"})
+
+	err := db.ReplaceIssueLabels(repo.ID, map[int64][]string{
+		one.ID: {"bug", "status: ready"},
+		two.ID: {"bug"},
+	}, prefixCategory)
+	if err != nil {
+		t.Fatalf("ReplaceIssueLabels() error: %v", err)
+	}
+	usage, err := db.GetLabelUsage(repo.ID)
+	if err != nil {
+		t.Fatalf("GetLabelUsage() error: %v", err)
+	}
+	if want := map[string]int{"bug": 2, "status: ready": 1}; !reflect.DeepEqual(usage, want) {
+		t.Errorf("GetLabelUsage() = %v, want %v", usage, want)
+	}
+
+	// A relabeled issue drops its old labels; issues left out keep theirs
+	if err := db.ReplaceIssueLabels(repo.ID, map[int64][]string{one.ID: {"status: review"}}, prefixCategory); err != nil {
+		t.Fatalf("ReplaceIssueLabels() error: %v", err)
+	}
+	usage, _ = db.GetLabelUsage(repo.ID)
+	if want := map[string]int{"bug": 1, "status: review": 1}; !reflect.DeepEqual(usage, want) {
+		t.Errorf("GetLabelUsage() after relabel = %v, want %v", usage, want)
+	}
+
+	// The existing label keeps its color; new ones are added without one
+	labels, _ := db.GetLabelsByRepo(repo.ID)
+	colors := make(map[string]string)
+	for _, l := range labels {
+		colors[l.Name] = l.Color
+	}
+	if colors["bug"] != "d73a4a" || colors["status: review"] != "" {
+		t.Errorf("label colors = %v, want bug kept and new labels empty", colors)
+	}
+}
+
+func TestDeleteLabel(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	now := time.Now()
+	issue := &Issue{RepoID: repo.ID, Number: 1, Title: "One", State: "open", GHCreatedAt: now, GHUpdatedAt: now}
+	if err := db.UpsertIssue(issue); err != nil {
+		t.Fatalf("UpsertIssue() error: %v", err)
+	}
+	if err := db.ReplaceIssueLabels(repo.ID, map[int64][]string{issue.ID: {"wontfix", "bug"}}, prefixCategory); err != nil {
+		t.Fatalf("ReplaceIssueLabels() error: %v", err)
+	}
+
+	// Names match in any case, as on GitHub
+	if err := db.DeleteLabel(repo.ID, "WontFix"); err != nil {
+		t.Fatalf("DeleteLabel() error: %v", err)
+	}
+	usage, _ := db.GetLabelUsage(repo.ID)
+	if want := map[string]int{"bug": 1}; !reflect.DeepEqual(usage, want) {
+		t.Errorf("GetLabelUsage() = %v, want %v", usage, want)
+	}
+	labels, _ := db.GetLabelsByRepo(repo.ID)
+	if len(labels) != 1 || labels[0].Name != "bug" {
+		t.Errorf("GetLabelsByRepo() = %v, want only bug", labels)
+	}
+}
+
+func TestRenameLabel(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	now := time.Now()
+	one := &Issue{RepoID: repo.ID, Number: 1, Title: "One", State: "open", GHCreatedAt: now, GHUpdatedAt: now}
+	two := &Issue{RepoID: repo.ID, Number: 2, Title: "Two", State: "open", GHCreatedAt: now, GHUpdatedAt: now}
+	if err := db.UpsertIssueBatch([]*Issue{one, two}); err != nil {
+		t.Fatalf("UpsertIssueBatch() error: %v", err)
+	}
+	err := db.ReplaceIssueLabels(repo.ID, map[int64][]string{
+		one.ID: {"Bug", "enhancement"},
+		two.ID: {"enhancement", "type: feature"},
+	}, prefixCategory)
+	if err != nil {
+		t.Fatalf("ReplaceIssueLabels() error: %v", err)
+	}
+
+	// A plain rename keeps the issues; a label already cached under the new
+	// name takes them over
+	if err := db.RenameLabel(repo.ID, "bug", "type: bug", "type"); err != nil {
+		t.Fatalf("RenameLabel() error: %v", err)
+	}
+	if err := db.RenameLabel(repo.ID, "enhancement", "type: feature", "type"); err != nil {
+		t.Fatalf("RenameLabel() error: %v", err)
+	}
+	usage, _ := db.GetLabelUsage(repo.ID)
+	if want := map[string]int{"type: bug": 1, "type: feature": 2}; !reflect.DeepEqual(usage, want) {
+		t.Errorf("GetLabelUsage() = %v, want %v", usage, want)
+	}
+
+	labels, _ := db.GetLabelsByRepo(repo.ID)
+	for _, l := range labels {
+		if l.Name == "type: bug" && l.Category != "type" {
+			t.Errorf("renamed label category = %q, want type", l.Category)
+		}
+	}
+
+	if err := db.RenameLabel(repo.ID, "missing", "other", "special"); err != nil {
+		t.Errorf("RenameLabel() of an uncached label error: %v", err)
+	}
+}
+
+func TestGetIssuesByLabel(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+	other, _ := db.GetOrCreateRepo(org.ID, "other", "testorg/other")
+
+	now := time.Now()
+	var issues []*Issue
+	for n := 1; n <= 3; n++ {
+		issues = append(issues, &Issue{RepoID: repo.ID, Number: n, Title: "Issue", State: "open", GHCreatedAt: now, GHUpdatedAt: now})
+	}
+	elsewhere := &Issue{RepoID: other.ID, Number: 1, Title: "Other", State: "open", GHCreatedAt: now, GHUpdatedAt: now}
+	db.UpsertIssueBatch(issues)
+	db.UpsertIssueBatch([]*Issue{elsewhere})
+
+	db.ReplaceIssueLabels(repo.ID, map[int64][]string{
+		issues[0].ID: {"Bug"},
+		issues[1].ID: {"feature"},
+		issues[2].ID: {"Bug", "feature"},
+	}, prefixCategory)
+	db.ReplaceIssueLabels(other.ID, map[int64][]string{elsewhere.ID: {"Bug"}}, prefixCategory)
+
+	got, err := db.GetIssuesByLabel(repo.ID, "bug")
+	if err != nil {
+		t.Fatalf("GetIssuesByLabel() error: %v", err)
+	}
+	var numbers []int
+	for _, i := range got {
+		numbers = append(numbers, i.Number)
+	}
+	if want := []int{1, 3}; !reflect.DeepEqual(numbers, want) {
+		t.Errorf("GetIssuesByLabel(bug) numbers = %v, want %v", numbers, want)
+	}
+
+	if got, _ := db.GetIssuesByLabel(repo.ID, "missing"); len(got) != 0 {
+		t.Errorf("GetIssuesByLabel(missing) = %d issues, want 0", len(got))
+	}
+}
+
+func TestSearchIssues(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	now := time.Now()
+	closed := now.Add(-time.Hour)
+	issues := []*Issue{
+		{RepoID: repo.ID, Number: 1, Title: "Fix auth timeout", State: "open", CurrentStatus: "in-progress",
+			CurrentType: "bug", Assignee: "alice", GHCreatedAt: now, GHUpdatedAt: now.Add(-2 * time.Hour)},
+		{RepoID: repo.ID, Number: 2, Title: "Auth docs", State: "open", CurrentStatus: "ready",
+			Assignee: "bob", IsBlocked: true, GHCreatedAt: now, GHUpdatedAt: now.Add(-time.Hour)},
+		{RepoID: repo.ID, Number: 3, Title: "Old auth bug", State: "closed", CurrentType: "bug",
+			GHCreatedAt: now, GHUpdatedAt: now, GHClosedAt: &closed},
+		{RepoID: repo.ID, Number: 4, Title: "100% coverage", State: "open", GHCreatedAt: now, GHUpdatedAt: now},
+	}
+	db.UpsertIssueBatch(issues)
+	db.ReplaceIssueLabels(repo.ID, map[int64][]string{
+		issues[0].ID: {"type: bug", "status: in-progress"},
+		issues[2].ID: {"type: bug"},
+	}, prefixCategory)
+
+	tests := []struct {
+		name   string
+		search IssueSearch
+		want   []int
+	}{
+		{"text, newest first", IssueSearch{Text: "AUTH"}, []int{3, 2, 1}},
+		{"open only", IssueSearch{Text: "auth", State: "open"}, []int{2, 1}},
+		{"label ignores spaces and case", IssueSearch{Labels: []string{"Type:Bug"}}, []int{3, 1}},
+		{"all labels must match", IssueSearch{Labels: []string{"type:bug", "status:in-progress"}}, []int{1}},
+		{"fields", IssueSearch{Status: "in-progress", Assignee: "ALICE"}, []int{1}},
+		{"blocked", IssueSearch{Blocked: true}, []int{2}},
+		{"like wildcards are literal", IssueSearch{Text: "100%"}, []int{4}},
+		{"underscore is literal", IssueSearch{Text: "_"}, nil},
+		{"limit", IssueSearch{Text: "auth", Limit: 1}, []int{3}},
+		{"other org", IssueSearch{Org: "elsewhere"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := db.SearchIssues(tt.search)
+			if err != nil {
+				t.Fatalf("SearchIssues() error: %v", err)
+			}
+			var numbers []int
+			for _, i := range got {
+				numbers = append(numbers, i.Number)
+			}
+			if !reflect.DeepEqual(numbers, tt.want) {
+				t.Errorf("SearchIssues() numbers = %v, want %v", numbers, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetBoardIssues(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -187,9 +573,9 @@ func TestGetBoardIssues(t *testing.T) {
 
 	// Create test issues
 	issues := []*Issue{
-		{RepoID: repo.ID, Number: 1, Title: "Issue 1", State: "open", CurrentStatus: "backlog", GHCreatedAt: now, GHUpdatedAt: now},
-		{RepoID: repo.ID, Number: 2, Title: "Issue 2", State: "open", CurrentStatus: "in-progress", GHCreatedAt: now, GHUpdatedAt: now},
-		{RepoID: repo.ID, Number: 3, Title: "Issue 3", State: "closed", CurrentStatus: "done", GHCreatedAt: now, GHUpdatedAt: now},
+		{RepoID: repo.ID, Number: 1, Title: "Issue 1", State: "open", CurrentStatus: "backlog", CurrentType: "bug", GHCreatedAt: now, GHUpdatedAt: now},
+		{RepoID: repo.ID, Number: 2, Title: "Issue 2", State: "open", CurrentStatus: "in-progress", CurrentType: "feature", GHCreatedAt: now, GHUpdatedAt: now},
+		{RepoID: repo.ID, Number: 3, Title: "Issue 3", State: "closed", CurrentStatus: "done", CurrentType: "docs", GHCreatedAt: now, GHUpdatedAt: now},
 	}
 
 	for _, issue := range issues {
@@ -217,6 +603,20 @@ func TestGetBoardIssues(t *testing.T) {
 	if len(backlogIssues) != 1 {
 		t.Errorf("GetBoardIssues(backlog) returned %d issues, want 1", len(backlogIssues))
 	}
+
+	// Filter by type
+	typed, err := db.GetBoardIssues("", "", "bug", "feature")
+	if err != nil {
+		t.Fatalf("GetBoardIssues(bug, feature) error: %v", err)
+	}
+	if len(typed) != 2 {
+		t.Errorf("GetBoardIssues(bug, feature) returned %d issues, want 2", len(typed))
+	}
+	for _, issue := range typed {
+		if issue.Type != "bug" && issue.Type != "feature" {
+			t.Errorf("GetBoardIssues(bug, feature) returned #%d of type %q", issue.Number, issue.Type)
+		}
+	}
 }
 
 func TestGetWIPSummary(t *testing.T) {
@@ -316,6 +716,183 @@ func TestBackupAndRestore(t *testing.T) {
 	}
 }
 
+func TestBackup_ConcurrentWrites(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+	now := time.Now().UTC()
+	for i := 1; i <= 200; i++ {
+		db.UpsertIssue(&Issue{RepoID: repo.ID, Number: i, Title: "Issue", State: "open", GHCreatedAt: now, GHUpdatedAt: now})
+	}
+
+	// A second handle stands in for another kanban process writing meanwhile
+	writer, err := Open(db.path)
+	if err != nil {
+		t.Fatalf("Open() second handle error: %v", err)
+	}
+	defer writer.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 201; i <= 400; i++ {
+			writer.UpsertIssue(&Issue{RepoID: repo.ID, Number: i, Title: "Concurrent", State: "open", GHCreatedAt: now, GHUpdatedAt: now})
+		}
+	}()
+
+	backupPath := filepath.Join(t.TempDir(), "backup.db")
+	if err := db.Backup(backupPath); err != nil {
+		t.Fatalf("Backup() error: %v", err)
+	}
+	<-done
+
+	// An existing backup is replaced
+	if err := db.Backup(backupPath); err != nil {
+		t.Fatalf("Backup() over existing file error: %v", err)
+	}
+	if _, err := os.Stat(backupPath + ".tmp"); !os.IsNotExist(err) {
+		t.Error("Backup() left its temporary file behind")
+	}
+
+	backup, err := Open(backupPath)
+	if err != nil {
+		t.Fatalf("Open(backup) error: %v", err)
+	}
+	defer backup.Close()
+
+	var integrity string
+	if err := backup.QueryRow("PRAGMA integrity_check").Scan(&integrity); err != nil || integrity != "ok" {
+		t.Fatalf("backup integrity_check = %q, %v; want ok", integrity, err)
+	}
+	var want, got int
+	db.QueryRow("SELECT COUNT(*) FROM issues").Scan(&want)
+	backup.QueryRow("SELECT COUNT(*) FROM issues").Scan(&got)
+	if got != want || got < 200 {
+		t.Errorf("backup has %d issues, want %d (at least 200)", got, want)
+	}
+}
+
+func TestRestore_OverWALDatabase(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	backupPath := filepath.Join(t.TempDir(), "backup.db")
+	if err := db.Backup(backupPath); err != nil {
+		t.Fatalf("Backup() error: %v", err)
+	}
+
+	// Write after the backup so the live DB has pages only in its WAL
+	db.GetOrCreateOrg("walonly")
+	if _, err := os.Stat(db.Path() + "-wal"); err != nil {
+		t.Fatalf("Expected WAL file before restore: %v", err)
+	}
+
+	if err := db.Restore(backupPath); err != nil {
+		t.Fatalf("Restore() error: %v", err)
+	}
+
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if _, err := os.Stat(db.Path() + suffix); !os.IsNotExist(err) {
+			t.Errorf("Stale %s file left after restore", suffix)
+		}
+	}
+
+	restored, err := Open(db.Path())
+	if err != nil {
+		t.Fatalf("Failed to reopen after restore: %v", err)
+	}
+	defer restored.Close()
+
+	if err := restored.IntegrityCheck(); err != nil {
+		t.Fatalf("IntegrityCheck() error: %v", err)
+	}
+
+	var count int
+	restored.QueryRow("SELECT COUNT(*) FROM organizations").Scan(&count)
+	if count != 1 {
+		t.Errorf("Restored DB has %d organizations, want 1", count)
+	}
+}
+
+func TestRestore_BadBackup(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	good := filepath.Join(t.TempDir(), "good.db")
+	if err := db.Backup(good); err != nil {
+		t.Fatalf("Backup() error: %v", err)
+	}
+	data, _ := os.ReadFile(good)
+
+	dir := t.TempDir()
+	backups := map[string][]byte{
+		"garbage":   []byte("this is not a database"),
+		"truncated": data[:len(data)/2],
+		"empty":     {},
+	}
+	for name, content := range backups {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name+".db")
+			os.WriteFile(path, content, 0644)
+
+			if err := db.Restore(path); err == nil {
+				t.Fatal("Restore() should fail on a bad backup")
+			}
+
+			// The handle stays open and the original data is intact
+			var count int
+			if err := db.QueryRow("SELECT COUNT(*) FROM repositories").Scan(&count); err != nil {
+				t.Fatalf("database unusable after failed restore: %v", err)
+			}
+			if count != 1 {
+				t.Errorf("database has %d repositories after failed restore, want 1", count)
+			}
+			leftovers, _ := filepath.Glob(db.Path() + ".restore-*")
+			if len(leftovers) > 0 {
+				t.Errorf("failed restore left temporary files: %v", leftovers)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	problems, err := db.Verify()
+	if err != nil {
+		t.Fatalf("Verify() error: %v", err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("Verify() on a healthy database = %v, want none", problems)
+	}
+
+	// An issue pointing at a repository that doesn't exist
+	db.Exec("PRAGMA foreign_keys = OFF")
+	db.Exec(`INSERT INTO issues (repo_id, number, title, state, gh_created_at, gh_updated_at)
+		VALUES (999, 1, 'Orphan', 'open', datetime('now'), datetime('now'))`)
+	db.Exec("PRAGMA foreign_keys = ON")
+
+	problems, err = db.Verify()
+	if err != nil {
+		t.Fatalf("Verify() error: %v", err)
+	}
+	if len(problems) != 1 || !strings.Contains(problems[0], "repositories") {
+		t.Errorf("Verify() = %v, want one missing repositories row", problems)
+	}
+}
+
 func TestExportAndImport(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -379,60 +956,1616 @@ func TestExportAndImport(t *testing.T) {
 	}
 }
 
-func TestGetClosedIssuesInPeriod(t *testing.T) {
+func TestExportCompactGzip_RoundTrip(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
 	org, _ := db.GetOrCreateOrg("testorg")
 	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
-
 	now := time.Now()
-	closedAt := now.Add(-24 * time.Hour) // Closed yesterday
-	oldClosedAt := now.Add(-60 * 24 * time.Hour) // Closed 60 days ago
+	db.UpsertIssue(&Issue{RepoID: repo.ID, Number: 1, Title: "Test Issue", State: "open", GHCreatedAt: now, GHUpdatedAt: now})
 
-	issues := []*Issue{
-		{RepoID: repo.ID, Number: 1, Title: "Recent closed", State: "closed", CurrentStatus: "done", GHCreatedAt: now.Add(-48 * time.Hour), GHUpdatedAt: now, GHClosedAt: &closedAt, LeadTimeHours: 24},
-		{RepoID: repo.ID, Number: 2, Title: "Old closed", State: "closed", CurrentStatus: "done", GHCreatedAt: now.Add(-90 * 24 * time.Hour), GHUpdatedAt: now, GHClosedAt: &oldClosedAt, LeadTimeHours: 720},
-		{RepoID: repo.ID, Number: 3, Title: "Still open", State: "open", CurrentStatus: "in-progress", GHCreatedAt: now, GHUpdatedAt: now},
+	var pretty, compact, gzipped bytes.Buffer
+	if err := db.Export(&pretty); err != nil {
+		t.Fatalf("Export() error: %v", err)
 	}
-
-	for _, issue := range issues {
-		db.UpsertIssue(issue)
+	if err := db.ExportWithOptions(&compact, ExportOptions{Compact: true}); err != nil {
+		t.Fatalf("ExportWithOptions(compact) error: %v", err)
+	}
+	if err := db.ExportWithOptions(&gzipped, ExportOptions{Compact: true, Gzip: true}); err != nil {
+		t.Fatalf("ExportWithOptions(gzip) error: %v", err)
 	}
 
-	// Get closed issues in last 30 days
-	closed, err := db.GetClosedIssuesInPeriod("testorg/myrepo", 30)
-	if err != nil {
-		t.Fatalf("GetClosedIssuesInPeriod() error: %v", err)
+	if compact.Len() >= pretty.Len() {
+		t.Errorf("compact export is %d bytes, want less than pretty %d", compact.Len(), pretty.Len())
+	}
+	if !bytes.HasPrefix(gzipped.Bytes(), []byte{0x1f, 0x8b}) {
+		t.Error("gzip export does not start with gzip magic bytes")
 	}
 
-	if len(closed) != 1 {
-		t.Errorf("GetClosedIssuesInPeriod() returned %d issues, want 1", len(closed))
+	db2, cleanup2 := setupTestDB(t)
+	defer cleanup2()
+	if err := db2.Import(&gzipped); err != nil {
+		t.Fatalf("Import(gzip) error: %v", err)
 	}
-	if len(closed) > 0 && closed[0].Number != 1 {
-		t.Errorf("Expected issue #1, got #%d", closed[0].Number)
+
+	var count int
+	db2.QueryRow("SELECT COUNT(*) FROM issues").Scan(&count)
+	if count != 1 {
+		t.Errorf("Imported DB has %d issues, want 1", count)
 	}
 }
 
-func TestRecordStatusTransition(t *testing.T) {
+func TestExportAndImport_History(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
 
 	org, _ := db.GetOrCreateOrg("testorg")
 	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+	now := time.Now().UTC().Truncate(time.Second)
+	blockedAt := now.Add(-48 * time.Hour)
+	mergedAt := now.Add(-2 * time.Hour)
 
-	now := time.Now()
-	issue := &Issue{
-		RepoID:      repo.ID,
-		Number:      1,
-		Title:       "Test Issue",
-		State:       "open",
-		GHCreatedAt: now,
-		GHUpdatedAt: now,
-	}
+	issue := &Issue{RepoID: repo.ID, Number: 1, Title: "Tracked", State: "open", GHCreatedAt: now.Add(-72 * time.Hour), GHUpdatedAt: now}
 	db.UpsertIssue(issue)
+	issueID, _ := db.GetIssueIDByNumber(repo.ID, 1)
+	db.RecordStatusTransition(issueID, "", "backlog", now.Add(-72*time.Hour))
+	db.RecordStatusTransition(issueID, "backlog", "in-progress", now.Add(-24*time.Hour))
+	db.RecordStatusTimestamps(issueID, map[string]time.Time{"in-progress": now.Add(-24 * time.Hour), "qa": now.Add(-6 * time.Hour)})
+	db.RecordBlockedPeriod(issueID, &blockedAt, &now, "waiting on API")
 
-	// Record transition
+	pr := &PullRequest{RepoID: repo.ID, Number: 10, Title: "Fix it", State: "MERGED", GHCreatedAt: now.Add(-5 * time.Hour), GHUpdatedAt: now, GHMergedAt: &mergedAt, Author: "alice", ReviewTimeHours: 1.5}
+	if err := db.UpsertPR(pr); err != nil {
+		t.Fatalf("UpsertPR() error: %v", err)
+	}
+	db.LinkPRToIssue(pr.ID, issueID)
+
+	db.SaveMetricsSnapshot(&MetricsDaily{RepoID: repo.ID, SnapshotDate: now, WIPInProgress: 1, WIPTotal: 1, Throughput30d: 4})
+	db.SaveCFDSnapshot(repo.ID, now, map[string]int{"in-progress": 1, "done": 3})
+	db.SaveBaselines("sprint-1", []MetricBaseline{{Repo: "testorg/myrepo", Metrics: json.RawMessage(`{"wip":1}`), CreatedAt: now}})
+
+	var buf bytes.Buffer
+	if err := db.Export(&buf); err != nil {
+		t.Fatalf("Export() error: %v", err)
+	}
+
+	db2, cleanup2 := setupTestDB(t)
+	defer cleanup2()
+	if err := db2.Import(&buf); err != nil {
+		t.Fatalf("Import() error: %v", err)
+	}
+
+	for _, table := range []string{
+		"issues", "pull_requests", "pr_issue_links", "status_transitions", "status_timestamps",
+		"blocked_periods", "metrics_daily", "cfd_data", "metric_baselines",
+	} {
+		var want, got int
+		db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&want)
+		db2.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&got)
+		if want == 0 {
+			t.Errorf("source %s is empty; test setup is broken", table)
+		}
+		if got != want {
+			t.Errorf("imported %s has %d rows, want %d", table, got, want)
+		}
+	}
+
+	prs, err := db2.GetPRsForIssue(repo.ID, 1)
+	if err != nil || len(prs) != 1 {
+		t.Fatalf("GetPRsForIssue() after import = %v, %v; want 1 PR", prs, err)
+	}
+	if prs[0].Author != "alice" || prs[0].ReviewTimeHours != 1.5 {
+		t.Errorf("imported PR = %+v, want author alice with 1.5h review time", prs[0])
+	}
+
+	transitions, _ := db2.GetStatusTransitions(issueID)
+	if len(transitions) != 2 || transitions[0].FromStatus != "" || transitions[1].ToStatus != "in-progress" {
+		t.Errorf("imported transitions = %+v, want backlog then in-progress", transitions)
+	}
+
+	// Entry times of custom statuses only live in status_timestamps
+	entered, err := db2.GetStatusTimestamps(issueID)
+	if err != nil {
+		t.Fatalf("GetStatusTimestamps() after import error: %v", err)
+	}
+	if !entered["qa"].Equal(now.Add(-6*time.Hour)) || !entered["in-progress"].Equal(now.Add(-24*time.Hour)) {
+		t.Errorf("imported status timestamps = %v, want qa 6h and in-progress 24h ago", entered)
+	}
+}
+
+func TestExportStream(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+	now := time.Now().UTC().Truncate(time.Second)
+	db.UpsertIssue(&Issue{RepoID: repo.ID, Number: 1, Title: "First", State: "open", GHCreatedAt: now, GHUpdatedAt: now})
+	db.UpsertIssue(&Issue{RepoID: repo.ID, Number: 2, Title: "Second", State: "open", IsBlocked: true, GHCreatedAt: now, GHUpdatedAt: now})
+
+	var ndjson bytes.Buffer
+	if err := db.ExportStream(&ndjson, "ndjson"); err != nil {
+		t.Fatalf("ExportStream(ndjson) error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(ndjson.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("ExportStream(ndjson) wrote %d lines, want 2", len(lines))
+	}
+	var rec StreamIssue
+	if err := json.Unmarshal([]byte(lines[1]), &rec); err != nil {
+		t.Fatalf("line 2 is not valid JSON: %v", err)
+	}
+	if rec.RepoFullName != "testorg/myrepo" || rec.Number != 2 || !rec.IsBlocked {
+		t.Errorf("line 2 = %+v, want blocked testorg/myrepo#2", rec)
+	}
+
+	var array bytes.Buffer
+	if err := db.ExportStream(&array, "json"); err != nil {
+		t.Fatalf("ExportStream(json) error: %v", err)
+	}
+	var recs []StreamIssue
+	if err := json.Unmarshal(array.Bytes(), &recs); err != nil {
+		t.Fatalf("ExportStream(json) produced invalid JSON: %v", err)
+	}
+	if len(recs) != 2 {
+		t.Errorf("ExportStream(json) wrote %d issues, want 2", len(recs))
+	}
+
+	if err := db.ExportStream(&array, "csv"); err == nil {
+		t.Error("ExportStream(csv) error = nil, want unknown format error")
+	}
+}
+
+func TestStreamIssues(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+	other, _ := db.GetOrCreateRepo(org.ID, "other", "testorg/other")
+	now := time.Now().UTC().Truncate(time.Second)
+	old := now.Add(-30 * 24 * time.Hour)
+	db.UpsertIssue(&Issue{RepoID: repo.ID, Number: 1, Title: "Stale", State: "open", GHCreatedAt: old, GHUpdatedAt: old})
+	db.UpsertIssue(&Issue{RepoID: repo.ID, Number: 2, Title: "Done", State: "closed", GHCreatedAt: old, GHUpdatedAt: now, GHClosedAt: &now})
+	db.UpsertIssue(&Issue{RepoID: other.ID, Number: 1, Title: "Other", State: "open", GHCreatedAt: now, GHUpdatedAt: now})
+	progress := now.Add(-48 * time.Hour)
+	db.Exec("UPDATE issues SET entered_progress_at = ? WHERE repo_id = ? AND number = 2", sqlTime(progress), repo.ID)
+
+	stream := func(filter IssueFilter) []StreamIssue {
+		t.Helper()
+		var recs []StreamIssue
+		err := db.StreamIssues(filter, func(rec StreamIssue) error {
+			recs = append(recs, rec)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("StreamIssues(%+v) error: %v", filter, err)
+		}
+		return recs
+	}
+
+	if got := stream(IssueFilter{}); len(got) != 3 {
+		t.Errorf("StreamIssues() = %d issues, want 3", len(got))
+	}
+	if got := stream(IssueFilter{Org: "elsewhere"}); len(got) != 0 {
+		t.Errorf("StreamIssues(other org) = %d issues, want 0", len(got))
+	}
+	if got := stream(IssueFilter{Repo: "testorg/myrepo", Since: now.Add(-time.Hour)}); len(got) != 1 || got[0].Number != 2 {
+		t.Errorf("StreamIssues(repo, since) = %+v, want only #2", got)
+	}
+
+	got := stream(IssueFilter{Repo: "testorg/myrepo", State: "closed"})
+	if len(got) != 1 {
+		t.Fatalf("StreamIssues(closed) = %d issues, want 1", len(got))
+	}
+	if got[0].RepoFullName != "testorg/myrepo" {
+		t.Errorf("RepoFullName = %q, want testorg/myrepo", got[0].RepoFullName)
+	}
+	if got[0].EnteredProgressAt == nil || !got[0].EnteredProgressAt.Equal(progress) {
+		t.Errorf("EnteredProgressAt = %v, want %v", got[0].EnteredProgressAt, progress)
+	}
+	if got[0].EnteredReadyAt != nil {
+		t.Errorf("EnteredReadyAt = %v, want nil", got[0].EnteredReadyAt)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err := db.StreamIssues(IssueFilter{}, func(StreamIssue) error {
+		calls++
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Errorf("StreamIssues() with failing callback = %v after %d calls, want stop after 1", err, calls)
+	}
+}
+
+func TestImport_ShorterThanGzipMagic(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	for _, data := range []string{"", "{"} {
+		if err := db.Import(strings.NewReader(data)); err == nil || !strings.Contains(err.Error(), "decode JSON") {
+			t.Errorf("Import(%q) error = %v, want a JSON decode error", data, err)
+		}
+	}
+}
+
+func TestImport_MissingTitle(t *testing.T) {
+	tests := []struct {
+		name  string
+		issue string
+	}{
+		{"missing", `{"id": 1, "repo_id": 1, "number": 7, "state": "open"}`},
+		{"null", `{"id": 1, "repo_id": 1, "number": 7, "title": null, "state": "open"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			data := `{"organizations": [{"id": 1, "name": "testorg"}],
+				"repositories": [{"id": 1, "org_id": 1, "name": "myrepo", "full_name": "testorg/myrepo"}],
+				"issues": [` + tt.issue + `]}`
+
+			err := db.Import(strings.NewReader(data))
+			if err == nil {
+				t.Fatal("Import() expected error for issue without title")
+			}
+			if !strings.Contains(err.Error(), "#7") || !strings.Contains(err.Error(), "no title") {
+				t.Errorf("Import() error = %q, want it to name issue #7 and the missing title", err)
+			}
+
+			var count int
+			db.QueryRow("SELECT COUNT(*) FROM organizations").Scan(&count)
+			if count != 0 {
+				t.Errorf("Import() wrote %d organizations before failing, want 0", count)
+			}
+		})
+	}
+}
+
+func TestImport_EmptyTitle(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	data := `{"organizations": [{"id": 1, "name": "testorg"}],
+		"repositories": [{"id": 1, "org_id": 1, "name": "myrepo", "full_name": "testorg/myrepo"}],
+		"issues": [{"id": 1, "repo_id": 1, "number": 7, "title": "", "state": "open"}]}`
+
+	if err := db.Import(strings.NewReader(data)); err != nil {
+		t.Fatalf("Import() error: %v", err)
+	}
+}
+
+func TestGetClosedIssuesInPeriod(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	now := time.Now()
+	closedAt := now.Add(-24 * time.Hour) // Closed yesterday
+	oldClosedAt := now.Add(-60 * 24 * time.Hour) // Closed 60 days ago
+
+	issues := []*Issue{
+		{RepoID: repo.ID, Number: 1, Title: "Recent closed", State: "closed", CurrentStatus: "done", GHCreatedAt: now.Add(-48 * time.Hour), GHUpdatedAt: now, GHClosedAt: &closedAt, LeadTimeHours: 24},
+		{RepoID: repo.ID, Number: 2, Title: "Old closed", State: "closed", CurrentStatus: "done", GHCreatedAt: now.Add(-90 * 24 * time.Hour), GHUpdatedAt: now, GHClosedAt: &oldClosedAt, LeadTimeHours: 720},
+		{RepoID: repo.ID, Number: 3, Title: "Still open", State: "open", CurrentStatus: "in-progress", GHCreatedAt: now, GHUpdatedAt: now},
+	}
+
+	for _, issue := range issues {
+		db.UpsertIssue(issue)
+	}
+
+	// Get closed issues in last 30 days
+	closed, err := db.GetClosedIssuesInPeriod("testorg/myrepo", 30)
+	if err != nil {
+		t.Fatalf("GetClosedIssuesInPeriod() error: %v", err)
+	}
+
+	if len(closed) != 1 {
+		t.Errorf("GetClosedIssuesInPeriod() returned %d issues, want 1", len(closed))
+	}
+	if len(closed) > 0 && closed[0].Number != 1 {
+		t.Errorf("Expected issue #1, got #%d", closed[0].Number)
+	}
+}
+
+func TestGetClosedIssuesInPeriod_NotPlanned(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	now := time.Now()
+	closedAt := now.Add(-24 * time.Hour)
+	issues := []*Issue{
+		{RepoID: repo.ID, Number: 1, Title: "Completed", State: "closed", StateReason: "completed", GHCreatedAt: now.Add(-48 * time.Hour), GHUpdatedAt: now, GHClosedAt: &closedAt},
+		{RepoID: repo.ID, Number: 2, Title: "Won't fix", State: "closed", StateReason: "not_planned", GHCreatedAt: now.Add(-48 * time.Hour), GHUpdatedAt: now, GHClosedAt: &closedAt},
+		{RepoID: repo.ID, Number: 3, Title: "Duplicate", State: "closed", StateReason: "duplicate", GHCreatedAt: now.Add(-48 * time.Hour), GHUpdatedAt: now, GHClosedAt: &closedAt},
+		{RepoID: repo.ID, Number: 4, Title: "No reason cached", State: "closed", GHCreatedAt: now.Add(-48 * time.Hour), GHUpdatedAt: now, GHClosedAt: &closedAt},
+	}
+	if err := db.UpsertIssueBatch(issues); err != nil {
+		t.Fatalf("UpsertIssueBatch() error: %v", err)
+	}
+
+	closed, err := db.GetClosedIssuesInPeriod("testorg/myrepo", 30)
+	if err != nil {
+		t.Fatalf("GetClosedIssuesInPeriod() error: %v", err)
+	}
+	var numbers []int
+	for _, issue := range closed {
+		numbers = append(numbers, issue.Number)
+	}
+	sort.Ints(numbers)
+	if !reflect.DeepEqual(numbers, []int{1, 4}) {
+		t.Errorf("GetClosedIssuesInPeriod() = %v, want [1 4] (not planned and duplicates left out)", numbers)
+	}
+	if byRepo, _ := db.GetThroughputByRepo(30); byRepo["testorg/myrepo"] != 2 {
+		t.Errorf("GetThroughputByRepo() = %d, want 2", byRepo["testorg/myrepo"])
+	}
+
+	db.SetIncludeNotPlanned(true)
+	closed, err = db.GetClosedIssuesInPeriod("testorg/myrepo", 30)
+	if err != nil {
+		t.Fatalf("GetClosedIssuesInPeriod() error: %v", err)
+	}
+	if len(closed) != 4 {
+		t.Errorf("GetClosedIssuesInPeriod() with not planned = %d issues, want 4", len(closed))
+	}
+}
+
+func TestExclusions(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	now := time.Now()
+	closedAt := now.Add(-24 * time.Hour)
+	epic := &Issue{RepoID: repo.ID, Number: 1, Title: "Epic", State: "open", CurrentStatus: "in-progress", GHCreatedAt: now, GHUpdatedAt: now}
+	tracker := &Issue{RepoID: repo.ID, Number: 2, Title: "Tracker", State: "open", CurrentStatus: "in-progress", IsBlocked: true, GHCreatedAt: now, GHUpdatedAt: now}
+	work := &Issue{RepoID: repo.ID, Number: 3, Title: "Work", State: "open", CurrentStatus: "in-progress", GHCreatedAt: now, GHUpdatedAt: now}
+	closedEpic := &Issue{RepoID: repo.ID, Number: 4, Title: "Closed epic", State: "closed", CurrentStatus: "done", GHCreatedAt: now.Add(-48 * time.Hour), GHUpdatedAt: now, GHClosedAt: &closedAt}
+	if err := db.UpsertIssueBatch([]*Issue{epic, tracker, work, closedEpic}); err != nil {
+		t.Fatalf("UpsertIssueBatch() error: %v", err)
+	}
+	if err := db.ReplaceIssueLabels(repo.ID, map[int64][]string{epic.ID: {"Type: Epic"}, closedEpic.ID: {"type: epic"}}, prefixCategory); err != nil {
+		t.Fatalf("ReplaceIssueLabels() error: %v", err)
+	}
+
+	db.SetExclusions(Exclusions{Labels: []string{"type:epic"}, Issues: map[string][]int{"myrepo": {2}}})
+
+	board, err := db.GetBoardIssues("testorg/myrepo", "")
+	if err != nil {
+		t.Fatalf("GetBoardIssues() error: %v", err)
+	}
+	if len(board) != 1 || board[0].Number != 3 {
+		t.Errorf("GetBoardIssues() = %v, want only #3", board)
+	}
+
+	wip, err := db.GetWIPSummary("testorg/myrepo")
+	if err != nil {
+		t.Fatalf("GetWIPSummary() error: %v", err)
+	}
+	for _, s := range wip {
+		if s.Status == "in-progress" && s.Count != 1 {
+			t.Errorf("GetWIPSummary() in-progress = %d, want 1", s.Count)
+		}
+		if s.Status == "done" {
+			t.Errorf("GetWIPSummary() counted the excluded done issue")
+		}
+	}
+
+	closed, err := db.GetClosedIssuesInPeriod("testorg/myrepo", 30)
+	if err != nil {
+		t.Fatalf("GetClosedIssuesInPeriod() error: %v", err)
+	}
+	if len(closed) != 0 {
+		t.Errorf("GetClosedIssuesInPeriod() = %d issues, want 0", len(closed))
+	}
+
+	// Excluded issues are still reported as blocked
+	blocked, err := db.GetBlockedIssues("testorg/myrepo")
+	if err != nil {
+		t.Fatalf("GetBlockedIssues() error: %v", err)
+	}
+	if len(blocked) != 1 || blocked[0].Number != 2 {
+		t.Errorf("GetBlockedIssues() = %v, want #2", blocked)
+	}
+}
+
+func TestGetClosedIssuesInWindow(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for i, daysAgo := range []int{5, 20, 45} {
+		closedAt := now.AddDate(0, 0, -daysAgo)
+		db.UpsertIssue(&Issue{RepoID: repo.ID, Number: i + 1, Title: "Closed", State: "closed",
+			GHCreatedAt: closedAt.Add(-48 * time.Hour), GHUpdatedAt: closedAt, GHClosedAt: &closedAt})
+	}
+
+	// Previous 30-day window: 30-60 days ago
+	closed, err := db.GetClosedIssuesInWindow("testorg/myrepo", now.AddDate(0, 0, -60), now.AddDate(0, 0, -30))
+	if err != nil {
+		t.Fatalf("GetClosedIssuesInWindow() error: %v", err)
+	}
+	if len(closed) != 1 || closed[0].Number != 3 {
+		t.Errorf("previous window = %+v, want only #3", closed)
+	}
+	if len(closed) == 1 && closed[0].LeadTimeHours != 48 {
+		t.Errorf("LeadTimeHours = %v, want 48", closed[0].LeadTimeHours)
+	}
+
+	// Current 30-day window
+	closed, _ = db.GetClosedIssuesInWindow("testorg/myrepo", now.AddDate(0, 0, -30), now.Add(time.Minute))
+	if len(closed) != 2 {
+		t.Errorf("current window returned %d issues, want 2", len(closed))
+	}
+}
+
+func TestGetDailyThroughput(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	today := now.Truncate(24 * time.Hour).Add(time.Minute)
+	yesterday := today.Add(-24 * time.Hour)
+	old := now.Add(-60 * 24 * time.Hour)
+
+	issues := []*Issue{
+		{RepoID: repo.ID, Number: 1, Title: "Today", State: "closed", GHCreatedAt: old, GHUpdatedAt: now, GHClosedAt: &today},
+		{RepoID: repo.ID, Number: 2, Title: "Yesterday A", State: "closed", GHCreatedAt: old, GHUpdatedAt: now, GHClosedAt: &yesterday},
+		{RepoID: repo.ID, Number: 3, Title: "Yesterday B", State: "closed", GHCreatedAt: old, GHUpdatedAt: now, GHClosedAt: &yesterday},
+		{RepoID: repo.ID, Number: 4, Title: "Too old", State: "closed", GHCreatedAt: old, GHUpdatedAt: now, GHClosedAt: &old},
+	}
+	for _, issue := range issues {
+		if err := db.UpsertIssue(issue); err != nil {
+			t.Fatalf("UpsertIssue() error: %v", err)
+		}
+	}
+
+	counts, err := db.GetDailyThroughput("testorg/myrepo", 7)
+	if err != nil {
+		t.Fatalf("GetDailyThroughput() error: %v", err)
+	}
+	if len(counts) != 7 {
+		t.Fatalf("GetDailyThroughput() returned %d days, want 7", len(counts))
+	}
+	if counts[6] != 1 || counts[5] != 2 {
+		t.Errorf("counts = %v, want 1 today and 2 yesterday", counts)
+	}
+
+	total := 0
+	for _, c := range counts {
+		total += c
+	}
+	if total != 3 {
+		t.Errorf("total = %d, want 3 (old issue excluded)", total)
+	}
+}
+
+func TestGetVelocity(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	closed := now.Add(-24 * time.Hour)
+	old := now.Add(-60 * 24 * time.Hour)
+	three, five, eight := 3.0, 5.0, 8.0
+
+	issues := []*Issue{
+		{RepoID: repo.ID, Number: 1, Title: "Medium", State: "closed", CurrentSize: "m", SizePoints: &three, GHCreatedAt: old, GHUpdatedAt: now, GHClosedAt: &closed},
+		{RepoID: repo.ID, Number: 2, Title: "Large", State: "closed", CurrentSize: "l", SizePoints: &five, GHCreatedAt: old, GHUpdatedAt: now, GHClosedAt: &closed},
+		{RepoID: repo.ID, Number: 3, Title: "Unsized", State: "closed", GHCreatedAt: old, GHUpdatedAt: now, GHClosedAt: &closed},
+		{RepoID: repo.ID, Number: 4, Title: "Too old", State: "closed", CurrentSize: "xl", SizePoints: &eight, GHCreatedAt: old, GHUpdatedAt: now, GHClosedAt: &old},
+	}
+	for _, issue := range issues {
+		if err := db.UpsertIssue(issue); err != nil {
+			t.Fatalf("UpsertIssue() error: %v", err)
+		}
+	}
+
+	points, err := db.GetVelocity("testorg/myrepo", 30)
+	if err != nil {
+		t.Fatalf("GetVelocity() error: %v", err)
+	}
+	if points != 8 {
+		t.Errorf("GetVelocity() = %v, want 8 (old issue excluded)", points)
+	}
+
+	unsized, err := db.GetUnsizedClosedCount("testorg/myrepo", 30)
+	if err != nil {
+		t.Fatalf("GetUnsizedClosedCount() error: %v", err)
+	}
+	if unsized != 1 {
+		t.Errorf("GetUnsizedClosedCount() = %d, want 1", unsized)
+	}
+
+	if points, _ := db.GetVelocity("testorg/other", 30); points != 0 {
+		t.Errorf("GetVelocity() for a repo without issues = %v, want 0", points)
+	}
+}
+
+func TestGetClosedIssuesByAssignee(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	now := time.Now()
+	closedAt := now.Add(-24 * time.Hour)
+
+	issues := []*Issue{
+		{RepoID: repo.ID, Number: 1, Title: "Alice one", State: "closed", CurrentStatus: "done", GHCreatedAt: now.Add(-48 * time.Hour), GHUpdatedAt: now, GHClosedAt: &closedAt, LeadTimeHours: 24, Assignee: "alice"},
+		{RepoID: repo.ID, Number: 2, Title: "Alice two", State: "closed", CurrentStatus: "done", GHCreatedAt: now.Add(-72 * time.Hour), GHUpdatedAt: now, GHClosedAt: &closedAt, LeadTimeHours: 48, Assignee: "alice", Author: "renovate[bot]"},
+		{RepoID: repo.ID, Number: 3, Title: "Nobody", State: "closed", CurrentStatus: "done", GHCreatedAt: now.Add(-48 * time.Hour), GHUpdatedAt: now, GHClosedAt: &closedAt, LeadTimeHours: 24},
+		{RepoID: repo.ID, Number: 4, Title: "Open", State: "open", CurrentStatus: "in-progress", GHCreatedAt: now, GHUpdatedAt: now, Assignee: "bob"},
+	}
+	for _, issue := range issues {
+		db.UpsertIssue(issue)
+	}
+
+	byAssignee, err := db.GetClosedIssuesByAssignee("testorg/myrepo", 30)
+	if err != nil {
+		t.Fatalf("GetClosedIssuesByAssignee() error: %v", err)
+	}
+
+	if got := len(byAssignee["alice"]); got != 2 {
+		t.Errorf("alice has %d closed issues, want 2", got)
+	}
+	for _, issue := range byAssignee["alice"] {
+		if issue.Number == 2 && issue.Author != "renovate[bot]" {
+			t.Errorf("#2 author = %q, want renovate[bot] (left for the caller to filter)", issue.Author)
+		}
+	}
+	if got := len(byAssignee[UnassignedBucket]); got != 1 {
+		t.Errorf("%s has %d closed issues, want 1", UnassignedBucket, got)
+	}
+	if _, ok := byAssignee["bob"]; ok {
+		t.Error("bob has no closed issues and should not appear")
+	}
+}
+
+func TestGetBoardArrivals(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	if _, ok, err := db.GetBoardHistoryStart("testorg/myrepo"); err != nil || ok {
+		t.Fatalf("GetBoardHistoryStart() on an empty repo = %v, %v; want no start", ok, err)
+	}
+
+	now := time.Now()
+	closedAt := now.Add(-time.Hour)
+	issues := []*Issue{
+		{RepoID: repo.ID, Number: 1, Title: "On the board", State: "open", CurrentStatus: "ready", GHCreatedAt: now.Add(-48 * time.Hour), GHUpdatedAt: now},
+		{RepoID: repo.ID, Number: 2, Title: "Moved off the board", State: "open", CurrentStatus: "in-progress", GHCreatedAt: now.Add(-48 * time.Hour), GHUpdatedAt: now},
+		{RepoID: repo.ID, Number: 3, Title: "Never labelled", State: "open", GHCreatedAt: now.Add(-48 * time.Hour), GHUpdatedAt: now},
+		{RepoID: repo.ID, Number: 4, Title: "Old", State: "open", CurrentStatus: "backlog", GHCreatedAt: now.Add(-60 * 24 * time.Hour), GHUpdatedAt: now},
+		{RepoID: repo.ID, Number: 5, Title: "Closed not planned", State: "closed", StateReason: "not_planned", CurrentStatus: "review", GHCreatedAt: now.Add(-48 * time.Hour), GHUpdatedAt: now, GHClosedAt: &closedAt},
+	}
+	for _, issue := range issues {
+		if err := db.UpsertIssue(issue); err != nil {
+			t.Fatalf("UpsertIssue() error: %v", err)
+		}
+	}
+	// #2's status label is removed; its history keeps it an arrival
+	issues[1].CurrentStatus = ""
+	if err := db.UpsertIssue(issues[1]); err != nil {
+		t.Fatalf("UpsertIssue() error: %v", err)
+	}
+
+	count, err := db.GetBoardArrivals("testorg/myrepo", 30)
+	if err != nil {
+		t.Fatalf("GetBoardArrivals() error: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("GetBoardArrivals() = %d, want 3 (#1, #2 and #5)", count)
+	}
+
+	// Board history starts with the earliest recorded status entry
+	issueID, _ := db.GetIssueIDByNumber(repo.ID, 4)
+	labelledAt := now.Add(-10 * 24 * time.Hour).UTC().Truncate(time.Second)
+	if err := db.RecordStatusTransition(issueID, "", "backlog", labelledAt); err != nil {
+		t.Fatal(err)
+	}
+	start, ok, err := db.GetBoardHistoryStart("testorg/myrepo")
+	if err != nil || !ok || !start.Equal(labelledAt) {
+		t.Errorf("GetBoardHistoryStart() = %v, %v, %v; want %v", start, ok, err, labelledAt)
+	}
+}
+
+func TestGetArrivalByAuthor(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+	other, _ := db.GetOrCreateRepo(org.ID, "other", "testorg/other")
+
+	now := time.Now()
+	issues := []*Issue{
+		{RepoID: repo.ID, Number: 1, Title: "Alice one", State: "open", CurrentStatus: "backlog", GHCreatedAt: now.Add(-24 * time.Hour), GHUpdatedAt: now, Author: "alice"},
+		{RepoID: repo.ID, Number: 2, Title: "Alice two", State: "open", CurrentStatus: "backlog", GHCreatedAt: now.Add(-48 * time.Hour), GHUpdatedAt: now, Author: "alice"},
+		{RepoID: repo.ID, Number: 3, Title: "Ghost", State: "open", CurrentStatus: "backlog", GHCreatedAt: now.Add(-24 * time.Hour), GHUpdatedAt: now},
+		{RepoID: repo.ID, Number: 4, Title: "Old", State: "open", CurrentStatus: "backlog", GHCreatedAt: now.Add(-60 * 24 * time.Hour), GHUpdatedAt: now, Author: "bob"},
+		{RepoID: other.ID, Number: 1, Title: "Elsewhere", State: "open", CurrentStatus: "backlog", GHCreatedAt: now.Add(-24 * time.Hour), GHUpdatedAt: now, Author: "carol"},
+	}
+	for _, issue := range issues {
+		db.UpsertIssue(issue)
+	}
+
+	byAuthor, err := db.GetArrivalByAuthor(30)
+	if err != nil {
+		t.Fatalf("GetArrivalByAuthor() error: %v", err)
+	}
+
+	if got := byAuthor["alice"]; got != 2 {
+		t.Errorf("alice opened %d issues, want 2", got)
+	}
+	if got := byAuthor[UnknownAuthorBucket]; got != 1 {
+		t.Errorf("%s opened %d issues, want 1", UnknownAuthorBucket, got)
+	}
+	if _, ok := byAuthor["bob"]; ok {
+		t.Error("bob opened nothing in the period and should not appear")
+	}
+	if got := byAuthor["carol"]; got != 1 {
+		t.Errorf("carol opened %d issues in another repo, want 1", got)
+	}
+}
+
+func TestRecalcCycleTime_CycleExceedsLead(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	closedAt := now.Add(-24 * time.Hour)
+	issue := &Issue{
+		RepoID:        repo.ID,
+		Number:        1,
+		Title:         "Moved to progress before creation",
+		State:         "closed",
+		CurrentStatus: "done",
+		GHCreatedAt:   now.Add(-48 * time.Hour),
+		GHUpdatedAt:   now,
+		GHClosedAt:    &closedAt,
+	}
+	db.UpsertIssue(issue)
+
+	// Progress timestamp predates creation, so cycle time > lead time
+	progressAt := now.Add(-96 * time.Hour)
+	if err := db.UpdateIssueTimestamps(issue.ID, map[string]time.Time{"in-progress": progressAt}); err != nil {
+		t.Fatalf("UpdateIssueTimestamps() error: %v", err)
+	}
+
+	err := db.RecalcCycleTime(issue.ID)
+	if !errors.Is(err, ErrCycleExceedsLead) {
+		t.Fatalf("RecalcCycleTime() error = %v, want ErrCycleExceedsLead", err)
+	}
+
+	closed, err := db.GetClosedIssuesInPeriod("testorg/myrepo", 30)
+	if err != nil {
+		t.Fatalf("GetClosedIssuesInPeriod() error: %v", err)
+	}
+	if len(closed) != 1 {
+		t.Fatalf("GetClosedIssuesInPeriod() returned %d issues, want 1", len(closed))
+	}
+	if !closed[0].CycleExceedsLead() {
+		t.Errorf("CycleExceedsLead() = false, want true (cycle %.1fh, lead %.1fh)", closed[0].CycleTimeHours, closed[0].LeadTimeHours)
+	}
+}
+
+func TestRecalcCycleTime_StoredTimestamps(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	// Non-UTC times with sub-second precision, as a caller might pass them
+	zone := time.FixedZone("UTC+2", 2*60*60)
+	created := time.Date(2024, 3, 1, 10, 0, 0, 123456789, zone)
+	started := created.Add(24 * time.Hour)
+	done := created.Add(72 * time.Hour)
+	issue := &Issue{RepoID: repo.ID, Number: 1, Title: "Timestamps", State: "closed",
+		GHCreatedAt: created, GHUpdatedAt: done, GHClosedAt: &done}
+	if err := db.UpsertIssue(issue); err != nil {
+		t.Fatalf("UpsertIssue() error: %v", err)
+	}
+	if err := db.UpdateIssueTimestamps(issue.ID, map[string]time.Time{"in-progress": started, "done": done}); err != nil {
+		t.Fatalf("UpdateIssueTimestamps() error: %v", err)
+	}
+
+	var createdAt, progressAt string
+	db.QueryRow("SELECT gh_created_at, entered_progress_at FROM issues WHERE id = ?", issue.ID).Scan(&createdAt, &progressAt)
+	if _, err := time.Parse(time.RFC3339, createdAt); err != nil || !strings.HasPrefix(createdAt, "2024-03-01T08:00:00") {
+		t.Errorf("gh_created_at = %q, want 2024-03-01 08:00:00 UTC", createdAt)
+	}
+	if !strings.HasPrefix(progressAt, "2024-03-02T08:00:00") {
+		t.Errorf("entered_progress_at = %q, want 2024-03-02 08:00:00 UTC", progressAt)
+	}
+
+	if err := db.RecalcCycleTime(issue.ID); err != nil {
+		t.Fatalf("RecalcCycleTime() error: %v", err)
+	}
+	var cycle, lead sql.NullFloat64
+	db.QueryRow("SELECT cycle_time_hours, lead_time_hours FROM issues WHERE id = ?", issue.ID).Scan(&cycle, &lead)
+	if !cycle.Valid || math.Abs(cycle.Float64-48) > 0.01 {
+		t.Errorf("cycle_time_hours = %v, want 48", cycle)
+	}
+	if !lead.Valid || math.Abs(lead.Float64-72) > 0.01 {
+		t.Errorf("lead_time_hours = %v, want 72", lead)
+	}
+}
+
+func TestNormalizeTimestamps(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, n := range []int{1, 2} {
+		if err := db.UpsertIssue(&Issue{RepoID: repo.ID, Number: n, Title: "Issue", State: "closed",
+			CurrentStatus: "done", GHCreatedAt: now, GHUpdatedAt: now}); err != nil {
+			t.Fatalf("UpsertIssue() error: %v", err)
+		}
+	}
+
+	// #1 as older versions stored it; #2 is already canonical
+	_, err := db.Exec(`UPDATE issues SET gh_created_at = '2024-03-01 10:00:00 +0200 EET',
+		entered_progress_at = '2024-03-02 08:00:00.5 +0000 UTC m=+0.000123',
+		gh_closed_at = '2024-03-04T08:00:00Z' WHERE number = 1`)
+	if err != nil {
+		t.Fatalf("failed to set old timestamps: %v", err)
+	}
+	db.Exec(`UPDATE status_transitions SET transitioned_at = '2024-03-02 08:00:00 +0000 UTC'`)
+	db.Exec(`UPDATE issues SET entered_review_at = 'not a time' WHERE number = 2`)
+
+	repair, err := db.NormalizeTimestamps(true)
+	if err != nil {
+		t.Fatalf("NormalizeTimestamps(dry run) error: %v", err)
+	}
+	if repair.Issues != 1 || repair.Transitions != 2 || repair.Unparsed != 1 || repair.Recalculated != 2 {
+		t.Errorf("dry run = %+v, want 1 issue, 2 transitions, 1 unparsed, 2 recalculated", repair)
+	}
+	var created string
+	db.QueryRow("SELECT CAST(gh_created_at AS TEXT) FROM issues WHERE number = 1").Scan(&created)
+	if created != "2024-03-01 10:00:00 +0200 EET" {
+		t.Errorf("dry run changed gh_created_at to %q", created)
+	}
+
+	if _, err := db.NormalizeTimestamps(false); err != nil {
+		t.Fatalf("NormalizeTimestamps() error: %v", err)
+	}
+	var progress, closed, transitioned string
+	var cycle, lead sql.NullFloat64
+	db.QueryRow(`SELECT CAST(gh_created_at AS TEXT), CAST(entered_progress_at AS TEXT), CAST(gh_closed_at AS TEXT),
+		cycle_time_hours, lead_time_hours FROM issues WHERE number = 1`).Scan(&created, &progress, &closed, &cycle, &lead)
+	if created != "2024-03-01 08:00:00" || progress != "2024-03-02 08:00:00" || closed != "2024-03-04 08:00:00" {
+		t.Errorf("timestamps = %q, %q, %q; want UTC in SQLite datetime format", created, progress, closed)
+	}
+	if !cycle.Valid || math.Abs(cycle.Float64-48) > 0.01 || !lead.Valid || math.Abs(lead.Float64-72) > 0.01 {
+		t.Errorf("cycle, lead = %v, %v; want 48h and 72h", cycle, lead)
+	}
+	db.QueryRow("SELECT CAST(transitioned_at AS TEXT) FROM status_transitions LIMIT 1").Scan(&transitioned)
+	if transitioned != "2024-03-02 08:00:00" {
+		t.Errorf("transitioned_at = %q, want 2024-03-02 08:00:00", transitioned)
+	}
+
+	// A second run finds nothing left to fix
+	repair, _ = db.NormalizeTimestamps(false)
+	if repair.Issues != 0 || repair.Transitions != 0 {
+		t.Errorf("second run = %+v, want nothing rewritten", repair)
+	}
+}
+
+func TestFlowEfficiency(t *testing.T) {
+	issues := []ClosedIssueStats{
+		{Number: 1, CycleTimeHours: 10, LeadTimeHours: 100},  // 10%
+		{Number: 2, CycleTimeHours: 90, LeadTimeHours: 100},  // 90%
+		{Number: 3, CycleTimeHours: 200, LeadTimeHours: 100}, // bad data, clamped to 100%
+		{Number: 4, CycleTimeHours: 0, LeadTimeHours: 50},    // no cycle time, skipped
+	}
+	got := FlowEfficiency(issues)
+	want := (10.0 + 90.0 + 100.0) / 3
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("FlowEfficiency() = %v, want %v", got, want)
+	}
+
+	// Every issue fully active: 100%, never more
+	full := []ClosedIssueStats{
+		{CycleTimeHours: 1, LeadTimeHours: 1},
+		{CycleTimeHours: 500, LeadTimeHours: 400},
+	}
+	if got := FlowEfficiency(full); got != 100 {
+		t.Errorf("FlowEfficiency() = %v, want 100", got)
+	}
+
+	if got := FlowEfficiency(nil); got != 0 {
+		t.Errorf("FlowEfficiency(nil) = %v, want 0", got)
+	}
+}
+
+func TestRecalcCycleTime_ActiveStartStatus(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if err := db.SetActiveStartStatus("done"); err == nil {
+		t.Error("SetActiveStartStatus(done) should fail")
+	}
+	if err := db.SetActiveStartStatus("ready"); err != nil {
+		t.Fatalf("SetActiveStartStatus(ready) error: %v", err)
+	}
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	closedAt := now
+	issue := &Issue{
+		RepoID:        repo.ID,
+		Number:        1,
+		Title:         "Queued in ready",
+		State:         "closed",
+		CurrentStatus: "done",
+		GHCreatedAt:   now.Add(-100 * time.Hour),
+		GHUpdatedAt:   now,
+		GHClosedAt:    &closedAt,
+	}
+	db.UpsertIssue(issue)
+
+	readyAt := now.Add(-50 * time.Hour)
+	progressAt := now.Add(-10 * time.Hour)
+	db.UpdateIssueTimestamps(issue.ID, map[string]time.Time{"ready": readyAt, "in-progress": progressAt})
+
+	if err := db.RecalcCycleTime(issue.ID); err != nil {
+		t.Fatalf("RecalcCycleTime() error: %v", err)
+	}
+
+	var cycle float64
+	db.QueryRow("SELECT cycle_time_hours FROM issues WHERE id = ?", issue.ID).Scan(&cycle)
+	if cycle < 49.9 || cycle > 50.1 {
+		t.Errorf("cycle_time_hours = %.2f, want 50 (measured from ready)", cycle)
+	}
+}
+
+func TestRecalcCycleTime_CustomActiveStart(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if err := db.SetActiveStartStatus("analysis"); err != nil {
+		t.Fatalf("SetActiveStartStatus(analysis) error: %v", err)
+	}
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	closedAt := now
+	issue := &Issue{
+		RepoID:        repo.ID,
+		Number:        1,
+		Title:         "Custom workflow",
+		State:         "closed",
+		CurrentStatus: "done",
+		GHCreatedAt:   now.Add(-100 * time.Hour),
+		GHUpdatedAt:   now,
+		GHClosedAt:    &closedAt,
+	}
+	db.UpsertIssue(issue)
+
+	analysisAt := now.Add(-30 * time.Hour)
+	if err := db.RecordStatusTimestamps(issue.ID, map[string]time.Time{"analysis": analysisAt}); err != nil {
+		t.Fatalf("RecordStatusTimestamps() error: %v", err)
+	}
+	// A later entry doesn't move the first one
+	db.RecordStatusTimestamps(issue.ID, map[string]time.Time{"analysis": now.Add(-5 * time.Hour)})
+
+	entered, err := db.GetStatusTimestamps(issue.ID)
+	if err != nil {
+		t.Fatalf("GetStatusTimestamps() error: %v", err)
+	}
+	if !entered["analysis"].Equal(analysisAt) {
+		t.Errorf("entered analysis at %v, want %v", entered["analysis"], analysisAt)
+	}
+
+	if err := db.RecalcCycleTime(issue.ID); err != nil {
+		t.Fatalf("RecalcCycleTime() error: %v", err)
+	}
+
+	var cycle float64
+	db.QueryRow("SELECT cycle_time_hours FROM issues WHERE id = ?", issue.ID).Scan(&cycle)
+	if cycle < 29.9 || cycle > 30.1 {
+		t.Errorf("cycle_time_hours = %.2f, want 30 (measured from analysis)", cycle)
+	}
+}
+
+func TestGetIssueStatuses(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+	other, _ := db.GetOrCreateRepo(org.ID, "other", "testorg/other")
+
+	now := time.Now().UTC()
+	db.UpsertIssue(&Issue{RepoID: repo.ID, Number: 1, Title: "A", State: "open", CurrentStatus: "review", GHCreatedAt: now, GHUpdatedAt: now})
+	db.UpsertIssue(&Issue{RepoID: repo.ID, Number: 2, Title: "B", State: "open", GHCreatedAt: now, GHUpdatedAt: now})
+	db.UpsertIssue(&Issue{RepoID: other.ID, Number: 3, Title: "C", State: "open", CurrentStatus: "ready", GHCreatedAt: now, GHUpdatedAt: now})
+
+	statuses, err := db.GetIssueStatuses(repo.ID)
+	if err != nil {
+		t.Fatalf("GetIssueStatuses() error: %v", err)
+	}
+	want := map[int]string{1: "review", 2: ""}
+	if len(statuses) != len(want) {
+		t.Fatalf("GetIssueStatuses() = %v, want %v", statuses, want)
+	}
+	for number, status := range want {
+		if got, ok := statuses[number]; !ok || got != status {
+			t.Errorf("status of #%d = %q (present %v), want %q", number, got, ok, status)
+		}
+	}
+
+	// A repo that was never synced has no issues
+	if statuses, err := db.GetIssueStatuses(0); err != nil || len(statuses) != 0 {
+		t.Errorf("GetIssueStatuses(0) = %v, %v, want empty", statuses, err)
+	}
+}
+
+func TestGetTimeInCurrentStatus(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+	other, _ := db.GetOrCreateRepo(org.ID, "other", "testorg/other")
+
+	now := time.Now().UTC()
+	created := now.Add(-30 * 24 * time.Hour)
+
+	// Moved to review 20 days ago and commented on since
+	stuck := &Issue{RepoID: repo.ID, Number: 1, Title: "Stuck", State: "open", CurrentStatus: "review", Assignee: "alice", GHCreatedAt: created, GHUpdatedAt: now.Add(-20 * 24 * time.Hour)}
+	db.UpsertIssue(stuck)
+	stuck.GHUpdatedAt = now
+	db.UpsertIssue(stuck)
+
+	// Status label added on creation, no transition recorded
+	db.Exec(`INSERT INTO issues (repo_id, number, title, state, current_status, gh_created_at, gh_updated_at)
+		VALUES (?, 2, 'Untouched', 'open', 'ready', ?, ?)`, repo.ID, sqlTime(now.Add(-3*24*time.Hour)), sqlTime(now))
+
+	// Not in a status, done, closed or in another repo
+	db.UpsertIssue(&Issue{RepoID: repo.ID, Number: 3, Title: "No status", State: "open", GHCreatedAt: created, GHUpdatedAt: created})
+	db.UpsertIssue(&Issue{RepoID: repo.ID, Number: 4, Title: "Done", State: "open", CurrentStatus: "done", GHCreatedAt: created, GHUpdatedAt: created})
+	db.UpsertIssue(&Issue{RepoID: repo.ID, Number: 5, Title: "Closed", State: "closed", CurrentStatus: "review", GHCreatedAt: created, GHUpdatedAt: created})
+	db.UpsertIssue(&Issue{RepoID: other.ID, Number: 6, Title: "Other", State: "open", CurrentStatus: "review", GHCreatedAt: created, GHUpdatedAt: created})
+
+	issues, err := db.GetTimeInCurrentStatus("testorg/myrepo")
+	if err != nil {
+		t.Fatalf("GetTimeInCurrentStatus() error: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("GetTimeInCurrentStatus() returned %d issues, want 2: %+v", len(issues), issues)
+	}
+
+	if issues[0].Number != 1 || issues[0].Status != "review" || issues[0].Assignee != "alice" {
+		t.Errorf("first issue = %+v, want #1 in review assigned to alice", issues[0])
+	}
+	if days := issues[0].HoursInStatus / 24; days < 19.9 || days > 20.1 {
+		t.Errorf("#1 days in status = %.2f, want ~20 (since the transition, not the last update)", days)
+	}
+	if days := issues[1].HoursInStatus / 24; issues[1].Number != 2 || days < 2.9 || days > 3.1 {
+		t.Errorf("second issue = #%d with %.2f days, want #2 with ~3 (since creation)", issues[1].Number, days)
+	}
+
+	all, err := db.GetTimeInCurrentStatus("")
+	if err != nil {
+		t.Fatalf("GetTimeInCurrentStatus(\"\") error: %v", err)
+	}
+	if len(all) != 3 {
+		t.Errorf("GetTimeInCurrentStatus(\"\") returned %d issues, want 3", len(all))
+	}
+}
+
+func TestGetTimeInStatus(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+	other, _ := db.GetOrCreateRepo(org.ID, "other", "testorg/other")
+	now := time.Now().UTC().Truncate(time.Second)
+	day := 24 * time.Hour
+
+	move := func(repoID int64, number int, steps ...interface{}) {
+		db.UpsertIssue(&Issue{RepoID: repoID, Number: number, Title: "Issue", State: "open", GHCreatedAt: now.Add(-60 * day), GHUpdatedAt: now})
+		issueID, _ := db.GetIssueIDByNumber(repoID, number)
+		from := ""
+		for i := 0; i < len(steps); i += 2 {
+			to := steps[i].(string)
+			db.RecordStatusTransition(issueID, from, to, now.Add(-steps[i+1].(time.Duration)))
+			from = to
+		}
+	}
+
+	// ready 2d, in-progress 3d, then in review until now
+	move(repo.ID, 1, "ready", 10*day, "in-progress", 8*day, "review", 5*day)
+	// ready 4d, then in-progress; recorded out of order
+	move(repo.ID, 2, "in-progress", 2*day, "ready", 6*day)
+	// left ready 40 days ago, outside a 30-day period
+	move(repo.ID, 3, "ready", 50*day, "done", 40*day)
+	move(other.ID, 4, "ready", 10*day, "review", 1*day)
+
+	dwell, err := db.GetTimeInStatus(repo.ID, 30)
+	if err != nil {
+		t.Fatalf("GetTimeInStatus() error: %v", err)
+	}
+	want := map[string]TimeStats{
+		"ready":       {Average: 3, Median: 3, P85: 4, Count: 2},
+		"in-progress": {Average: 3, Median: 3, P85: 3, Count: 1},
+	}
+	if len(dwell) != len(want) {
+		t.Fatalf("GetTimeInStatus() = %v, want %v", dwell, want)
+	}
+	for status, w := range want {
+		got := dwell[status]
+		if got.Count != w.Count || math.Abs(got.Average-w.Average) > 0.01 ||
+			math.Abs(got.Median-w.Median) > 0.01 || math.Abs(got.P85-w.P85) > 0.01 {
+			t.Errorf("%s time in status = %+v, want %+v", status, got, w)
+		}
+	}
+
+	// Without a period the older dwell counts too
+	all, err := db.GetTimeInStatus(repo.ID, 0)
+	if err != nil {
+		t.Fatalf("GetTimeInStatus(0) error: %v", err)
+	}
+	if got := all["ready"].Count; got != 3 {
+		t.Errorf("all-time ready dwells = %d, want 3", got)
+	}
+}
+
+func TestGetBlockedPeriods(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	issue := &Issue{RepoID: repo.ID, Number: 1, Title: "Blocked twice", State: "open", GHCreatedAt: now, GHUpdatedAt: now}
+	db.UpsertIssue(issue)
+	other := &Issue{RepoID: repo.ID, Number: 2, Title: "Other", State: "open", GHCreatedAt: now, GHUpdatedAt: now}
+	db.UpsertIssue(other)
+
+	first, firstEnd, second := now.Add(-72*time.Hour), now.Add(-48*time.Hour), now.Add(-2*time.Hour)
+	db.RecordBlockedPeriod(issue.ID, &second, nil, "")
+	db.RecordBlockedPeriod(issue.ID, &first, &firstEnd, "waiting on API")
+	db.RecordBlockedPeriod(other.ID, &first, nil, "")
+
+	periods, err := db.GetBlockedPeriods(issue.ID)
+	if err != nil {
+		t.Fatalf("GetBlockedPeriods() error: %v", err)
+	}
+	if len(periods) != 2 {
+		t.Fatalf("GetBlockedPeriods() returned %d periods, want 2", len(periods))
+	}
+	if !periods[0].BlockedAt.Equal(first) || periods[0].UnblockedAt == nil || periods[0].DurationHours != 24 || periods[0].Reason != "waiting on API" {
+		t.Errorf("first period = %+v, want the closed 24h period with its reason", periods[0])
+	}
+	if periods[1].UnblockedAt != nil || periods[1].Reason != "" {
+		t.Errorf("second period = %+v, want an open period without reason", periods[1])
+	}
+}
+
+func TestReplaceBlockedPeriods(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+	now := time.Now().UTC().Truncate(time.Second)
+	issue := &Issue{RepoID: repo.ID, Number: 1, Title: "Blocked", State: "open", GHCreatedAt: now, GHUpdatedAt: now}
+	db.UpsertIssue(issue)
+
+	if err := db.BlockIssue(issue.ID, now.Add(-time.Hour), "manual hold"); err != nil {
+		t.Fatalf("BlockIssue() error: %v", err)
+	}
+
+	start, end := now.Add(-72*time.Hour), now.Add(-48*time.Hour)
+	periods := []BlockedPeriod{
+		{BlockedAt: start, UnblockedAt: &end, Reason: "waiting on API"},
+		{BlockedAt: now.Add(-2 * time.Hour)},
+	}
+	// Every timeline sync records the same periods again
+	for i := 0; i < 3; i++ {
+		if err := db.ReplaceBlockedPeriods(issue.ID, periods); err != nil {
+			t.Fatalf("ReplaceBlockedPeriods() error: %v", err)
+		}
+	}
+
+	got, err := db.GetBlockedPeriods(issue.ID)
+	if err != nil {
+		t.Fatalf("GetBlockedPeriods() error: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d blocked periods, want 2 from the timeline and 1 manual: %+v", len(got), got)
+	}
+	if !got[0].BlockedAt.Equal(start) || got[0].DurationHours != 24 || got[0].Reason != "waiting on API" {
+		t.Errorf("first period = %+v, want the closed 24h period with its reason", got[0])
+	}
+	if !got[2].Manual || got[2].Reason != "manual hold" {
+		t.Errorf("last period = %+v, want the manual one kept", got[2])
+	}
+}
+
+func TestPruneIssues(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+	other, _ := db.GetOrCreateRepo(org.ID, "other", "testorg/other")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	for n := 1; n <= 3; n++ {
+		db.UpsertIssue(&Issue{RepoID: repo.ID, Number: n, Title: "Issue", State: "open", CurrentStatus: "review", GHCreatedAt: now, GHUpdatedAt: now})
+	}
+	db.UpsertIssue(&Issue{RepoID: other.ID, Number: 2, Title: "Other", State: "open", CurrentStatus: "ready", GHCreatedAt: now, GHUpdatedAt: now})
+
+	goneID, _ := db.GetIssueIDByNumber(repo.ID, 2)
+	db.RecordStatusTimestamps(goneID, map[string]time.Time{"review": now})
+	db.RecordBlockedPeriod(goneID, &now, nil, "waiting")
+	pr := &PullRequest{RepoID: repo.ID, Number: 10, Title: "Fix", State: "OPEN", GHCreatedAt: now, GHUpdatedAt: now}
+	db.UpsertPR(pr)
+	db.LinkPRToIssue(pr.ID, goneID)
+
+	pruned, err := db.PruneIssues(repo.ID, []int{1, 3, 4})
+	if err != nil {
+		t.Fatalf("PruneIssues() error: %v", err)
+	}
+	if len(pruned) != 1 || pruned[0] != 2 {
+		t.Fatalf("PruneIssues() = %v, want [2]", pruned)
+	}
+
+	statuses, _ := db.GetIssueStatuses(repo.ID)
+	if _, ok := statuses[2]; ok || len(statuses) != 2 {
+		t.Errorf("issues left after prune = %v, want #1 and #3", statuses)
+	}
+	for _, table := range []string{"status_transitions", "status_timestamps", "blocked_periods", "pr_issue_links"} {
+		var count int
+		db.QueryRow("SELECT COUNT(*) FROM "+table+" WHERE issue_id = ?", goneID).Scan(&count)
+		if count != 0 {
+			t.Errorf("%s has %d rows for the pruned issue, want 0", table, count)
+		}
+	}
+
+	// Other repos keep their issues, and a second prune finds nothing
+	if statuses, _ := db.GetIssueStatuses(other.ID); len(statuses) != 1 {
+		t.Errorf("other repo has %d issues after prune, want 1", len(statuses))
+	}
+	if pruned, err := db.PruneIssues(repo.ID, []int{1, 3}); err != nil || len(pruned) != 0 {
+		t.Errorf("second PruneIssues() = %v, %v, want nothing pruned", pruned, err)
+	}
+}
+
+func TestDeleteIssues(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	db.UpsertIssue(&Issue{RepoID: repo.ID, Number: 1, Title: "Feature", State: "open", Author: "alice", GHCreatedAt: now, GHUpdatedAt: now})
+	db.UpsertIssue(&Issue{RepoID: repo.ID, Number: 2, Title: "Bump deps", State: "open", CurrentStatus: "ready", Author: "dependabot[bot]", GHCreatedAt: now, GHUpdatedAt: now})
+
+	var author string
+	db.QueryRow("SELECT author FROM issues WHERE number = 2").Scan(&author)
+	if author != "dependabot[bot]" {
+		t.Errorf("stored author = %q, want dependabot[bot]", author)
+	}
+
+	deleted, err := db.DeleteIssues(repo.ID, []int{2, 5})
+	if err != nil {
+		t.Fatalf("DeleteIssues() error: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0] != 2 {
+		t.Errorf("DeleteIssues() = %v, want [2]", deleted)
+	}
+	statuses, _ := db.GetIssueStatuses(repo.ID)
+	if _, ok := statuses[1]; !ok || len(statuses) != 1 {
+		t.Errorf("issues left after delete = %v, want only #1", statuses)
+	}
+	var transitions int
+	db.QueryRow("SELECT COUNT(*) FROM status_transitions").Scan(&transitions)
+	if transitions != 0 {
+		t.Errorf("%d status transitions left for the deleted issue, want 0", transitions)
+	}
+}
+
+func TestGetRepoFirstSync(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	firstSync, err := db.GetRepoFirstSync()
+	if err != nil {
+		t.Fatalf("GetRepoFirstSync() error: %v", err)
+	}
+
+	synced, ok := firstSync["testorg/myrepo"]
+	if !ok {
+		t.Fatal("GetRepoFirstSync() missing testorg/myrepo")
+	}
+	if time.Since(synced) > time.Hour {
+		t.Errorf("First sync = %v, want recent", synced)
+	}
+}
+
+func TestRecordSyncCancelled(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	syncID, err := db.RecordSyncStart(&repo.ID, "full")
+	if err != nil {
+		t.Fatalf("RecordSyncStart() error: %v", err)
+	}
+	if err := db.RecordSyncCancelled(syncID, 7, "cancelled: --timeout 1m0s expired"); err != nil {
+		t.Fatalf("RecordSyncCancelled() error: %v", err)
+	}
+
+	var status, errMsg string
+	var items int
+	err = db.QueryRow(`SELECT status, items_synced, error_message FROM sync_history WHERE id = ?`, syncID).
+		Scan(&status, &items, &errMsg)
+	if err != nil {
+		t.Fatalf("query sync_history: %v", err)
+	}
+	if status != "cancelled" {
+		t.Errorf("status = %q, want cancelled", status)
+	}
+	if items != 7 {
+		t.Errorf("items_synced = %d, want 7", items)
+	}
+	if !strings.Contains(errMsg, "--timeout") {
+		t.Errorf("error_message = %q, want timeout reason", errMsg)
+	}
+}
+
+func TestGetMilestoneIssues(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	closedAt := now.Add(-time.Hour)
+	issues := []*Issue{
+		{RepoID: repo.ID, Number: 1, Title: "Sprint done", State: "closed", GHCreatedAt: now.Add(-72 * time.Hour), GHUpdatedAt: now, GHClosedAt: &closedAt, Milestone: "Sprint 5"},
+		{RepoID: repo.ID, Number: 2, Title: "Sprint open", State: "open", GHCreatedAt: now.Add(-48 * time.Hour), GHUpdatedAt: now, Milestone: "Sprint 5"},
+		{RepoID: repo.ID, Number: 3, Title: "Other sprint", State: "open", GHCreatedAt: now, GHUpdatedAt: now, Milestone: "Sprint 6"},
+		{RepoID: repo.ID, Number: 4, Title: "No milestone", State: "open", GHCreatedAt: now, GHUpdatedAt: now},
+	}
+	for _, issue := range issues {
+		if err := db.UpsertIssue(issue); err != nil {
+			t.Fatalf("UpsertIssue() error: %v", err)
+		}
+	}
+
+	got, err := db.GetMilestoneIssues("testorg/myrepo", "Sprint 5")
+	if err != nil {
+		t.Fatalf("GetMilestoneIssues() error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("GetMilestoneIssues() returned %d issues, want 2", len(got))
+	}
+	if got[0].Number != 1 || got[0].DoneAt == nil {
+		t.Errorf("Issue #%d DoneAt = %v, want closed issue #1 first", got[0].Number, got[0].DoneAt)
+	}
+	if got[1].Number != 2 || got[1].DoneAt != nil {
+		t.Errorf("Issue #%d DoneAt = %v, want open issue #2 with no done time", got[1].Number, got[1].DoneAt)
+	}
+}
+
+func TestInit_AddsMilestoneColumn(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// Simulate a v2 database created before issues.milestone existed
+	if _, err := db.Exec("ALTER TABLE issues DROP COLUMN milestone"); err != nil {
+		t.Fatalf("Failed to drop column: %v", err)
+	}
+	db.Exec("DELETE FROM schema_version")
+	db.Exec("INSERT INTO schema_version (version) VALUES (2)")
+
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init() error: %v", err)
+	}
+	if _, err := db.Exec("UPDATE issues SET milestone = 'x' WHERE 0"); err != nil {
+		t.Errorf("milestone column missing after Init(): %v", err)
+	}
+}
+
+func TestMigrations_CoverEveryVersion(t *testing.T) {
+	if len(migrations)+1 != SchemaVersion {
+		t.Errorf("have %d migrations for schema version %d, want %d", len(migrations), SchemaVersion, SchemaVersion-1)
+	}
+}
+
+func TestInit_MigratesV1Database(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+	now := time.Now().UTC().Truncate(time.Second)
+	if err := db.UpsertIssue(&Issue{RepoID: repo.ID, Number: 7, Title: "Survives migration", State: "open", GHCreatedAt: now, GHUpdatedAt: now}); err != nil {
+		t.Fatalf("UpsertIssue() error: %v", err)
+	}
+
+	// Reduce the database to its v1 shape
+	for _, stmt := range []string{
+		"DROP TABLE pr_issue_links",
+		"DROP TABLE pull_requests",
+		"DROP TABLE metric_baselines",
+		"DROP TABLE status_timestamps",
+		"ALTER TABLE issues DROP COLUMN milestone",
+		"DELETE FROM schema_version",
+		"INSERT INTO schema_version (version) VALUES (1)",
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init() error: %v", err)
+	}
+
+	for _, table := range []string{"pull_requests", "pr_issue_links", "metric_baselines", "status_timestamps"} {
+		var name string
+		if err := db.QueryRow("SELECT name FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&name); err != nil {
+			t.Errorf("table %s missing after migration: %v", table, err)
+		}
+	}
+	if _, err := db.Exec("UPDATE issues SET milestone = 'x' WHERE 0"); err != nil {
+		t.Errorf("milestone column missing after migration: %v", err)
+	}
+
+	issue, err := db.GetIssueByRepoAndNumber(repo.ID, 7)
+	if err != nil {
+		t.Fatalf("issue lost during migration: %v", err)
+	}
+	if issue.Title != "Survives migration" {
+		t.Errorf("Title = %q, want %q", issue.Title, "Survives migration")
+	}
+
+	// Every intermediate version is recorded
+	var count, version int
+	db.QueryRow("SELECT COUNT(*), MAX(version) FROM schema_version").Scan(&count, &version)
+	if version != SchemaVersion || count != SchemaVersion {
+		t.Errorf("schema_version has %d rows up to %d, want %d rows up to %d", count, version, SchemaVersion, SchemaVersion)
+	}
+}
+
+func TestInit_NormalizesTimestamps(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+	issue := &Issue{RepoID: repo.ID, Number: 1, Title: "Old format", State: "closed", GHCreatedAt: time.Now(), GHUpdatedAt: time.Now()}
+	if err := db.UpsertIssue(issue); err != nil {
+		t.Fatalf("UpsertIssue() error: %v", err)
+	}
+
+	// Timestamps as a v5 database stored them
+	_, err := db.Exec(`UPDATE issues SET gh_created_at = '2024-03-01 08:00:00 +0000 UTC',
+		gh_closed_at = '2024-03-04T08:00:00Z', entered_progress_at = '2024-03-02 08:00:00.5 +0000 UTC' WHERE id = ?`, issue.ID)
+	if err != nil {
+		t.Fatalf("failed to set old timestamps: %v", err)
+	}
+	db.Exec("DELETE FROM schema_version")
+	db.Exec("INSERT INTO schema_version (version) VALUES (5)")
+
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init() error: %v", err)
+	}
+
+	var created, closed, progress string
+	db.QueryRow(`SELECT CAST(gh_created_at AS TEXT), CAST(gh_closed_at AS TEXT), CAST(entered_progress_at AS TEXT)
+		FROM issues WHERE id = ?`, issue.ID).Scan(&created, &closed, &progress)
+	if created != "2024-03-01 08:00:00" || closed != "2024-03-04 08:00:00" || progress != "2024-03-02 08:00:00" {
+		t.Errorf("timestamps = %q, %q, %q; want SQLite datetime format", created, closed, progress)
+	}
+
+	if err := db.RecalcCycleTime(issue.ID); err != nil {
+		t.Fatalf("RecalcCycleTime() error: %v", err)
+	}
+	var cycle sql.NullFloat64
+	db.QueryRow("SELECT cycle_time_hours FROM issues WHERE id = ?", issue.ID).Scan(&cycle)
+	if !cycle.Valid || math.Abs(cycle.Float64-48) > 0.01 {
+		t.Errorf("cycle_time_hours = %v, want 48", cycle)
+	}
+}
+
+func TestInit_NormalizesEventTimestamps(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+	now := time.Now().UTC()
+	issue := &Issue{RepoID: repo.ID, Number: 1, Title: "Blocked", State: "open", CurrentStatus: "ready",
+		IsBlocked: true, GHCreatedAt: now, GHUpdatedAt: now}
+	if err := db.UpsertIssue(issue); err != nil {
+		t.Fatalf("UpsertIssue() error: %v", err)
+	}
+
+	// A blocked period and baseline as a v10 database stored them
+	blockedAt := now.Add(-48 * time.Hour).Truncate(time.Second)
+	if _, err := db.Exec(`INSERT INTO blocked_periods (issue_id, blocked_at, reason) VALUES (?, ?, 'waiting')`,
+		issue.ID, blockedAt.In(time.FixedZone("EET", 2*60*60)).String()); err != nil {
+		t.Fatalf("failed to insert blocked period: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO metric_baselines (name, repo, metrics_json, created_at)
+		VALUES ('before', 'myrepo', '{}', '2024-03-01T08:00:00Z')`); err != nil {
+		t.Fatalf("failed to insert baseline: %v", err)
+	}
+	db.Exec("DELETE FROM schema_version")
+	db.Exec("INSERT INTO schema_version (version) VALUES (10)")
+
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init() error: %v", err)
+	}
+
+	var blocked, baseline string
+	db.QueryRow("SELECT CAST(blocked_at AS TEXT) FROM blocked_periods").Scan(&blocked)
+	db.QueryRow("SELECT CAST(created_at AS TEXT) FROM metric_baselines").Scan(&baseline)
+	if blocked != sqlTime(blockedAt) || baseline != "2024-03-01 08:00:00" {
+		t.Errorf("timestamps = %q, %q; want UTC in SQLite datetime format", blocked, baseline)
+	}
+
+	issues, err := db.GetBlockedIssues("")
+	if err != nil {
+		t.Fatalf("GetBlockedIssues() error: %v", err)
+	}
+	if len(issues) != 1 || math.Abs(issues[0].BlockedHours-48) > 0.1 {
+		t.Errorf("GetBlockedIssues() = %+v, want one issue blocked ~48h", issues)
+	}
+}
+
+func TestSaveMetricsSnapshot_ReplacesDay(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	today := time.Now().UTC()
+	for _, m := range []MetricsDaily{
+		{RepoID: repo.ID, SnapshotDate: today.AddDate(0, 0, -1), WIPInProgress: 1, Throughput30d: 3},
+		{RepoID: repo.ID, SnapshotDate: today, WIPInProgress: 2, Throughput30d: 4},
+		{RepoID: repo.ID, SnapshotDate: today, WIPInProgress: 5, Throughput30d: 6, LeadTimeAvg30d: 2.5},
+	} {
+		if err := db.SaveMetricsSnapshot(&m); err != nil {
+			t.Fatalf("SaveMetricsSnapshot() error: %v", err)
+		}
+	}
+
+	history, err := db.GetMetricsHistory(repo.ID, 7)
+	if err != nil {
+		t.Fatalf("GetMetricsHistory() error: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("GetMetricsHistory() returned %d snapshots, want 2 (one per day)", len(history))
+	}
+	last := history[1]
+	if last.WIPInProgress != 5 || last.Throughput30d != 6 || last.LeadTimeAvg30d != 2.5 {
+		t.Errorf("today's snapshot = %+v, want the second save of the day", last)
+	}
+}
+
+func TestSaveAndGetBaseline(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	first := []MetricBaseline{
+		{Repo: "api", Metrics: json.RawMessage(`{"flow_load":10}`), CreatedAt: now},
+		{Repo: "web", Metrics: json.RawMessage(`{"flow_load":4}`), CreatedAt: now},
+	}
+	if err := db.SaveBaselines("before", first); err != nil {
+		t.Fatalf("SaveBaselines() error: %v", err)
+	}
+
+	// Saving again under the same name replaces the whole set
+	second := []MetricBaseline{{Repo: "api", Metrics: json.RawMessage(`{"flow_load":7}`), CreatedAt: now}}
+	if err := db.SaveBaselines("before", second); err != nil {
+		t.Fatalf("SaveBaselines() error: %v", err)
+	}
+
+	got, err := db.GetBaseline("before")
+	if err != nil {
+		t.Fatalf("GetBaseline() error: %v", err)
+	}
+	if len(got) != 1 || got[0].Repo != "api" {
+		t.Fatalf("GetBaseline() = %+v, want only api", got)
+	}
+	if string(got[0].Metrics) != `{"flow_load":7}` {
+		t.Errorf("GetBaseline() metrics = %s, want the replaced value", got[0].Metrics)
+	}
+	if !got[0].CreatedAt.Equal(now) {
+		t.Errorf("GetBaseline() created_at = %v, want %v", got[0].CreatedAt, now)
+	}
+
+	missing, err := db.GetBaseline("nope")
+	if err != nil {
+		t.Fatalf("GetBaseline() error: %v", err)
+	}
+	if len(missing) != 0 {
+		t.Errorf("GetBaseline(nope) returned %d rows, want 0", len(missing))
+	}
+
+	db.SaveBaselines("after", first)
+	names, err := db.GetBaselineNames()
+	if err != nil {
+		t.Fatalf("GetBaselineNames() error: %v", err)
+	}
+	if want := []string{"after", "before"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("GetBaselineNames() = %v, want %v", names, want)
+	}
+}
+
+func TestRecordStatusTransition(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	now := time.Now()
+	issue := &Issue{
+		RepoID:      repo.ID,
+		Number:      1,
+		Title:       "Test Issue",
+		State:       "open",
+		GHCreatedAt: now,
+		GHUpdatedAt: now,
+	}
+	db.UpsertIssue(issue)
+
+	// Record transition
 	transitionTime := now.Add(-1 * time.Hour)
 	err := db.RecordStatusTransition(issue.ID, "backlog", "in-progress", transitionTime)
 	if err != nil {
@@ -447,6 +2580,296 @@ func TestRecordStatusTransition(t *testing.T) {
 	}
 }
 
+func TestGetStatusTransitions(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	moved := &Issue{RepoID: repo.ID, Number: 1, Title: "Moved", State: "open", GHCreatedAt: now, GHUpdatedAt: now}
+	single := &Issue{RepoID: repo.ID, Number: 2, Title: "Single", State: "open", GHCreatedAt: now, GHUpdatedAt: now}
+	db.UpsertIssue(moved)
+	db.UpsertIssue(single)
+
+	// Recorded out of order; reads must come back by transitioned_at
+	db.RecordStatusTransition(moved.ID, "review", "in-progress", now.Add(-1*time.Hour))
+	db.RecordStatusTransition(moved.ID, "", "in-progress", now.Add(-3*time.Hour))
+	db.RecordStatusTransition(moved.ID, "in-progress", "review", now.Add(-2*time.Hour))
+	db.RecordStatusTransition(single.ID, "", "backlog", now.Add(-1*time.Hour))
+
+	transitions, err := db.GetStatusTransitions(moved.ID)
+	if err != nil {
+		t.Fatalf("GetStatusTransitions() error: %v", err)
+	}
+	if len(transitions) != 3 {
+		t.Fatalf("GetStatusTransitions() returned %d transitions, want 3", len(transitions))
+	}
+	want := []string{"in-progress", "review", "in-progress"}
+	for i, tr := range transitions {
+		if tr.ToStatus != want[i] {
+			t.Errorf("transition %d to = %q, want %q", i, tr.ToStatus, want[i])
+		}
+	}
+	if transitions[0].FromStatus != "" {
+		t.Errorf("first transition from = %q, want empty", transitions[0].FromStatus)
+	}
+
+	issues, err := db.GetTransitionedIssues("testorg/myrepo")
+	if err != nil {
+		t.Fatalf("GetTransitionedIssues() error: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Number != 1 {
+		t.Errorf("GetTransitionedIssues() = %+v, want only issue #1", issues)
+	}
+}
+
+func TestGetTransitionMatrix(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+	other, _ := db.GetOrCreateRepo(org.ID, "other", "testorg/other")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	first := &Issue{RepoID: repo.ID, Number: 1, Title: "First", State: "open", GHCreatedAt: now, GHUpdatedAt: now}
+	second := &Issue{RepoID: repo.ID, Number: 2, Title: "Second", State: "open", GHCreatedAt: now, GHUpdatedAt: now}
+	elsewhere := &Issue{RepoID: other.ID, Number: 1, Title: "Elsewhere", State: "open", GHCreatedAt: now, GHUpdatedAt: now}
+	for _, issue := range []*Issue{first, second, elsewhere} {
+		db.UpsertIssue(issue)
+	}
+
+	db.RecordStatusTransition(first.ID, "", "ready", now.Add(-5*time.Hour))
+	db.RecordStatusTransition(first.ID, "ready", "in-progress", now.Add(-4*time.Hour))
+	db.RecordStatusTransition(second.ID, "ready", "in-progress", now.Add(-3*time.Hour))
+	db.RecordStatusTransition(second.ID, "in-progress", "ready", now.Add(-2*time.Hour))
+	// Outside the period and in another repo
+	db.RecordStatusTransition(second.ID, "backlog", "ready", now.AddDate(0, 0, -60))
+	db.RecordStatusTransition(elsewhere.ID, "ready", "in-progress", now.Add(-1*time.Hour))
+
+	matrix, err := db.GetTransitionMatrix(repo.ID, 30)
+	if err != nil {
+		t.Fatalf("GetTransitionMatrix() error: %v", err)
+	}
+	want := map[[2]string]int{
+		{"", "ready"}:            1,
+		{"ready", "in-progress"}: 2,
+		{"in-progress", "ready"}: 1,
+	}
+	if len(matrix) != len(want) {
+		t.Errorf("GetTransitionMatrix() = %v, want %v", matrix, want)
+	}
+	for edge, count := range want {
+		if matrix[edge] != count {
+			t.Errorf("edge %s -> %s = %d, want %d", edge[0], edge[1], matrix[edge], count)
+		}
+	}
+}
+
+func TestTriageLatency(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	triaged := &Issue{RepoID: repo.ID, Number: 1, Title: "Triaged", State: "open", GHCreatedAt: now.Add(-48 * time.Hour), GHUpdatedAt: now}
+	waiting := &Issue{RepoID: repo.ID, Number: 2, Title: "Waiting", State: "open", GHCreatedAt: now.Add(-72 * time.Hour), GHUpdatedAt: now}
+	newer := &Issue{RepoID: repo.ID, Number: 3, Title: "Newer", State: "open", GHCreatedAt: now.Add(-24 * time.Hour), GHUpdatedAt: now}
+	closed := &Issue{RepoID: repo.ID, Number: 4, Title: "Closed", State: "closed", GHCreatedAt: now.Add(-96 * time.Hour), GHUpdatedAt: now}
+	for _, issue := range []*Issue{triaged, waiting, newer, closed} {
+		db.UpsertIssue(issue)
+	}
+
+	// First status 6h after creation; the later move doesn't count
+	db.RecordStatusTransition(triaged.ID, "", "ready", now.Add(-42*time.Hour))
+	db.RecordStatusTransition(triaged.ID, "ready", "in-progress", now.Add(-10*time.Hour))
+	db.Exec("UPDATE issues SET current_status = 'in-progress' WHERE id = ?", triaged.ID)
+
+	latencies, err := db.GetTriageLatencies("testorg/myrepo", 30)
+	if err != nil {
+		t.Fatalf("GetTriageLatencies() error: %v", err)
+	}
+	if len(latencies) != 1 {
+		t.Fatalf("GetTriageLatencies() returned %d latencies, want 1", len(latencies))
+	}
+	if h := latencies[0]; h < 5.9 || h > 6.1 {
+		t.Errorf("triage latency = %.2fh, want 6h", h)
+	}
+
+	untriaged, err := db.GetUntriagedIssues("testorg/myrepo", 10)
+	if err != nil {
+		t.Fatalf("GetUntriagedIssues() error: %v", err)
+	}
+	if len(untriaged) != 2 {
+		t.Fatalf("GetUntriagedIssues() returned %d issues, want 2", len(untriaged))
+	}
+	if untriaged[0].Number != 2 || untriaged[1].Number != 3 {
+		t.Errorf("GetUntriagedIssues() order = #%d, #%d; want #2, #3", untriaged[0].Number, untriaged[1].Number)
+	}
+	if h := untriaged[0].WaitingHours; h < 71.9 || h > 72.1 {
+		t.Errorf("issue #2 waiting %.2fh, want 72h", h)
+	}
+}
+
+func TestGetBlockedIssues(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	recent := &Issue{RepoID: repo.ID, Number: 1, Title: "Blocked today", State: "open", CurrentStatus: "in-progress", IsBlocked: true, GHCreatedAt: now, GHUpdatedAt: now}
+	old := &Issue{RepoID: repo.ID, Number: 2, Title: "Blocked for days", State: "open", CurrentStatus: "review", IsBlocked: true, Assignee: "alice", GHCreatedAt: now, GHUpdatedAt: now}
+	noPeriod := &Issue{RepoID: repo.ID, Number: 3, Title: "No timeline", State: "open", CurrentStatus: "ready", IsBlocked: true, GHCreatedAt: now, GHUpdatedAt: now}
+	unblocked := &Issue{RepoID: repo.ID, Number: 4, Title: "Not blocked", State: "open", CurrentStatus: "ready", GHCreatedAt: now, GHUpdatedAt: now}
+	for _, issue := range []*Issue{recent, old, noPeriod, unblocked} {
+		db.UpsertIssue(issue)
+	}
+
+	recentStart := now.Add(-2 * time.Hour)
+	oldStart := now.Add(-96 * time.Hour)
+	earlierStart := now.Add(-200 * time.Hour)
+	earlierEnd := now.Add(-150 * time.Hour)
+	db.RecordBlockedPeriod(recent.ID, &recentStart, nil, "")
+	db.RecordBlockedPeriod(old.ID, &earlierStart, &earlierEnd, "old reason") // ended, ignored
+	db.RecordBlockedPeriod(old.ID, &oldStart, nil, "waiting on upstream fix")
+
+	issues, err := db.GetBlockedIssues("testorg/myrepo")
+	if err != nil {
+		t.Fatalf("GetBlockedIssues() error: %v", err)
+	}
+	if len(issues) != 3 {
+		t.Fatalf("GetBlockedIssues() returned %d issues, want 3", len(issues))
+	}
+
+	wantOrder := []int{2, 1, 3}
+	for i, issue := range issues {
+		if issue.Number != wantOrder[i] {
+			t.Errorf("issues[%d] = #%d, want #%d", i, issue.Number, wantOrder[i])
+		}
+	}
+	if h := issues[0].BlockedHours; h < 95 || h > 97 {
+		t.Errorf("issue #2 blocked %.1fh, want ~96h", h)
+	}
+	if issues[0].Assignee != "alice" {
+		t.Errorf("issue #2 assignee = %q, want alice", issues[0].Assignee)
+	}
+	if issues[0].Reason != "waiting on upstream fix" {
+		t.Errorf("issue #2 reason = %q, want the open period's reason", issues[0].Reason)
+	}
+	if issues[1].Reason != "" {
+		t.Errorf("issue #1 reason = %q, want empty", issues[1].Reason)
+	}
+	if issues[2].BlockedSince != nil || issues[2].BlockedHours != 0 {
+		t.Errorf("issue #3 has no open blocked period, got since=%v hours=%.1f", issues[2].BlockedSince, issues[2].BlockedHours)
+	}
+}
+
+func TestBlockIssue(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+	now := time.Now().UTC().Truncate(time.Second)
+	issue := &Issue{RepoID: repo.ID, Number: 1, Title: "Needs API", State: "open", GHCreatedAt: now, GHUpdatedAt: now}
+	db.UpsertIssue(issue)
+
+	blockedAt := now.Add(-3 * time.Hour)
+	if err := db.BlockIssue(issue.ID, blockedAt, "waiting on API"); err != nil {
+		t.Fatalf("BlockIssue() error: %v", err)
+	}
+	if err := db.BlockIssue(issue.ID, now, ""); !errors.Is(err, ErrAlreadyBlocked) {
+		t.Errorf("BlockIssue() twice error = %v, want ErrAlreadyBlocked", err)
+	}
+
+	// A sync without a blocked label keeps the manual block
+	db.UpsertIssueBatch([]*Issue{{RepoID: repo.ID, Number: 1, Title: "Needs API", State: "open", GHCreatedAt: now, GHUpdatedAt: now}})
+	blocked, _ := db.GetBlockedIssues("")
+	if len(blocked) != 1 || blocked[0].Reason != "waiting on API" {
+		t.Fatalf("GetBlockedIssues() after sync = %+v, want #1 blocked waiting on API", blocked)
+	}
+
+	if err := db.UnblockIssue(issue.ID, now); err != nil {
+		t.Fatalf("UnblockIssue() error: %v", err)
+	}
+	if err := db.UnblockIssue(issue.ID, now); !errors.Is(err, ErrNotBlocked) {
+		t.Errorf("UnblockIssue() twice error = %v, want ErrNotBlocked", err)
+	}
+
+	// The ended period's time survives the next sync
+	db.UpsertIssue(&Issue{RepoID: repo.ID, Number: 1, Title: "Needs API", State: "open", GHCreatedAt: now, GHUpdatedAt: now})
+	got, _ := db.GetIssueByRepoAndNumber(repo.ID, 1)
+	if got.IsBlocked {
+		t.Error("issue still blocked after UnblockIssue")
+	}
+	if got.BlockedTimeHours != 3 {
+		t.Errorf("BlockedTimeHours = %v, want 3", got.BlockedTimeHours)
+	}
+
+	periods, _ := db.GetBlockedPeriods(issue.ID)
+	if len(periods) != 1 || !periods[0].Manual || periods[0].UnblockedAt == nil || periods[0].DurationHours != 3 {
+		t.Errorf("GetBlockedPeriods() = %+v, want one ended 3h manual period", periods)
+	}
+}
+
+func TestGetPRsForIssue(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	now := time.Now().UTC().Truncate(time.Second)
+	issue := &Issue{RepoID: repo.ID, Number: 7, Title: "Linked issue", State: "open", GHCreatedAt: now, GHUpdatedAt: now}
+	if err := db.UpsertIssue(issue); err != nil {
+		t.Fatalf("UpsertIssue() error: %v", err)
+	}
+	issueID, err := db.GetIssueIDByNumber(repo.ID, 7)
+	if err != nil {
+		t.Fatalf("GetIssueIDByNumber() error: %v", err)
+	}
+
+	for _, pr := range []*PullRequest{
+		{RepoID: repo.ID, Number: 10, Title: "First fix", State: "MERGED", GHCreatedAt: now, GHUpdatedAt: now},
+		{RepoID: repo.ID, Number: 11, Title: "Follow-up", State: "OPEN", GHCreatedAt: now, GHUpdatedAt: now},
+		{RepoID: repo.ID, Number: 12, Title: "Unrelated", State: "OPEN", GHCreatedAt: now, GHUpdatedAt: now},
+	} {
+		if err := db.UpsertPR(pr); err != nil {
+			t.Fatalf("UpsertPR() error: %v", err)
+		}
+		if pr.Number != 12 {
+			db.LinkPRToIssue(pr.ID, issueID)
+		}
+	}
+
+	repoID, err := db.GetRepoID("testorg/myrepo")
+	if err != nil || repoID != repo.ID {
+		t.Fatalf("GetRepoID() = %d, %v; want %d", repoID, err, repo.ID)
+	}
+
+	prs, err := db.GetPRsForIssue(repo.ID, 7)
+	if err != nil {
+		t.Fatalf("GetPRsForIssue() error: %v", err)
+	}
+	if len(prs) != 2 {
+		t.Fatalf("GetPRsForIssue() returned %d PRs, want 2", len(prs))
+	}
+	if prs[0].Number != 11 || prs[1].Number != 10 {
+		t.Errorf("PRs = #%d, #%d; want #11, #10", prs[0].Number, prs[1].Number)
+	}
+
+	linked, err := db.GetLinkedIssues(prs[0].ID)
+	if err != nil || len(linked) != 1 || linked[0] != 7 {
+		t.Errorf("GetLinkedIssues() = %v, %v; want [7]", linked, err)
+	}
+}
+
 func TestSaveCFDSnapshot(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -473,6 +2896,110 @@ func TestSaveCFDSnapshot(t *testing.T) {
 	}
 }
 
+func TestGetIssueStatusHistory(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return base.AddDate(0, 0, n) }
+	closed := day(6)
+
+	// #1: created day 0, ready day 1 (timeline), in-progress day 3, closed day 6
+	db.UpsertIssue(&Issue{RepoID: repo.ID, Number: 1, Title: "Tracked", State: "closed", GHCreatedAt: day(0), GHUpdatedAt: day(6), GHClosedAt: &closed})
+	id1, _ := db.GetIssueIDByNumber(repo.ID, 1)
+	db.RecordStatusTimestamps(id1, map[string]time.Time{"ready": day(1)})
+	db.RecordStatusTransition(id1, "ready", "in-progress", day(3))
+
+	// #2: moved from ready to review on day 4, nothing recorded earlier
+	db.UpsertIssue(&Issue{RepoID: repo.ID, Number: 2, Title: "Late", State: "open", GHCreatedAt: day(0), GHUpdatedAt: day(4)})
+	id2, _ := db.GetIssueIDByNumber(repo.ID, 2)
+	db.RecordStatusTransition(id2, "ready", "review", day(4))
+
+	history, err := db.GetIssueStatusHistory(repo.ID)
+	if err != nil {
+		t.Fatalf("GetIssueStatusHistory() error: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("GetIssueStatusHistory() returned %d issues, want 2", len(history))
+	}
+
+	tracked, late := history[0], history[1]
+	tests := []struct {
+		name      string
+		h         IssueStatusHistory
+		at        time.Time
+		wantOpen  bool
+		want      string
+		wantKnown bool
+	}{
+		{"before any status", tracked, day(0).Add(time.Hour), true, "none", false},
+		{"timeline entry", tracked, day(2), true, "ready", true},
+		{"transition", tracked, day(5), true, "in-progress", true},
+		{"closed", tracked, day(7), false, "in-progress", true},
+		{"before first change, from its from_status", late, day(2), true, "ready", true},
+		{"after first transition", late, day(5), true, "review", true},
+		{"not created yet", late, day(-1), false, "ready", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.h.OpenAt(tt.at); got != tt.wantOpen {
+				t.Errorf("OpenAt() = %v, want %v", got, tt.wantOpen)
+			}
+			status, known := tt.h.StatusAt(tt.at)
+			if status != tt.want || known != tt.wantKnown {
+				t.Errorf("StatusAt() = %q, %v, want %q, %v", status, known, tt.want, tt.wantKnown)
+			}
+		})
+	}
+}
+
+func TestGetAggregateCFDData(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	api, _ := db.GetOrCreateRepo(org.ID, "api", "testorg/api")
+	web, _ := db.GetOrCreateRepo(org.ID, "web", "testorg/web")
+	otherOrg, _ := db.GetOrCreateOrg("otherorg")
+	other, _ := db.GetOrCreateRepo(otherOrg.ID, "api", "otherorg/api")
+
+	today := time.Now().UTC()
+	yesterday := today.Add(-24 * time.Hour)
+	db.SaveCFDSnapshot(api.ID, yesterday, map[string]int{"backlog": 4, "done": 1})
+	db.SaveCFDSnapshot(api.ID, today, map[string]int{"backlog": 3, "done": 2})
+	db.SaveCFDSnapshot(web.ID, today, map[string]int{"backlog": 2, "in-progress": 1})
+	db.SaveCFDSnapshot(other.ID, today, map[string]int{"backlog": 50})
+
+	data, err := db.GetAggregateCFDData(org.ID, 30)
+	if err != nil {
+		t.Fatalf("GetAggregateCFDData() error: %v", err)
+	}
+
+	got := make(map[string]int)
+	for _, d := range data {
+		got[d.Date[:10]+" "+d.Status] = d.Count
+	}
+	day, prev := today.Format("2006-01-02"), yesterday.Format("2006-01-02")
+	want := map[string]int{
+		prev + " backlog":    4,
+		prev + " done":       1,
+		day + " backlog":     5,
+		day + " done":        2,
+		day + " in-progress": 1,
+	}
+	if len(got) != len(want) {
+		t.Errorf("GetAggregateCFDData() = %v, want %v", got, want)
+	}
+	for key, count := range want {
+		if got[key] != count {
+			t.Errorf("%s = %d, want %d", key, got[key], count)
+		}
+	}
+}
+
 func TestGetStats(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -506,3 +3033,48 @@ func TestGetStats(t *testing.T) {
 		t.Errorf("Issues = %d, want 1", stats.Issues)
 	}
 }
+
+func TestGetPRSummary_AvgReviewTime(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	org, _ := db.GetOrCreateOrg("testorg")
+	repo, _ := db.GetOrCreateRepo(org.ID, "myrepo", "testorg/myrepo")
+	now := time.Now().UTC().Truncate(time.Second)
+	merged := now.Add(-24 * time.Hour)
+
+	prs := []*PullRequest{
+		{Number: 1, State: "MERGED", GHMergedAt: &merged, ReviewTimeHours: 4, MergeTimeHours: 10},
+		{Number: 2, State: "MERGED", GHMergedAt: &merged, ReviewTimeHours: 8, MergeTimeHours: 20},
+		// Merged without a review: no review time to average
+		{Number: 3, State: "MERGED", GHMergedAt: &merged, MergeTimeHours: 2},
+		// Still open: reviewed, but only merged PRs are summarised
+		{Number: 4, State: "OPEN", ReviewTimeHours: 100},
+		{Number: 5, State: "OPEN", IsDraft: true},
+	}
+	for _, pr := range prs {
+		pr.RepoID, pr.Title = repo.ID, "PR"
+		pr.GHCreatedAt, pr.GHUpdatedAt = now.Add(-48*time.Hour), now
+		if err := db.UpsertPR(pr); err != nil {
+			t.Fatalf("UpsertPR() error: %v", err)
+		}
+	}
+
+	summary, err := db.GetPRSummary("testorg/myrepo")
+	if err != nil {
+		t.Fatalf("GetPRSummary() error: %v", err)
+	}
+	if summary.AvgReviewTimeHrs != 6 {
+		t.Errorf("AvgReviewTimeHrs = %v, want 6 (unreviewed and open PRs left out)", summary.AvgReviewTimeHrs)
+	}
+	if summary.OpenPRs != 2 || summary.DraftPRs != 1 || summary.MergedLast30d != 3 {
+		t.Errorf("summary = %+v, want 2 open, 1 draft, 3 merged", summary)
+	}
+
+	// No reviewed PRs at all leaves the average at zero
+	empty, _ := db.GetOrCreateRepo(org.ID, "empty", "testorg/empty")
+	db.UpsertPR(&PullRequest{RepoID: empty.ID, Number: 1, Title: "PR", State: "OPEN", GHCreatedAt: now, GHUpdatedAt: now})
+	if summary, err := db.GetPRSummary("testorg/empty"); err != nil || summary.AvgReviewTimeHrs != 0 {
+		t.Errorf("GetPRSummary() without reviews = %+v, %v; want a zero average", summary, err)
+	}
+}
@@ -0,0 +1,222 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// migrations upgrade an existing database one schema version at a time:
+// migrations[i] takes version i+1 to version i+2. Fresh databases get the
+// full Schema instead. Append a migration whenever SchemaVersion is bumped,
+// and never edit one that has shipped.
+var migrations = []func(*sql.Tx) error{
+	migrateV2PullRequests,
+	migrateV3IssueMilestone,
+	migrateV4MetricBaselines,
+	migrateV5StatusTimestamps,
+	migrateV6NormalizeTimestamps,
+	migrateV7IssueAuthor,
+	migrateV8IssueSizePoints,
+	migrateV9BlockedPeriodManual,
+	migrateV10IssueStateReason,
+	migrateV11NormalizeEventTimestamps,
+}
+
+// Version 2: pull_requests and pr_issue_links tables
+func migrateV2PullRequests(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+CREATE TABLE IF NOT EXISTS pull_requests (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    repo_id         INTEGER NOT NULL REFERENCES repositories(id),
+    number          INTEGER NOT NULL,
+    title           TEXT NOT NULL,
+    state           TEXT NOT NULL,
+    is_draft        BOOLEAN DEFAULT FALSE,
+
+    gh_created_at   DATETIME NOT NULL,
+    gh_updated_at   DATETIME NOT NULL,
+    gh_merged_at    DATETIME,
+    gh_closed_at    DATETIME,
+
+    author          TEXT,
+    additions       INTEGER DEFAULT 0,
+    deletions       INTEGER DEFAULT 0,
+    changed_files   INTEGER DEFAULT 0,
+
+    review_time_hours    REAL,
+    merge_time_hours     REAL,
+
+    created_at      DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at      DATETIME DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(repo_id, number)
+);
+
+CREATE TABLE IF NOT EXISTS pr_issue_links (
+    pr_id           INTEGER NOT NULL REFERENCES pull_requests(id),
+    issue_id        INTEGER NOT NULL REFERENCES issues(id),
+    created_at      DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (pr_id, issue_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_prs_repo_state ON pull_requests(repo_id, state);
+CREATE INDEX IF NOT EXISTS idx_prs_author ON pull_requests(author);
+CREATE INDEX IF NOT EXISTS idx_pr_links_pr ON pr_issue_links(pr_id);
+CREATE INDEX IF NOT EXISTS idx_pr_links_issue ON pr_issue_links(issue_id);`)
+	return err
+}
+
+// Version 3: issues.milestone
+func migrateV3IssueMilestone(tx *sql.Tx) error {
+	return addColumnIfMissing(tx, "issues", "milestone", "TEXT")
+}
+
+// Version 4: metric_baselines table
+func migrateV4MetricBaselines(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+CREATE TABLE IF NOT EXISTS metric_baselines (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    name            TEXT NOT NULL,
+    repo            TEXT NOT NULL,
+    metrics_json    TEXT NOT NULL,
+    created_at      DATETIME NOT NULL,
+    UNIQUE(name, repo)
+);`)
+	return err
+}
+
+// Version 5: status_timestamps table, backfilled from the entered_*_at columns
+func migrateV5StatusTimestamps(tx *sql.Tx) error {
+	if _, err := tx.Exec(`
+CREATE TABLE IF NOT EXISTS status_timestamps (
+    issue_id        INTEGER NOT NULL REFERENCES issues(id),
+    status          TEXT NOT NULL,
+    entered_at      DATETIME NOT NULL,
+    PRIMARY KEY (issue_id, status)
+);`); err != nil {
+		return err
+	}
+
+	for status, column := range statusColumns {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO status_timestamps (issue_id, status, entered_at)
+			SELECT id, ?, `+column+` FROM issues WHERE `+column+` IS NOT NULL`, status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Version 6: issue timestamps rewritten from Go's time.String() format
+// ("2006-01-02 15:04:05 +0000 UTC") or RFC3339 to timeFormat. Values SQLite
+// can't parse are left as they are.
+func migrateV6NormalizeTimestamps(tx *sql.Tx) error {
+	columns := []string{"gh_created_at", "gh_updated_at", "gh_closed_at",
+		"entered_ready_at", "entered_progress_at", "entered_review_at", "entered_testing_at", "entered_done_at"}
+	for _, column := range columns {
+		if _, err := tx.Exec(`UPDATE issues SET ` + column + ` = COALESCE(
+			datetime(REPLACE(REPLACE(` + column + `, ' +0000 UTC', ''), ' UTC', '')), ` + column + `)
+			WHERE ` + column + ` IS NOT NULL`); err != nil {
+			return err
+		}
+	}
+	_, err := tx.Exec(`UPDATE status_timestamps SET entered_at = COALESCE(
+		datetime(REPLACE(REPLACE(entered_at, ' +0000 UTC', ''), ' UTC', '')), entered_at)`)
+	return err
+}
+
+// migrate applies every migration above version, each in its own
+// transaction, recording the version reached after each one
+func (db *DB) migrate(version int) error {
+	for v := version + 1; v <= SchemaVersion; v++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+
+		if err := migrations[v-2](tx); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration to schema version %d failed: %w", v, err)
+		}
+		if _, err := tx.Exec("INSERT OR REPLACE INTO schema_version (version) VALUES (?)", v); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to record schema version %d: %w", v, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("migration to schema version %d failed: %w", v, err)
+		}
+	}
+	return nil
+}
+
+// addColumnIfMissing adds a column to an existing table if it isn't there yet
+func addColumnIfMissing(tx *sql.Tx, table, column, definition string) error {
+	rows, err := tx.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, ctype string
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &name, &ctype, &notNull, &dflt, &pk); err != nil {
+			return fmt.Errorf("failed to inspect %s: %w", table, err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	rows.Close()
+
+	if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add %s.%s: %w", table, column, err)
+	}
+	return nil
+}
+
+// Version 7: issues.author
+func migrateV7IssueAuthor(tx *sql.Tx) error {
+	return addColumnIfMissing(tx, "issues", "author", "TEXT")
+}
+
+// Version 8: issues.size_points
+func migrateV8IssueSizePoints(tx *sql.Tx) error {
+	return addColumnIfMissing(tx, "issues", "size_points", "REAL")
+}
+
+// Version 9: blocked_periods.manual
+func migrateV9BlockedPeriodManual(tx *sql.Tx) error {
+	return addColumnIfMissing(tx, "blocked_periods", "manual", "BOOLEAN DEFAULT FALSE")
+}
+
+// Version 10: issues.state_reason
+func migrateV10IssueStateReason(tx *sql.Tx) error {
+	return addColumnIfMissing(tx, "issues", "state_reason", "TEXT")
+}
+
+// Version 11: timestamps that blocked periods, baselines, pull requests and
+// imports stored as driver-formatted time.Time values rewritten to
+// timeFormat, so queries can use julianday() on them directly
+func migrateV11NormalizeEventTimestamps(tx *sql.Tx) error {
+	tables := []struct {
+		name    string
+		columns []string
+	}{
+		{"organizations", []string{"created_at"}},
+		{"repositories", []string{"last_sync_at", "created_at"}},
+		{"issues", issueTimeColumns},
+		{"pull_requests", []string{"gh_created_at", "gh_updated_at", "gh_merged_at", "gh_closed_at"}},
+		{"status_transitions", []string{"transitioned_at", "created_at"}},
+		{"blocked_periods", []string{"blocked_at", "unblocked_at", "created_at"}},
+		{"metrics_daily", []string{"created_at"}},
+		{"metric_baselines", []string{"created_at"}},
+	}
+	for _, table := range tables {
+		if _, _, err := normalizeTimeColumns(tx, table.name, table.columns); err != nil {
+			return err
+		}
+	}
+	return nil
+}
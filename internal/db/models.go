@@ -1,6 +1,7 @@
 package db
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -48,12 +49,18 @@ type Issue struct {
 	GHUpdatedAt time.Time  `json:"gh_updated_at"`
 	GHClosedAt  *time.Time `json:"gh_closed_at,omitempty"`
 
+	// Why a closed issue was closed: completed, not_planned or duplicate.
+	// Empty for open issues and closures cached before it was tracked.
+	StateReason string `json:"state_reason,omitempty"`
+
 	CurrentStatus   string `json:"current_status,omitempty"`
 	CurrentPriority string `json:"current_priority,omitempty"`
 	CurrentType     string `json:"current_type,omitempty"`
 	CurrentSize     string `json:"current_size,omitempty"`
 	IsBlocked       bool   `json:"is_blocked"`
 	Assignee        string `json:"assignee,omitempty"`
+	Milestone       string `json:"milestone,omitempty"`
+	Author          string `json:"author,omitempty"`
 
 	EnteredReadyAt    *time.Time `json:"entered_ready_at,omitempty"`
 	EnteredProgressAt *time.Time `json:"entered_progress_at,omitempty"`
@@ -65,6 +72,9 @@ type Issue struct {
 	CycleTimeHours   float64 `json:"cycle_time_hours,omitempty"`
 	BlockedTimeHours float64 `json:"blocked_time_hours,omitempty"`
 
+	// Story points from the size label (settings.size_points), closed issues only
+	SizePoints *float64 `json:"size_points,omitempty"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -79,6 +89,13 @@ type StatusTransition struct {
 	CreatedAt     time.Time `json:"created_at"`
 }
 
+// StatusTimestamp is when an issue first entered a status
+type StatusTimestamp struct {
+	IssueID   int64     `json:"issue_id"`
+	Status    string    `json:"status"`
+	EnteredAt time.Time `json:"entered_at"`
+}
+
 // BlockedPeriod represents a period when an issue was blocked
 type BlockedPeriod struct {
 	ID            int64      `json:"id"`
@@ -87,9 +104,18 @@ type BlockedPeriod struct {
 	UnblockedAt   *time.Time `json:"unblocked_at,omitempty"`
 	DurationHours float64    `json:"duration_hours"`
 	Reason        string     `json:"reason,omitempty"`
+	Manual        bool       `json:"manual,omitempty"` // recorded by 'kanban issue <n> block', not a label
 	CreatedAt     time.Time  `json:"created_at"`
 }
 
+// MetricBaseline is a named snapshot of one repo's metrics, stored as JSON
+type MetricBaseline struct {
+	Name      string          `json:"name"`
+	Repo      string          `json:"repo"`
+	Metrics   json.RawMessage `json:"metrics"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
 // MetricsDaily represents daily metrics snapshot
 type MetricsDaily struct {
 	ID           int64     `json:"id"`
@@ -121,6 +147,14 @@ type MetricsDaily struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// CFDEntry is one status count of a daily CFD snapshot
+type CFDEntry struct {
+	RepoID          int64  `json:"repo_id"`
+	SnapshotDate    string `json:"snapshot_date"`
+	Status          string `json:"status"`
+	CumulativeCount int    `json:"cumulative_count"`
+}
+
 // SyncHistory represents a sync operation record
 type SyncHistory struct {
 	ID           int64      `json:"id"`
@@ -150,6 +184,38 @@ type BoardIssue struct {
 	UpdatedAt        time.Time `json:"updated_at"`
 }
 
+// UntriagedIssue is an open issue that has never received a status
+type UntriagedIssue struct {
+	Repo         string    `json:"repo"`
+	Number       int       `json:"number"`
+	Title        string    `json:"title"`
+	CreatedAt    time.Time `json:"created_at"`
+	WaitingHours float64   `json:"waiting_hours"`
+}
+
+// IssueInStatus is an open issue with how long it has been in its current
+// status, measured from its last status transition
+type IssueInStatus struct {
+	Repo          string  `json:"repo"`
+	Number        int     `json:"number"`
+	Title         string  `json:"title"`
+	Status        string  `json:"status"`
+	Assignee      string  `json:"assignee,omitempty"`
+	HoursInStatus float64 `json:"hours_in_status"`
+}
+
+// BlockedIssue is an open issue that is currently blocked
+type BlockedIssue struct {
+	Repo         string     `json:"repo"`
+	Number       int        `json:"number"`
+	Title        string     `json:"title"`
+	Status       string     `json:"status"`
+	Assignee     string     `json:"assignee,omitempty"`
+	BlockedSince *time.Time `json:"blocked_since,omitempty"` // nil when no open blocked period was recorded
+	BlockedHours float64    `json:"blocked_hours"`
+	Reason       string     `json:"reason,omitempty"`
+}
+
 // WIPSummary represents WIP summary per status
 type WIPSummary struct {
 	Repo        string  `json:"repo"`
@@ -202,3 +268,17 @@ type PRSummary struct {
 	AvgAdditions      float64 `json:"avg_additions"`
 	AvgDeletions      float64 `json:"avg_deletions"`
 }
+
+// SearchedIssue is a cached issue matched by SearchIssues
+type SearchedIssue struct {
+	Repo      string    `json:"repo"`
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	State     string    `json:"state"`
+	Status    string    `json:"status,omitempty"`
+	Priority  string    `json:"priority,omitempty"`
+	Type      string    `json:"type,omitempty"`
+	Assignee  string    `json:"assignee,omitempty"`
+	IsBlocked bool      `json:"is_blocked"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
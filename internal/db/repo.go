@@ -2,7 +2,10 @@ package db
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"strings"
 	"time"
 )
@@ -87,18 +90,18 @@ func (db *DB) UpsertIssue(issue *Issue) error {
 		// Insert new issue
 		result, err := db.Exec(`INSERT INTO issues
 			(repo_id, number, title, state, gh_created_at, gh_updated_at, gh_closed_at,
-			current_status, current_priority, current_type, current_size, is_blocked, assignee,
+			current_status, current_priority, current_type, current_size, is_blocked, assignee, milestone, author, state_reason,
 			entered_ready_at, entered_progress_at, entered_review_at, entered_testing_at, entered_done_at,
-			lead_time_hours, cycle_time_hours, blocked_time_hours)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			lead_time_hours, cycle_time_hours, blocked_time_hours, size_points)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 			issue.RepoID, issue.Number, issue.Title, issue.State,
-			issue.GHCreatedAt, issue.GHUpdatedAt, issue.GHClosedAt,
+			sqlTime(issue.GHCreatedAt), sqlTime(issue.GHUpdatedAt), nullTime(issue.GHClosedAt),
 			nullString(issue.CurrentStatus), nullString(issue.CurrentPriority),
 			nullString(issue.CurrentType), nullString(issue.CurrentSize),
-			issue.IsBlocked, nullString(issue.Assignee),
-			issue.EnteredReadyAt, issue.EnteredProgressAt, issue.EnteredReviewAt,
-			issue.EnteredTestingAt, issue.EnteredDoneAt,
-			issue.LeadTimeHours, issue.CycleTimeHours, issue.BlockedTimeHours)
+			issue.IsBlocked, nullString(issue.Assignee), nullString(issue.Milestone), nullString(issue.Author), nullString(issue.StateReason),
+			nullTime(issue.EnteredReadyAt), nullTime(issue.EnteredProgressAt), nullTime(issue.EnteredReviewAt),
+			nullTime(issue.EnteredTestingAt), nullTime(issue.EnteredDoneAt),
+			issue.LeadTimeHours, issue.CycleTimeHours, issue.BlockedTimeHours, issue.SizePoints)
 		if err != nil {
 			return err
 		}
@@ -128,15 +131,15 @@ func (db *DB) UpsertIssue(issue *Issue) error {
 		_, err := db.Exec(`UPDATE issues SET
 			title = ?, state = ?, gh_updated_at = ?, gh_closed_at = ?,
 			current_status = ?, current_priority = ?, current_type = ?, current_size = ?,
-			is_blocked = ?, assignee = ?,
-			lead_time_hours = ?, cycle_time_hours = ?, blocked_time_hours = ?,
+			is_blocked = `+keepManualBlock+`, assignee = ?, milestone = ?, author = ?, state_reason = ?,
+			lead_time_hours = ?, cycle_time_hours = ?, blocked_time_hours = `+withManualBlockedTime+`, size_points = ?,
 			updated_at = CURRENT_TIMESTAMP
 			WHERE id = ?`,
-			issue.Title, issue.State, issue.GHUpdatedAt, issue.GHClosedAt,
+			issue.Title, issue.State, sqlTime(issue.GHUpdatedAt), nullTime(issue.GHClosedAt),
 			nullString(issue.CurrentStatus), nullString(issue.CurrentPriority),
 			nullString(issue.CurrentType), nullString(issue.CurrentSize),
-			issue.IsBlocked, nullString(issue.Assignee),
-			issue.LeadTimeHours, issue.CycleTimeHours, issue.BlockedTimeHours,
+			issue.IsBlocked, nullString(issue.Assignee), nullString(issue.Milestone), nullString(issue.Author), nullString(issue.StateReason),
+			issue.LeadTimeHours, issue.CycleTimeHours, issue.BlockedTimeHours, issue.SizePoints,
 			issue.ID)
 		if err != nil {
 			return err
@@ -147,32 +150,273 @@ func (db *DB) UpsertIssue(issue *Issue) error {
 }
 
 func (db *DB) updateStatusTimestamp(issueID int64, status string) {
-	column := ""
-	switch status {
-	case "ready":
-		column = "entered_ready_at"
-	case "in-progress":
-		column = "entered_progress_at"
-	case "review":
-		column = "entered_review_at"
-	case "testing":
-		column = "entered_testing_at"
-	case "done":
-		column = "entered_done_at"
-	}
-	if column != "" {
+	if status == "" {
+		return
+	}
+	if column, ok := statusColumns[status]; ok {
 		db.Exec("UPDATE issues SET "+column+" = CURRENT_TIMESTAMP WHERE id = ? AND "+column+" IS NULL",
 			issueID)
 	}
+	db.Exec(`INSERT OR IGNORE INTO status_timestamps (issue_id, status, entered_at)
+		VALUES (?, ?, CURRENT_TIMESTAMP)`, issueID, status)
+}
+
+// RecordStatusTimestamps records when an issue first entered each status,
+// e.g. from its timeline. Earlier times replace later ones.
+func (db *DB) RecordStatusTimestamps(issueID int64, entered map[string]time.Time) error {
+	for status, at := range entered {
+		_, err := db.Exec(`INSERT INTO status_timestamps (issue_id, status, entered_at) VALUES (?, ?, ?)
+			ON CONFLICT(issue_id, status) DO UPDATE SET entered_at = excluded.entered_at
+			WHERE excluded.entered_at < status_timestamps.entered_at`,
+			issueID, status, sqlTime(at))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetStatusTimestamps returns when an issue first entered each status
+func (db *DB) GetStatusTimestamps(issueID int64) (map[string]time.Time, error) {
+	rows, err := db.Query("SELECT status, entered_at FROM status_timestamps WHERE issue_id = ?", issueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entered := make(map[string]time.Time)
+	for rows.Next() {
+		var status string
+		var at time.Time
+		if err := rows.Scan(&status, &at); err != nil {
+			return nil, err
+		}
+		entered[status] = at
+	}
+	return entered, rows.Err()
 }
 
 // RecordStatusTransition records a status change
 func (db *DB) RecordStatusTransition(issueID int64, fromStatus, toStatus string, transitionedAt time.Time) error {
 	_, err := db.Exec(`INSERT INTO status_transitions (issue_id, from_status, to_status, transitioned_at)
-		VALUES (?, ?, ?, ?)`, issueID, nullString(fromStatus), toStatus, transitionedAt)
+		VALUES (?, ?, ?, ?)`, issueID, nullString(fromStatus), toStatus, sqlTime(transitionedAt))
 	return err
 }
 
+// GetStatusTransitions returns an issue's status transitions in the order they happened
+func (db *DB) GetStatusTransitions(issueID int64) ([]StatusTransition, error) {
+	rows, err := db.Query(`SELECT id, issue_id, from_status, to_status, transitioned_at, created_at
+		FROM status_transitions WHERE issue_id = ?
+		ORDER BY transitioned_at, id`, issueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transitions []StatusTransition
+	for rows.Next() {
+		var t StatusTransition
+		var fromStatus sql.NullString
+		if err := rows.Scan(&t.ID, &t.IssueID, &fromStatus, &t.ToStatus, &t.TransitionedAt, &t.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		t.FromStatus = fromStatus.String
+		transitions = append(transitions, t)
+	}
+	return transitions, rows.Err()
+}
+
+// GetTimeInCurrentStatus returns open issues that have a status (other than
+// done) with the time since their last status transition, longest first.
+// Issues without recorded transitions count from their creation.
+func (db *DB) GetTimeInCurrentStatus(repoFilter string) ([]IssueInStatus, error) {
+	query := `SELECT r.full_name, i.number, i.title, i.current_status, COALESCE(i.assignee, ''),
+		(julianday('now') - julianday(COALESCE(
+			(SELECT MAX(t.transitioned_at) FROM status_transitions t WHERE t.issue_id = i.id),
+			i.gh_created_at))) * 24 AS hours
+		FROM issues i
+		JOIN repositories r ON i.repo_id = r.id
+		WHERE i.state = 'open' AND i.current_status IS NOT NULL AND i.current_status NOT IN ('', 'done')`
+	var args []interface{}
+
+	if repoFilter != "" {
+		query += " AND r.full_name = ?"
+		args = append(args, repoFilter)
+	}
+	query, args = db.withoutExcluded(query, args)
+	query += " ORDER BY hours DESC"
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var issues []IssueInStatus
+	for rows.Next() {
+		var i IssueInStatus
+		var hours sql.NullFloat64
+		if err := rows.Scan(&i.Repo, &i.Number, &i.Title, &i.Status, &i.Assignee, &hours); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		i.HoursInStatus = hours.Float64
+		issues = append(issues, i)
+	}
+	return issues, rows.Err()
+}
+
+// TimeStats summarizes the dwell times GetTimeInStatus collects for one
+// status, in days
+type TimeStats struct {
+	Average float64
+	Median  float64
+	P85     float64
+	Count   int
+}
+
+// GetTimeInStatus returns how long issues stayed in each status before moving
+// on, per status. Each transition's dwell time runs to the issue's next
+// transition (LEAD over transitioned_at); the status an issue is in now has
+// no dwell time yet. Only dwells that ended in the last days count (days <= 0
+// for all).
+func (db *DB) GetTimeInStatus(repoID int64, days int) (map[string]TimeStats, error) {
+	query := `SELECT status, left_at - entered FROM (
+			SELECT t.to_status AS status, t.entered,
+				LEAD(t.entered) OVER (PARTITION BY t.issue_id ORDER BY t.entered, t.id) AS left_at
+			FROM (
+				SELECT st.id, st.issue_id, st.to_status,
+					julianday(st.transitioned_at) AS entered
+				FROM status_transitions st
+				JOIN issues i ON i.id = st.issue_id
+				WHERE i.repo_id = ?
+			) t
+		)
+		WHERE left_at IS NOT NULL AND left_at >= entered`
+	args := []interface{}{repoID}
+	if days > 0 {
+		query += " AND left_at >= julianday('now', ?)"
+		args = append(args, fmt.Sprintf("-%d days", days))
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dwell := make(map[string][]float64)
+	for rows.Next() {
+		var status string
+		var dwellDays float64
+		if err := rows.Scan(&status, &dwellDays); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		dwell[status] = append(dwell[status], dwellDays)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]TimeStats, len(dwell))
+	for status, values := range dwell {
+		stats[status] = dwellStats(values)
+	}
+	return stats, nil
+}
+
+// dwellStats computes the average, median and 85th percentile of values
+func dwellStats(values []float64) TimeStats {
+	sort.Float64s(values)
+	n := len(values)
+
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+
+	p85 := int(float64(n) * 0.85)
+	if p85 >= n {
+		p85 = n - 1
+	}
+	median := values[n/2]
+	if n%2 == 0 {
+		median = (values[n/2-1] + values[n/2]) / 2
+	}
+
+	return TimeStats{
+		Average: sum / float64(n),
+		Median:  median,
+		P85:     values[p85],
+		Count:   n,
+	}
+}
+
+// TransitionedIssue identifies an issue that has recorded status transitions
+type TransitionedIssue struct {
+	ID     int64
+	Repo   string
+	Number int
+	Title  string
+}
+
+// GetTransitionedIssues returns issues with more than one status transition,
+// optionally limited to one repo
+func (db *DB) GetTransitionedIssues(repoFilter string) ([]TransitionedIssue, error) {
+	query := `SELECT i.id, r.full_name, i.number, i.title
+		FROM issues i
+		JOIN repositories r ON i.repo_id = r.id
+		WHERE (SELECT COUNT(*) FROM status_transitions t WHERE t.issue_id = i.id) > 1`
+	var args []interface{}
+
+	if repoFilter != "" {
+		query += " AND r.full_name = ?"
+		args = append(args, repoFilter)
+	}
+	query += " ORDER BY r.full_name, i.number"
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var issues []TransitionedIssue
+	for rows.Next() {
+		var issue TransitionedIssue
+		if err := rows.Scan(&issue.ID, &issue.Repo, &issue.Number, &issue.Title); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		issues = append(issues, issue)
+	}
+	return issues, rows.Err()
+}
+
+// GetTransitionMatrix counts a repo's status transitions in the last days by
+// (from, to) edge. An issue's first recorded status has an empty from status.
+func (db *DB) GetTransitionMatrix(repoID int64, days int) (map[[2]string]int, error) {
+	rows, err := db.Query(`SELECT COALESCE(t.from_status, ''), t.to_status, COUNT(*)
+		FROM status_transitions t
+		JOIN issues i ON t.issue_id = i.id
+		WHERE i.repo_id = ?
+		AND t.transitioned_at > datetime('now', '-' || ? || ' days')
+		GROUP BY 1, 2`, repoID, days)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matrix := make(map[[2]string]int)
+	for rows.Next() {
+		var from, to string
+		var count int
+		if err := rows.Scan(&from, &to, &count); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		matrix[[2]string{from, to}] = count
+	}
+	return matrix, rows.Err()
+}
+
 // GetLabelsByRepo returns all labels for a repository
 func (db *DB) GetLabelsByRepo(repoID int64) ([]Label, error) {
 	rows, err := db.Query(`SELECT id, repo_id, name, color, description, category
@@ -228,11 +472,8 @@ func (db *DB) LabelsNeedSync(repoID int64, names, colors, descriptions []string)
 	return false, nil // All labels match
 }
 
-// UpsertLabel inserts or updates a label
+// UpsertLabel inserts or updates a label, category included
 func (db *DB) UpsertLabel(label *Label) error {
-	// Determine category from label name
-	label.Category = categorizeLabel(label.Name)
-
 	result, err := db.Exec(`INSERT INTO labels (repo_id, name, color, description, category)
 		VALUES (?, ?, ?, ?, ?)
 		ON CONFLICT(repo_id, name) DO UPDATE SET
@@ -248,26 +489,59 @@ func (db *DB) UpsertLabel(label *Label) error {
 	return nil
 }
 
-// categorizeLabel determines the category of a label
-func categorizeLabel(name string) string {
-	lower := strings.ToLower(name)
-	if strings.HasPrefix(lower, "status:") || strings.HasPrefix(lower, "status ") {
-		return "status"
-	}
-	if strings.HasPrefix(lower, "priority:") || strings.HasPrefix(lower, "priority ") {
-		return "priority"
-	}
-	if strings.HasPrefix(lower, "type:") || strings.HasPrefix(lower, "type ") {
-		return "type"
-	}
-	if strings.HasPrefix(lower, "size:") || strings.HasPrefix(lower, "size ") {
-		return "size"
-	}
-	return "special"
+// DeleteLabel removes a repo's cached label and its use on issues. GitHub
+// label names are case-insensitive, so name matches any case.
+func (db *DB) DeleteLabel(repoID int64, name string) error {
+	return db.Transaction(func(tx *Tx) error {
+		if _, err := tx.Exec(`DELETE FROM issue_labels WHERE label_id IN
+			(SELECT id FROM labels WHERE repo_id = ? AND LOWER(name) = LOWER(?))`, repoID, name); err != nil {
+			return err
+		}
+		_, err := tx.Exec("DELETE FROM labels WHERE repo_id = ? AND LOWER(name) = LOWER(?)", repoID, name)
+		return err
+	})
 }
 
-// GetBoardIssues returns issues for board display
-func (db *DB) GetBoardIssues(repoFullName string, status string) ([]BoardIssue, error) {
+// RenameLabel renames a repo's cached label, keeping the issues that carry
+// it. from matches any case; a label cached under to takes over its issues.
+// category is the category of the new name.
+func (db *DB) RenameLabel(repoID int64, from, to, category string) error {
+	return db.Transaction(func(tx *Tx) error {
+		var fromID int64
+		err := tx.QueryRow("SELECT id FROM labels WHERE repo_id = ? AND LOWER(name) = LOWER(?)", repoID, from).Scan(&fromID)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var toID int64
+		err = tx.QueryRow("SELECT id FROM labels WHERE repo_id = ? AND name = ? AND id != ?", repoID, to, fromID).Scan(&toID)
+		if err == sql.ErrNoRows {
+			_, err = tx.Exec("UPDATE labels SET name = ?, category = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+				to, category, fromID)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO issue_labels (issue_id, label_id, added_at)
+			SELECT issue_id, ?, added_at FROM issue_labels WHERE label_id = ?`, toID, fromID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE FROM issue_labels WHERE label_id = ?", fromID); err != nil {
+			return err
+		}
+		_, err = tx.Exec("DELETE FROM labels WHERE id = ?", fromID)
+		return err
+	})
+}
+
+// GetBoardIssues returns issues for board display, optionally only those of
+// the given types
+func (db *DB) GetBoardIssues(repoFullName string, status string, types ...string) ([]BoardIssue, error) {
 	query := `SELECT repo, number, title, status, priority, type, assignee, is_blocked, blocked_time_hours, age_hours, gh_created_at, gh_updated_at
 		FROM board_view WHERE 1=1`
 	args := []interface{}{}
@@ -280,6 +554,17 @@ func (db *DB) GetBoardIssues(repoFullName string, status string) ([]BoardIssue,
 		query += " AND status = ?"
 		args = append(args, status)
 	}
+	if len(types) > 0 {
+		query += " AND type IN (?" + strings.Repeat(", ?", len(types)-1) + ")"
+		for _, t := range types {
+			args = append(args, t)
+		}
+	}
+	if cond, condArgs := db.excludedCondition(); cond != "" {
+		query += ` AND NOT EXISTS (SELECT 1 FROM issues i JOIN repositories r ON i.repo_id = r.id
+			WHERE r.full_name = board_view.repo AND i.number = board_view.number AND ` + cond + `)`
+		args = append(args, condArgs...)
+	}
 
 	rows, err := db.Query(query, args...)
 	if err != nil {
@@ -321,15 +606,150 @@ func (db *DB) GetBoardIssues(repoFullName string, status string) ([]BoardIssue,
 	return issues, nil
 }
 
-// GetWIPSummary returns WIP summary
+// GetTriageLatencies returns, for issues created within the period, the hours
+// from creation to their earliest recorded status transition. Issues first
+// seen by sync with a status already set get their first transition at sync
+// time, so latencies are an upper bound until timelines are fetched.
+func (db *DB) GetTriageLatencies(repoFilter string, days int) ([]float64, error) {
+	query := `SELECT (julianday(MIN(t.transitioned_at))
+			- julianday(i.gh_created_at)) * 24
+		FROM issues i
+		JOIN repositories r ON i.repo_id = r.id
+		JOIN status_transitions t ON t.issue_id = i.id AND t.to_status != ''
+		WHERE i.gh_created_at > datetime('now', '-' || ? || ' days')`
+	args := []interface{}{days}
+
+	if repoFilter != "" {
+		query += " AND r.full_name = ?"
+		args = append(args, repoFilter)
+	}
+	query += " GROUP BY i.id"
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var latencies []float64
+	for rows.Next() {
+		var hours sql.NullFloat64
+		if err := rows.Scan(&hours); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		if !hours.Valid {
+			continue
+		}
+		if hours.Float64 < 0 {
+			hours.Float64 = 0
+		}
+		latencies = append(latencies, hours.Float64)
+	}
+	return latencies, rows.Err()
+}
+
+// GetUntriagedIssues returns open issues without a status, longest waiting first
+func (db *DB) GetUntriagedIssues(repoFilter string, limit int) ([]UntriagedIssue, error) {
+	query := `SELECT r.full_name, i.number, i.title, i.gh_created_at,
+		(julianday('now') - julianday(i.gh_created_at)) * 24
+		FROM issues i
+		JOIN repositories r ON i.repo_id = r.id
+		WHERE i.state = 'open' AND (i.current_status IS NULL OR i.current_status = '')`
+	var args []interface{}
+
+	if repoFilter != "" {
+		query += " AND r.full_name = ?"
+		args = append(args, repoFilter)
+	}
+	query += " ORDER BY i.gh_created_at LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var issues []UntriagedIssue
+	for rows.Next() {
+		var i UntriagedIssue
+		var waiting sql.NullFloat64
+		if err := rows.Scan(&i.Repo, &i.Number, &i.Title, &i.CreatedAt, &waiting); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		i.WaitingHours = waiting.Float64
+		issues = append(issues, i)
+	}
+	return issues, rows.Err()
+}
+
+// GetBlockedIssues returns open blocked issues with how long they have been
+// blocked, longest first. Duration comes from the most recent blocked period
+// that has not ended; issues without one sort last.
+func (db *DB) GetBlockedIssues(repoFilter string) ([]BlockedIssue, error) {
+	query := `SELECT r.full_name, i.number, i.title, i.current_status, i.assignee, bp.blocked_at,
+		(julianday('now') - julianday(bp.blocked_at)) * 24,
+		bp.reason
+		FROM issues i
+		JOIN repositories r ON i.repo_id = r.id
+		LEFT JOIN blocked_periods bp ON bp.id = (
+			SELECT id FROM blocked_periods
+			WHERE issue_id = i.id AND unblocked_at IS NULL
+			ORDER BY blocked_at DESC, id DESC LIMIT 1)
+		WHERE i.state = 'open' AND i.is_blocked = TRUE`
+	var args []interface{}
+
+	if repoFilter != "" {
+		query += " AND r.full_name = ?"
+		args = append(args, repoFilter)
+	}
+	query += " ORDER BY bp.blocked_at IS NULL, bp.blocked_at, r.full_name, i.number"
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var issues []BlockedIssue
+	for rows.Next() {
+		var i BlockedIssue
+		var status, assignee, reason sql.NullString
+		var blockedAt sql.NullTime
+		var blockedHours sql.NullFloat64
+		if err := rows.Scan(&i.Repo, &i.Number, &i.Title, &status, &assignee, &blockedAt, &blockedHours, &reason); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		i.Status = status.String
+		i.Assignee = assignee.String
+		i.Reason = reason.String
+		if blockedAt.Valid {
+			i.BlockedSince = &blockedAt.Time
+		}
+		if blockedHours.Valid {
+			i.BlockedHours = blockedHours.Float64
+		}
+		issues = append(issues, i)
+	}
+	return issues, rows.Err()
+}
+
+// GetWIPSummary returns WIP summary. It counts the issues of the wip_summary
+// view, less excluded ones.
 func (db *DB) GetWIPSummary(repoFullName string) ([]WIPSummary, error) {
-	query := "SELECT repo, status, count, avg_age_hours FROM wip_summary"
+	query := `SELECT r.full_name, i.current_status, COUNT(*),
+		AVG((julianday('now') - julianday(i.gh_updated_at)) * 24)
+		FROM issues i
+		JOIN repositories r ON i.repo_id = r.id
+		WHERE (i.state = 'open' OR (i.state = 'closed' AND i.current_status = 'done'))`
 	args := []interface{}{}
 
 	if repoFullName != "" {
-		query += " WHERE repo = ?"
+		query += " AND r.full_name = ?"
 		args = append(args, repoFullName)
 	}
+	query, args = db.withoutExcluded(query, args)
+	query += " GROUP BY r.full_name, i.current_status"
 
 	rows, err := db.Query(query, args...)
 	if err != nil {
@@ -363,6 +783,15 @@ func (db *DB) RecordSyncComplete(syncID int64, itemsSynced int, errMsg string) e
 	if errMsg != "" {
 		status = "failed"
 	}
+	return db.finishSync(syncID, status, itemsSynced, errMsg)
+}
+
+// RecordSyncCancelled records a sync operation that was aborted before it finished
+func (db *DB) RecordSyncCancelled(syncID int64, itemsSynced int, errMsg string) error {
+	return db.finishSync(syncID, "cancelled", itemsSynced, errMsg)
+}
+
+func (db *DB) finishSync(syncID int64, status string, itemsSynced int, errMsg string) error {
 	_, err := db.Exec(`UPDATE sync_history SET
 		completed_at = CURRENT_TIMESTAMP, status = ?, items_synced = ?, error_message = ?
 		WHERE id = ?`, status, itemsSynced, nullString(errMsg), syncID)
@@ -409,77 +838,296 @@ func (db *DB) GetMetricsHistory(repoID int64, days int) ([]MetricsDaily, error)
 	return metrics, nil
 }
 
-// RecordBlockedPeriod inserts a blocked period
-func (db *DB) RecordBlockedPeriod(issueID int64, blockedAt, unblockedAt *time.Time, reason string) error {
-	var duration float64
-	if unblockedAt != nil && blockedAt != nil {
-		duration = unblockedAt.Sub(*blockedAt).Hours()
-	}
-	_, err := db.Exec(`INSERT INTO blocked_periods (issue_id, blocked_at, unblocked_at, duration_hours, reason)
-		VALUES (?, ?, ?, ?, ?)`, issueID, blockedAt, unblockedAt, duration, nullString(reason))
-	return err
+// SaveBaselines stores a named set of per-repo metrics, replacing any
+// baseline with the same name
+func (db *DB) SaveBaselines(name string, baselines []MetricBaseline) error {
+	return db.Transaction(func(tx *Tx) error {
+		if _, err := tx.Exec("DELETE FROM metric_baselines WHERE name = ?", name); err != nil {
+			return err
+		}
+		for _, b := range baselines {
+			if _, err := tx.Exec(`INSERT INTO metric_baselines (name, repo, metrics_json, created_at)
+				VALUES (?, ?, ?, ?)`, name, b.Repo, string(b.Metrics), sqlTime(b.CreatedAt)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
-// UpdateIssueBlockedTime updates total blocked time for an issue
-func (db *DB) UpdateIssueBlockedTime(issueID int64, totalHours float64) error {
-	_, err := db.Exec("UPDATE issues SET blocked_time_hours = ?, is_blocked = ? WHERE id = ?",
-		totalHours, totalHours > 0, issueID)
-	return err
+// GetBaseline returns the per-repo metrics saved under name
+func (db *DB) GetBaseline(name string) ([]MetricBaseline, error) {
+	rows, err := db.Query(`SELECT name, repo, metrics_json, created_at
+		FROM metric_baselines WHERE name = ? ORDER BY repo`, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var baselines []MetricBaseline
+	for rows.Next() {
+		var b MetricBaseline
+		var metricsJSON string
+		if err := rows.Scan(&b.Name, &b.Repo, &metricsJSON, &b.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		b.Metrics = []byte(metricsJSON)
+		baselines = append(baselines, b)
+	}
+	return baselines, rows.Err()
 }
 
-// UpdateIssueTimestamps updates the entered_*_at timestamps from timeline data
-func (db *DB) UpdateIssueTimestamps(issueID int64, ready, progress, review, testing, done *time.Time) error {
-	_, err := db.Exec(`UPDATE issues SET
-		entered_ready_at = COALESCE(?, entered_ready_at),
-		entered_progress_at = COALESCE(?, entered_progress_at),
-		entered_review_at = COALESCE(?, entered_review_at),
-		entered_testing_at = COALESCE(?, entered_testing_at),
-		entered_done_at = COALESCE(?, entered_done_at),
-		updated_at = CURRENT_TIMESTAMP
-		WHERE id = ?`, ready, progress, review, testing, done, issueID)
+// GetBaselineNames returns the names of the saved baselines, sorted
+func (db *DB) GetBaselineNames() ([]string, error) {
+	rows, err := db.Query("SELECT DISTINCT name FROM metric_baselines ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// RecordBlockedPeriod inserts a blocked period
+func (db *DB) RecordBlockedPeriod(issueID int64, blockedAt, unblockedAt *time.Time, reason string) error {
+	var duration float64
+	if unblockedAt != nil && blockedAt != nil {
+		duration = unblockedAt.Sub(*blockedAt).Hours()
+	}
+	_, err := db.Exec(`INSERT INTO blocked_periods (issue_id, blocked_at, unblocked_at, duration_hours, reason)
+		VALUES (?, ?, ?, ?, ?)`, issueID, nullTime(blockedAt), nullTime(unblockedAt), duration, nullString(reason))
 	return err
 }
 
+// ReplaceBlockedPeriods sets an issue's label-derived blocked periods, as
+// read from its timeline, replacing those recorded by an earlier sync.
+// Manual periods from 'kanban issue <n> block' are kept.
+func (db *DB) ReplaceBlockedPeriods(issueID int64, periods []BlockedPeriod) error {
+	return db.Transaction(func(tx *Tx) error {
+		if _, err := tx.Exec("DELETE FROM blocked_periods WHERE issue_id = ? AND NOT COALESCE(manual, FALSE)", issueID); err != nil {
+			return err
+		}
+		for _, p := range periods {
+			var duration float64
+			if p.UnblockedAt != nil {
+				duration = p.UnblockedAt.Sub(p.BlockedAt).Hours()
+			}
+			if _, err := tx.Exec(`INSERT INTO blocked_periods (issue_id, blocked_at, unblocked_at, duration_hours, reason)
+				VALUES (?, ?, ?, ?, ?)`, issueID, sqlTime(p.BlockedAt), nullTime(p.UnblockedAt), duration, nullString(p.Reason)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// keepManualBlock sets is_blocked from a label-derived value (the
+// placeholder) while keeping issues with an open manual blocked period blocked
+const keepManualBlock = `(? OR EXISTS (SELECT 1 FROM blocked_periods
+	WHERE issue_id = issues.id AND manual AND unblocked_at IS NULL))`
+
+// withManualBlockedTime adds the issue's ended manual blocked periods to a
+// blocked time (the placeholder) found from labels
+const withManualBlockedTime = `(? + (SELECT COALESCE(SUM(duration_hours), 0) FROM blocked_periods
+	WHERE issue_id = issues.id AND manual AND unblocked_at IS NOT NULL))`
+
+// ErrAlreadyBlocked is returned by BlockIssue when the issue already has an
+// open manual blocked period
+var ErrAlreadyBlocked = errors.New("issue is already blocked")
+
+// ErrNotBlocked is returned by UnblockIssue when the issue has no open
+// manual blocked period
+var ErrNotBlocked = errors.New("issue has no manual block to end")
+
+// BlockIssue marks an issue blocked from at with a manual blocked period,
+// for blocking that isn't tracked with the blocked label. Sync keeps the
+// issue blocked until UnblockIssue ends the period.
+func (db *DB) BlockIssue(issueID int64, at time.Time, reason string) error {
+	return db.Transaction(func(tx *Tx) error {
+		var open int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM blocked_periods
+			WHERE issue_id = ? AND manual AND unblocked_at IS NULL`, issueID).Scan(&open); err != nil {
+			return err
+		}
+		if open > 0 {
+			return ErrAlreadyBlocked
+		}
+		if _, err := tx.Exec(`INSERT INTO blocked_periods (issue_id, blocked_at, reason, manual)
+			VALUES (?, ?, ?, TRUE)`, issueID, sqlTime(at), nullString(reason)); err != nil {
+			return err
+		}
+		_, err := tx.Exec("UPDATE issues SET is_blocked = TRUE WHERE id = ?", issueID)
+		return err
+	})
+}
+
+// UnblockIssue ends an issue's open manual blocked period at at and adds it
+// to the issue's blocked time. The issue is unblocked until a sync finds a
+// blocked label on it.
+func (db *DB) UnblockIssue(issueID int64, at time.Time) error {
+	return db.Transaction(func(tx *Tx) error {
+		var id int64
+		var blockedAt time.Time
+		err := tx.QueryRow(`SELECT id, blocked_at FROM blocked_periods
+			WHERE issue_id = ? AND manual AND unblocked_at IS NULL
+			ORDER BY blocked_at DESC, id DESC LIMIT 1`, issueID).Scan(&id, &blockedAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotBlocked
+		} else if err != nil {
+			return err
+		}
+
+		hours := at.Sub(blockedAt).Hours()
+		if hours < 0 {
+			hours = 0
+		}
+		if _, err := tx.Exec("UPDATE blocked_periods SET unblocked_at = ?, duration_hours = ? WHERE id = ?",
+			sqlTime(at), hours, id); err != nil {
+			return err
+		}
+		_, err = tx.Exec(`UPDATE issues SET is_blocked = FALSE,
+			blocked_time_hours = COALESCE(blocked_time_hours, 0) + ? WHERE id = ?`, hours, issueID)
+		return err
+	})
+}
+
+// GetBlockedPeriods returns an issue's blocked periods, oldest first
+func (db *DB) GetBlockedPeriods(issueID int64) ([]BlockedPeriod, error) {
+	rows, err := db.Query(`SELECT id, issue_id, blocked_at, unblocked_at, duration_hours, reason, manual, created_at
+		FROM blocked_periods WHERE issue_id = ?
+		ORDER BY blocked_at, id`, issueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var periods []BlockedPeriod
+	for rows.Next() {
+		var bp BlockedPeriod
+		var unblockedAt sql.NullTime
+		var duration sql.NullFloat64
+		var reason sql.NullString
+		var manual sql.NullBool
+		if err := rows.Scan(&bp.ID, &bp.IssueID, &bp.BlockedAt, &unblockedAt, &duration, &reason, &manual, &bp.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		bp.Manual = manual.Bool
+		if unblockedAt.Valid {
+			bp.UnblockedAt = &unblockedAt.Time
+		}
+		bp.DurationHours = duration.Float64
+		bp.Reason = reason.String
+		periods = append(periods, bp)
+	}
+	return periods, rows.Err()
+}
+
+// UpdateIssueBlockedTime updates total blocked time for an issue
+func (db *DB) UpdateIssueBlockedTime(issueID int64, totalHours float64) error {
+	_, err := db.Exec("UPDATE issues SET blocked_time_hours = "+withManualBlockedTime+", is_blocked = ? WHERE id = ?",
+		totalHours, totalHours > 0, issueID)
+	return err
+}
+
+// UpdateIssueTimestamps sets the entered_*_at timestamps from timeline data,
+// keyed by status. Statuses without an entry column on issues are skipped;
+// RecordStatusTimestamps keeps those.
+func (db *DB) UpdateIssueTimestamps(issueID int64, entered map[string]time.Time) error {
+	var sets []string
+	var args []interface{}
+	for status, at := range entered {
+		if column, ok := statusColumns[status]; ok {
+			sets = append(sets, column+" = ?")
+			args = append(args, sqlTime(at))
+		}
+	}
+	if len(sets) == 0 {
+		return nil
+	}
+	_, err := db.Exec("UPDATE issues SET "+strings.Join(sets, ", ")+", updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+		append(args, issueID)...)
+	return err
+}
+
+// ErrCycleExceedsLead is returned by RecalcCycleTime when the computed cycle time
+// is longer than the lead time, which indicates bad timeline data (e.g. the issue
+// entered in-progress before it was created).
+var ErrCycleExceedsLead = errors.New("cycle time exceeds lead time")
+
 // RecalcCycleTime recalculates cycle time from timestamps
-// Cycle time: only calculated when issue went through in-progress (real workflow)
+// Cycle time: only calculated when issue went through the active start status
+// (in-progress by default, see SetActiveStartStatus)
 // Lead time: calculated for all closed issues (creation → done)
+// Returns ErrCycleExceedsLead (wrapped) if the result is inconsistent; the
+// values are still stored so the issue can be inspected.
 func (db *DB) RecalcCycleTime(issueID int64) error {
-	// Note: REPLACE strips Go time format suffix " +0000 UTC" for SQLite julianday compatibility
-	_, err := db.Exec(`UPDATE issues SET
+	_, err := db.Exec(db.recalcTimesSQL()+" WHERE id = ?", issueID)
+	if err != nil {
+		return err
+	}
+
+	// Sanity check: active time can never exceed total time
+	var number int
+	var cycle, lead sql.NullFloat64
+	err = db.QueryRow("SELECT number, cycle_time_hours, lead_time_hours FROM issues WHERE id = ?", issueID).
+		Scan(&number, &cycle, &lead)
+	if err != nil {
+		return err
+	}
+	if cycle.Valid && lead.Valid && cycle.Float64 > lead.Float64 {
+		return fmt.Errorf("issue #%d: %w (%.1fh > %.1fh)", number, ErrCycleExceedsLead, cycle.Float64, lead.Float64)
+	}
+	return nil
+}
+
+// recalcTimesSQL is the UPDATE behind RecalcCycleTime, without a WHERE clause.
+// Timestamps are stored in timeFormat (see sqlTime), which julianday reads as is.
+func (db *DB) recalcTimesSQL() string {
+	// Cycle time starts at the configured active status (entered_progress_at by default)
+	start := db.activeStart
+	return `UPDATE issues SET
 		cycle_time_hours = CASE
-			WHEN entered_progress_at IS NOT NULL AND (entered_done_at IS NOT NULL OR gh_closed_at IS NOT NULL)
-			THEN (julianday(REPLACE(REPLACE(COALESCE(entered_done_at, gh_closed_at), ' +0000 UTC', ''), ' UTC', ''))
-			    - julianday(REPLACE(REPLACE(entered_progress_at, ' +0000 UTC', ''), ' UTC', ''))) * 24
+			WHEN ` + start + ` IS NOT NULL AND (entered_done_at IS NOT NULL OR gh_closed_at IS NOT NULL)
+			THEN (julianday(COALESCE(entered_done_at, gh_closed_at)) - julianday(` + start + `)) * 24
 			    - COALESCE(blocked_time_hours, 0)
 			ELSE NULL
 		END,
 		lead_time_hours = CASE
 			WHEN entered_done_at IS NOT NULL OR gh_closed_at IS NOT NULL
-			THEN (julianday(REPLACE(REPLACE(COALESCE(entered_done_at, gh_closed_at), ' +0000 UTC', ''), ' UTC', ''))
-			    - julianday(gh_created_at)) * 24
+			THEN (julianday(COALESCE(entered_done_at, gh_closed_at)) - julianday(gh_created_at)) * 24
 			ELSE NULL
-		END
-		WHERE id = ?`, issueID)
-	return err
+		END`
 }
 
-// GetIssueByRepoAndNumber gets an issue by repo and number
-func (db *DB) GetIssueByRepoAndNumber(repoID int64, number int) (*Issue, error) {
+// issueColumns are the issues columns scanIssue reads, in order
+const issueColumns = `id, repo_id, number, title, state,
+		gh_created_at, gh_updated_at, gh_closed_at,
+		current_status, current_priority, current_type, current_size, is_blocked, assignee,
+		entered_ready_at, entered_progress_at, entered_review_at, entered_testing_at, entered_done_at,
+		lead_time_hours, cycle_time_hours, blocked_time_hours`
+
+// scanIssue scans a row selected with issueColumns
+func scanIssue(row interface{ Scan(...interface{}) error }) (*Issue, error) {
 	var i Issue
 	var closedAt, readyAt, progressAt, reviewAt, testingAt, doneAt sql.NullTime
 	var status, priority, itype, size, assignee sql.NullString
+	// Recalculating times leaves NULL where there is no start or end
+	var leadTime, cycleTime, blockedTime sql.NullFloat64
 
-	err := db.QueryRow(`SELECT id, repo_id, number, title, state,
-		gh_created_at, gh_updated_at, gh_closed_at,
-		current_status, current_priority, current_type, current_size, is_blocked, assignee,
-		entered_ready_at, entered_progress_at, entered_review_at, entered_testing_at, entered_done_at,
-		lead_time_hours, cycle_time_hours, blocked_time_hours
-		FROM issues WHERE repo_id = ? AND number = ?`, repoID, number).Scan(
+	err := row.Scan(
 		&i.ID, &i.RepoID, &i.Number, &i.Title, &i.State,
 		&i.GHCreatedAt, &i.GHUpdatedAt, &closedAt,
 		&status, &priority, &itype, &size, &i.IsBlocked, &assignee,
 		&readyAt, &progressAt, &reviewAt, &testingAt, &doneAt,
-		&i.LeadTimeHours, &i.CycleTimeHours, &i.BlockedTimeHours)
+		&leadTime, &cycleTime, &blockedTime)
 
 	if err != nil {
 		return nil, err
@@ -518,10 +1166,131 @@ func (db *DB) GetIssueByRepoAndNumber(repoID int64, number int) (*Issue, error)
 	if doneAt.Valid {
 		i.EnteredDoneAt = &doneAt.Time
 	}
+	i.LeadTimeHours = leadTime.Float64
+	i.CycleTimeHours = cycleTime.Float64
+	i.BlockedTimeHours = blockedTime.Float64
 
 	return &i, nil
 }
 
+// GetIssueByRepoAndNumber gets an issue by repo and number
+func (db *DB) GetIssueByRepoAndNumber(repoID int64, number int) (*Issue, error) {
+	return scanIssue(db.QueryRow("SELECT "+issueColumns+" FROM issues WHERE repo_id = ? AND number = ?", repoID, number))
+}
+
+// GetIssuesByLabel returns a repo's cached issues carrying the label, matched
+// case-insensitively as GitHub does, by number. It relies on the issue labels
+// sync records.
+func (db *DB) GetIssuesByLabel(repoID int64, label string) ([]Issue, error) {
+	rows, err := db.Query(`SELECT `+issueColumns+`
+		FROM issues
+		WHERE repo_id = ? AND id IN (
+			SELECT il.issue_id FROM issue_labels il
+			JOIN labels l ON l.id = il.label_id
+			WHERE l.repo_id = ? AND l.name = ? COLLATE NOCASE)
+		ORDER BY number`, repoID, repoID, label)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var issues []Issue
+	for rows.Next() {
+		issue, err := scanIssue(rows)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, *issue)
+	}
+	return issues, rows.Err()
+}
+
+// IssueSearch filters SearchIssues. Empty fields match every issue.
+type IssueSearch struct {
+	Org      string   // organization name
+	Repo     string   // repository full name
+	Text     string   // substring of the title, case-insensitive
+	Labels   []string // labels the issue must all carry; spaces and case are ignored
+	Status   string
+	Priority string
+	Type     string
+	Assignee string
+	Blocked  bool   // only blocked issues
+	State    string // open or closed; empty for both
+	Limit    int    // 0 for no limit
+}
+
+// SearchIssues returns cached issues matching the search, most recently
+// updated first. Label filters rely on the issue labels sync records.
+func (db *DB) SearchIssues(search IssueSearch) ([]SearchedIssue, error) {
+	query := `SELECT r.full_name, i.number, i.title, i.state, i.current_status, i.current_priority,
+		i.current_type, i.assignee, i.is_blocked, i.gh_updated_at
+		FROM issues i
+		JOIN repositories r ON r.id = i.repo_id
+		JOIN organizations o ON o.id = r.org_id
+		WHERE 1=1`
+	args := []interface{}{}
+
+	if search.Org != "" {
+		query += " AND o.name = ?"
+		args = append(args, search.Org)
+	}
+	if search.Repo != "" {
+		query += " AND r.full_name = ?"
+		args = append(args, search.Repo)
+	}
+	if search.Text != "" {
+		query += ` AND i.title LIKE ? ESCAPE '\'`
+		escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(search.Text)
+		args = append(args, "%"+escaped+"%")
+	}
+	for _, label := range search.Labels {
+		query += ` AND i.id IN (SELECT il.issue_id FROM issue_labels il
+			JOIN labels l ON l.id = il.label_id
+			WHERE l.repo_id = i.repo_id AND REPLACE(l.name, ' ', '') = REPLACE(?, ' ', '') COLLATE NOCASE)`
+		args = append(args, label)
+	}
+	for _, f := range []struct{ column, value string }{
+		{"current_status", search.Status},
+		{"current_priority", search.Priority},
+		{"current_type", search.Type},
+		{"assignee", search.Assignee},
+		{"state", search.State},
+	} {
+		if f.value != "" {
+			query += " AND i." + f.column + " = ? COLLATE NOCASE"
+			args = append(args, f.value)
+		}
+	}
+	if search.Blocked {
+		query += " AND i.is_blocked"
+	}
+	query += " ORDER BY i.gh_updated_at DESC, r.full_name, i.number"
+	if search.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, search.Limit)
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var issues []SearchedIssue
+	for rows.Next() {
+		var i SearchedIssue
+		var status, priority, itype, assignee sql.NullString
+		if err := rows.Scan(&i.Repo, &i.Number, &i.Title, &i.State, &status, &priority,
+			&itype, &assignee, &i.IsBlocked, &i.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		i.Status, i.Priority, i.Type, i.Assignee = status.String, priority.String, itype.String, assignee.String
+		issues = append(issues, i)
+	}
+	return issues, rows.Err()
+}
+
 // SaveCFDSnapshot saves CFD data for a date
 func (db *DB) SaveCFDSnapshot(repoID int64, date time.Time, statusCounts map[string]int) error {
 	for status, count := range statusCounts {
@@ -565,6 +1334,173 @@ func (db *DB) GetCFDData(repoID int64, days int) ([]struct {
 	return data, nil
 }
 
+// GetAggregateCFDData returns CFD data summed across an organization's repos,
+// one row per date and status, for an org-wide cumulative flow
+func (db *DB) GetAggregateCFDData(orgID int64, days int) ([]struct {
+	Date   string
+	Status string
+	Count  int
+}, error) {
+	rows, err := db.Query(`SELECT c.snapshot_date, c.status, SUM(c.cumulative_count)
+		FROM cfd_data c
+		JOIN repositories r ON c.repo_id = r.id
+		WHERE r.org_id = ? AND c.snapshot_date > date('now', '-' || ? || ' days')
+		GROUP BY c.snapshot_date, c.status
+		ORDER BY c.snapshot_date, c.status`, orgID, days)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var data []struct {
+		Date   string
+		Status string
+		Count  int
+	}
+	for rows.Next() {
+		var d struct {
+			Date   string
+			Status string
+			Count  int
+		}
+		if err := rows.Scan(&d.Date, &d.Status, &d.Count); err != nil {
+			return nil, err
+		}
+		data = append(data, d)
+	}
+	return data, rows.Err()
+}
+
+// GetCFDSnapshotDates returns the dates ("2006-01-02") a repo has CFD snapshots for
+func (db *DB) GetCFDSnapshotDates(repoID int64) (map[string]bool, error) {
+	rows, err := db.Query("SELECT DISTINCT date(snapshot_date) FROM cfd_data WHERE repo_id = ?", repoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dates := make(map[string]bool)
+	for rows.Next() {
+		var date string
+		if err := rows.Scan(&date); err != nil {
+			return nil, err
+		}
+		dates[date] = true
+	}
+	return dates, rows.Err()
+}
+
+// StatusEvent is an issue entering a status. From is the status it left,
+// when known.
+type StatusEvent struct {
+	From string
+	To   string
+	At   time.Time
+}
+
+// IssueStatusHistory holds what's needed to replay an issue's status over time
+type IssueStatusHistory struct {
+	Number    int
+	CreatedAt time.Time
+	ClosedAt  *time.Time
+	Events    []StatusEvent // oldest first
+}
+
+// OpenAt returns true if the issue had been created and not yet closed at t
+func (h IssueStatusHistory) OpenAt(t time.Time) bool {
+	if h.CreatedAt.After(t) {
+		return false
+	}
+	return h.ClosedAt == nil || h.ClosedAt.After(t)
+}
+
+// StatusAt returns the status the issue was in at t: the last one entered at
+// or before t, or else the one its first recorded change left. known is false
+// when neither is recorded and status falls back to "none".
+func (h IssueStatusHistory) StatusAt(t time.Time) (status string, known bool) {
+	for i := len(h.Events) - 1; i >= 0; i-- {
+		if !h.Events[i].At.After(t) {
+			return h.Events[i].To, true
+		}
+	}
+	if len(h.Events) > 0 && h.Events[0].From != "" {
+		return h.Events[0].From, true
+	}
+	return "none", false
+}
+
+// GetIssueStatusHistory returns every cached issue of a repo with its status
+// changes, from status_transitions and the first-entry times in
+// status_timestamps (which timeline syncs fill in)
+func (db *DB) GetIssueStatusHistory(repoID int64) ([]IssueStatusHistory, error) {
+	rows, err := db.Query(`SELECT id, number, gh_created_at, gh_closed_at
+		FROM issues WHERE repo_id = ? ORDER BY number`, repoID)
+	if err != nil {
+		return nil, err
+	}
+
+	var history []IssueStatusHistory
+	index := make(map[int64]int)
+	for rows.Next() {
+		var id int64
+		var h IssueStatusHistory
+		var closedAt sql.NullTime
+		if err := rows.Scan(&id, &h.Number, &h.CreatedAt, &closedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if closedAt.Valid {
+			h.ClosedAt = &closedAt.Time
+		}
+		index[id] = len(history)
+		history = append(history, h)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = db.Query(`SELECT t.issue_id, COALESCE(t.from_status, ''), t.to_status, t.transitioned_at
+		FROM status_transitions t
+		JOIN issues i ON t.issue_id = i.id
+		WHERE i.repo_id = ? AND t.to_status != ''
+		UNION ALL
+		SELECT s.issue_id, '', s.status, s.entered_at
+		FROM status_timestamps s
+		JOIN issues i ON s.issue_id = i.id
+		WHERE i.repo_id = ?`, repoID, repoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var issueID int64
+		var e StatusEvent
+		var at string
+		if err := rows.Scan(&issueID, &e.From, &e.To, &at); err != nil {
+			return nil, err
+		}
+		var ok bool
+		if e.At, ok = parseStoredTime(at); !ok {
+			continue
+		}
+		if i, ok := index[issueID]; ok {
+			history[i].Events = append(history[i].Events, e)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range history {
+		sort.SliceStable(history[i].Events, func(a, b int) bool {
+			return history[i].Events[a].At.Before(history[i].Events[b].At)
+		})
+	}
+	return history, nil
+}
+
 // GetLastCFDSnapshot returns the date of the last CFD snapshot
 func (db *DB) GetLastCFDSnapshot(repoID int64) (*time.Time, error) {
 	var dateStr sql.NullString
@@ -606,6 +1542,48 @@ func nullString(s string) interface{} {
 	return s
 }
 
+// timeFormat is how issue timestamps are stored: UTC in the format of
+// SQLite's CURRENT_TIMESTAMP, so julianday() and datetime() comparisons
+// work on them directly
+const timeFormat = "2006-01-02 15:04:05"
+
+// sqlTime formats t for storage
+func sqlTime(t time.Time) string {
+	return t.UTC().Format(timeFormat)
+}
+
+// nullTime formats t for storage, or NULL when t is nil
+func nullTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return sqlTime(*t)
+}
+
+// storedTimeLayouts are the formats earlier versions stored timestamps in:
+// Go's time.String(), RFC3339 and the SQLite driver's own format
+var storedTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// parseStoredTime parses a timestamp in any of storedTimeLayouts
+func parseStoredTime(s string) (time.Time, bool) {
+	// time.String() appends the monotonic clock reading, e.g. " m=+0.000012"
+	if i := strings.Index(s, " m="); i >= 0 {
+		s = s[:i]
+	}
+	for _, layout := range storedTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
 // ClosedIssueStats represents a closed issue with timing data
 type ClosedIssueStats struct {
 	Number         int
@@ -614,22 +1592,203 @@ type ClosedIssueStats struct {
 	ClosedAt       time.Time
 	LeadTimeHours  float64
 	CycleTimeHours float64
+	BlockedHours   float64 // taken out of CycleTimeHours
+	Assignee       string
+	Author         string
+}
+
+// CycleExceedsLead reports whether the issue has a cycle time longer than its
+// lead time. Such issues have inconsistent timeline data and should be left out
+// of cycle time and flow efficiency averages.
+func (s ClosedIssueStats) CycleExceedsLead() bool {
+	return s.CycleTimeHours > 0 && s.LeadTimeHours > 0 && s.CycleTimeHours > s.LeadTimeHours
+}
+
+// FlowEfficiency returns the average of each issue's cycle/lead ratio as a
+// percentage, so active time never counts for more than an issue's own lead
+// time. Ratios are clamped to [0, 100]; issues missing either time are
+// skipped. It returns 0 when no issue has both.
+func FlowEfficiency(issues []ClosedIssueStats) float64 {
+	var sum float64
+	var n int
+	for _, issue := range issues {
+		if issue.CycleTimeHours <= 0 || issue.LeadTimeHours <= 0 {
+			continue
+		}
+		sum += math.Min(issue.CycleTimeHours/issue.LeadTimeHours, 1)
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n) * 100
+}
+
+// closedIssueColumns are the columns scanClosedIssues reads, from issues
+// aliased as i
+const closedIssueColumns = `i.number, i.title, i.gh_created_at, i.gh_closed_at,
+		COALESCE(i.lead_time_hours, 0), COALESCE(i.cycle_time_hours, 0), COALESCE(i.blocked_time_hours, 0),
+		COALESCE(i.assignee, ''), COALESCE(i.author, '')`
+
+// GetClosedIssuesInPeriod returns closed issues within the specified days for flow metrics
+func (db *DB) GetClosedIssuesInPeriod(repoFilter string, days int) ([]ClosedIssueStats, error) {
+	query := `SELECT ` + closedIssueColumns + `
+		FROM issues i
+		JOIN repositories r ON i.repo_id = r.id
+		WHERE i.state = 'closed'
+		AND i.gh_closed_at > datetime('now', '-' || ? || ' days')` + db.completedFilter()
+	args := []interface{}{days}
+
+	if repoFilter != "" {
+		query += " AND r.full_name = ?"
+		args = append(args, repoFilter)
+	}
+	query, args = db.withoutExcluded(query, args)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanClosedIssues(rows)
+}
+
+// GetClosedIssuesInWindow returns issues closed at or after start and before end
+func (db *DB) GetClosedIssuesInWindow(repoFilter string, start, end time.Time) ([]ClosedIssueStats, error) {
+	query := `SELECT ` + closedIssueColumns + `
+		FROM issues i
+		JOIN repositories r ON i.repo_id = r.id
+		WHERE i.state = 'closed'
+		AND i.gh_closed_at >= ? AND i.gh_closed_at < ?` + db.completedFilter()
+	args := []interface{}{start.UTC().Format("2006-01-02 15:04:05"), end.UTC().Format("2006-01-02 15:04:05")}
+
+	if repoFilter != "" {
+		query += " AND r.full_name = ?"
+		args = append(args, repoFilter)
+	}
+	query, args = db.withoutExcluded(query, args)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanClosedIssues(rows)
+}
+
+// scanClosedIssues reads rows selected with closedIssueColumns
+func scanClosedIssues(rows *sql.Rows) ([]ClosedIssueStats, error) {
+	var issues []ClosedIssueStats
+	for rows.Next() {
+		var issue ClosedIssueStats
+		var createdAt, closedAt string
+		err := rows.Scan(&issue.Number, &issue.Title, &createdAt, &closedAt,
+			&issue.LeadTimeHours, &issue.CycleTimeHours, &issue.BlockedHours,
+			&issue.Assignee, &issue.Author)
+		if err != nil {
+			return nil, err
+		}
+		issue.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		issue.ClosedAt, _ = time.Parse(time.RFC3339, closedAt)
+
+		// Calculate lead time if not stored
+		if issue.LeadTimeHours == 0 && !issue.ClosedAt.IsZero() && !issue.CreatedAt.IsZero() {
+			issue.LeadTimeHours = issue.ClosedAt.Sub(issue.CreatedAt).Hours()
+		}
+
+		issues = append(issues, issue)
+	}
+	return issues, rows.Err()
+}
+
+// GetDailyThroughput returns how many issues were closed on each of the last
+// days days (UTC), oldest first. Days without completions count as zero.
+func (db *DB) GetDailyThroughput(repoFilter string, days int) ([]int, error) {
+	closed, err := db.GetClosedIssuesInPeriod(repoFilter, days)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]int, days)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for _, issue := range closed {
+		if issue.ClosedAt.IsZero() {
+			continue
+		}
+		daysAgo := int(today.Sub(issue.ClosedAt.UTC().Truncate(24*time.Hour)).Hours() / 24)
+		if idx := days - 1 - daysAgo; idx >= 0 && idx < days {
+			counts[idx]++
+		}
+	}
+	return counts, nil
+}
+
+// GetVelocity returns the story points (size_points) of issues closed in the
+// last days days. Closed issues without points contribute 0.
+func (db *DB) GetVelocity(repoFilter string, days int) (float64, error) {
+	query := `SELECT COALESCE(SUM(i.size_points), 0)
+		FROM issues i
+		JOIN repositories r ON i.repo_id = r.id
+		WHERE i.state = 'closed'
+		AND i.gh_closed_at > datetime('now', '-' || ? || ' days')` + db.completedFilter()
+	args := []interface{}{days}
+
+	if repoFilter != "" {
+		query += " AND r.full_name = ?"
+		args = append(args, repoFilter)
+	}
+	query, args = db.withoutExcluded(query, args)
+
+	var points float64
+	err := db.QueryRow(query, args...).Scan(&points)
+	return points, err
+}
+
+// GetUnsizedClosedCount returns how many issues closed in the last days days
+// have no story points, so velocity can be read next to them
+func (db *DB) GetUnsizedClosedCount(repoFilter string, days int) (int, error) {
+	query := `SELECT COUNT(*)
+		FROM issues i
+		JOIN repositories r ON i.repo_id = r.id
+		WHERE i.state = 'closed' AND i.size_points IS NULL
+		AND i.gh_closed_at > datetime('now', '-' || ? || ' days')` + db.completedFilter()
+	args := []interface{}{days}
+
+	if repoFilter != "" {
+		query += " AND r.full_name = ?"
+		args = append(args, repoFilter)
+	}
+	query, args = db.withoutExcluded(query, args)
+
+	var count int
+	err := db.QueryRow(query, args...).Scan(&count)
+	return count, err
 }
 
-// GetClosedIssuesInPeriod returns closed issues within the specified days for flow metrics
-func (db *DB) GetClosedIssuesInPeriod(repoFilter string, days int) ([]ClosedIssueStats, error) {
-	query := `SELECT i.number, i.title, i.gh_created_at, i.gh_closed_at,
-		COALESCE(i.lead_time_hours, 0), COALESCE(i.cycle_time_hours, 0)
+// MilestoneIssue holds the dates needed to plot a milestone burndown
+type MilestoneIssue struct {
+	Repo      string
+	Number    int
+	Title     string
+	CreatedAt time.Time
+	DoneAt    *time.Time // entered done, or closed; nil while open
+}
+
+// GetMilestoneIssues returns issues assigned to a milestone, optionally limited to one repo
+func (db *DB) GetMilestoneIssues(repoFilter, milestone string) ([]MilestoneIssue, error) {
+	query := `SELECT r.full_name, i.number, i.title, i.gh_created_at, i.entered_done_at, i.gh_closed_at
 		FROM issues i
 		JOIN repositories r ON i.repo_id = r.id
-		WHERE i.state = 'closed'
-		AND i.gh_closed_at > datetime('now', '-' || ? || ' days')`
-	args := []interface{}{days}
+		WHERE i.milestone = ?`
+	args := []interface{}{milestone}
 
 	if repoFilter != "" {
 		query += " AND r.full_name = ?"
 		args = append(args, repoFilter)
 	}
+	query += " ORDER BY i.gh_created_at"
 
 	rows, err := db.Query(query, args...)
 	if err != nil {
@@ -637,26 +1796,43 @@ func (db *DB) GetClosedIssuesInPeriod(repoFilter string, days int) ([]ClosedIssu
 	}
 	defer rows.Close()
 
-	var issues []ClosedIssueStats
+	var issues []MilestoneIssue
 	for rows.Next() {
-		var issue ClosedIssueStats
-		var createdAt, closedAt string
-		err := rows.Scan(&issue.Number, &issue.Title, &createdAt, &closedAt,
-			&issue.LeadTimeHours, &issue.CycleTimeHours)
-		if err != nil {
-			continue
+		var issue MilestoneIssue
+		var doneAt, closedAt sql.NullTime
+		if err := rows.Scan(&issue.Repo, &issue.Number, &issue.Title, &issue.CreatedAt, &doneAt, &closedAt); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
 		}
-		issue.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
-		issue.ClosedAt, _ = time.Parse(time.RFC3339, closedAt)
-
-		// Calculate lead time if not stored
-		if issue.LeadTimeHours == 0 && !issue.ClosedAt.IsZero() && !issue.CreatedAt.IsZero() {
-			issue.LeadTimeHours = issue.ClosedAt.Sub(issue.CreatedAt).Hours()
+		if doneAt.Valid {
+			issue.DoneAt = &doneAt.Time
+		} else if closedAt.Valid {
+			issue.DoneAt = &closedAt.Time
 		}
-
 		issues = append(issues, issue)
 	}
-	return issues, nil
+	return issues, rows.Err()
+}
+
+// UnassignedBucket is the GetClosedIssuesByAssignee key for issues with no assignee
+const UnassignedBucket = "@unassigned"
+
+// GetClosedIssuesByAssignee returns the issues of GetClosedIssuesInPeriod
+// grouped by assignee
+func (db *DB) GetClosedIssuesByAssignee(repoFilter string, days int) (map[string][]ClosedIssueStats, error) {
+	closed, err := db.GetClosedIssuesInPeriod(repoFilter, days)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]ClosedIssueStats)
+	for _, issue := range closed {
+		assignee := issue.Assignee
+		if assignee == "" {
+			assignee = UnassignedBucket
+		}
+		result[assignee] = append(result[assignee], issue)
+	}
+	return result, nil
 }
 
 // GetThroughputByRepo returns throughput data grouped by repo
@@ -665,10 +1841,11 @@ func (db *DB) GetThroughputByRepo(days int) (map[string]int, error) {
 		FROM issues i
 		JOIN repositories r ON i.repo_id = r.id
 		WHERE i.state = 'closed'
-		AND i.gh_closed_at > datetime('now', '-' || ? || ' days')
-		GROUP BY r.full_name`
+		AND i.gh_closed_at > datetime('now', '-' || ? || ' days')` + db.completedFilter()
+	query, args := db.withoutExcluded(query, []interface{}{days})
+	query += " GROUP BY r.full_name"
 
-	rows, err := db.Query(query, days)
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -689,10 +1866,11 @@ func (db *DB) GetArrivalByRepo(days int) (map[string]int, error) {
 	query := `SELECT r.full_name, COUNT(*) as created
 		FROM issues i
 		JOIN repositories r ON i.repo_id = r.id
-		WHERE i.gh_created_at > datetime('now', '-' || ? || ' days')
-		GROUP BY r.full_name`
+		WHERE i.gh_created_at > datetime('now', '-' || ? || ' days')`
+	query, args := db.withoutExcluded(query, []interface{}{days})
+	query += " GROUP BY r.full_name"
 
-	rows, err := db.Query(query, days)
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -708,6 +1886,108 @@ func (db *DB) GetArrivalByRepo(days int) (map[string]int, error) {
 	return result, nil
 }
 
+// GetBoardArrivals counts the issues of a repo created in the last days days
+// that have been on the board: issues with a status now or one recorded in
+// their status history, so issues since closed or moved off the board still
+// count. Issues that never had a status aren't arrivals to the board.
+func (db *DB) GetBoardArrivals(repoFullName string, days int) (int, error) {
+	query := `SELECT COUNT(*)
+		FROM issues i
+		JOIN repositories r ON i.repo_id = r.id
+		WHERE r.full_name = ?
+		AND i.gh_created_at > datetime('now', '-' || ? || ' days')
+		AND (COALESCE(i.current_status, '') != ''
+			OR EXISTS (SELECT 1 FROM status_transitions t WHERE t.issue_id = i.id)
+			OR EXISTS (SELECT 1 FROM status_timestamps s WHERE s.issue_id = i.id))`
+	query, args := db.withoutExcluded(query, []interface{}{repoFullName, days})
+
+	var count int
+	err := db.QueryRow(query, args...).Scan(&count)
+	return count, err
+}
+
+// GetBoardHistoryStart returns the earliest status entry recorded for a
+// repo's issues, where the board history GetBoardArrivals counts from
+// begins. ok is false when none is recorded.
+func (db *DB) GetBoardHistoryStart(repoFullName string) (start time.Time, ok bool, err error) {
+	queries := []string{
+		`SELECT MIN(t.transitioned_at) FROM status_transitions t
+			JOIN issues i ON t.issue_id = i.id
+			JOIN repositories r ON i.repo_id = r.id
+			WHERE r.full_name = ?`,
+		`SELECT MIN(s.entered_at) FROM status_timestamps s
+			JOIN issues i ON s.issue_id = i.id
+			JOIN repositories r ON i.repo_id = r.id
+			WHERE r.full_name = ?`,
+	}
+	for _, query := range queries {
+		var earliest sql.NullString
+		if err := db.QueryRow(query, repoFullName).Scan(&earliest); err != nil {
+			return time.Time{}, false, err
+		}
+		if t, parsed := parseStoredTime(earliest.String); parsed && (!ok || t.Before(start)) {
+			start, ok = t, true
+		}
+	}
+	return start, ok, nil
+}
+
+// UnknownAuthorBucket is the GetArrivalByAuthor key for issues with no recorded author:
+// deleted (ghost) accounts, or issues cached before authors were tracked
+const UnknownAuthorBucket = "@unknown"
+
+// GetArrivalByAuthor returns count of issues created in the period across all
+// cached repositories, grouped by author
+func (db *DB) GetArrivalByAuthor(days int) (map[string]int, error) {
+	query := `SELECT COALESCE(i.author, ''), COUNT(*) as created
+		FROM issues i
+		JOIN repositories r ON i.repo_id = r.id
+		WHERE i.gh_created_at > datetime('now', '-' || ? || ' days')`
+	query, args := db.withoutExcluded(query, []interface{}{days})
+	query += " GROUP BY COALESCE(i.author, '')"
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]int)
+	for rows.Next() {
+		var author string
+		var count int
+		if err := rows.Scan(&author, &count); err != nil {
+			return nil, err
+		}
+		if author == "" {
+			author = UnknownAuthorBucket
+		}
+		result[author] += count
+	}
+	return result, rows.Err()
+}
+
+// GetRepoFirstSync returns when each repo was first synced, keyed by full name.
+// Repository rows are created on first sync, so created_at marks the start of data coverage.
+func (db *DB) GetRepoFirstSync() (map[string]time.Time, error) {
+	rows, err := db.Query("SELECT full_name, created_at FROM repositories")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]time.Time)
+	for rows.Next() {
+		var repo string
+		var createdAt time.Time
+		if err := rows.Scan(&repo, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		result[repo] = createdAt
+	}
+	return result, rows.Err()
+}
+
 // Transaction wraps a function in a database transaction
 func (db *DB) Transaction(fn func(tx *Tx) error) error {
 	sqlTx, err := db.Begin()
@@ -746,10 +2026,10 @@ func (db *DB) UpsertIssueBatch(issues []*Issue) error {
 		// Prepare insert statement
 		insertStmt, err := tx.Prepare(`INSERT INTO issues
 			(repo_id, number, title, state, gh_created_at, gh_updated_at, gh_closed_at,
-			current_status, current_priority, current_type, current_size, is_blocked, assignee,
+			current_status, current_priority, current_type, current_size, is_blocked, assignee, milestone, author, state_reason,
 			entered_ready_at, entered_progress_at, entered_review_at, entered_testing_at, entered_done_at,
-			lead_time_hours, cycle_time_hours, blocked_time_hours)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+			lead_time_hours, cycle_time_hours, blocked_time_hours, size_points)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
 		if err != nil {
 			return err
 		}
@@ -759,8 +2039,8 @@ func (db *DB) UpsertIssueBatch(issues []*Issue) error {
 		updateStmt, err := tx.Prepare(`UPDATE issues SET
 			title = ?, state = ?, gh_updated_at = ?, gh_closed_at = ?,
 			current_status = ?, current_priority = ?, current_type = ?, current_size = ?,
-			is_blocked = ?, assignee = ?,
-			lead_time_hours = ?, cycle_time_hours = ?, blocked_time_hours = ?,
+			is_blocked = ` + keepManualBlock + `, assignee = ?, milestone = ?, author = ?, state_reason = ?,
+			lead_time_hours = ?, cycle_time_hours = ?, blocked_time_hours = ` + withManualBlockedTime + `, size_points = ?,
 			updated_at = CURRENT_TIMESTAMP
 			WHERE id = ?`)
 		if err != nil {
@@ -768,6 +2048,21 @@ func (db *DB) UpsertIssueBatch(issues []*Issue) error {
 		}
 		defer updateStmt.Close()
 
+		// Prepare status history statements, as UpsertIssue records it
+		transitionStmt, err := tx.Prepare(`INSERT INTO status_transitions (issue_id, from_status, to_status, transitioned_at)
+			VALUES (?, ?, ?, ?)`)
+		if err != nil {
+			return err
+		}
+		defer transitionStmt.Close()
+
+		timestampStmt, err := tx.Prepare(`INSERT OR IGNORE INTO status_timestamps (issue_id, status, entered_at)
+			VALUES (?, ?, CURRENT_TIMESTAMP)`)
+		if err != nil {
+			return err
+		}
+		defer timestampStmt.Close()
+
 		for _, issue := range issues {
 			var existingID int64
 			var existingStatus sql.NullString
@@ -777,28 +2072,54 @@ func (db *DB) UpsertIssueBatch(issues []*Issue) error {
 				// Insert new issue
 				result, err := insertStmt.Exec(
 					issue.RepoID, issue.Number, issue.Title, issue.State,
-					issue.GHCreatedAt, issue.GHUpdatedAt, issue.GHClosedAt,
+					sqlTime(issue.GHCreatedAt), sqlTime(issue.GHUpdatedAt), nullTime(issue.GHClosedAt),
 					nullString(issue.CurrentStatus), nullString(issue.CurrentPriority),
 					nullString(issue.CurrentType), nullString(issue.CurrentSize),
-					issue.IsBlocked, nullString(issue.Assignee),
-					issue.EnteredReadyAt, issue.EnteredProgressAt, issue.EnteredReviewAt,
-					issue.EnteredTestingAt, issue.EnteredDoneAt,
-					issue.LeadTimeHours, issue.CycleTimeHours, issue.BlockedTimeHours)
+					issue.IsBlocked, nullString(issue.Assignee), nullString(issue.Milestone), nullString(issue.Author), nullString(issue.StateReason),
+					nullTime(issue.EnteredReadyAt), nullTime(issue.EnteredProgressAt), nullTime(issue.EnteredReviewAt),
+					nullTime(issue.EnteredTestingAt), nullTime(issue.EnteredDoneAt),
+					issue.LeadTimeHours, issue.CycleTimeHours, issue.BlockedTimeHours, issue.SizePoints)
 				if err != nil {
 					return err
 				}
 				issue.ID, _ = result.LastInsertId()
+
+				// Record initial status transition
+				if issue.CurrentStatus != "" {
+					if _, err := transitionStmt.Exec(issue.ID, nil, issue.CurrentStatus, sqlTime(issue.GHUpdatedAt)); err != nil {
+						return err
+					}
+				}
 			} else if err != nil {
 				return err
 			} else {
 				// Update existing issue
 				issue.ID = existingID
+
+				// Check for status change
+				if oldStatus := existingStatus.String; oldStatus != issue.CurrentStatus {
+					if _, err := transitionStmt.Exec(issue.ID, nullString(oldStatus), issue.CurrentStatus, sqlTime(time.Now())); err != nil {
+						return err
+					}
+					if issue.CurrentStatus != "" {
+						if column, ok := statusColumns[issue.CurrentStatus]; ok {
+							if _, err := tx.Exec("UPDATE issues SET "+column+" = CURRENT_TIMESTAMP WHERE id = ? AND "+column+" IS NULL",
+								issue.ID); err != nil {
+								return err
+							}
+						}
+						if _, err := timestampStmt.Exec(issue.ID, issue.CurrentStatus); err != nil {
+							return err
+						}
+					}
+				}
+
 				_, err := updateStmt.Exec(
-					issue.Title, issue.State, issue.GHUpdatedAt, issue.GHClosedAt,
+					issue.Title, issue.State, sqlTime(issue.GHUpdatedAt), nullTime(issue.GHClosedAt),
 					nullString(issue.CurrentStatus), nullString(issue.CurrentPriority),
 					nullString(issue.CurrentType), nullString(issue.CurrentSize),
-					issue.IsBlocked, nullString(issue.Assignee),
-					issue.LeadTimeHours, issue.CycleTimeHours, issue.BlockedTimeHours,
+					issue.IsBlocked, nullString(issue.Assignee), nullString(issue.Milestone), nullString(issue.Author), nullString(issue.StateReason),
+					issue.LeadTimeHours, issue.CycleTimeHours, issue.BlockedTimeHours, issue.SizePoints,
 					issue.ID)
 				if err != nil {
 					return err
@@ -809,7 +2130,8 @@ func (db *DB) UpsertIssueBatch(issues []*Issue) error {
 	})
 }
 
-// UpsertLabelBatch inserts or updates multiple labels in a single transaction
+// UpsertLabelBatch inserts or updates multiple labels, categories included,
+// in a single transaction
 func (db *DB) UpsertLabelBatch(labels []*Label) error {
 	if len(labels) == 0 {
 		return nil
@@ -827,7 +2149,6 @@ func (db *DB) UpsertLabelBatch(labels []*Label) error {
 		defer stmt.Close()
 
 		for _, label := range labels {
-			label.Category = categorizeLabel(label.Name)
 			_, err := stmt.Exec(label.RepoID, label.Name, label.Color, label.Description, label.Category)
 			if err != nil {
 				return err
@@ -837,6 +2158,138 @@ func (db *DB) UpsertLabelBatch(labels []*Label) error {
 	})
 }
 
+// ReplaceIssueLabels sets the cached labels of a repo's issues (issue ID to
+// label names) in a single transaction. Labels the repo has no row for yet
+// are added without a color, in the category given by category; issues not
+// in labels are left alone.
+func (db *DB) ReplaceIssueLabels(repoID int64, labels map[int64][]string, category func(name string) string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	return db.Transaction(func(tx *Tx) error {
+		labelStmt, err := tx.Prepare(`INSERT INTO labels (repo_id, name, color, category)
+			VALUES (?, ?, '', ?)
+			ON CONFLICT(repo_id, name) DO NOTHING`)
+		if err != nil {
+			return err
+		}
+		defer labelStmt.Close()
+
+		idStmt, err := tx.Prepare("SELECT id FROM labels WHERE repo_id = ? AND name = ?")
+		if err != nil {
+			return err
+		}
+		defer idStmt.Close()
+
+		currentStmt, err := tx.Prepare("SELECT label_id FROM issue_labels WHERE issue_id = ?")
+		if err != nil {
+			return err
+		}
+		defer currentStmt.Close()
+
+		addStmt, err := tx.Prepare("INSERT OR IGNORE INTO issue_labels (issue_id, label_id) VALUES (?, ?)")
+		if err != nil {
+			return err
+		}
+		defer addStmt.Close()
+
+		removeStmt, err := tx.Prepare("DELETE FROM issue_labels WHERE issue_id = ? AND label_id = ?")
+		if err != nil {
+			return err
+		}
+		defer removeStmt.Close()
+
+		labelIDs := make(map[string]int64)
+		labelID := func(name string) (int64, error) {
+			if id, ok := labelIDs[name]; ok {
+				return id, nil
+			}
+			if _, err := labelStmt.Exec(repoID, name, category(name)); err != nil {
+				return 0, err
+			}
+			var id int64
+			if err := idStmt.QueryRow(repoID, name).Scan(&id); err != nil {
+				return 0, err
+			}
+			labelIDs[name] = id
+			return id, nil
+		}
+
+		for issueID, names := range labels {
+			want := make(map[int64]bool, len(names))
+			for _, name := range names {
+				id, err := labelID(name)
+				if err != nil {
+					return err
+				}
+				want[id] = true
+			}
+
+			// Only touch rows that changed so added_at keeps when a label was first seen
+			rows, err := currentStmt.Query(issueID)
+			if err != nil {
+				return err
+			}
+			have := make(map[int64]bool)
+			for rows.Next() {
+				var id int64
+				if err := rows.Scan(&id); err != nil {
+					rows.Close()
+					return err
+				}
+				have[id] = true
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return err
+			}
+
+			for id := range have {
+				if !want[id] {
+					if _, err := removeStmt.Exec(issueID, id); err != nil {
+						return err
+					}
+				}
+			}
+			for id := range want {
+				if !have[id] {
+					if _, err := addStmt.Exec(issueID, id); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// GetLabelUsage returns how many of a repo's cached issues, open or closed,
+// carry each label
+func (db *DB) GetLabelUsage(repoID int64) (map[string]int, error) {
+	rows, err := db.Query(`SELECT l.name, COUNT(*)
+		FROM issue_labels il
+		JOIN labels l ON l.id = il.label_id
+		JOIN issues i ON i.id = il.issue_id
+		WHERE l.repo_id = ? AND i.repo_id = ?
+		GROUP BY l.name`, repoID, repoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	usage := make(map[string]int)
+	for rows.Next() {
+		var name string
+		var count int
+		if err := rows.Scan(&name, &count); err != nil {
+			return nil, err
+		}
+		usage[name] = count
+	}
+	return usage, rows.Err()
+}
+
 // Vacuum optimizes the database file
 func (db *DB) Vacuum() error {
 	_, err := db.Exec("VACUUM")
@@ -857,6 +2310,116 @@ func (db *DB) Optimize() error {
 	return db.Analyze()
 }
 
+// TimestampRepair counts what NormalizeTimestamps rewrote
+type TimestampRepair struct {
+	Issues       int   // issues with at least one timestamp rewritten
+	Transitions  int   // status transitions rewritten
+	Unparsed     int   // timestamps in no known format, left as they are
+	Recalculated int64 // issues whose lead and cycle time were recalculated
+}
+
+// issueTimeColumns are the issue timestamps that feed lead and cycle time
+var issueTimeColumns = []string{"gh_created_at", "gh_updated_at", "gh_closed_at",
+	"entered_ready_at", "entered_progress_at", "entered_review_at", "entered_testing_at", "entered_done_at"}
+
+// NormalizeTimestamps rewrites issue and status transition timestamps stored
+// in an older format (see storedTimeLayouts) to timeFormat, then recalculates
+// lead and cycle time for every issue. It runs in one transaction; with
+// dryRun the transaction is rolled back and only the counts are returned.
+func (db *DB) NormalizeTimestamps(dryRun bool) (*TimestampRepair, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	repair := &TimestampRepair{}
+	var unparsed int
+	if repair.Issues, unparsed, err = normalizeTimeColumns(tx, "issues", issueTimeColumns); err != nil {
+		return nil, err
+	}
+	repair.Unparsed += unparsed
+	if repair.Transitions, unparsed, err = normalizeTimeColumns(tx, "status_transitions", []string{"transitioned_at"}); err != nil {
+		return nil, err
+	}
+	repair.Unparsed += unparsed
+
+	result, err := tx.Exec(db.recalcTimesSQL())
+	if err != nil {
+		return nil, fmt.Errorf("failed to recalculate cycle times: %w", err)
+	}
+	repair.Recalculated, _ = result.RowsAffected()
+
+	if dryRun {
+		return repair, nil
+	}
+	return repair, tx.Commit()
+}
+
+// normalizeTimeColumns rewrites non-canonical timestamps in columns of table
+// and returns how many rows changed and how many values couldn't be parsed
+func normalizeTimeColumns(tx *sql.Tx, table string, columns []string) (changed, unparsed int, err error) {
+	selects := make([]string, len(columns))
+	for i, column := range columns {
+		selects[i] = "CAST(" + column + " AS TEXT)"
+	}
+	rows, err := tx.Query("SELECT id, " + strings.Join(selects, ", ") + " FROM " + table)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read %s: %w", table, err)
+	}
+
+	// Collect the rewrites first; the single connection is busy until rows is closed
+	type rewrite struct {
+		id     int64
+		values map[string]string
+	}
+	var rewrites []rewrite
+	for rows.Next() {
+		var id int64
+		values := make([]sql.NullString, len(columns))
+		dest := []interface{}{&id}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			rows.Close()
+			return 0, 0, fmt.Errorf("failed to read %s: %w", table, err)
+		}
+
+		r := rewrite{id: id, values: make(map[string]string)}
+		for i, v := range values {
+			if !v.Valid {
+				continue
+			}
+			if _, err := time.Parse(timeFormat, v.String); err == nil {
+				continue
+			}
+			t, ok := parseStoredTime(v.String)
+			if !ok {
+				unparsed++
+				continue
+			}
+			r.values[columns[i]] = sqlTime(t)
+		}
+		if len(r.values) > 0 {
+			rewrites = append(rewrites, r)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, fmt.Errorf("failed to read %s: %w", table, err)
+	}
+
+	for _, r := range rewrites {
+		for column, value := range r.values {
+			if _, err := tx.Exec("UPDATE "+table+" SET "+column+" = ? WHERE id = ?", value, r.id); err != nil {
+				return 0, 0, fmt.Errorf("failed to update %s: %w", table, err)
+			}
+		}
+	}
+	return len(rewrites), unparsed, nil
+}
+
 // UpsertPR inserts or updates a pull request
 func (db *DB) UpsertPR(pr *PullRequest) error {
 	// Calculate review and merge times
@@ -877,7 +2440,7 @@ func (db *DB) UpsertPR(pr *PullRequest) error {
 			review_time_hours, merge_time_hours)
 			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 			pr.RepoID, pr.Number, pr.Title, pr.State, pr.IsDraft,
-			pr.GHCreatedAt, pr.GHUpdatedAt, pr.GHMergedAt, pr.GHClosedAt,
+			sqlTime(pr.GHCreatedAt), sqlTime(pr.GHUpdatedAt), nullTime(pr.GHMergedAt), nullTime(pr.GHClosedAt),
 			nullString(pr.Author), pr.Additions, pr.Deletions, pr.ChangedFiles,
 			pr.ReviewTimeHours, pr.MergeTimeHours)
 		if err != nil {
@@ -897,7 +2460,7 @@ func (db *DB) UpsertPR(pr *PullRequest) error {
 			updated_at = CURRENT_TIMESTAMP
 			WHERE id = ?`,
 			pr.Title, pr.State, pr.IsDraft,
-			pr.GHUpdatedAt, pr.GHMergedAt, pr.GHClosedAt,
+			sqlTime(pr.GHUpdatedAt), nullTime(pr.GHMergedAt), nullTime(pr.GHClosedAt),
 			nullString(pr.Author), pr.Additions, pr.Deletions, pr.ChangedFiles,
 			pr.ReviewTimeHours, pr.MergeTimeHours,
 			pr.ID)
@@ -937,6 +2500,30 @@ func (db *DB) GetPRsByRepo(repoID int64, state string) ([]PullRequest, error) {
 	}
 	defer rows.Close()
 
+	return scanPRs(rows), nil
+}
+
+// GetPRsForIssue returns the PRs linked to an issue, newest first
+func (db *DB) GetPRsForIssue(repoID int64, issueNumber int) ([]PullRequest, error) {
+	rows, err := db.Query(`SELECT p.id, p.repo_id, p.number, p.title, p.state, p.is_draft,
+		p.gh_created_at, p.gh_updated_at, p.gh_merged_at, p.gh_closed_at,
+		p.author, p.additions, p.deletions, p.changed_files,
+		p.review_time_hours, p.merge_time_hours
+		FROM pull_requests p
+		JOIN pr_issue_links l ON l.pr_id = p.id
+		JOIN issues i ON i.id = l.issue_id
+		WHERE i.repo_id = ? AND i.number = ?
+		ORDER BY p.number DESC`, repoID, issueNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPRs(rows), nil
+}
+
+// scanPRs reads pull_requests rows selected in GetPRsByRepo column order
+func scanPRs(rows *sql.Rows) []PullRequest {
 	var prs []PullRequest
 	for rows.Next() {
 		var pr PullRequest
@@ -970,7 +2557,7 @@ func (db *DB) GetPRsByRepo(repoID int64, state string) ([]PullRequest, error) {
 
 		prs = append(prs, pr)
 	}
-	return prs, nil
+	return prs
 }
 
 // GetPRSummary returns PR metrics summary for a repo
@@ -994,6 +2581,10 @@ func (db *DB) GetPRSummary(repoFullName string) (*PRSummary, error) {
 	db.QueryRow(`SELECT COUNT(*) FROM pull_requests WHERE repo_id = ?
 		AND gh_merged_at > datetime('now', '-30 days')`, repoID).Scan(&summary.MergedLast30d)
 
+	// Average review time (for merged PRs in last 30 days)
+	db.QueryRow(`SELECT AVG(review_time_hours) FROM pull_requests WHERE repo_id = ?
+		AND gh_merged_at > datetime('now', '-30 days') AND review_time_hours > 0`, repoID).Scan(&summary.AvgReviewTimeHrs)
+
 	// Average merge time (for merged PRs in last 30 days)
 	db.QueryRow(`SELECT AVG(merge_time_hours) FROM pull_requests WHERE repo_id = ?
 		AND gh_merged_at > datetime('now', '-30 days') AND merge_time_hours > 0`, repoID).Scan(&summary.AvgMergeTimeHrs)
@@ -1024,6 +2615,112 @@ func (db *DB) GetLinkedIssues(prID int64) ([]int, error) {
 	return issues, nil
 }
 
+// GetRepoID returns the ID of a cached repository by full name
+func (db *DB) GetRepoID(fullName string) (int64, error) {
+	var id int64
+	err := db.QueryRow("SELECT id FROM repositories WHERE full_name = ?", fullName).Scan(&id)
+	return id, err
+}
+
+// GetIssueStatuses returns the current status of every cached issue in a
+// repo, keyed by issue number
+func (db *DB) GetIssueStatuses(repoID int64) (map[int]string, error) {
+	rows, err := db.Query("SELECT number, COALESCE(current_status, '') FROM issues WHERE repo_id = ?", repoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	statuses := make(map[int]string)
+	for rows.Next() {
+		var number int
+		var status string
+		if err := rows.Scan(&number, &status); err != nil {
+			return nil, err
+		}
+		statuses[number] = status
+	}
+	return statuses, rows.Err()
+}
+
+// issueDependents are the tables whose rows belong to a cached issue
+var issueDependents = []string{"status_transitions", "status_timestamps", "blocked_periods", "issue_labels", "pr_issue_links"}
+
+// PruneIssues deletes a repo's cached issues whose numbers are not in keep,
+// with their transitions, timestamps, blocked periods, labels and PR links.
+// keep must be the repo's complete issue list. Returns the pruned numbers.
+func (db *DB) PruneIssues(repoID int64, keep []int) ([]int, error) {
+	statuses, err := db.GetIssueStatuses(repoID)
+	if err != nil {
+		return nil, err
+	}
+	keepSet := make(map[int]bool, len(keep))
+	for _, n := range keep {
+		keepSet[n] = true
+	}
+
+	var pruned []int
+	for number := range statuses {
+		if !keepSet[number] {
+			pruned = append(pruned, number)
+		}
+	}
+	if len(pruned) == 0 {
+		return nil, nil
+	}
+	sort.Ints(pruned)
+
+	if err := db.Transaction(func(tx *Tx) error { return deleteIssues(tx, repoID, pruned) }); err != nil {
+		return nil, err
+	}
+	return pruned, nil
+}
+
+// DeleteIssues deletes a repo's cached issues with the given numbers, with
+// their history as in PruneIssues. Numbers that aren't cached are ignored.
+// Returns the deleted numbers.
+func (db *DB) DeleteIssues(repoID int64, numbers []int) ([]int, error) {
+	statuses, err := db.GetIssueStatuses(repoID)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []int
+	for _, number := range numbers {
+		if _, ok := statuses[number]; ok {
+			deleted = append(deleted, number)
+		}
+	}
+	if len(deleted) == 0 {
+		return nil, nil
+	}
+	sort.Ints(deleted)
+
+	if err := db.Transaction(func(tx *Tx) error { return deleteIssues(tx, repoID, deleted) }); err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
+
+// deleteIssues deletes cached issues by number along with their issueDependents rows
+func deleteIssues(tx *Tx, repoID int64, numbers []int) error {
+	for _, number := range numbers {
+		var issueID int64
+		if err := tx.QueryRow("SELECT id FROM issues WHERE repo_id = ? AND number = ?", repoID, number).Scan(&issueID); err != nil {
+			return err
+		}
+		for _, table := range issueDependents {
+			if _, err := tx.Exec("DELETE FROM "+table+" WHERE issue_id = ?", issueID); err != nil {
+				return fmt.Errorf("failed to delete %s of #%d: %w", table, number, err)
+			}
+		}
+		if _, err := tx.Exec("DELETE FROM issues WHERE id = ?", issueID); err != nil {
+			return fmt.Errorf("failed to delete #%d: %w", number, err)
+		}
+	}
+	return nil
+}
+
 // GetIssueIDByNumber returns the issue ID for a repo and issue number
 func (db *DB) GetIssueIDByNumber(repoID int64, number int) (int64, error) {
 	var id int64
@@ -2,7 +2,16 @@ package db
 
 // Schema version for migrations
 // Version 2: Added pull_requests and pr_issue_links tables
-const SchemaVersion = 2
+// Version 3: Added issues.milestone
+// Version 4: Added metric_baselines table
+// Version 5: Added status_timestamps table
+// Version 6: Normalized issue timestamps to SQLite's datetime format
+// Version 7: Added issues.author
+// Version 8: Added issues.size_points
+// Version 9: Added blocked_periods.manual
+// Version 10: Added issues.state_reason
+// Version 11: Normalized every stored timestamp to SQLite's datetime format
+const SchemaVersion = 11
 
 // Schema contains the database schema
 const Schema = `
@@ -61,6 +70,7 @@ CREATE TABLE IF NOT EXISTS issues (
     gh_created_at   DATETIME NOT NULL,
     gh_updated_at   DATETIME NOT NULL,
     gh_closed_at    DATETIME,
+    state_reason    TEXT,
 
     current_status  TEXT,
     current_priority TEXT,
@@ -69,6 +79,8 @@ CREATE TABLE IF NOT EXISTS issues (
     is_blocked      BOOLEAN DEFAULT FALSE,
 
     assignee        TEXT,
+    milestone       TEXT,
+    author          TEXT,
 
     entered_ready_at      DATETIME,
     entered_progress_at   DATETIME,
@@ -79,6 +91,7 @@ CREATE TABLE IF NOT EXISTS issues (
     lead_time_hours       REAL,
     cycle_time_hours      REAL,
     blocked_time_hours    REAL,
+    size_points           REAL,
 
     created_at      DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at      DATETIME DEFAULT CURRENT_TIMESTAMP,
@@ -144,6 +157,14 @@ CREATE TABLE IF NOT EXISTS status_transitions (
     created_at      DATETIME DEFAULT CURRENT_TIMESTAMP
 );
 
+-- First entry into any workflow status, including custom ones
+CREATE TABLE IF NOT EXISTS status_timestamps (
+    issue_id        INTEGER NOT NULL REFERENCES issues(id),
+    status          TEXT NOT NULL,
+    entered_at      DATETIME NOT NULL,
+    PRIMARY KEY (issue_id, status)
+);
+
 CREATE TABLE IF NOT EXISTS blocked_periods (
     id              INTEGER PRIMARY KEY AUTOINCREMENT,
     issue_id        INTEGER NOT NULL REFERENCES issues(id),
@@ -151,6 +172,7 @@ CREATE TABLE IF NOT EXISTS blocked_periods (
     unblocked_at    DATETIME,
     duration_hours  REAL,
     reason          TEXT,
+    manual          BOOLEAN DEFAULT FALSE,
     created_at      DATETIME DEFAULT CURRENT_TIMESTAMP
 );
 
@@ -200,6 +222,15 @@ CREATE TABLE IF NOT EXISTS cfd_data (
     UNIQUE(repo_id, snapshot_date, status)
 );
 
+CREATE TABLE IF NOT EXISTS metric_baselines (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    name            TEXT NOT NULL,
+    repo            TEXT NOT NULL,
+    metrics_json    TEXT NOT NULL,
+    created_at      DATETIME NOT NULL,
+    UNIQUE(name, repo)
+);
+
 -- ═══════════════════════════════════════════════════════════════
 -- SYNC METADATA
 -- ═══════════════════════════════════════════════════════════════
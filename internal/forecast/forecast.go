@@ -0,0 +1,101 @@
+// Package forecast runs Monte Carlo simulations over historical daily
+// throughput to answer "when will N items be done?" and "how many items
+// will be done by a date?".
+package forecast
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+const (
+	// DefaultTrials is the number of simulated futures per forecast
+	DefaultTrials = 10000
+
+	// MaxDays caps a single trial so rare completions can't loop forever
+	MaxDays = 3650
+)
+
+// ErrNoThroughput is returned when the history has no completed items to sample
+var ErrNoThroughput = errors.New("no completed items in the sampled period")
+
+// Simulation samples days at random from a throughput history
+type Simulation struct {
+	samples []int
+	trials  int
+	rng     *rand.Rand
+}
+
+// New creates a simulation over daily completion counts. A seed of 0 picks
+// a time-based seed; any other seed gives repeatable results.
+func New(samples []int, trials int, seed int64) (*Simulation, error) {
+	total := 0
+	for _, s := range samples {
+		total += s
+	}
+	if total == 0 {
+		return nil, ErrNoThroughput
+	}
+	if trials <= 0 {
+		trials = DefaultTrials
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Simulation{
+		samples: samples,
+		trials:  trials,
+		rng:     rand.New(rand.NewSource(seed)),
+	}, nil
+}
+
+// DaysToComplete returns, for each trial, how many days it took to finish
+// items, sorted ascending. Trials that hit MaxDays report MaxDays.
+func (s *Simulation) DaysToComplete(items int) []int {
+	results := make([]int, s.trials)
+	for t := range results {
+		done, day := 0, 0
+		for done < items && day < MaxDays {
+			done += s.sample()
+			day++
+		}
+		results[t] = day
+	}
+	sort.Ints(results)
+	return results
+}
+
+// ItemsWithin returns, for each trial, how many items were finished in days,
+// sorted ascending
+func (s *Simulation) ItemsWithin(days int) []int {
+	results := make([]int, s.trials)
+	for t := range results {
+		for d := 0; d < days; d++ {
+			results[t] += s.sample()
+		}
+	}
+	sort.Ints(results)
+	return results
+}
+
+func (s *Simulation) sample() int {
+	return s.samples[s.rng.Intn(len(s.samples))]
+}
+
+// Percentile returns the value at or below which p percent of the sorted
+// results fall
+func Percentile(sorted []int, p int) int {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := (len(sorted)*p+99)/100 - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
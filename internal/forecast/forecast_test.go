@@ -0,0 +1,91 @@
+package forecast
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNew_NoThroughput(t *testing.T) {
+	if _, err := New([]int{0, 0, 0}, 100, 1); !errors.Is(err, ErrNoThroughput) {
+		t.Errorf("New() error = %v, want ErrNoThroughput", err)
+	}
+	if _, err := New(nil, 100, 1); !errors.Is(err, ErrNoThroughput) {
+		t.Errorf("New(nil) error = %v, want ErrNoThroughput", err)
+	}
+}
+
+func TestDaysToComplete_ConstantThroughput(t *testing.T) {
+	sim, err := New([]int{2}, 50, 1)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	// Two items a day, every day: ten items always take five days
+	results := sim.DaysToComplete(10)
+	if len(results) != 50 {
+		t.Fatalf("DaysToComplete() returned %d trials, want 50", len(results))
+	}
+	for _, p := range []int{50, 85, 95} {
+		if got := Percentile(results, p); got != 5 {
+			t.Errorf("P%d = %d, want 5", p, got)
+		}
+	}
+}
+
+func TestItemsWithin_ConstantThroughput(t *testing.T) {
+	sim, err := New([]int{3}, 20, 1)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	results := sim.ItemsWithin(4)
+	if Percentile(results, 50) != 12 {
+		t.Errorf("P50 = %d, want 12", Percentile(results, 50))
+	}
+}
+
+func TestSimulation_SeedIsDeterministic(t *testing.T) {
+	samples := []int{0, 1, 0, 3, 2, 0, 1}
+
+	a, _ := New(samples, 1000, 42)
+	b, _ := New(samples, 1000, 42)
+
+	ra := a.DaysToComplete(20)
+	rb := b.DaysToComplete(20)
+	for i := range ra {
+		if ra[i] != rb[i] {
+			t.Fatalf("trial %d differs with the same seed: %d vs %d", i, ra[i], rb[i])
+		}
+	}
+
+	// Later percentiles can't finish sooner than earlier ones
+	if Percentile(ra, 50) > Percentile(ra, 85) || Percentile(ra, 85) > Percentile(ra, 95) {
+		t.Errorf("percentiles not ordered: P50=%d P85=%d P95=%d",
+			Percentile(ra, 50), Percentile(ra, 85), Percentile(ra, 95))
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	tests := []struct {
+		p    int
+		want int
+	}{
+		{0, 1},
+		{50, 5},
+		{85, 9},
+		{95, 10},
+		{100, 10},
+	}
+
+	for _, tt := range tests {
+		if got := Percentile(sorted, tt.p); got != tt.want {
+			t.Errorf("Percentile(%d) = %d, want %d", tt.p, got, tt.want)
+		}
+	}
+
+	if got := Percentile(nil, 50); got != 0 {
+		t.Errorf("Percentile(nil) = %d, want 0", got)
+	}
+}
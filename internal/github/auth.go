@@ -0,0 +1,76 @@
+package github
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Auth backends reported by AuthInfo
+const (
+	AuthBackendGHToken  = "env token (GH_TOKEN)"
+	AuthBackendEnvToken = "env token (GITHUB_TOKEN)"
+	AuthBackendKeyring  = "gh keyring/config"
+)
+
+// AuthInfo describes the identity the gh CLI resolves to for this tool
+type AuthInfo struct {
+	Login          string `json:"login"`
+	Host           string `json:"host"`
+	Backend        string `json:"backend"`
+	GHTokenIgnored bool   `json:"gh_token_ignored,omitempty"` // GH_TOKEN is set but stripped for gh calls
+}
+
+// CurrentUser returns the login of the authenticated GitHub user
+func (c *Client) CurrentUser() (string, error) {
+	output, err := c.runGH([]string{"api", "user", "--jq", ".login"})
+	if err != nil {
+		return "", fmt.Errorf("failed to get current user: %w", err)
+	}
+
+	login := strings.TrimSpace(string(output))
+	if login == "" {
+		return "", fmt.Errorf("failed to get current user: empty login")
+	}
+	return login, nil
+}
+
+// AuthInfo returns the authenticated login, the auth backend and the GitHub host.
+// gh uses GH_TOKEN when it is passed through (see Options.UseGHToken), then
+// GITHUB_TOKEN if set, otherwise the credentials stored by `gh auth login`.
+func (c *Client) AuthInfo() (*AuthInfo, error) {
+	login, err := c.CurrentUser()
+	if err != nil {
+		return nil, err
+	}
+
+	info := &AuthInfo{
+		Login:          login,
+		Host:           c.Host(),
+		Backend:        AuthBackendKeyring,
+		GHTokenIgnored: os.Getenv("GH_TOKEN") != "" && !c.useGHToken(),
+	}
+	switch {
+	case c.useGHToken():
+		info.Backend = AuthBackendGHToken
+	case os.Getenv("GITHUB_TOKEN") != "":
+		info.Backend = AuthBackendEnvToken
+	}
+	return info, nil
+}
+
+// AuthStatus runs `gh auth status` and returns its output on failure
+func (c *Client) AuthStatus() error {
+	if _, err := c.runGH([]string{"auth", "status"}); err != nil {
+		return fmt.Errorf("gh is not authenticated: %w", err)
+	}
+	return nil
+}
+
+// CheckOrgAccess verifies the authenticated user can list repositories in org
+func (c *Client) CheckOrgAccess(org string) error {
+	if _, err := c.runGH([]string{"repo", "list", org, "--limit", "1", "--json", "name"}); err != nil {
+		return fmt.Errorf("cannot access %s: %w", org, err)
+	}
+	return nil
+}
@@ -2,9 +2,15 @@ package github
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"os/exec"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,11 +18,40 @@ import (
 )
 
 // Client wraps GitHub operations (using gh CLI)
-type Client struct{}
+type Client struct {
+	ctx  context.Context
+	opts Options
+}
+
+// unlimitedFetch is passed to gh --limit when no fetch limit is set; gh
+// pages through the results until they run out
+const unlimitedFetch = math.MaxInt32
+
+// SearchResultCap is the most results GitHub search returns for one query,
+// whatever the limit
+const SearchResultCap = 1000
 
-// NewClient creates a new GitHub client
-func NewClient() *Client {
-	return &Client{}
+// ghLimit formats a fetch limit for gh --limit; 0 or less means no limit
+func ghLimit(limit int) string {
+	if limit <= 0 {
+		limit = unlimitedFetch
+	}
+	return strconv.Itoa(limit)
+}
+
+// NewClient creates a GitHub client whose gh calls are bounded by ctx
+// (cancel it or give it a deadline to abort in-flight calls) and run with opts
+func NewClient(ctx context.Context, opts Options) *Client {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if opts.MaxRetries < 0 {
+		opts.MaxRetries = config.DefaultMaxRetries
+	}
+	if opts.LabelDelimiter == "" {
+		opts.LabelDelimiter = config.DefaultLabelDelimiter
+	}
+	return &Client{ctx: ctx, opts: opts}
 }
 
 // ghLabel represents a label from gh CLI
@@ -28,12 +63,7 @@ type ghLabel struct {
 
 // ListRepos lists repositories in an organization
 func (c *Client) ListRepos(org string) ([]string, error) {
-	cmd := exec.Command("gh", "repo", "list", org, "--limit", "500", "--json", "name")
-
-	// Unset GH_TOKEN to use default auth
-	cmd.Env = filterEnv("GH_TOKEN")
-
-	output, err := cmd.Output()
+	output, err := c.runGH([]string{"repo", "list", org, "--limit", "500", "--json", "name"})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list repos: %w", err)
 	}
@@ -52,12 +82,17 @@ func (c *Client) ListRepos(org string) ([]string, error) {
 	return names, nil
 }
 
+// RepoExists checks that a repository exists and is accessible
+func (c *Client) RepoExists(org, repo string) error {
+	if _, err := c.runGH([]string{"repo", "view", fmt.Sprintf("%s/%s", org, repo), "--json", "name"}); err != nil {
+		return fmt.Errorf("cannot access %s/%s: %w", org, repo, err)
+	}
+	return nil
+}
+
 // ListLabels lists labels for a repository
 func (c *Client) ListLabels(org, repo string) ([]config.Label, error) {
-	cmd := exec.Command("gh", "label", "list", "--repo", fmt.Sprintf("%s/%s", org, repo), "--json", "name,color,description")
-	cmd.Env = filterEnv("GH_TOKEN")
-
-	output, err := cmd.Output()
+	output, err := c.runGH([]string{"label", "list", "--repo", fmt.Sprintf("%s/%s", org, repo), "--json", "name,color,description"})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list labels: %w", err)
 	}
@@ -126,20 +161,43 @@ func (c *Client) SyncLabels(org, repo string, labels []config.Label, dryRun bool
 	return nil
 }
 
+// ApplyLabelFixes creates the missing labels and updates the modified ones
+// found by an audit, returning the names it created and updated. A label
+// that fails is skipped and its error included in the returned error. With
+// dryRun nothing is changed and the names that would be are returned.
+func (c *Client) ApplyLabelFixes(org, repo string, missing, modified []config.Label, dryRun bool) (created, updated []string, err error) {
+	repoPath := fmt.Sprintf("%s/%s", org, repo)
+
+	var errs []error
+	for _, label := range missing {
+		if !dryRun {
+			if err := c.createLabel(repoPath, label); err != nil {
+				errs = append(errs, fmt.Errorf("create %s: %w", label.Name, err))
+				continue
+			}
+		}
+		created = append(created, label.Name)
+	}
+	for _, label := range modified {
+		if !dryRun {
+			if err := c.editLabel(repoPath, label); err != nil {
+				errs = append(errs, fmt.Errorf("update %s: %w", label.Name, err))
+				continue
+			}
+		}
+		updated = append(updated, label.Name)
+	}
+	return created, updated, errors.Join(errs...)
+}
+
 func (c *Client) createLabel(repo string, label config.Label) error {
 	args := []string{"label", "create", label.Name, "--repo", repo, "--color", label.Color}
 	if label.Description != "" {
 		args = append(args, "--description", label.Description)
 	}
 
-	cmd := exec.Command("gh", args...)
-	cmd.Env = filterEnv("GH_TOKEN")
-
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("%s: %s", err, stderr.String())
+	if _, err := c.runGH(args); err != nil {
+		return err
 	}
 	return nil
 }
@@ -150,14 +208,8 @@ func (c *Client) editLabel(repo string, label config.Label) error {
 		args = append(args, "--description", label.Description)
 	}
 
-	cmd := exec.Command("gh", args...)
-	cmd.Env = filterEnv("GH_TOKEN")
-
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("%s: %s", err, stderr.String())
+	if _, err := c.runGH(args); err != nil {
+		return err
 	}
 	return nil
 }
@@ -169,14 +221,22 @@ func (c *Client) DeleteLabel(org, repo, name string, dryRun bool) error {
 		return nil
 	}
 
-	cmd := exec.Command("gh", "label", "delete", name, "--repo", fmt.Sprintf("%s/%s", org, repo), "--yes")
-	cmd.Env = filterEnv("GH_TOKEN")
+	if _, err := c.runGH([]string{"label", "delete", name, "--repo", fmt.Sprintf("%s/%s", org, repo), "--yes"}); err != nil {
+		return err
+	}
+	return nil
+}
 
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
+// RenameLabel renames a label in place; issues carrying it keep it under
+// the new name. It fails if the repo already has a label named to.
+func (c *Client) RenameLabel(org, repo, from, to string, dryRun bool) error {
+	if dryRun {
+		fmt.Printf("  Would rename: %s -> %s\n", from, to)
+		return nil
+	}
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("%s: %s", err, stderr.String())
+	if _, err := c.runGH([]string{"label", "edit", from, "--name", to, "--repo", fmt.Sprintf("%s/%s", org, repo)}); err != nil {
+		return err
 	}
 	return nil
 }
@@ -224,14 +284,17 @@ type ghIssue struct {
 
 // BoardIssue represents an issue for board display
 type BoardIssue struct {
-	Number   int      `json:"number"`
-	Title    string   `json:"title"`
-	Labels   []string `json:"labels"`
-	Assignee string   `json:"assignee"`
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	Labels    []string  `json:"labels"`
+	Assignee  string    `json:"assignee"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
-// ListIssuesForBoard lists issues with a specific label for board display
-func (c *Client) ListIssuesForBoard(org, repo, label string, includeClosed bool, limit int) ([]BoardIssue, error) {
+// ListIssuesForBoard lists issues carrying any of labels for board display.
+// Repeated --label flags are ANDed by gh, so several labels are matched with
+// one "label:a,b" search qualifier, which GitHub ORs. A limit of 0 lists all.
+func (c *Client) ListIssuesForBoard(org, repo string, labels []string, includeClosed bool, limit int) ([]BoardIssue, error) {
 	repoPath := fmt.Sprintf("%s/%s", org, repo)
 
 	state := "open"
@@ -239,15 +302,22 @@ func (c *Client) ListIssuesForBoard(org, repo, label string, includeClosed bool,
 		state = "all"
 	}
 
-	cmd := exec.Command("gh", "issue", "list",
-		"--repo", repoPath,
-		"--label", label,
-		"--json", "number,title,labels,assignees",
-		"--limit", fmt.Sprintf("%d", limit),
+	args := []string{"issue", "list", "--repo", repoPath}
+	if len(labels) == 1 {
+		args = append(args, "--label", labels[0])
+	} else {
+		quoted := make([]string, len(labels))
+		for i, l := range labels {
+			quoted[i] = fmt.Sprintf("%q", l)
+		}
+		args = append(args, "--search", "label:"+strings.Join(quoted, ","))
+	}
+	args = append(args,
+		"--json", "number,title,labels,assignees,createdAt",
+		"--limit", ghLimit(limit),
 		"--state", state)
-	cmd.Env = filterEnv("GH_TOKEN")
 
-	output, err := cmd.Output()
+	output, err := c.runGH(args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list issues: %w", err)
 	}
@@ -261,6 +331,7 @@ func (c *Client) ListIssuesForBoard(org, repo, label string, includeClosed bool,
 		Assignees []struct {
 			Login string `json:"login"`
 		} `json:"assignees"`
+		CreatedAt time.Time `json:"createdAt"`
 	}
 
 	if err := json.Unmarshal(output, &rawIssues); err != nil {
@@ -268,7 +339,14 @@ func (c *Client) ListIssuesForBoard(org, repo, label string, includeClosed bool,
 	}
 
 	var issues []BoardIssue
+	seen := make(map[int]bool)
 	for _, ri := range rawIssues {
+		// An issue carrying two aliases of one status is listed once
+		if seen[ri.Number] {
+			continue
+		}
+		seen[ri.Number] = true
+
 		var labels []string
 		for _, l := range ri.Labels {
 			labels = append(labels, l.Name)
@@ -280,21 +358,58 @@ func (c *Client) ListIssuesForBoard(org, repo, label string, includeClosed bool,
 		}
 
 		issues = append(issues, BoardIssue{
-			Number:   ri.Number,
-			Title:    ri.Title,
-			Labels:   labels,
-			Assignee: assignee,
+			Number:    ri.Number,
+			Title:     ri.Title,
+			Labels:    labels,
+			Assignee:  assignee,
+			CreatedAt: ri.CreatedAt,
 		})
 	}
 
 	return issues, nil
 }
 
-func (c *Client) listIssuesWithLabel(repo, label string) ([]ghIssue, error) {
-	cmd := exec.Command("gh", "issue", "list", "--repo", repo, "--label", label, "--json", "number,title", "--limit", "500", "--state", "all")
-	cmd.Env = filterEnv("GH_TOKEN")
+// CountIssuesWithLabel returns how many issues, open or closed, carry the
+// label. It uses the search API, which allows 30 requests a minute.
+func (c *Client) CountIssuesWithLabel(org, repo, label string) (int, error) {
+	query := fmt.Sprintf("repo:%s/%s is:issue label:%q", org, repo, label)
+	output, err := c.runGH([]string{"api", "-X", "GET", "search/issues",
+		"-f", "q=" + query, "-f", "per_page=1", "--jq", ".total_count"})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count issues labeled %s: %w", label, err)
+	}
+	return strconv.Atoi(strings.TrimSpace(string(output)))
+}
 
-	output, err := cmd.Output()
+// OpenIssueLabelCounts returns how many open issues carry each label, keyed
+// by lowercased label name
+func (c *Client) OpenIssueLabelCounts(org, repo string) (map[string]int, error) {
+	output, err := c.runGH([]string{"issue", "list", "--repo", fmt.Sprintf("%s/%s", org, repo),
+		"--state", "open", "--json", "labels", "--limit", ghLimit(0)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open issues: %w", err)
+	}
+
+	var issues []struct {
+		Labels []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+	}
+	if err := json.Unmarshal(output, &issues); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, issue := range issues {
+		for _, l := range issue.Labels {
+			counts[strings.ToLower(l.Name)]++
+		}
+	}
+	return counts, nil
+}
+
+func (c *Client) listIssuesWithLabel(repo, label string) ([]ghIssue, error) {
+	output, err := c.runGH([]string{"issue", "list", "--repo", repo, "--label", label, "--json", "number,title", "--limit", "500", "--state", "all"})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list issues: %w", err)
 	}
@@ -308,27 +423,15 @@ func (c *Client) listIssuesWithLabel(repo, label string) ([]ghIssue, error) {
 }
 
 func (c *Client) addLabelToIssue(repo string, issueNum int, label string) error {
-	cmd := exec.Command("gh", "issue", "edit", fmt.Sprintf("%d", issueNum), "--repo", repo, "--add-label", label)
-	cmd.Env = filterEnv("GH_TOKEN")
-
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("%s: %s", err, stderr.String())
+	if _, err := c.runGH([]string{"issue", "edit", fmt.Sprintf("%d", issueNum), "--repo", repo, "--add-label", label}); err != nil {
+		return err
 	}
 	return nil
 }
 
 func (c *Client) removeLabelFromIssue(repo string, issueNum int, label string) error {
-	cmd := exec.Command("gh", "issue", "edit", fmt.Sprintf("%d", issueNum), "--repo", repo, "--remove-label", label)
-	cmd.Env = filterEnv("GH_TOKEN")
-
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("%s: %s", err, stderr.String())
+	if _, err := c.runGH([]string{"issue", "edit", fmt.Sprintf("%d", issueNum), "--repo", repo, "--remove-label", label}); err != nil {
+		return err
 	}
 	return nil
 }
@@ -343,6 +446,25 @@ type IssueDetails struct {
 	ClosedAt  time.Time `json:"closedAt"`
 	Labels    []string  `json:"labels"`
 	Assignee  string    `json:"assignee"`
+	Milestone string    `json:"milestone"`
+	Author    string    `json:"author"`
+	// StateReason is why a closed issue was closed: completed, not_planned or duplicate
+	StateReason string `json:"stateReason"`
+}
+
+// ghAuthor is the author object in gh's --json output
+type ghAuthor struct {
+	Login string `json:"login"`
+	IsBot bool   `json:"is_bot"`
+}
+
+// String returns the author's login. gh shows GitHub Apps as "app/<name>";
+// they're returned as "<name>[bot]", the login the REST API and the web UI use.
+func (a ghAuthor) String() string {
+	if name, ok := strings.CutPrefix(a.Login, "app/"); ok && a.IsBot {
+		return name + "[bot]"
+	}
+	return a.Login
 }
 
 // IssueWithTimes contains issue with timeline data
@@ -356,35 +478,51 @@ type IssueWithTimes struct {
 	ReadyAt         time.Time `json:"readyAt"`         // When moved to ready
 	Labels          []string  `json:"labels"`
 	BlockedDuration float64   `json:"blockedDuration"` // Hours blocked
+	Author          string    `json:"author"`
+	StateReason     string    `json:"stateReason"` // completed, not_planned or duplicate
+}
+
+// NotPlanned reports whether the issue was closed without being completed,
+// as not planned or as a duplicate
+func (i IssueWithTimes) NotPlanned() bool {
+	return i.StateReason == "not_planned" || i.StateReason == "duplicate"
+}
+
+// closeReason returns a closed issue's stateReason in lowercase. gh reports
+// REOPENED for issues that were reopened, so open issues get none.
+func closeReason(state, reason string) string {
+	if !strings.EqualFold(state, "closed") {
+		return ""
+	}
+	return strings.ToLower(reason)
 }
 
 // GetIssueDetails gets detailed info for a single issue
 func (c *Client) GetIssueDetails(org, repo string, number int) (*IssueDetails, error) {
 	repoPath := fmt.Sprintf("%s/%s", org, repo)
 
-	cmd := exec.Command("gh", "issue", "view", fmt.Sprintf("%d", number),
+	output, err := c.runGH([]string{"issue", "view", fmt.Sprintf("%d", number),
 		"--repo", repoPath,
-		"--json", "number,title,state,createdAt,updatedAt,closedAt,labels,assignees")
-	cmd.Env = filterEnv("GH_TOKEN")
-
-	output, err := cmd.Output()
+		"--json", "number,title,state,stateReason,createdAt,updatedAt,closedAt,labels,assignees,author"})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get issue details: %w", err)
 	}
 
 	var raw struct {
-		Number    int       `json:"number"`
-		Title     string    `json:"title"`
-		State     string    `json:"state"`
-		CreatedAt time.Time `json:"createdAt"`
-		UpdatedAt time.Time `json:"updatedAt"`
-		ClosedAt  time.Time `json:"closedAt"`
-		Labels    []struct {
+		Number      int       `json:"number"`
+		Title       string    `json:"title"`
+		State       string    `json:"state"`
+		StateReason string    `json:"stateReason"`
+		CreatedAt   time.Time `json:"createdAt"`
+		UpdatedAt   time.Time `json:"updatedAt"`
+		ClosedAt    time.Time `json:"closedAt"`
+		Labels      []struct {
 			Name string `json:"name"`
 		} `json:"labels"`
 		Assignees []struct {
 			Login string `json:"login"`
 		} `json:"assignees"`
+		Author ghAuthor `json:"author"`
 	}
 
 	if err := json.Unmarshal(output, &raw); err != nil {
@@ -398,7 +536,9 @@ func (c *Client) GetIssueDetails(org, repo string, number int) (*IssueDetails, e
 		CreatedAt: raw.CreatedAt,
 		UpdatedAt: raw.UpdatedAt,
 		ClosedAt:  raw.ClosedAt,
+		Author:    raw.Author.String(),
 	}
+	details.StateReason = closeReason(raw.State, raw.StateReason)
 
 	for _, l := range raw.Labels {
 		details.Labels = append(details.Labels, l.Name)
@@ -411,33 +551,33 @@ func (c *Client) GetIssueDetails(org, repo string, number int) (*IssueDetails, e
 	return details, nil
 }
 
-// ListClosedIssuesWithTimes lists closed issues with timing info
-func (c *Client) ListClosedIssuesWithTimes(org, repo string, days int) ([]IssueWithTimes, error) {
+// ListClosedIssuesWithTimes lists issues closed in the last days with timing
+// info, at most limit (0 for no limit) and SearchResultCap
+func (c *Client) ListClosedIssuesWithTimes(org, repo string, days, limit int) ([]IssueWithTimes, error) {
 	repoPath := fmt.Sprintf("%s/%s", org, repo)
 	since := time.Now().AddDate(0, 0, -days).Format("2006-01-02")
 
-	cmd := exec.Command("gh", "issue", "list",
+	output, err := c.runGH([]string{"issue", "list",
 		"--repo", repoPath,
 		"--state", "closed",
-		"--json", "number,title,state,createdAt,closedAt,labels",
-		"--limit", "500",
-		"--search", fmt.Sprintf("closed:>=%s", since))
-	cmd.Env = filterEnv("GH_TOKEN")
-
-	output, err := cmd.Output()
+		"--json", "number,title,state,stateReason,createdAt,closedAt,labels,author",
+		"--limit", ghLimit(limit),
+		"--search", fmt.Sprintf("closed:>=%s", since)})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list closed issues: %w", err)
 	}
 
 	var rawIssues []struct {
-		Number    int       `json:"number"`
-		Title     string    `json:"title"`
-		State     string    `json:"state"`
-		CreatedAt time.Time `json:"createdAt"`
-		ClosedAt  time.Time `json:"closedAt"`
-		Labels    []struct {
+		Number      int       `json:"number"`
+		Title       string    `json:"title"`
+		State       string    `json:"state"`
+		StateReason string    `json:"stateReason"`
+		CreatedAt   time.Time `json:"createdAt"`
+		ClosedAt    time.Time `json:"closedAt"`
+		Labels      []struct {
 			Name string `json:"name"`
 		} `json:"labels"`
+		Author ghAuthor `json:"author"`
 	}
 
 	if err := json.Unmarshal(output, &rawIssues); err != nil {
@@ -452,7 +592,9 @@ func (c *Client) ListClosedIssuesWithTimes(org, repo string, days int) ([]IssueW
 			State:     ri.State,
 			CreatedAt: ri.CreatedAt,
 			ClosedAt:  ri.ClosedAt,
+			Author:    ri.Author.String(),
 		}
+		issue.StateReason = closeReason(ri.State, ri.StateReason)
 		for _, l := range ri.Labels {
 			issue.Labels = append(issue.Labels, l.Name)
 		}
@@ -482,18 +624,31 @@ type BlockedPeriod struct {
 	Start    time.Time
 	End      time.Time // zero if still blocked
 	Duration float64   // hours
+	Reason   string    // latest comment before the blocked label, if any
+}
+
+// blockedReasonWindow is how old a comment may be and still explain a blocked label
+const blockedReasonWindow = 24 * time.Hour
+
+// maxBlockedReasonLen caps stored blocked reasons
+const maxBlockedReasonLen = 80
+
+// blockedReason condenses a comment body to a single line of at most maxBlockedReasonLen characters
+func blockedReason(body string) string {
+	reason := strings.Join(strings.Fields(body), " ")
+	if runes := []rune(reason); len(runes) > maxBlockedReasonLen {
+		reason = string(runes[:maxBlockedReasonLen-3]) + "..."
+	}
+	return reason
 }
 
 // GetIssueTimeline gets timeline events for an issue
 func (c *Client) GetIssueTimeline(org, repo string, number int) (*TimelineResult, error) {
 	repoPath := fmt.Sprintf("%s/%s", org, repo)
 
-	cmd := exec.Command("gh", "api",
+	output, err := c.runGH([]string{"api",
 		fmt.Sprintf("repos/%s/issues/%d/timeline", repoPath, number),
-		"--paginate")
-	cmd.Env = filterEnv("GH_TOKEN")
-
-	output, err := cmd.Output()
+		"--paginate"})
 	if err != nil {
 		return nil, fmt.Errorf("timeline API failed: %w", err)
 	}
@@ -504,6 +659,7 @@ func (c *Client) GetIssueTimeline(org, repo string, number int) (*TimelineResult
 		Label     *struct {
 			Name string `json:"name"`
 		} `json:"label"`
+		Body string `json:"body"` // commented events
 	}
 
 	if err := json.Unmarshal(output, &rawEvents); err != nil {
@@ -515,8 +671,17 @@ func (c *Client) GetIssueTimeline(org, repo string, number int) (*TimelineResult
 	}
 
 	var blockedStart time.Time
+	var blockedWhy string
+	var lastComment struct {
+		body string
+		at   time.Time
+	}
 
 	for _, e := range rawEvents {
+		if e.Event == "commented" {
+			lastComment.body, lastComment.at = e.Body, e.CreatedAt
+			continue
+		}
 		if e.Label == nil {
 			continue
 		}
@@ -529,8 +694,7 @@ func (c *Client) GetIssueTimeline(org, repo string, number int) (*TimelineResult
 		result.Events = append(result.Events, evt)
 
 		// Track status label changes (first entry only)
-		if e.Event == "labeled" && strings.HasPrefix(strings.ToLower(e.Label.Name), "status:") {
-			status := extractStatus(e.Label.Name)
+		if status, ok := extractStatus(e.Label.Name, c.opts.LabelDelimiter); ok && e.Event == "labeled" {
 			if _, exists := result.StatusChanges[status]; !exists {
 				result.StatusChanges[status] = e.CreatedAt
 			}
@@ -539,12 +703,17 @@ func (c *Client) GetIssueTimeline(org, repo string, number int) (*TimelineResult
 		// Track blocked periods
 		if e.Event == "labeled" && strings.ToLower(e.Label.Name) == "blocked" {
 			blockedStart = e.CreatedAt
+			blockedWhy = ""
+			if !lastComment.at.IsZero() && e.CreatedAt.Sub(lastComment.at) <= blockedReasonWindow {
+				blockedWhy = blockedReason(lastComment.body)
+			}
 		}
 		if e.Event == "unlabeled" && strings.ToLower(e.Label.Name) == "blocked" && !blockedStart.IsZero() {
 			period := BlockedPeriod{
 				Start:    blockedStart,
 				End:      e.CreatedAt,
 				Duration: e.CreatedAt.Sub(blockedStart).Hours(),
+				Reason:   blockedWhy,
 			}
 			result.BlockedPeriods = append(result.BlockedPeriods, period)
 			result.TotalBlocked += period.Duration
@@ -557,6 +726,7 @@ func (c *Client) GetIssueTimeline(org, repo string, number int) (*TimelineResult
 		period := BlockedPeriod{
 			Start:    blockedStart,
 			Duration: time.Since(blockedStart).Hours(),
+			Reason:   blockedWhy,
 		}
 		result.BlockedPeriods = append(result.BlockedPeriods, period)
 		result.TotalBlocked += period.Duration
@@ -565,47 +735,60 @@ func (c *Client) GetIssueTimeline(org, repo string, number int) (*TimelineResult
 	return result, nil
 }
 
-// extractStatus extracts status name from label like "status: in-progress"
-func extractStatus(label string) string {
-	lower := strings.ToLower(label)
-	if strings.HasPrefix(lower, "status:") {
-		return strings.TrimSpace(strings.TrimPrefix(lower, "status:"))
-	}
-	if strings.HasPrefix(lower, "status ") {
-		return strings.TrimSpace(strings.TrimPrefix(lower, "status "))
-	}
-	return lower
+// extractStatus extracts status name from label like "status: in-progress",
+// written with the given label delimiter
+func extractStatus(label, delimiter string) (string, bool) {
+	return config.LabelValue(label, "status", delimiter)
 }
 
-// ListAllIssues lists all issues (open and closed) for metrics
+// ListAllIssues lists all issues (open and closed) for metrics, at most
+// limit (0 for no limit)
 func (c *Client) ListAllIssues(org, repo string, limit int) ([]IssueDetails, error) {
+	return c.listIssueDetails(org, repo, limit)
+}
+
+// ListIssuesUpdatedSince lists issues (open and closed) updated at or after since.
+// Reopening an issue bumps updatedAt, so issues closed before the cutoff
+// but reopened after it are included.
+func (c *Client) ListIssuesUpdatedSince(org, repo string, since time.Time, limit int) ([]IssueDetails, error) {
+	return c.listIssueDetails(org, repo, limit,
+		"--search", fmt.Sprintf("updated:>=%s", since.UTC().Format("2006-01-02T15:04:05Z")))
+}
+
+// listIssueDetails runs gh issue list for all states with optional extra args
+func (c *Client) listIssueDetails(org, repo string, limit int, extraArgs ...string) ([]IssueDetails, error) {
 	repoPath := fmt.Sprintf("%s/%s", org, repo)
 
-	cmd := exec.Command("gh", "issue", "list",
+	args := []string{"issue", "list",
 		"--repo", repoPath,
 		"--state", "all",
-		"--json", "number,title,state,createdAt,updatedAt,closedAt,labels,assignees",
-		"--limit", fmt.Sprintf("%d", limit))
-	cmd.Env = filterEnv("GH_TOKEN")
+		"--json", "number,title,state,stateReason,createdAt,updatedAt,closedAt,labels,assignees,milestone,author",
+		"--limit", ghLimit(limit)}
+	args = append(args, extraArgs...)
 
-	output, err := cmd.Output()
+	output, err := c.runGH(args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list issues: %w", err)
 	}
 
 	var rawIssues []struct {
-		Number    int       `json:"number"`
-		Title     string    `json:"title"`
-		State     string    `json:"state"`
-		CreatedAt time.Time `json:"createdAt"`
-		UpdatedAt time.Time `json:"updatedAt"`
-		ClosedAt  time.Time `json:"closedAt"`
-		Labels    []struct {
+		Number      int       `json:"number"`
+		Title       string    `json:"title"`
+		State       string    `json:"state"`
+		StateReason string    `json:"stateReason"`
+		CreatedAt   time.Time `json:"createdAt"`
+		UpdatedAt   time.Time `json:"updatedAt"`
+		ClosedAt    time.Time `json:"closedAt"`
+		Labels      []struct {
 			Name string `json:"name"`
 		} `json:"labels"`
 		Assignees []struct {
 			Login string `json:"login"`
 		} `json:"assignees"`
+		Milestone *struct {
+			Title string `json:"title"`
+		} `json:"milestone"`
+		Author ghAuthor `json:"author"`
 	}
 
 	if err := json.Unmarshal(output, &rawIssues); err != nil {
@@ -621,24 +804,30 @@ func (c *Client) ListAllIssues(org, repo string, limit int) ([]IssueDetails, err
 			CreatedAt: ri.CreatedAt,
 			UpdatedAt: ri.UpdatedAt,
 			ClosedAt:  ri.ClosedAt,
+			Author:    ri.Author.String(),
 		}
+		issue.StateReason = closeReason(ri.State, ri.StateReason)
 		for _, l := range ri.Labels {
 			issue.Labels = append(issue.Labels, l.Name)
 		}
 		if len(ri.Assignees) > 0 {
 			issue.Assignee = ri.Assignees[0].Login
 		}
+		if ri.Milestone != nil {
+			issue.Milestone = ri.Milestone.Title
+		}
 		issues = append(issues, issue)
 	}
 
 	return issues, nil
 }
 
-// filterEnv returns environment without specified variable
-func filterEnv(exclude string) []string {
+// filterEnv returns environment without the specified variables
+func filterEnv(exclude ...string) []string {
 	var env []string
 	for _, e := range exec.Command("").Environ() {
-		if !strings.HasPrefix(e, exclude+"=") {
+		name, _, _ := strings.Cut(e, "=")
+		if !slices.Contains(exclude, name) {
 			env = append(env, e)
 		}
 	}
@@ -669,14 +858,11 @@ type PRDetails struct {
 func (c *Client) ListPRs(org, repo string, limit int) ([]PRDetails, error) {
 	repoPath := fmt.Sprintf("%s/%s", org, repo)
 
-	cmd := exec.Command("gh", "pr", "list",
+	output, err := c.runGH([]string{"pr", "list",
 		"--repo", repoPath,
 		"--state", "all",
 		"--json", "number,title,state,isDraft,createdAt,updatedAt,mergedAt,closedAt,labels,author,assignees,additions,deletions,changedFiles",
-		"--limit", fmt.Sprintf("%d", limit))
-	cmd.Env = filterEnv("GH_TOKEN")
-
-	output, err := cmd.Output()
+		"--limit", fmt.Sprintf("%d", limit)})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list PRs: %w", err)
 	}
@@ -736,6 +922,111 @@ func (c *Client) ListPRs(org, repo string, limit int) ([]PRDetails, error) {
 	return prs, nil
 }
 
+// PRReview is a submitted pull request review
+type PRReview struct {
+	Reviewer    string    `json:"reviewer"`
+	State       string    `json:"state"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+// PRReviewTimeline holds what's needed to measure how long a PR waited for review
+type PRReviewTimeline struct {
+	Reviews     []PRReview  // Submitted reviews, oldest first
+	ReadyEvents []time.Time // ready_for_review events (PR was a draft), oldest first
+}
+
+// GetPRReviewTimeline gets a PR's submitted reviews and, when it has any,
+// the times it was marked ready for review
+func (c *Client) GetPRReviewTimeline(org, repo string, prNumber int) (*PRReviewTimeline, error) {
+	repoPath := fmt.Sprintf("%s/%s", org, repo)
+
+	output, err := c.runGH([]string{"api",
+		fmt.Sprintf("repos/%s/pulls/%d/reviews", repoPath, prNumber),
+		"--paginate"})
+	if err != nil {
+		return nil, fmt.Errorf("reviews API failed: %w", err)
+	}
+
+	var rawReviews []struct {
+		User *struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		State       string     `json:"state"`
+		SubmittedAt *time.Time `json:"submitted_at"`
+	}
+	if err := json.Unmarshal(output, &rawReviews); err != nil {
+		return nil, err
+	}
+
+	result := &PRReviewTimeline{}
+	for _, r := range rawReviews {
+		// Pending reviews have no submission time; deleted users have no login
+		if r.SubmittedAt == nil || r.User == nil {
+			continue
+		}
+		result.Reviews = append(result.Reviews, PRReview{Reviewer: r.User.Login, State: r.State, SubmittedAt: *r.SubmittedAt})
+	}
+	sort.Slice(result.Reviews, func(i, j int) bool {
+		return result.Reviews[i].SubmittedAt.Before(result.Reviews[j].SubmittedAt)
+	})
+
+	// Without reviews there's nothing to measure, so skip the timeline call
+	if len(result.Reviews) == 0 {
+		return result, nil
+	}
+
+	output, err = c.runGH([]string{"api",
+		fmt.Sprintf("repos/%s/issues/%d/timeline", repoPath, prNumber),
+		"--paginate"})
+	if err != nil {
+		return nil, fmt.Errorf("timeline API failed: %w", err)
+	}
+
+	var rawEvents []struct {
+		Event     string    `json:"event"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+	if err := json.Unmarshal(output, &rawEvents); err != nil {
+		return nil, err
+	}
+	for _, e := range rawEvents {
+		if e.Event == "ready_for_review" {
+			result.ReadyEvents = append(result.ReadyEvents, e.CreatedAt)
+		}
+	}
+
+	return result, nil
+}
+
+// ReviewTimeHours returns the hours from when the PR was open for review to
+// its first review by someone other than author, or 0 if there is none.
+// Review starts at creation, or at the last ready_for_review event before
+// the first review for PRs opened as drafts.
+func (t *PRReviewTimeline) ReviewTimeHours(author string, createdAt time.Time) float64 {
+	var first *PRReview
+	for i, r := range t.Reviews {
+		if !strings.EqualFold(r.Reviewer, author) {
+			first = &t.Reviews[i]
+			break
+		}
+	}
+	if first == nil {
+		return 0
+	}
+
+	start := createdAt
+	for _, ready := range t.ReadyEvents {
+		if !ready.After(first.SubmittedAt) && ready.After(start) {
+			start = ready
+		}
+	}
+
+	if hours := first.SubmittedAt.Sub(start).Hours(); hours > 0 {
+		return hours
+	}
+	return 0
+}
+
 // GetPRLinkedIssues gets issues linked to a PR
 func (c *Client) GetPRLinkedIssues(org, repo string, prNumber int) ([]int, error) {
 	repoPath := fmt.Sprintf("%s/%s", org, repo)
@@ -753,10 +1044,7 @@ func (c *Client) GetPRLinkedIssues(org, repo string, prNumber int) ([]int, error
 		}
 	}`, org, repo, prNumber)
 
-	cmd := exec.Command("gh", "api", "graphql", "-f", fmt.Sprintf("query=%s", query))
-	cmd.Env = filterEnv("GH_TOKEN")
-
-	output, err := cmd.Output()
+	output, err := c.runGH([]string{"api", "graphql", "-f", fmt.Sprintf("query=%s", query)})
 	if err != nil {
 		// Fallback: try to parse PR body for issue references
 		return c.parseLinkedIssuesFromPR(repoPath, prNumber)
@@ -789,12 +1077,9 @@ func (c *Client) GetPRLinkedIssues(org, repo string, prNumber int) ([]int, error
 
 // parseLinkedIssuesFromPR parses PR body for issue references
 func (c *Client) parseLinkedIssuesFromPR(repo string, prNumber int) ([]int, error) {
-	cmd := exec.Command("gh", "pr", "view", fmt.Sprintf("%d", prNumber),
+	output, err := c.runGH([]string{"pr", "view", fmt.Sprintf("%d", prNumber),
 		"--repo", repo,
-		"--json", "body")
-	cmd.Env = filterEnv("GH_TOKEN")
-
-	output, err := cmd.Output()
+		"--json", "body"})
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,178 @@
+package github
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	retryBaseDelay = 2 * time.Second
+	retryMaxDelay  = time.Minute
+)
+
+// rateLimitSignatures are stderr fragments gh prints when GitHub throttles a request
+var rateLimitSignatures = []string{
+	"api rate limit exceeded",
+	"secondary rate limit",
+}
+
+var (
+	// storedLogin reports whether gh has a login of its own (keyring, config
+	// or GITHUB_TOKEN) to fall back on when GH_TOKEN is removed. Checked once.
+	storedLogin     = probeStoredLogin
+	storedLoginOnce sync.Once
+	hasStoredLogin  bool
+)
+
+// Options configure every gh invocation a Client makes
+type Options struct {
+	// MaxRetries is how many times rate-limited calls are retried; < 0 uses
+	// config.DefaultMaxRetries
+	MaxRetries int
+	// Host is the GitHub host calls target; empty keeps GH_HOST from the
+	// environment, or github.com when that isn't set either
+	Host string
+	// UseGHToken decides whether a GH_TOKEN in the environment reaches gh.
+	// nil keeps it in CI and when gh has no login of its own, and removes it
+	// otherwise so the interactive login wins.
+	UseGHToken *bool
+	// LabelDelimiter separates status labels' category from their value in
+	// issue timelines; empty uses config.DefaultLabelDelimiter
+	LabelDelimiter string
+}
+
+// Host returns the GitHub host the client's gh calls go to: the configured
+// host, GH_HOST or github.com
+func (c *Client) Host() string {
+	if c.opts.Host != "" {
+		return c.opts.Host
+	}
+	if host := os.Getenv("GH_HOST"); host != "" {
+		return host
+	}
+	return "github.com"
+}
+
+// IsCI reports whether kanban runs in a CI environment
+func IsCI() bool {
+	return os.Getenv("CI") != "" || os.Getenv("GITHUB_ACTIONS") != ""
+}
+
+// ghEnv is the environment gh runs with: GH_TOKEN removed unless
+// useGHToken allows it and, when a host is configured, GH_HOST set to it
+// (replacing any inherited value)
+func (c *Client) ghEnv() []string {
+	return c.hostEnv(!c.useGHToken())
+}
+
+// hostEnv is the environment with GH_HOST applied, and GH_TOKEN removed if
+// stripToken is set
+func (c *Client) hostEnv(stripToken bool) []string {
+	var exclude []string
+	if stripToken {
+		exclude = append(exclude, "GH_TOKEN")
+	}
+	if c.opts.Host == "" {
+		return filterEnv(exclude...)
+	}
+	return append(filterEnv(append(exclude, "GH_HOST")...), "GH_HOST="+c.opts.Host)
+}
+
+// useGHToken reports whether gh calls keep GH_TOKEN: as configured, else in
+// CI or when gh has no login to fall back on
+func (c *Client) useGHToken() bool {
+	if os.Getenv("GH_TOKEN") == "" {
+		return false
+	}
+	if c.opts.UseGHToken != nil {
+		return *c.opts.UseGHToken
+	}
+	if IsCI() {
+		return true
+	}
+	storedLoginOnce.Do(func() { hasStoredLogin = storedLogin(c) })
+	return !hasStoredLogin
+}
+
+// probeStoredLogin runs `gh auth status` without GH_TOKEN
+func probeStoredLogin(c *Client) bool {
+	cmd := exec.CommandContext(c.ctx, "gh", "auth", "status")
+	cmd.Env = c.hostEnv(true)
+	return cmd.Run() == nil
+}
+
+// ghError is a failed gh invocation with its stderr
+type ghError struct {
+	err    error
+	stderr string
+}
+
+func (e *ghError) Error() string {
+	if e.stderr == "" {
+		return e.err.Error()
+	}
+	return fmt.Sprintf("%v: %s", e.err, e.stderr)
+}
+
+func (e *ghError) Unwrap() error {
+	return e.err
+}
+
+// runGH runs gh with the environment from ghEnv and returns its stdout. Calls that fail
+// with a rate-limit error are retried with exponential backoff.
+func (c *Client) runGH(args []string) ([]byte, error) {
+	name := "gh " + strings.Join(args[:min(2, len(args))], " ")
+	for attempt := 0; ; attempt++ {
+		cmd := exec.CommandContext(c.ctx, "gh", args...)
+		cmd.Env = c.ghEnv()
+
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+
+		output, err := cmd.Output()
+		if err == nil {
+			return output, nil
+		}
+		if ctxErr := c.ctx.Err(); ctxErr != nil {
+			return nil, fmt.Errorf("%s aborted: %w", name, ctxErr)
+		}
+
+		errMsg := strings.TrimSpace(stderr.String())
+		if !isRateLimited(errMsg) || attempt >= c.opts.MaxRetries {
+			return nil, &ghError{err: err, stderr: errMsg}
+		}
+
+		delay := retryDelay(attempt)
+		fmt.Fprintf(os.Stderr, "  Rate limited by GitHub, retrying in %s (%d/%d)\n", delay, attempt+1, c.opts.MaxRetries)
+		select {
+		case <-time.After(delay):
+		case <-c.ctx.Done():
+			return nil, fmt.Errorf("%s aborted: %w", name, c.ctx.Err())
+		}
+	}
+}
+
+// isRateLimited reports whether gh's stderr indicates a rate limit
+func isRateLimited(stderr string) bool {
+	lower := strings.ToLower(stderr)
+	for _, sig := range rateLimitSignatures {
+		if strings.Contains(lower, sig) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryDelay is the backoff before retry attempt+1: 2s, 4s, 8s, ... capped at a minute
+func retryDelay(attempt int) time.Duration {
+	delay := retryBaseDelay << attempt
+	if delay > retryMaxDelay || delay <= 0 {
+		return retryMaxDelay
+	}
+	return delay
+}
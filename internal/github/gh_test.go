@@ -0,0 +1,387 @@
+package github
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// withStoredLogin fakes the `gh auth status` probe for the test
+func withStoredLogin(t *testing.T, loggedIn bool) {
+	t.Helper()
+	storedLogin = func(*Client) bool { return loggedIn }
+	storedLoginOnce = sync.Once{}
+	t.Cleanup(func() {
+		storedLogin = probeStoredLogin
+		storedLoginOnce = sync.Once{}
+	})
+}
+
+// withFakeGH puts a gh on PATH that prints output and records its arguments,
+// one per line, to the returned file
+func withFakeGH(t *testing.T, output string) string {
+	t.Helper()
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "args")
+	script := "#!/bin/sh\nprintf '%s\\n' \"$@\" > " + argsFile + "\ncat <<'EOF'\n" + output + "\nEOF\n"
+	if err := os.WriteFile(filepath.Join(dir, "gh"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("GH_TOKEN", "")
+	return argsFile
+}
+
+func TestFilterEnv(t *testing.T) {
+	t.Setenv("GH_TOKEN", "ghp_token")
+	t.Setenv("GH_HOST", "github.example.com")
+	t.Setenv("GH_TOKEN_EXTRA", "kept")
+
+	env := filterEnv("GH_TOKEN", "GH_HOST")
+	for _, e := range env {
+		if strings.HasPrefix(e, "GH_TOKEN=") || strings.HasPrefix(e, "GH_HOST=") {
+			t.Errorf("filterEnv() kept %q", e)
+		}
+	}
+	if !slices.Contains(env, "GH_TOKEN_EXTRA=kept") {
+		t.Error("filterEnv() should only drop exact variable names")
+	}
+	if !slices.Contains(filterEnv(), "GH_TOKEN=ghp_token") {
+		t.Error("filterEnv() without names should keep everything")
+	}
+}
+
+func TestGHEnv_Host(t *testing.T) {
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GH_HOST", "inherited.example.com")
+	withStoredLogin(t, true)
+
+	tests := []struct {
+		name     string
+		host     string
+		wantHost string
+	}{
+		{"inherited GH_HOST is kept", "", "GH_HOST=inherited.example.com"},
+		{"configured host overrides GH_HOST", "github.example.com", "GH_HOST=github.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(context.Background(), Options{Host: tt.host})
+
+			var hosts []string
+			for _, e := range c.ghEnv() {
+				if strings.HasPrefix(e, "GH_HOST=") {
+					hosts = append(hosts, e)
+				}
+			}
+			if len(hosts) != 1 || hosts[0] != tt.wantHost {
+				t.Errorf("GH_HOST entries = %v, want [%s]", hosts, tt.wantHost)
+			}
+		})
+	}
+}
+
+func TestGHEnv_Token(t *testing.T) {
+	yes, no := true, false
+	tests := []struct {
+		name        string
+		useGHToken  *bool
+		ci          string
+		storedLogin bool
+		wantToken   bool
+	}{
+		{"interactive login wins", nil, "", true, false},
+		{"token used without a login", nil, "", false, true},
+		{"token used in CI", nil, "true", true, true},
+		{"forced on", &yes, "", true, true},
+		{"forced off", &no, "true", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GH_TOKEN", "ghp_token")
+			t.Setenv("CI", tt.ci)
+			t.Setenv("GITHUB_ACTIONS", "")
+			withStoredLogin(t, tt.storedLogin)
+			c := NewClient(context.Background(), Options{UseGHToken: tt.useGHToken})
+
+			if got := slices.Contains(c.ghEnv(), "GH_TOKEN=ghp_token"); got != tt.wantToken {
+				t.Errorf("GH_TOKEN passed to gh = %v, want %v", got, tt.wantToken)
+			}
+		})
+	}
+}
+
+func TestHost(t *testing.T) {
+	withStoredLogin(t, true)
+
+	c := NewClient(context.Background(), Options{})
+	t.Setenv("GH_HOST", "")
+	if got := c.Host(); got != "github.com" {
+		t.Errorf("Host() = %q, want github.com", got)
+	}
+
+	t.Setenv("GH_HOST", "inherited.example.com")
+	if got := c.Host(); got != "inherited.example.com" {
+		t.Errorf("Host() = %q, want inherited.example.com", got)
+	}
+
+	c = NewClient(context.Background(), Options{Host: "github.example.com"})
+	if got := c.Host(); got != "github.example.com" {
+		t.Errorf("Host() = %q, want github.example.com", got)
+	}
+}
+
+func TestGHAuthor_String(t *testing.T) {
+	tests := []struct {
+		author ghAuthor
+		want   string
+	}{
+		{ghAuthor{Login: "alice"}, "alice"},
+		{ghAuthor{Login: "app/dependabot", IsBot: true}, "dependabot[bot]"},
+		{ghAuthor{Login: "renovate[bot]", IsBot: true}, "renovate[bot]"},
+		{ghAuthor{Login: "app/not-a-bot"}, "app/not-a-bot"},
+		{ghAuthor{}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.author.String(); got != tt.want {
+			t.Errorf("ghAuthor%+v.String() = %q, want %q", tt.author, got, tt.want)
+		}
+	}
+}
+
+func TestListIssuesForBoard_Limit(t *testing.T) {
+	argsFile := withFakeGH(t, "[]")
+
+	tests := []struct {
+		limit int
+		want  string
+	}{
+		{5, "5"},
+		{0, "2147483647"}, // no limit
+	}
+	for _, tt := range tests {
+		if _, err := NewClient(context.Background(), Options{}).ListIssuesForBoard("acme", "app", []string{"status: ready"}, false, tt.limit); err != nil {
+			t.Fatalf("ListIssuesForBoard() error: %v", err)
+		}
+		data, err := os.ReadFile(argsFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		args := strings.Split(strings.TrimSpace(string(data)), "\n")
+		i := slices.Index(args, "--limit")
+		if i < 0 || i+1 >= len(args) || args[i+1] != tt.want {
+			t.Errorf("limit %d: gh args = %v, want --limit %s", tt.limit, args, tt.want)
+		}
+	}
+}
+
+func TestExtractStatus(t *testing.T) {
+	tests := []struct {
+		delimiter string
+		label     string
+		want      string
+		wantOK    bool
+	}{
+		{": ", "status: in-progress", "in-progress", true},
+		{": ", "Status:Review", "review", true},
+		{": ", "blocked", "", false},
+		{"/", "status/in-progress", "in-progress", true},
+		{"/", "status: in-progress", "", false},
+		{" ", "status review", "review", true},
+	}
+
+	for _, tt := range tests {
+		got, ok := extractStatus(tt.label, tt.delimiter)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("extractStatus(%q) with delimiter %q = %q, %v; want %q, %v", tt.label, tt.delimiter, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestCloseReason(t *testing.T) {
+	tests := []struct {
+		state, reason string
+		want          string
+		notPlanned    bool
+	}{
+		{"CLOSED", "COMPLETED", "completed", false},
+		{"CLOSED", "NOT_PLANNED", "not_planned", true},
+		{"CLOSED", "DUPLICATE", "duplicate", true},
+		{"CLOSED", "", "", false},
+		{"OPEN", "REOPENED", "", false},
+	}
+
+	for _, tt := range tests {
+		got := closeReason(tt.state, tt.reason)
+		if got != tt.want {
+			t.Errorf("closeReason(%q, %q) = %q, want %q", tt.state, tt.reason, got, tt.want)
+		}
+		if notPlanned := (IssueWithTimes{StateReason: got}).NotPlanned(); notPlanned != tt.notPlanned {
+			t.Errorf("NotPlanned() for %q = %v, want %v", got, notPlanned, tt.notPlanned)
+		}
+	}
+}
+
+func TestProjectStatusSource_Normalize(t *testing.T) {
+	p := NewProjectStatusSource(nil, "acme", 1, "", map[string]string{
+		"Code Review": "review",
+		"Doing":       "testing", // overrides a default
+	})
+
+	tests := []struct {
+		column string
+		want   string
+	}{
+		{"Todo", "ready"},
+		{"  In Progress ", "in-progress"},
+		{"QA", "testing"},
+		{"code review", "review"},
+		{"DOING", "testing"},
+		{"Icebox", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := p.Normalize(tt.column); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.column, got, tt.want)
+		}
+	}
+	if p.field != "Status" {
+		t.Errorf("field = %q, want the Status default", p.field)
+	}
+}
+
+func TestProjectStatusSource_Load(t *testing.T) {
+	argsFile := withFakeGH(t, `{"data":{"organization":{"projectV2":{"items":{"nodes":[
+  {"content":{"number":1,"repository":{"nameWithOwner":"acme/app"}},"fieldValues":{"nodes":[
+    {"name":"In Progress","field":{"name":"Status"}},{"name":"P1","field":{"name":"Priority"}}]}},
+  {"content":{"number":1,"repository":{"nameWithOwner":"other/app"}},"fieldValues":{"nodes":[
+    {"name":"Done","field":{"name":"Status"}}]}},
+  {"content":{"number":2,"repository":{"nameWithOwner":"acme/app"}},"fieldValues":{"nodes":[
+    {"name":"Icebox","field":{"name":"status"}}]}},
+  {"content":{},"fieldValues":{"nodes":[{"name":"Todo","field":{"name":"Status"}}]}}]}}}}}`)
+
+	p := NewProjectStatusSource(NewClient(context.Background(), Options{}), "acme", 7, "", nil)
+	if err := p.Load(); err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	data, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if i := slices.Index(args, "org=acme"); i < 1 || args[i-1] != "-f" {
+		t.Errorf("gh args = %v, want the org passed as a string with -f", args)
+	}
+	if i := slices.Index(args, "number=7"); i < 1 || args[i-1] != "-F" {
+		t.Errorf("gh args = %v, want the number passed typed with -F", args)
+	}
+
+	tests := []struct {
+		repo   string
+		number int
+		want   string
+	}{
+		{"acme/app", 1, "in-progress"},
+		{"ACME/App", 1, "in-progress"},
+		{"other/app", 1, "done"}, // same repo name in another org
+		{"acme/app", 2, ""},      // unmapped column
+		{"acme/app", 3, ""},      // not on the board
+		{"app", 1, ""},
+	}
+	for _, tt := range tests {
+		if got := p.Status(tt.repo, tt.number); got != tt.want {
+			t.Errorf("Status(%q, %d) = %q, want %q", tt.repo, tt.number, got, tt.want)
+		}
+	}
+}
+
+func TestPRReviewTimeline_ReviewTimeHours(t *testing.T) {
+	created := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time { return created.Add(time.Duration(hours) * time.Hour) }
+
+	tests := []struct {
+		name     string
+		timeline PRReviewTimeline
+		want     float64
+	}{
+		{"open, no reviews yet", PRReviewTimeline{}, 0},
+		{"only the author commented", PRReviewTimeline{Reviews: []PRReview{
+			{Reviewer: "Alice", State: "COMMENTED", SubmittedAt: at(2)},
+		}}, 0},
+		{"first review by someone else", PRReviewTimeline{Reviews: []PRReview{
+			{Reviewer: "alice", State: "COMMENTED", SubmittedAt: at(1)},
+			{Reviewer: "bob", State: "APPROVED", SubmittedAt: at(5)},
+			{Reviewer: "carol", State: "APPROVED", SubmittedAt: at(9)},
+		}}, 5},
+		{"still open with changes requested", PRReviewTimeline{Reviews: []PRReview{
+			{Reviewer: "bob", State: "CHANGES_REQUESTED", SubmittedAt: at(3)},
+		}}, 3},
+		{"draft, still awaiting review", PRReviewTimeline{ReadyEvents: []time.Time{at(10)}}, 0},
+		{"draft marked ready", PRReviewTimeline{
+			Reviews:     []PRReview{{Reviewer: "bob", State: "APPROVED", SubmittedAt: at(30)}},
+			ReadyEvents: []time.Time{at(20), at(40)}, // the second came after the review
+		}, 10},
+		{"draft reviewed before it was ready", PRReviewTimeline{
+			Reviews:     []PRReview{{Reviewer: "bob", State: "COMMENTED", SubmittedAt: at(4)}},
+			ReadyEvents: []time.Time{at(8)},
+		}, 4},
+	}
+	for _, tt := range tests {
+		if got := tt.timeline.ReviewTimeHours("alice", created); got != tt.want {
+			t.Errorf("%s: ReviewTimeHours() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBlockedReason(t *testing.T) {
+	long := strings.Repeat("waiting ", 20)
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"empty", "", ""},
+		{"one line", "Waiting on the API team", "Waiting on the API team"},
+		{"whitespace collapsed", "  Waiting on\n\n  the API   team\n", "Waiting on the API team"},
+		{"truncated", long, strings.TrimSpace(long)[:maxBlockedReasonLen-3] + "..."},
+	}
+	for _, tt := range tests {
+		if got := blockedReason(tt.body); got != tt.want {
+			t.Errorf("%s: blockedReason(%q) = %q, want %q", tt.name, tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestGetIssueTimeline_BlockedReason(t *testing.T) {
+	// Blocked right after a comment, which becomes the reason, then again
+	// days later with only that stale comment to go on
+	withFakeGH(t, `[
+  {"event": "commented", "created_at": "2026-03-02T09:00:00Z", "body": "Waiting on\n the API team"},
+  {"event": "labeled", "created_at": "2026-03-02T10:00:00Z", "label": {"name": "blocked"}},
+  {"event": "unlabeled", "created_at": "2026-03-02T14:00:00Z", "label": {"name": "blocked"}},
+  {"event": "labeled", "created_at": "2026-03-05T10:00:00Z", "label": {"name": "Blocked"}},
+  {"event": "unlabeled", "created_at": "2026-03-05T12:00:00Z", "label": {"name": "Blocked"}}
+]`)
+
+	timeline, err := NewClient(context.Background(), Options{}).GetIssueTimeline("acme", "app", 1)
+	if err != nil {
+		t.Fatalf("GetIssueTimeline() error: %v", err)
+	}
+	want := []string{"Waiting on the API team", ""}
+	if len(timeline.BlockedPeriods) != len(want) {
+		t.Fatalf("got %d blocked periods, want %d", len(timeline.BlockedPeriods), len(want))
+	}
+	for i, p := range timeline.BlockedPeriods {
+		if p.Reason != want[i] {
+			t.Errorf("period %d reason = %q, want %q", i, p.Reason, want[i])
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// defaultProjectStatusMap normalizes common Projects v2 column names.
+// Keys are lowercase; lookups are case-insensitive.
+var defaultProjectStatusMap = map[string]string{
+	"backlog":     "backlog",
+	"todo":        "ready",
+	"to do":       "ready",
+	"ready":       "ready",
+	"in progress": "in-progress",
+	"in-progress": "in-progress",
+	"doing":       "in-progress",
+	"in review":   "review",
+	"review":      "review",
+	"testing":     "testing",
+	"qa":          "testing",
+	"done":        "done",
+}
+
+// ProjectStatusSource reads issue statuses from a GitHub Projects v2
+// single-select field instead of status: labels
+type ProjectStatusSource struct {
+	client    *Client
+	org       string
+	number    int
+	field     string
+	statusMap map[string]string
+
+	// lowercased repo full name (org/repo) -> issue number -> project column name
+	columns map[string]map[int]string
+}
+
+// NewProjectStatusSource creates a status source for an org-level project,
+// read through client. statusMap maps project column names to internal
+// statuses and is merged over the built-in defaults; field defaults to "Status".
+func NewProjectStatusSource(client *Client, org string, number int, field string, statusMap map[string]string) *ProjectStatusSource {
+	if field == "" {
+		field = "Status"
+	}
+	merged := make(map[string]string, len(defaultProjectStatusMap)+len(statusMap))
+	for k, v := range defaultProjectStatusMap {
+		merged[k] = v
+	}
+	for k, v := range statusMap {
+		merged[strings.ToLower(k)] = v
+	}
+	return &ProjectStatusSource{
+		client:    client,
+		org:       org,
+		number:    number,
+		field:     field,
+		statusMap: merged,
+		columns:   make(map[string]map[int]string),
+	}
+}
+
+const projectItemsQuery = `query($org: String!, $number: Int!, $endCursor: String) {
+  organization(login: $org) {
+    projectV2(number: $number) {
+      items(first: 100, after: $endCursor) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          content {
+            ... on Issue { number repository { nameWithOwner } }
+          }
+          fieldValues(first: 20) {
+            nodes {
+              ... on ProjectV2ItemFieldSingleSelectValue {
+                name
+                field { ... on ProjectV2SingleSelectField { name } }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// Load fetches all project items and their status field values
+func (p *ProjectStatusSource) Load() error {
+	output, err := p.client.runGH([]string{"api", "graphql", "--paginate",
+		"-f", "query=" + projectItemsQuery,
+		"-f", "org=" + p.org,
+		"-F", fmt.Sprintf("number=%d", p.number)})
+	if err != nil {
+		return fmt.Errorf("failed to query project %d: %w", p.number, err)
+	}
+
+	// --paginate emits one JSON document per page
+	dec := json.NewDecoder(bytes.NewReader(output))
+	for {
+		var page struct {
+			Data struct {
+				Organization struct {
+					ProjectV2 *struct {
+						Items struct {
+							Nodes []struct {
+								Content struct {
+									Number     int `json:"number"`
+									Repository struct {
+										NameWithOwner string `json:"nameWithOwner"`
+									} `json:"repository"`
+								} `json:"content"`
+								FieldValues struct {
+									Nodes []struct {
+										Name  string `json:"name"`
+										Field struct {
+											Name string `json:"name"`
+										} `json:"field"`
+									} `json:"nodes"`
+								} `json:"fieldValues"`
+							} `json:"nodes"`
+						} `json:"items"`
+					} `json:"projectV2"`
+				} `json:"organization"`
+			} `json:"data"`
+		}
+		if err := dec.Decode(&page); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("failed to parse project items: %w", err)
+		}
+
+		project := page.Data.Organization.ProjectV2
+		if project == nil {
+			return fmt.Errorf("project %d not found in %s", p.number, p.org)
+		}
+
+		for _, item := range project.Items.Nodes {
+			// Skip draft issues and pull requests
+			if item.Content.Number == 0 || item.Content.Repository.NameWithOwner == "" {
+				continue
+			}
+			for _, fv := range item.FieldValues.Nodes {
+				if strings.EqualFold(fv.Field.Name, p.field) && fv.Name != "" {
+					// Org projects can hold issues from other orgs' repos, so
+					// key by full name; GitHub names are case-insensitive
+					repo := strings.ToLower(item.Content.Repository.NameWithOwner)
+					if p.columns[repo] == nil {
+						p.columns[repo] = make(map[int]string)
+					}
+					p.columns[repo][item.Content.Number] = fv.Name
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Status returns the internal status for issue number of fullName
+// (org/repo), or "" if the issue is not in the project or its column has no
+// mapping
+func (p *ProjectStatusSource) Status(fullName string, number int) string {
+	column, ok := p.columns[strings.ToLower(fullName)][number]
+	if !ok {
+		return ""
+	}
+	return p.Normalize(column)
+}
+
+// Normalize maps a project column name to an internal status
+func (p *ProjectStatusSource) Normalize(column string) string {
+	key := strings.ToLower(strings.TrimSpace(column))
+	if status, ok := p.statusMap[key]; ok {
+		return status
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+// Package notify posts bottleneck alerts to chat services. A Report is built
+// once and each Sender turns it into its own payload, so adding a service
+// only means writing another Sender.
+package notify
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Report is a titled list of alert sections, e.g. WIP violations or
+// stalled issues
+type Report struct {
+	Title    string
+	Sections []Section
+}
+
+// Section is one kind of alert with a line per affected column or issue
+type Section struct {
+	Heading string
+	Lines   []string
+}
+
+// Add appends a section, skipping it when it has no lines
+func (r *Report) Add(heading string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	r.Sections = append(r.Sections, Section{Heading: heading, Lines: lines})
+}
+
+// Alerts returns the number of alert lines across all sections
+func (r Report) Alerts() int {
+	n := 0
+	for _, s := range r.Sections {
+		n += len(s.Lines)
+	}
+	return n
+}
+
+// Sender delivers a report to one chat service
+type Sender interface {
+	// Payload returns the request body Send would post
+	Payload(r Report) ([]byte, error)
+	Send(r Report) error
+}
+
+// Text renders a report as plain text, one bulleted block per section.
+// bold wraps headings in the service's bold markup.
+func Text(r Report, bold func(string) string) string {
+	var b strings.Builder
+	b.WriteString(bold(r.Title))
+	for _, s := range r.Sections {
+		fmt.Fprintf(&b, "\n\n%s (%d)", bold(s.Heading), len(s.Lines))
+		for _, line := range s.Lines {
+			b.WriteString("\n• " + line)
+		}
+	}
+	return b.String()
+}
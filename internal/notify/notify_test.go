@@ -0,0 +1,88 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReport_Add(t *testing.T) {
+	r := Report{Title: "alerts"}
+	r.Add("WIP", []string{"app: review has 3 items (limit: 2)"})
+	r.Add("Stalled", nil)
+	r.Add("Blocked", []string{"app#1", "app#2"})
+
+	if len(r.Sections) != 2 {
+		t.Fatalf("Sections = %d, want 2 (empty sections skipped)", len(r.Sections))
+	}
+	if got := r.Alerts(); got != 3 {
+		t.Errorf("Alerts() = %d, want 3", got)
+	}
+	if got := (Report{}).Alerts(); got != 0 {
+		t.Errorf("empty Alerts() = %d, want 0", got)
+	}
+}
+
+func TestText(t *testing.T) {
+	r := Report{Title: "alerts"}
+	r.Add("Blocked", []string{"app#1", "app#2"})
+
+	got := Text(r, func(s string) string { return "*" + s + "*" })
+	want := "*alerts*\n\n*Blocked* (2)\n• app#1\n• app#2"
+	if got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+}
+
+func TestSlack_Payload(t *testing.T) {
+	r := Report{Title: "alerts"}
+	r.Add("Blocked", []string{"app#1 Fix <script> & co"})
+
+	payload, err := NewSlack("https://example.com").Payload(r)
+	if err != nil {
+		t.Fatalf("Payload() error: %v", err)
+	}
+	var msg slackMessage
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	if !strings.Contains(msg.Text, "Fix &lt;script&gt; &amp; co") {
+		t.Errorf("text %q should escape Slack markup", msg.Text)
+	}
+}
+
+func TestSlack_Send(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost || req.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %s with Content-Type %q, want a JSON POST", req.Method, req.Header.Get("Content-Type"))
+		}
+		body, _ = io.ReadAll(req.Body)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	r := Report{Title: "alerts"}
+	r.Add("Blocked", []string{"app#1"})
+	if err := NewSlack(server.URL).Send(r); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+	if !strings.Contains(string(body), "app#1") {
+		t.Errorf("posted body %q is missing the alert", body)
+	}
+}
+
+func TestSlack_SendError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	err := NewSlack(server.URL).Send(Report{Title: "alerts"})
+	if err == nil || !strings.Contains(err.Error(), "invalid_token") {
+		t.Errorf("Send() error = %v, want one mentioning invalid_token", err)
+	}
+}
@@ -0,0 +1,78 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Slack posts reports to a Slack incoming webhook
+type Slack struct {
+	WebhookURL string
+	Client     *http.Client
+}
+
+// NewSlack returns a Slack sender for webhookURL
+func NewSlack(webhookURL string) *Slack {
+	return &Slack{
+		WebhookURL: webhookURL,
+		Client:     &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// slackEscape escapes the characters Slack treats as markup
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// Payload returns the webhook JSON body for r, in Slack mrkdwn
+func (s *Slack) Payload(r Report) ([]byte, error) {
+	escaped := Report{Title: slackEscape(r.Title)}
+	for _, section := range r.Sections {
+		lines := make([]string, len(section.Lines))
+		for i, line := range section.Lines {
+			lines[i] = slackEscape(line)
+		}
+		escaped.Sections = append(escaped.Sections, Section{Heading: slackEscape(section.Heading), Lines: lines})
+	}
+
+	text := Text(escaped, func(s string) string { return "*" + s + "*" })
+
+	// Keep the entities readable in --dry-run output; Slack decodes either way
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(slackMessage{Text: text}); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// Send posts r to the webhook
+func (s *Slack) Send(r Report) error {
+	payload, err := s.Payload(r)
+	if err != nil {
+		return fmt.Errorf("failed to build slack message: %w", err)
+	}
+
+	resp, err := s.Client.Post(s.WebhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("slack webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("slack webhook returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}